@@ -0,0 +1,93 @@
+package alert
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// Service is used to deliver alerts to the notifiers associated to the alert rules
+// matching an event.
+type Service struct {
+	alertRuleService     portainer.AlertRuleService
+	alertNotifierService portainer.AlertNotifierService
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(alertRuleService portainer.AlertRuleService, alertNotifierService portainer.AlertNotifierService) *Service {
+	return &Service{
+		alertRuleService:     alertRuleService,
+		alertNotifierService: alertNotifierService,
+	}
+}
+
+// Notify delivers an event in the background to every notifier associated to an enabled
+// rule matching the event. A notifier shared by multiple rules is only used once.
+func (service *Service) Notify(event *portainer.AlertEvent) {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().Unix()
+	}
+
+	go func() {
+		rules, err := service.alertRuleService.AlertRules()
+		if err != nil {
+			log.Printf("[ERROR] [alert] [message: unable to retrieve alert rules] [err: %s]", err)
+			return
+		}
+
+		notified := make(map[portainer.AlertNotifierID]bool)
+		for _, rule := range rules {
+			if !ruleMatches(&rule, event) {
+				continue
+			}
+
+			for _, notifierID := range rule.NotifierIDs {
+				if notified[notifierID] {
+					continue
+				}
+				notified[notifierID] = true
+
+				notifier, err := service.alertNotifierService.AlertNotifier(notifierID)
+				if err != nil {
+					log.Printf("[ERROR] [alert] [rule: %s] [notifier_id: %d] [message: unable to retrieve alert notifier] [err: %s]", rule.Name, notifierID, err)
+					continue
+				}
+
+				err = Send(notifier, event)
+				if err != nil {
+					log.Printf("[ERROR] [alert] [rule: %s] [notifier: %s] [message: unable to deliver alert] [err: %s]", rule.Name, notifier.Name, err)
+				}
+			}
+		}
+	}()
+}
+
+func ruleMatches(rule *portainer.AlertRule, event *portainer.AlertEvent) bool {
+	if !rule.Enabled {
+		return false
+	}
+
+	eventTypeMatch := false
+	for _, eventType := range rule.EventTypes {
+		if eventType == event.Type {
+			eventTypeMatch = true
+			break
+		}
+	}
+	if !eventTypeMatch {
+		return false
+	}
+
+	if len(rule.EndpointIDs) == 0 {
+		return true
+	}
+
+	for _, endpointID := range rule.EndpointIDs {
+		if endpointID == event.EndpointID {
+			return true
+		}
+	}
+
+	return false
+}
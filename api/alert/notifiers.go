@@ -0,0 +1,153 @@
+package alert
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const notifierTimeout = 10 * time.Second
+
+var headerReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// Send delivers an event through a notifier.
+func Send(notifier *portainer.AlertNotifier, event *portainer.AlertEvent) error {
+	switch notifier.Type {
+	case portainer.WebhookAlertNotifier:
+		return postJSON(notifier.URL, event)
+	case portainer.SlackAlertNotifier:
+		return postJSON(notifier.URL, &slackPayload{Text: formatMessage(event)})
+	case portainer.EmailAlertNotifier:
+		return sendEmail(&notifier.SMTPSettings, event)
+	}
+	return portainer.Error("Unsupported alert notifier type")
+}
+
+func formatMessage(event *portainer.AlertEvent) string {
+	return fmt.Sprintf("[Portainer] %s (endpoint: %s)", event.Message, event.EndpointName)
+}
+
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: notifierTimeout,
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status from notifier: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func sendEmail(settings *portainer.AlertSMTPSettings, event *portainer.AlertEvent) error {
+	address := settings.Host + ":" + strconv.Itoa(settings.Port)
+
+	var auth smtp.Auth
+	if settings.Username != "" {
+		auth = smtp.PlainAuth("", settings.Username, settings.Password, settings.Host)
+	}
+
+	subject := headerReplacer.Replace(formatMessage(event))
+	body := event.Message + "\r\n\r\nEndpoint: " + event.EndpointName + "\r\nDate: " + time.Unix(event.Timestamp, 0).UTC().Format(time.RFC1123) + "\r\n"
+
+	message := "From: " + settings.From + "\r\n" +
+		"To: " + strings.Join(settings.To, ", ") + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	return sendMail(address, settings.Host, auth, settings.From, settings.To, []byte(message))
+}
+
+// sendMail behaves like smtp.SendMail but bounds the whole SMTP exchange with notifierTimeout,
+// so that an unresponsive server cannot block the alert evaluation.
+func sendMail(address, host string, auth smtp.Auth, from string, to []string, message []byte) error {
+	conn, err := net.DialTimeout("tcp", address, notifierTimeout)
+	if err != nil {
+		return err
+	}
+
+	err = conn.SetDeadline(time.Now().Add(notifierTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return portainer.Error("SMTP server does not support authentication")
+		}
+
+		err = client.Auth(auth)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range to {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(message)
+	if err != nil {
+		return err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -34,6 +34,218 @@ func NewAuthorizationService(parameters *AuthorizationServiceParameters) *Author
 	}
 }
 
+// knownAuthorizations contains the authorizations that can be associated to a role.
+// The undefined operations are excluded as they are only used when an API operation cannot be identified.
+var knownAuthorizations = map[Authorization]bool{
+	OperationDockerContainerArchiveInfo:         true,
+	OperationDockerContainerList:                true,
+	OperationDockerContainerExport:              true,
+	OperationDockerContainerChanges:             true,
+	OperationDockerContainerInspect:             true,
+	OperationDockerContainerTop:                 true,
+	OperationDockerContainerLogs:                true,
+	OperationDockerContainerStats:               true,
+	OperationDockerContainerAttachWebsocket:     true,
+	OperationDockerContainerArchive:             true,
+	OperationDockerContainerCreate:              true,
+	OperationDockerContainerPrune:               true,
+	OperationDockerContainerKill:                true,
+	OperationDockerContainerPause:               true,
+	OperationDockerContainerUnpause:             true,
+	OperationDockerContainerRestart:             true,
+	OperationDockerContainerStart:               true,
+	OperationDockerContainerStop:                true,
+	OperationDockerContainerWait:                true,
+	OperationDockerContainerResize:              true,
+	OperationDockerContainerAttach:              true,
+	OperationDockerContainerExec:                true,
+	OperationDockerContainerRename:              true,
+	OperationDockerContainerUpdate:              true,
+	OperationDockerContainerPutContainerArchive: true,
+	OperationDockerContainerDelete:              true,
+	OperationDockerImageList:                    true,
+	OperationDockerImageSearch:                  true,
+	OperationDockerImageGetAll:                  true,
+	OperationDockerImageGet:                     true,
+	OperationDockerImageHistory:                 true,
+	OperationDockerImageInspect:                 true,
+	OperationDockerImageLoad:                    true,
+	OperationDockerImageCreate:                  true,
+	OperationDockerImagePrune:                   true,
+	OperationDockerImagePush:                    true,
+	OperationDockerImageTag:                     true,
+	OperationDockerImageDelete:                  true,
+	OperationDockerImageCommit:                  true,
+	OperationDockerImageBuild:                   true,
+	OperationDockerNetworkList:                  true,
+	OperationDockerNetworkInspect:               true,
+	OperationDockerNetworkCreate:                true,
+	OperationDockerNetworkConnect:               true,
+	OperationDockerNetworkDisconnect:            true,
+	OperationDockerNetworkPrune:                 true,
+	OperationDockerNetworkDelete:                true,
+	OperationDockerVolumeList:                   true,
+	OperationDockerVolumeInspect:                true,
+	OperationDockerVolumeCreate:                 true,
+	OperationDockerVolumePrune:                  true,
+	OperationDockerVolumeDelete:                 true,
+	OperationDockerExecInspect:                  true,
+	OperationDockerExecStart:                    true,
+	OperationDockerExecResize:                   true,
+	OperationDockerSwarmInspect:                 true,
+	OperationDockerSwarmUnlockKey:               true,
+	OperationDockerSwarmInit:                    true,
+	OperationDockerSwarmJoin:                    true,
+	OperationDockerSwarmLeave:                   true,
+	OperationDockerSwarmUpdate:                  true,
+	OperationDockerSwarmUnlock:                  true,
+	OperationDockerNodeList:                     true,
+	OperationDockerNodeInspect:                  true,
+	OperationDockerNodeUpdate:                   true,
+	OperationDockerNodeDelete:                   true,
+	OperationDockerServiceList:                  true,
+	OperationDockerServiceInspect:               true,
+	OperationDockerServiceLogs:                  true,
+	OperationDockerServiceCreate:                true,
+	OperationDockerServiceUpdate:                true,
+	OperationDockerServiceDelete:                true,
+	OperationDockerSecretList:                   true,
+	OperationDockerSecretInspect:                true,
+	OperationDockerSecretCreate:                 true,
+	OperationDockerSecretUpdate:                 true,
+	OperationDockerSecretDelete:                 true,
+	OperationDockerConfigList:                   true,
+	OperationDockerConfigInspect:                true,
+	OperationDockerConfigCreate:                 true,
+	OperationDockerConfigUpdate:                 true,
+	OperationDockerConfigDelete:                 true,
+	OperationDockerTaskList:                     true,
+	OperationDockerTaskInspect:                  true,
+	OperationDockerTaskLogs:                     true,
+	OperationDockerPluginList:                   true,
+	OperationDockerPluginPrivileges:             true,
+	OperationDockerPluginInspect:                true,
+	OperationDockerPluginPull:                   true,
+	OperationDockerPluginCreate:                 true,
+	OperationDockerPluginEnable:                 true,
+	OperationDockerPluginDisable:                true,
+	OperationDockerPluginPush:                   true,
+	OperationDockerPluginUpgrade:                true,
+	OperationDockerPluginSet:                    true,
+	OperationDockerPluginDelete:                 true,
+	OperationDockerSessionStart:                 true,
+	OperationDockerDistributionInspect:          true,
+	OperationDockerBuildPrune:                   true,
+	OperationDockerBuildCancel:                  true,
+	OperationDockerPing:                         true,
+	OperationDockerInfo:                         true,
+	OperationDockerEvents:                       true,
+	OperationDockerSystem:                       true,
+	OperationDockerVersion:                      true,
+	OperationDockerAgentPing:                    true,
+	OperationDockerAgentList:                    true,
+	OperationDockerAgentHostInfo:                true,
+	OperationDockerAgentBrowseDelete:            true,
+	OperationDockerAgentBrowseGet:               true,
+	OperationDockerAgentBrowseList:              true,
+	OperationDockerAgentBrowsePut:               true,
+	OperationDockerAgentBrowseRename:            true,
+	OperationPortainerDockerHubInspect:          true,
+	OperationPortainerDockerHubUpdate:           true,
+	OperationPortainerEndpointGroupCreate:       true,
+	OperationPortainerEndpointGroupList:         true,
+	OperationPortainerEndpointGroupDelete:       true,
+	OperationPortainerEndpointGroupInspect:      true,
+	OperationPortainerEndpointGroupUpdate:       true,
+	OperationPortainerEndpointGroupAccess:       true,
+	OperationPortainerEndpointList:              true,
+	OperationPortainerEndpointInspect:           true,
+	OperationPortainerEndpointCreate:            true,
+	OperationPortainerEndpointExtensionAdd:      true,
+	OperationPortainerEndpointJob:               true,
+	OperationPortainerEndpointSnapshots:         true,
+	OperationPortainerEndpointSnapshot:          true,
+	OperationPortainerEndpointUpdate:            true,
+	OperationPortainerEndpointUpdateAccess:      true,
+	OperationPortainerEndpointDelete:            true,
+	OperationPortainerEndpointExtensionRemove:   true,
+	OperationPortainerExtensionList:             true,
+	OperationPortainerExtensionInspect:          true,
+	OperationPortainerExtensionCreate:           true,
+	OperationPortainerExtensionUpdate:           true,
+	OperationPortainerExtensionDelete:           true,
+	OperationPortainerMOTD:                      true,
+	OperationPortainerRegistryList:              true,
+	OperationPortainerRegistryInspect:           true,
+	OperationPortainerRegistryCreate:            true,
+	OperationPortainerRegistryConfigure:         true,
+	OperationPortainerRegistryUpdate:            true,
+	OperationPortainerRegistryUpdateAccess:      true,
+	OperationPortainerRegistryDelete:            true,
+	OperationPortainerResourceControlCreate:     true,
+	OperationPortainerResourceControlUpdate:     true,
+	OperationPortainerResourceControlDelete:     true,
+	OperationPortainerRoleList:                  true,
+	OperationPortainerRoleInspect:               true,
+	OperationPortainerRoleCreate:                true,
+	OperationPortainerRoleUpdate:                true,
+	OperationPortainerRoleDelete:                true,
+	OperationPortainerScheduleList:              true,
+	OperationPortainerScheduleInspect:           true,
+	OperationPortainerScheduleFile:              true,
+	OperationPortainerScheduleTasks:             true,
+	OperationPortainerScheduleCreate:            true,
+	OperationPortainerScheduleUpdate:            true,
+	OperationPortainerScheduleDelete:            true,
+	OperationPortainerSettingsInspect:           true,
+	OperationPortainerSettingsUpdate:            true,
+	OperationPortainerSettingsLDAPCheck:         true,
+	OperationPortainerStackList:                 true,
+	OperationPortainerStackInspect:              true,
+	OperationPortainerStackFile:                 true,
+	OperationPortainerStackCreate:               true,
+	OperationPortainerStackMigrate:              true,
+	OperationPortainerStackUpdate:               true,
+	OperationPortainerStackDelete:               true,
+	OperationPortainerTagList:                   true,
+	OperationPortainerTagCreate:                 true,
+	OperationPortainerTagDelete:                 true,
+	OperationPortainerTeamMembershipList:        true,
+	OperationPortainerTeamMembershipCreate:      true,
+	OperationPortainerTeamMembershipUpdate:      true,
+	OperationPortainerTeamMembershipDelete:      true,
+	OperationPortainerTeamList:                  true,
+	OperationPortainerTeamInspect:               true,
+	OperationPortainerTeamMemberships:           true,
+	OperationPortainerTeamCreate:                true,
+	OperationPortainerTeamUpdate:                true,
+	OperationPortainerTeamDelete:                true,
+	OperationPortainerTemplateList:              true,
+	OperationPortainerTemplateInspect:           true,
+	OperationPortainerTemplateCreate:            true,
+	OperationPortainerTemplateUpdate:            true,
+	OperationPortainerTemplateDelete:            true,
+	OperationPortainerUploadTLS:                 true,
+	OperationPortainerUserList:                  true,
+	OperationPortainerUserInspect:               true,
+	OperationPortainerUserMemberships:           true,
+	OperationPortainerUserCreate:                true,
+	OperationPortainerUserUpdate:                true,
+	OperationPortainerUserUpdatePassword:        true,
+	OperationPortainerUserDelete:                true,
+	OperationPortainerWebsocketExec:             true,
+	OperationPortainerWebhookList:               true,
+	OperationPortainerWebhookCreate:             true,
+	OperationPortainerWebhookDelete:             true,
+	OperationIntegrationStoridgeAdmin:           true,
+	EndpointResourcesAccess:                     true,
+}
+
+// IsKnownAuthorization returns true if the authorization can be associated to a role.
+func IsKnownAuthorization(authorization Authorization) bool {
+	return knownAuthorizations[authorization]
+}
+
 // DefaultEndpointAuthorizationsForEndpointAdministratorRole returns the default endpoint authorizations
 // associated to the endpoint administrator role.
 func DefaultEndpointAuthorizationsForEndpointAdministratorRole() Authorizations {
@@ -455,8 +667,9 @@ func (service AuthorizationService) UpdateVolumeBrowsingAuthorizations(remove bo
 	}
 
 	for _, role := range roles {
-		// all roles except endpoint administrator
-		if role.ID != RoleID(1) {
+		// all built-in roles except endpoint administrator, the authorizations of custom roles
+		// are defined by the administrators
+		if role.IsBuiltIn && role.ID != RoleID(1) {
 			updateRoleVolumeBrowsingAuthorizations(&role, remove)
 
 			err := service.roleService.UpdateRole(role.ID, &role)
@@ -774,6 +987,12 @@ func getAuthorizationsFromRoles(roleIdentifiers []RoleID, roles []Role) Authoriz
 	var associatedRoles []Role
 
 	for _, id := range roleIdentifiers {
+		// access policies created while the RBAC extension was not enabled are not associated
+		// to any role, they grant the authorizations of the standard user role
+		if id == 0 {
+			id = RoleID(3)
+		}
+
 		for _, role := range roles {
 			if role.ID == id {
 				associatedRoles = append(associatedRoles, role)
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+)
+
+func writeArchive(w io.Writer, databasePath, dataPath string, entries []string) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := addFileToArchive(tarWriter, databasePath, databaseFileName)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		entryPath := filepath.Join(dataPath, entry)
+
+		_, err := os.Stat(entryPath)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = filepath.Walk(entryPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name, err := filepath.Rel(dataPath, path)
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				return tarWriter.WriteHeader(&tar.Header{
+					Typeflag: tar.TypeDir,
+					Name:     filepath.ToSlash(name) + "/",
+					Mode:     int64(info.Mode().Perm()),
+					ModTime:  info.ModTime(),
+				})
+			}
+
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+
+			return addFileToArchive(tarWriter, path, filepath.ToSlash(name))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+func addFileToArchive(tarWriter *tar.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(info.Mode().Perm()),
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tarWriter, file)
+	return err
+}
+
+func extractArchive(r io.Reader, destination string) error {
+	gzipReader, err := gzip.NewReader(r)
+	if err == portainer.ErrBackupInvalidPassword {
+		// the first chunk of an encrypted archive is decrypted when reading the gzip header
+		return err
+	} else if err != nil {
+		return portainer.ErrBackupInvalidArchive
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	root := filepath.Clean(destination) + string(os.PathSeparator)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return portainer.ErrBackupInvalidArchive
+		}
+
+		target := filepath.Join(destination, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, root) {
+			return portainer.ErrBackupInvalidArchive
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			err = extractFile(tarReader, target, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(r io.Reader, target string, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r)
+	return err
+}
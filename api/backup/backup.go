@@ -0,0 +1,158 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+const databaseFileName = "portainer.db"
+
+// dataEntries are the files and folders of the data directory, relative to this directory,
+// that are stored inside a backup archive alongside the database.
+var dataEntries = []string{
+	filesystem.TLSStorePath,
+	filesystem.ComposeStorePath,
+	filesystem.EdgeStackStorePath,
+	filesystem.ScheduleStorePath,
+	filesystem.ExtensionRegistryManagementStorePath,
+	filesystem.SessionRecordingStorePath,
+	filesystem.PrivateKeyFile,
+	filesystem.PublicKeyFile,
+}
+
+// archiveEntries returns the data entries stored inside a backup archive. The encryption key
+// protecting the secrets of the database is only stored inside encrypted archives.
+func archiveEntries(includeEncryptionKey bool) []string {
+	if !includeEncryptionKey {
+		return dataEntries
+	}
+	return append(append([]string{}, dataEntries...), filesystem.EncryptionKeyFile)
+}
+
+// Service represents a service used to create and restore archives of the Portainer data.
+type Service struct {
+	dataStore portainer.DataStore
+	dataPath  string
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore portainer.DataStore, dataPath string) *Service {
+	return &Service{
+		dataStore: dataStore,
+		dataPath:  dataPath,
+	}
+}
+
+// CreateBackup writes a gzipped tar archive containing a consistent copy of the database and
+// the data files to w. The archive is encrypted when a password is specified, the encryption key
+// of the instance is only part of encrypted archives.
+func (service *Service) CreateBackup(w io.Writer, password string) error {
+	tempDir, err := ioutil.TempDir(service.dataPath, ".backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	databasePath := filepath.Join(tempDir, databaseFileName)
+	err = service.dataStore.Backup(databasePath)
+	if err != nil {
+		return err
+	}
+
+	if password == "" {
+		return writeArchive(w, databasePath, service.dataPath, archiveEntries(false))
+	}
+
+	encryptWriter, err := newEncryptWriter(w, password)
+	if err != nil {
+		return err
+	}
+
+	err = writeArchive(encryptWriter, databasePath, service.dataPath, archiveEntries(true))
+	if err != nil {
+		return err
+	}
+
+	return encryptWriter.Close()
+}
+
+// RestoreBackup restores an archive created via CreateBackup. The database is restored first
+// (and migrated if the archive was created by a previous version), the data files are then
+// swapped with the ones available in the archive. The encryption key of the instance is only
+// replaced when restoring an encrypted archive.
+// The restored data is only fully taken into account after a restart of the instance.
+func (service *Service) RestoreBackup(r io.Reader, password string) error {
+	reader := bufio.NewReader(r)
+
+	var archive io.Reader = reader
+	header, err := reader.Peek(len(encryptedArchiveHeader))
+	encrypted := err == nil && bytes.Equal(header, encryptedArchiveHeader)
+	if encrypted {
+		if password == "" {
+			return portainer.ErrBackupPasswordRequired
+		}
+
+		archive, err = newDecryptReader(reader, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	tempDir, err := ioutil.TempDir(service.dataPath, ".restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	err = extractArchive(archive, tempDir)
+	if err != nil {
+		return err
+	}
+
+	databasePath := filepath.Join(tempDir, databaseFileName)
+	if _, err := os.Stat(databasePath); err != nil {
+		return portainer.ErrBackupInvalidArchive
+	}
+
+	err = service.dataStore.Restore(databasePath)
+	if err != nil {
+		return err
+	}
+
+	if !encrypted {
+		log.Printf("[WARN] [backup] [message: the backup archive is not encrypted, the secrets of the restored database can only be decrypted with the encryption key of this instance]")
+	}
+
+	for _, entry := range archiveEntries(encrypted) {
+		err = restoreDataEntry(filepath.Join(tempDir, entry), filepath.Join(service.dataPath, entry))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restoreDataEntry(restoredPath, currentPath string) error {
+	_, err := os.Stat(restoredPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	err = os.RemoveAll(currentPath)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(restoredPath, currentPath)
+}
@@ -0,0 +1,241 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+// testDataStore uses a plain file as its database.
+type testDataStore struct {
+	portainer.DataStore
+	content    string
+	restoreErr error
+}
+
+func (store *testDataStore) Backup(databasePath string) error {
+	return ioutil.WriteFile(databasePath, []byte(store.content), 0600)
+}
+
+func (store *testDataStore) Restore(databasePath string) error {
+	if store.restoreErr != nil {
+		return store.restoreErr
+	}
+
+	data, err := ioutil.ReadFile(databasePath)
+	if err != nil {
+		return err
+	}
+	store.content = string(data)
+	return nil
+}
+
+func writeTestFiles(t *testing.T, dataPath string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dataPath, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatalf("unable to create directory: %s", err)
+		}
+
+		err = ioutil.WriteFile(path, []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("unable to write file: %s", err)
+		}
+	}
+}
+
+func checkTestFiles(t *testing.T, dataPath string, files map[string]string) {
+	for name, content := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dataPath, name))
+		if err != nil {
+			t.Errorf("unable to read file %s: %s", name, err)
+			continue
+		}
+
+		if string(data) != content {
+			t.Errorf("expected file %s to contain %q, got %q", name, content, string(data))
+		}
+	}
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	sourcePath, err := ioutil.TempDir("", "backup-source-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(sourcePath)
+
+	targetPath, err := ioutil.TempDir("", "backup-target-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(targetPath)
+
+	sourceFiles := map[string]string{
+		"tls/1/cert.pem":                   "certificate",
+		"compose/2/docker-compose.yml":     "version: '3'",
+		filesystem.PrivateKeyFile:          "private key",
+		filesystem.EncryptionKeyFile:       "source encryption key",
+		"compose/3/nested/stack/.env":      "KEY=value",
+		"edge_stacks/1/docker-compose.yml": "version: '3'",
+		"schedules/1/script.sh":            "echo",
+		"extensions/1/registry-mgmt.json":  "{}",
+	}
+	writeTestFiles(t, sourcePath, sourceFiles)
+	writeTestFiles(t, targetPath, map[string]string{
+		"compose/2/docker-compose.yml": "outdated",
+		filesystem.EncryptionKeyFile:   "target encryption key",
+	})
+
+	source := NewService(&testDataStore{content: "source database"}, sourcePath)
+
+	var archive bytes.Buffer
+	err = source.CreateBackup(&archive, "password")
+	if err != nil {
+		t.Fatalf("unable to create backup: %s", err)
+	}
+
+	targetStore := &testDataStore{content: "target database"}
+	target := NewService(targetStore, targetPath)
+
+	err = target.RestoreBackup(bytes.NewReader(archive.Bytes()), "")
+	if err != portainer.ErrBackupPasswordRequired {
+		t.Errorf("expected a password to be required, got %v", err)
+	}
+
+	err = target.RestoreBackup(bytes.NewReader(archive.Bytes()), "invalid")
+	if err != portainer.ErrBackupInvalidPassword {
+		t.Errorf("expected the password to be rejected, got %v", err)
+	}
+
+	err = target.RestoreBackup(bytes.NewReader(archive.Bytes()), "password")
+	if err != nil {
+		t.Fatalf("unable to restore backup: %s", err)
+	}
+
+	if targetStore.content != "source database" {
+		t.Errorf("expected the database to be restored, got %q", targetStore.content)
+	}
+	checkTestFiles(t, targetPath, sourceFiles)
+}
+
+func TestBackupRoundTripWithoutPassword(t *testing.T) {
+	sourcePath, err := ioutil.TempDir("", "backup-source-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(sourcePath)
+
+	targetPath, err := ioutil.TempDir("", "backup-target-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(targetPath)
+
+	writeTestFiles(t, sourcePath, map[string]string{
+		"tls/1/cert.pem":             "certificate",
+		filesystem.EncryptionKeyFile: "source encryption key",
+	})
+	writeTestFiles(t, targetPath, map[string]string{
+		filesystem.EncryptionKeyFile: "target encryption key",
+	})
+
+	var archive bytes.Buffer
+	err = NewService(&testDataStore{content: "source database"}, sourcePath).CreateBackup(&archive, "")
+	if err != nil {
+		t.Fatalf("unable to create backup: %s", err)
+	}
+
+	targetStore := &testDataStore{}
+	err = NewService(targetStore, targetPath).RestoreBackup(&archive, "")
+	if err != nil {
+		t.Fatalf("unable to restore backup: %s", err)
+	}
+
+	if targetStore.content != "source database" {
+		t.Errorf("expected the database to be restored, got %q", targetStore.content)
+	}
+
+	checkTestFiles(t, targetPath, map[string]string{
+		"tls/1/cert.pem":             "certificate",
+		filesystem.EncryptionKeyFile: "target encryption key",
+	})
+}
+
+func TestRestoreBackupDBVersionNotSupported(t *testing.T) {
+	sourcePath, err := ioutil.TempDir("", "backup-source-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(sourcePath)
+
+	targetPath, err := ioutil.TempDir("", "backup-target-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(targetPath)
+
+	writeTestFiles(t, sourcePath, map[string]string{"tls/1/cert.pem": "restored certificate"})
+	targetFiles := map[string]string{"tls/1/cert.pem": "current certificate"}
+	writeTestFiles(t, targetPath, targetFiles)
+
+	var archive bytes.Buffer
+	err = NewService(&testDataStore{content: "source database"}, sourcePath).CreateBackup(&archive, "")
+	if err != nil {
+		t.Fatalf("unable to create backup: %s", err)
+	}
+
+	targetStore := &testDataStore{content: "target database", restoreErr: portainer.ErrBackupDBVersionNotSupported}
+	err = NewService(targetStore, targetPath).RestoreBackup(&archive, "")
+	if err != portainer.ErrBackupDBVersionNotSupported {
+		t.Errorf("expected the database version to be rejected, got %v", err)
+	}
+
+	if targetStore.content != "target database" {
+		t.Errorf("expected the database to be left untouched, got %q", targetStore.content)
+	}
+	checkTestFiles(t, targetPath, targetFiles)
+}
+
+func TestExtractArchivePathTraversal(t *testing.T) {
+	destination, err := ioutil.TempDir("", "backup-extract-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+	defer os.RemoveAll(destination)
+
+	names := []string{"../escaped", "tls/../../escaped", "/../escaped"}
+	for _, name := range names {
+		var archive bytes.Buffer
+		gzipWriter := gzip.NewWriter(&archive)
+		tarWriter := tar.NewWriter(gzipWriter)
+
+		content := []byte("content")
+		err := tarWriter.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0600, Size: int64(len(content))})
+		if err != nil {
+			t.Fatalf("unable to write archive: %s", err)
+		}
+		tarWriter.Write(content)
+		tarWriter.Close()
+		gzipWriter.Close()
+
+		extractPath := filepath.Join(destination, "data")
+		err = extractArchive(&archive, extractPath)
+		if err != portainer.ErrBackupInvalidArchive {
+			t.Errorf("expected entry %s to be rejected, got %v", name, err)
+		}
+
+		if _, err := os.Stat(filepath.Join(destination, "escaped")); !os.IsNotExist(err) {
+			t.Errorf("expected entry %s not to be extracted outside of the destination", name)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"github.com/portainer/portainer/api"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptedArchiveHeader prefixes every encrypted archive. It is followed by the salt
+// used to derive the key from the password, the base nonce and the encrypted chunks of the archive.
+// Each chunk is made of a flag marking the last chunk, the length of the sealed data and the sealed data.
+var encryptedArchiveHeader = []byte("PORTAINER_BACKUP_V1")
+
+const (
+	saltLength = 16
+	// chunkSize is the maximum size of the plain text data sealed in a single chunk
+	chunkSize = 64 * 1024
+	// chunkHeaderLength is the size of the flag and of the length preceding each sealed chunk
+	chunkHeaderLength = 5
+	lastChunkFlag     = 1
+)
+
+func newCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, 32768, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce used to seal a chunk, based on the nonce of the archive and the chunk index.
+func chunkNonce(baseNonce []byte, index uint64) []byte {
+	nonce := append([]byte{}, baseNonce...)
+	counter := nonce[len(nonce)-8:]
+	binary.BigEndian.PutUint64(counter, binary.BigEndian.Uint64(counter)^index)
+	return nonce
+}
+
+// encryptWriter encrypts the data written to it in chunks so that an archive can be
+// encrypted without being kept in memory. Close must be called to write the last chunk.
+type encryptWriter struct {
+	writer io.Writer
+	aead   cipher.AEAD
+	nonce  []byte
+	index  uint64
+	buffer []byte
+}
+
+func newEncryptWriter(w io.Writer, password string) (*encryptWriter, error) {
+	salt := make([]byte, saltLength)
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(encryptedArchiveHeader)+saltLength+len(nonce))
+	header = append(header, encryptedArchiveHeader...)
+	header = append(header, salt...)
+	header = append(header, nonce...)
+	_, err = w.Write(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		writer: w,
+		aead:   aead,
+		nonce:  nonce,
+		buffer: make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (writer *encryptWriter) Write(data []byte) (int, error) {
+	written := 0
+	for len(data) > 0 {
+		size := chunkSize - len(writer.buffer)
+		if size > len(data) {
+			size = len(data)
+		}
+
+		writer.buffer = append(writer.buffer, data[:size]...)
+		data = data[size:]
+		written += size
+
+		if len(writer.buffer) == chunkSize {
+			err := writer.writeChunk(0)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close writes the remaining data as the last chunk of the archive.
+func (writer *encryptWriter) Close() error {
+	return writer.writeChunk(lastChunkFlag)
+}
+
+func (writer *encryptWriter) writeChunk(flag byte) error {
+	header := make([]byte, chunkHeaderLength, chunkHeaderLength+len(writer.buffer)+writer.aead.Overhead())
+	header[0] = flag
+
+	chunk := writer.aead.Seal(header, chunkNonce(writer.nonce, writer.index), writer.buffer, header[:1])
+	binary.BigEndian.PutUint32(chunk[1:chunkHeaderLength], uint32(len(chunk)-chunkHeaderLength))
+
+	_, err := writer.writer.Write(chunk)
+	if err != nil {
+		return err
+	}
+
+	writer.index++
+	writer.buffer = writer.buffer[:0]
+	return nil
+}
+
+// decryptReader decrypts an archive encrypted with an encryptWriter chunk by chunk.
+// A truncated archive is reported as an invalid archive.
+type decryptReader struct {
+	reader io.Reader
+	aead   cipher.AEAD
+	nonce  []byte
+	index  uint64
+	plain  []byte
+	last   bool
+}
+
+// newDecryptReader reads the header of an encrypted archive and returns a reader
+// of the decrypted archive.
+func newDecryptReader(r io.Reader, password string) (*decryptReader, error) {
+	header := make([]byte, len(encryptedArchiveHeader)+saltLength)
+	_, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, portainer.ErrBackupInvalidArchive
+	}
+
+	aead, err := newCipher(password, header[len(encryptedArchiveHeader):])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = io.ReadFull(r, nonce)
+	if err != nil {
+		return nil, portainer.ErrBackupInvalidArchive
+	}
+
+	return &decryptReader{
+		reader: r,
+		aead:   aead,
+		nonce:  nonce,
+	}, nil
+}
+
+func (reader *decryptReader) Read(data []byte) (int, error) {
+	for len(reader.plain) == 0 {
+		if reader.last {
+			return 0, io.EOF
+		}
+
+		err := reader.readChunk()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(data, reader.plain)
+	reader.plain = reader.plain[n:]
+	return n, nil
+}
+
+func (reader *decryptReader) readChunk() error {
+	header := make([]byte, chunkHeaderLength)
+	_, err := io.ReadFull(reader.reader, header)
+	if err != nil {
+		return portainer.ErrBackupInvalidArchive
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length > uint32(chunkSize+reader.aead.Overhead()) {
+		return portainer.ErrBackupInvalidArchive
+	}
+
+	sealed := make([]byte, length)
+	_, err = io.ReadFull(reader.reader, sealed)
+	if err != nil {
+		return portainer.ErrBackupInvalidArchive
+	}
+
+	plain, err := reader.aead.Open(sealed[:0], chunkNonce(reader.nonce, reader.index), sealed, header[:1])
+	if err != nil {
+		if reader.index == 0 {
+			return portainer.ErrBackupInvalidPassword
+		}
+		return portainer.ErrBackupInvalidArchive
+	}
+
+	reader.index++
+	reader.plain = plain
+	reader.last = header[0] == lastChunkFlag
+	return nil
+}
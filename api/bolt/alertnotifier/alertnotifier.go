@@ -0,0 +1,131 @@
+package alertnotifier
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "alert_notifiers"
+)
+
+// Service represents a service for managing alert notifier data.
+type Service struct {
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
+}
+
+// NewService creates a new instance of a service.
+// The SMTP passwords of the notifiers are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db:                db,
+		encryptionService: encryptionService,
+	}, nil
+}
+
+// AlertNotifier returns an alert notifier by ID.
+func (service *Service) AlertNotifier(ID portainer.AlertNotifierID) (*portainer.AlertNotifier, error) {
+	var notifier portainer.AlertNotifier
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &notifier)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier.SMTPSettings.Password, err = crypto.DecryptSecret(service.encryptionService, notifier.SMTPSettings.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notifier, nil
+}
+
+// AlertNotifiers returns an array containing all the alert notifiers.
+func (service *Service) AlertNotifiers() ([]portainer.AlertNotifier, error) {
+	var notifiers = make([]portainer.AlertNotifier, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var notifier portainer.AlertNotifier
+			err := internal.UnmarshalObject(v, &notifier)
+			if err != nil {
+				return err
+			}
+
+			notifier.SMTPSettings.Password, err = crypto.DecryptSecret(service.encryptionService, notifier.SMTPSettings.Password)
+			if err != nil {
+				return err
+			}
+			notifiers = append(notifiers, notifier)
+		}
+
+		return nil
+	})
+
+	return notifiers, err
+}
+
+// CreateAlertNotifier assigns an ID to a new alert notifier and saves it.
+func (service *Service) CreateAlertNotifier(notifier *portainer.AlertNotifier) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		notifier.ID = portainer.AlertNotifierID(id)
+
+		encryptedNotifier, err := service.encryptNotifier(notifier)
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(encryptedNotifier)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(notifier.ID)), data)
+	})
+}
+
+// UpdateAlertNotifier updates an alert notifier.
+func (service *Service) UpdateAlertNotifier(ID portainer.AlertNotifierID, notifier *portainer.AlertNotifier) error {
+	encryptedNotifier, err := service.encryptNotifier(notifier)
+	if err != nil {
+		return err
+	}
+
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, encryptedNotifier)
+}
+
+// DeleteAlertNotifier deletes an alert notifier.
+func (service *Service) DeleteAlertNotifier(ID portainer.AlertNotifierID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
+
+// encryptNotifier returns a copy of the alert notifier where the SMTP password is encrypted.
+func (service *Service) encryptNotifier(notifier *portainer.AlertNotifier) (*portainer.AlertNotifier, error) {
+	encryptedNotifier := *notifier
+
+	var err error
+	encryptedNotifier.SMTPSettings.Password, err = crypto.EncryptSecret(service.encryptionService, notifier.SMTPSettings.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedNotifier, nil
+}
@@ -0,0 +1,94 @@
+package alertrule
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "alert_rules"
+)
+
+// Service represents a service for managing alert rule data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// AlertRule returns an alert rule by ID.
+func (service *Service) AlertRule(ID portainer.AlertRuleID) (*portainer.AlertRule, error) {
+	var rule portainer.AlertRule
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// AlertRules returns an array containing all the alert rules.
+func (service *Service) AlertRules() ([]portainer.AlertRule, error) {
+	var rules = make([]portainer.AlertRule, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var rule portainer.AlertRule
+			err := internal.UnmarshalObject(v, &rule)
+			if err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+		}
+
+		return nil
+	})
+
+	return rules, err
+}
+
+// CreateAlertRule assigns an ID to a new alert rule and saves it.
+func (service *Service) CreateAlertRule(rule *portainer.AlertRule) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		rule.ID = portainer.AlertRuleID(id)
+
+		data, err := internal.MarshalObject(rule)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(rule.ID)), data)
+	})
+}
+
+// UpdateAlertRule updates an alert rule.
+func (service *Service) UpdateAlertRule(ID portainer.AlertRuleID, rule *portainer.AlertRule) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, rule)
+}
+
+// DeleteAlertRule deletes an alert rule.
+func (service *Service) DeleteAlertRule(ID portainer.AlertRuleID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
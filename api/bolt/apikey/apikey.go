@@ -0,0 +1,120 @@
+package apikey
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "api_keys"
+)
+
+// Service represents a service for managing API key data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// APIKey returns an API key by ID.
+func (service *Service) APIKey(ID portainer.APIKeyID) (*portainer.APIKey, error) {
+	var apiKey portainer.APIKey
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// APIKeys returns an array containing all the API keys.
+func (service *Service) APIKeys() ([]portainer.APIKey, error) {
+	var apiKeys = make([]portainer.APIKey, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var apiKey portainer.APIKey
+			err := internal.UnmarshalObject(v, &apiKey)
+			if err != nil {
+				return err
+			}
+			apiKeys = append(apiKeys, apiKey)
+		}
+
+		return nil
+	})
+
+	return apiKeys, err
+}
+
+// APIKeysByUserID returns an array containing all the API keys owned by a specific user.
+func (service *Service) APIKeysByUserID(userID portainer.UserID) ([]portainer.APIKey, error) {
+	var apiKeys = make([]portainer.APIKey, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var apiKey portainer.APIKey
+			err := internal.UnmarshalObject(v, &apiKey)
+			if err != nil {
+				return err
+			}
+
+			if apiKey.UserID == userID {
+				apiKeys = append(apiKeys, apiKey)
+			}
+		}
+
+		return nil
+	})
+
+	return apiKeys, err
+}
+
+// CreateAPIKey assigns an ID to a new API key and saves it.
+func (service *Service) CreateAPIKey(apiKey *portainer.APIKey) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		apiKey.ID = portainer.APIKeyID(id)
+
+		data, err := internal.MarshalObject(apiKey)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(apiKey.ID)), data)
+	})
+}
+
+// UpdateAPIKey updates an API key.
+func (service *Service) UpdateAPIKey(ID portainer.APIKeyID, apiKey *portainer.APIKey) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, apiKey)
+}
+
+// DeleteAPIKey deletes an API key.
+func (service *Service) DeleteAPIKey(ID portainer.APIKeyID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "audit_logs"
+)
+
+// Service represents a service for managing audit log data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// AuditLogs returns the audit logs created after the audit log identified by afterID for which match
+// returns true, ordered by creation. At most limit audit logs are returned when limit is positive.
+// Audit logs are keyed by a sequential identifier, the iteration starts right after afterID.
+func (service *Service) AuditLogs(afterID portainer.AuditLogID, limit int, match func(auditLog *portainer.AuditLog) bool) ([]portainer.AuditLog, error) {
+	var auditLogs = make([]portainer.AuditLog, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(internal.Itob(int(afterID) + 1)); k != nil; k, v = cursor.Next() {
+			var auditLog portainer.AuditLog
+			err := internal.UnmarshalObject(v, &auditLog)
+			if err != nil {
+				return err
+			}
+
+			if !match(&auditLog) {
+				continue
+			}
+
+			auditLogs = append(auditLogs, auditLog)
+			if limit > 0 && len(auditLogs) == limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return auditLogs, err
+}
+
+// CreateAuditLog assigns an ID to a new audit log and saves it.
+// Audit logs are created for most of the mutating requests, the concurrent creations are
+// batched inside a single transaction.
+func (service *Service) CreateAuditLog(auditLog *portainer.AuditLog) error {
+	return service.db.Batch(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		auditLog.ID = portainer.AuditLogID(id)
+
+		data, err := internal.MarshalObject(auditLog)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(auditLog.ID)), data)
+	})
+}
+
+// PruneAuditLogs removes the audit logs created before the specified time.
+// Audit logs are keyed by a sequential identifier, the iteration stops at the first
+// audit log created after the specified time.
+func (service *Service) PruneAuditLogs(before int64) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var auditLog portainer.AuditLog
+			err := internal.UnmarshalObject(v, &auditLog)
+			if err != nil {
+				return err
+			}
+
+			if auditLog.Timestamp >= before {
+				break
+			}
+			keys = append(keys, k)
+		}
+
+		for _, k := range keys {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package bolt
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/version"
+)
+
+// Backup writes a consistent copy of the database to the specified path.
+func (store *Store) Backup(databasePath string) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(databasePath, 0600)
+	})
+}
+
+// Restore replaces the content of the database with the content of the database
+// available at the specified path and migrates the restored data if needed.
+// The content is replaced inside a single transaction on the opened database so that
+// the services keep working on the same database handle. Backups created by a more
+// recent version of Portainer are rejected.
+func (store *Store) Restore(databasePath string) error {
+	restoredDB, err := bolt.Open(databasePath, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return err
+	}
+	defer restoredDB.Close()
+
+	versionService, err := version.NewService(restoredDB)
+	if err != nil {
+		return err
+	}
+
+	restoredVersion, err := versionService.DBVersion()
+	if err == portainer.ErrObjectNotFound {
+		return portainer.ErrBackupInvalidArchive
+	} else if err != nil {
+		return err
+	}
+
+	if restoredVersion > portainer.DBVersion {
+		return portainer.ErrBackupDBVersionNotSupported
+	}
+
+	err = restoredDB.View(func(restoredTx *bolt.Tx) error {
+		return store.db.Update(func(tx *bolt.Tx) error {
+			bucketNames := make([][]byte, 0)
+			err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+				bucketNames = append(bucketNames, append([]byte{}, name...))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, name := range bucketNames {
+				err = tx.DeleteBucket(name)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = restoredTx.ForEach(func(name []byte, restoredBucket *bolt.Bucket) error {
+				bucket, err := tx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(bucket, restoredBucket)
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, name := range bucketNames {
+				_, err = tx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	store.checkForDataMigration = true
+	return store.MigrateData()
+}
+
+func copyBucket(destination, source *bolt.Bucket) error {
+	err := destination.SetSequence(source.Sequence())
+	if err != nil {
+		return err
+	}
+
+	return source.ForEach(func(key, value []byte) error {
+		if value == nil {
+			nestedBucket, err := destination.CreateBucket(key)
+			if err != nil {
+				return err
+			}
+			return copyBucket(nestedBucket, source.Bucket(key))
+		}
+		return destination.Put(key, value)
+	})
+}
@@ -0,0 +1,94 @@
+package containerpolicy
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "container_policies"
+)
+
+// Service represents a service for managing container policy data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// ContainerPolicy returns a container policy by ID.
+func (service *Service) ContainerPolicy(ID portainer.ContainerPolicyID) (*portainer.ContainerPolicy, error) {
+	var policy portainer.ContainerPolicy
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// ContainerPolicies returns an array containing all the container policies.
+func (service *Service) ContainerPolicies() ([]portainer.ContainerPolicy, error) {
+	var policies = make([]portainer.ContainerPolicy, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var policy portainer.ContainerPolicy
+			err := internal.UnmarshalObject(v, &policy)
+			if err != nil {
+				return err
+			}
+			policies = append(policies, policy)
+		}
+
+		return nil
+	})
+
+	return policies, err
+}
+
+// CreateContainerPolicy assigns an ID to a new container policy and saves it.
+func (service *Service) CreateContainerPolicy(policy *portainer.ContainerPolicy) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		policy.ID = portainer.ContainerPolicyID(id)
+
+		data, err := internal.MarshalObject(policy)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(policy.ID)), data)
+	})
+}
+
+// UpdateContainerPolicy updates a container policy.
+func (service *Service) UpdateContainerPolicy(ID portainer.ContainerPolicyID, policy *portainer.ContainerPolicy) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, policy)
+}
+
+// DeleteContainerPolicy deletes a container policy.
+func (service *Service) DeleteContainerPolicy(ID portainer.ContainerPolicyID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -9,16 +9,29 @@ import (
 
 	"github.com/boltdb/bolt"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/alertnotifier"
+	"github.com/portainer/portainer/api/bolt/alertrule"
+	"github.com/portainer/portainer/api/bolt/apikey"
+	"github.com/portainer/portainer/api/bolt/audit"
+	"github.com/portainer/portainer/api/bolt/containerpolicy"
 	"github.com/portainer/portainer/api/bolt/dockerhub"
+	"github.com/portainer/portainer/api/bolt/edgejointoken"
+	"github.com/portainer/portainer/api/bolt/edgestack"
 	"github.com/portainer/portainer/api/bolt/endpoint"
+	"github.com/portainer/portainer/api/bolt/endpointevent"
 	"github.com/portainer/portainer/api/bolt/endpointgroup"
 	"github.com/portainer/portainer/api/bolt/extension"
+	"github.com/portainer/portainer/api/bolt/ldapsync"
 	"github.com/portainer/portainer/api/bolt/migrator"
+	"github.com/portainer/portainer/api/bolt/quota"
 	"github.com/portainer/portainer/api/bolt/registry"
 	"github.com/portainer/portainer/api/bolt/resourcecontrol"
 	"github.com/portainer/portainer/api/bolt/role"
 	"github.com/portainer/portainer/api/bolt/schedule"
+	"github.com/portainer/portainer/api/bolt/session"
+	"github.com/portainer/portainer/api/bolt/sessionrecording"
 	"github.com/portainer/portainer/api/bolt/settings"
+	"github.com/portainer/portainer/api/bolt/snapshothistory"
 	"github.com/portainer/portainer/api/bolt/stack"
 	"github.com/portainer/portainer/api/bolt/tag"
 	"github.com/portainer/portainer/api/bolt/team"
@@ -36,35 +49,51 @@ const (
 // Store defines the implementation of portainer.DataStore using
 // BoltDB as the storage system.
 type Store struct {
-	path                   string
-	db                     *bolt.DB
-	checkForDataMigration  bool
-	fileService            portainer.FileService
-	RoleService            *role.Service
-	DockerHubService       *dockerhub.Service
-	EndpointGroupService   *endpointgroup.Service
-	EndpointService        *endpoint.Service
-	ExtensionService       *extension.Service
-	RegistryService        *registry.Service
-	ResourceControlService *resourcecontrol.Service
-	SettingsService        *settings.Service
-	StackService           *stack.Service
-	TagService             *tag.Service
-	TeamMembershipService  *teammembership.Service
-	TeamService            *team.Service
-	TemplateService        *template.Service
-	TunnelServerService    *tunnelserver.Service
-	UserService            *user.Service
-	VersionService         *version.Service
-	WebhookService         *webhook.Service
-	ScheduleService        *schedule.Service
+	path                    string
+	db                      *bolt.DB
+	checkForDataMigration   bool
+	fileService             portainer.FileService
+	encryptionService       portainer.EncryptionService
+	AlertNotifierService    *alertnotifier.Service
+	AlertRuleService        *alertrule.Service
+	APIKeyService           *apikey.Service
+	AuditService            *audit.Service
+	SessionService          *session.Service
+	SessionRecordingService *sessionrecording.Service
+	RoleService             *role.Service
+	ContainerPolicyService  *containerpolicy.Service
+	DockerHubService        *dockerhub.Service
+	EdgeJoinTokenService    *edgejointoken.Service
+	EdgeStackService        *edgestack.Service
+	EndpointEventService    *endpointevent.Service
+	EndpointGroupService    *endpointgroup.Service
+	EndpointService         *endpoint.Service
+	ExtensionService        *extension.Service
+	LDAPSyncReportService   *ldapsync.Service
+	RegistryService         *registry.Service
+	QuotaService            *quota.Service
+	ResourceControlService  *resourcecontrol.Service
+	SettingsService         *settings.Service
+	SnapshotHistoryService  *snapshothistory.Service
+	StackService            *stack.Service
+	TagService              *tag.Service
+	TeamMembershipService   *teammembership.Service
+	TeamService             *team.Service
+	TemplateService         *template.Service
+	TunnelServerService     *tunnelserver.Service
+	UserService             *user.Service
+	VersionService          *version.Service
+	WebhookService          *webhook.Service
+	ScheduleService         *schedule.Service
 }
 
-// NewStore initializes a new Store and the associated services
-func NewStore(storePath string, fileService portainer.FileService) (*Store, error) {
+// NewStore initializes a new Store and the associated services.
+// The encryption service is used to encrypt the secrets stored in the database.
+func NewStore(storePath string, fileService portainer.FileService, encryptionService portainer.EncryptionService) (*Store, error) {
 	store := &Store{
-		path:        storePath,
-		fileService: fileService,
+		path:              storePath,
+		fileService:       fileService,
+		encryptionService: encryptionService,
 	}
 
 	databasePath := path.Join(storePath, databaseFileName)
@@ -119,6 +148,7 @@ func (store *Store) MigrateData() error {
 		migratorParams := &migrator.Parameters{
 			DB:                     store.db,
 			DatabaseVersion:        version,
+			DockerHubService:       store.DockerHubService,
 			EndpointGroupService:   store.EndpointGroupService,
 			EndpointService:        store.EndpointService,
 			ExtensionService:       store.ExtensionService,
@@ -148,13 +178,55 @@ func (store *Store) MigrateData() error {
 }
 
 func (store *Store) initServices() error {
+	alertNotifierService, err := alertnotifier.NewService(store.db, store.encryptionService)
+	if err != nil {
+		return err
+	}
+	store.AlertNotifierService = alertNotifierService
+
+	alertRuleService, err := alertrule.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.AlertRuleService = alertRuleService
+
+	apiKeyService, err := apikey.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.APIKeyService = apiKeyService
+
+	auditService, err := audit.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.AuditService = auditService
+
+	sessionService, err := session.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.SessionService = sessionService
+
+	sessionRecordingService, err := sessionrecording.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.SessionRecordingService = sessionRecordingService
+
 	authorizationsetService, err := role.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.RoleService = authorizationsetService
 
-	dockerhubService, err := dockerhub.NewService(store.db)
+	containerPolicyService, err := containerpolicy.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.ContainerPolicyService = containerPolicyService
+
+	dockerhubService, err := dockerhub.NewService(store.db, store.encryptionService)
 	if err != nil {
 		return err
 	}
@@ -166,37 +238,73 @@ func (store *Store) initServices() error {
 	}
 	store.EndpointGroupService = endpointgroupService
 
-	endpointService, err := endpoint.NewService(store.db)
+	edgeJoinTokenService, err := edgejointoken.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.EdgeJoinTokenService = edgeJoinTokenService
+
+	edgeStackService, err := edgestack.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.EdgeStackService = edgeStackService
+
+	endpointService, err := endpoint.NewService(store.db, store.encryptionService)
 	if err != nil {
 		return err
 	}
 	store.EndpointService = endpointService
 
+	endpointEventService, err := endpointevent.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.EndpointEventService = endpointEventService
+
 	extensionService, err := extension.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.ExtensionService = extensionService
 
-	registryService, err := registry.NewService(store.db)
+	ldapSyncReportService, err := ldapsync.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.LDAPSyncReportService = ldapSyncReportService
+
+	registryService, err := registry.NewService(store.db, store.encryptionService)
 	if err != nil {
 		return err
 	}
 	store.RegistryService = registryService
 
+	quotaService, err := quota.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.QuotaService = quotaService
+
 	resourcecontrolService, err := resourcecontrol.NewService(store.db)
 	if err != nil {
 		return err
 	}
 	store.ResourceControlService = resourcecontrolService
 
-	settingsService, err := settings.NewService(store.db)
+	snapshotHistoryService, err := snapshothistory.NewService(store.db)
+	if err != nil {
+		return err
+	}
+	store.SnapshotHistoryService = snapshotHistoryService
+
+	settingsService, err := settings.NewService(store.db, store.encryptionService)
 	if err != nil {
 		return err
 	}
 	store.SettingsService = settingsService
 
-	stackService, err := stack.NewService(store.db)
+	stackService, err := stack.NewService(store.db, store.encryptionService)
 	if err != nil {
 		return err
 	}
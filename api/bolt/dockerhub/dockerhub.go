@@ -3,6 +3,7 @@ package dockerhub
 import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
 
 	"github.com/boltdb/bolt"
 )
@@ -15,18 +16,21 @@ const (
 
 // Service represents a service for managing Dockerhub data.
 type Service struct {
-	db *bolt.DB
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
 }
 
 // NewService creates a new instance of a service.
-func NewService(db *bolt.DB) (*Service, error) {
+// The secrets of the objects are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Service{
-		db: db,
+		db:                db,
+		encryptionService: encryptionService,
 	}, nil
 }
 
@@ -39,10 +43,23 @@ func (service *Service) DockerHub() (*portainer.DockerHub, error) {
 		return nil, err
 	}
 
+	dockerhub.Password, err = crypto.DecryptSecret(service.encryptionService, dockerhub.Password)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dockerhub, nil
 }
 
 // UpdateDockerHub updates a DockerHub object.
 func (service *Service) UpdateDockerHub(dockerhub *portainer.DockerHub) error {
-	return internal.UpdateObject(service.db, BucketName, []byte(dockerHubKey), dockerhub)
+	encryptedDockerHub := *dockerhub
+
+	var err error
+	encryptedDockerHub.Password, err = crypto.EncryptSecret(service.encryptionService, dockerhub.Password)
+	if err != nil {
+		return err
+	}
+
+	return internal.UpdateObject(service.db, BucketName, []byte(dockerHubKey), &encryptedDockerHub)
 }
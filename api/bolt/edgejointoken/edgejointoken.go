@@ -0,0 +1,94 @@
+package edgejointoken
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "edge_join_tokens"
+)
+
+// Service represents a service for managing Edge join token data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// EdgeJoinToken returns an Edge join token by ID.
+func (service *Service) EdgeJoinToken(ID portainer.EdgeJoinTokenID) (*portainer.EdgeJoinToken, error) {
+	var token portainer.EdgeJoinToken
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// EdgeJoinTokens returns an array containing all the Edge join tokens.
+func (service *Service) EdgeJoinTokens() ([]portainer.EdgeJoinToken, error) {
+	var tokens = make([]portainer.EdgeJoinToken, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var token portainer.EdgeJoinToken
+			err := internal.UnmarshalObject(v, &token)
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+		}
+
+		return nil
+	})
+
+	return tokens, err
+}
+
+// CreateEdgeJoinToken assigns an ID to a new Edge join token and saves it.
+func (service *Service) CreateEdgeJoinToken(token *portainer.EdgeJoinToken) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		token.ID = portainer.EdgeJoinTokenID(id)
+
+		data, err := internal.MarshalObject(token)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(token.ID)), data)
+	})
+}
+
+// UpdateEdgeJoinToken updates an Edge join token.
+func (service *Service) UpdateEdgeJoinToken(ID portainer.EdgeJoinTokenID, token *portainer.EdgeJoinToken) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, token)
+}
+
+// DeleteEdgeJoinToken deletes an Edge join token.
+func (service *Service) DeleteEdgeJoinToken(ID portainer.EdgeJoinTokenID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,132 @@
+package edgestack
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "edge_stacks"
+)
+
+// Service represents a service for managing Edge stack data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// EdgeStack returns an Edge stack by ID.
+func (service *Service) EdgeStack(ID portainer.EdgeStackID) (*portainer.EdgeStack, error) {
+	var edgeStack portainer.EdgeStack
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &edgeStack)
+	if err != nil {
+		return nil, err
+	}
+
+	return &edgeStack, nil
+}
+
+// EdgeStacks returns an array containing all the Edge stacks.
+func (service *Service) EdgeStacks() ([]portainer.EdgeStack, error) {
+	var edgeStacks = make([]portainer.EdgeStack, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var edgeStack portainer.EdgeStack
+			err := internal.UnmarshalObject(v, &edgeStack)
+			if err != nil {
+				return err
+			}
+			edgeStacks = append(edgeStacks, edgeStack)
+		}
+
+		return nil
+	})
+
+	return edgeStacks, err
+}
+
+// GetNextIdentifier returns the next identifier for an Edge stack.
+func (service *Service) GetNextIdentifier() int {
+	return internal.GetNextIdentifier(service.db, BucketName)
+}
+
+// CreateEdgeStack creates a new Edge stack.
+func (service *Service) CreateEdgeStack(edgeStack *portainer.EdgeStack) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		// We manually manage sequences for Edge stacks
+		err := bucket.SetSequence(uint64(edgeStack.ID))
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(edgeStack)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(edgeStack.ID)), data)
+	})
+}
+
+// UpdateEdgeStack updates an Edge stack.
+func (service *Service) UpdateEdgeStack(ID portainer.EdgeStackID, edgeStack *portainer.EdgeStack) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, edgeStack)
+}
+
+// UpdateEdgeStackFunc applies updateFunc to the stored Edge stack and saves the result inside a single
+// transaction, so that the deployment statuses reported concurrently by the agents are not lost.
+func (service *Service) UpdateEdgeStackFunc(ID portainer.EdgeStackID, updateFunc func(edgeStack *portainer.EdgeStack)) error {
+	identifier := internal.Itob(int(ID))
+
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		value := bucket.Get(identifier)
+		if value == nil {
+			return portainer.ErrObjectNotFound
+		}
+
+		var edgeStack portainer.EdgeStack
+		err := internal.UnmarshalObject(value, &edgeStack)
+		if err != nil {
+			return err
+		}
+
+		updateFunc(&edgeStack)
+
+		data, err := internal.MarshalObject(&edgeStack)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(identifier, data)
+	})
+}
+
+// DeleteEdgeStack deletes an Edge stack.
+func (service *Service) DeleteEdgeStack(ID portainer.EdgeStackID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/boltdb/bolt"
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
 )
 
 const (
@@ -13,18 +14,21 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
 }
 
 // NewService creates a new instance of a service.
-func NewService(db *bolt.DB) (*Service, error) {
+// The secrets of the objects are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Service{
-		db: db,
+		db:                db,
+		encryptionService: encryptionService,
 	}, nil
 }
 
@@ -38,13 +42,23 @@ func (service *Service) Endpoint(ID portainer.EndpointID) (*portainer.Endpoint,
 		return nil, err
 	}
 
+	err = service.decryptEndpoint(&endpoint)
+	if err != nil {
+		return nil, err
+	}
+
 	return &endpoint, nil
 }
 
 // UpdateEndpoint updates an endpoint.
 func (service *Service) UpdateEndpoint(ID portainer.EndpointID, endpoint *portainer.Endpoint) error {
+	encryptedEndpoint, err := service.encryptEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+
 	identifier := internal.Itob(int(ID))
-	return internal.UpdateObject(service.db, BucketName, identifier, endpoint)
+	return internal.UpdateObject(service.db, BucketName, identifier, encryptedEndpoint)
 }
 
 // DeleteEndpoint deletes an endpoint.
@@ -67,6 +81,12 @@ func (service *Service) Endpoints() ([]portainer.Endpoint, error) {
 			if err != nil {
 				return err
 			}
+
+			err = service.decryptEndpoint(&endpoint)
+			if err != nil {
+				return err
+			}
+
 			endpoints = append(endpoints, endpoint)
 		}
 
@@ -87,7 +107,12 @@ func (service *Service) CreateEndpoint(endpoint *portainer.Endpoint) error {
 			return err
 		}
 
-		data, err := internal.MarshalObject(endpoint)
+		encryptedEndpoint, err := service.encryptEndpoint(endpoint)
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(encryptedEndpoint)
 		if err != nil {
 			return err
 		}
@@ -110,7 +135,12 @@ func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.En
 			id, _ := bucket.NextSequence()
 			endpoint.ID = portainer.EndpointID(id)
 
-			data, err := internal.MarshalObject(endpoint)
+			encryptedEndpoint, err := service.encryptEndpoint(endpoint)
+			if err != nil {
+				return err
+			}
+
+			data, err := internal.MarshalObject(encryptedEndpoint)
 			if err != nil {
 				return err
 			}
@@ -122,7 +152,12 @@ func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.En
 		}
 
 		for _, endpoint := range toUpdate {
-			data, err := internal.MarshalObject(endpoint)
+			encryptedEndpoint, err := service.encryptEndpoint(endpoint)
+			if err != nil {
+				return err
+			}
+
+			data, err := internal.MarshalObject(encryptedEndpoint)
 			if err != nil {
 				return err
 			}
@@ -143,3 +178,22 @@ func (service *Service) Synchronize(toCreate, toUpdate, toDelete []*portainer.En
 		return nil
 	})
 }
+
+// encryptEndpoint returns a copy of the endpoint where the Azure authentication key is encrypted.
+func (service *Service) encryptEndpoint(endpoint *portainer.Endpoint) (*portainer.Endpoint, error) {
+	encryptedEndpoint := *endpoint
+
+	var err error
+	encryptedEndpoint.AzureCredentials.AuthenticationKey, err = crypto.EncryptSecret(service.encryptionService, endpoint.AzureCredentials.AuthenticationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedEndpoint, nil
+}
+
+func (service *Service) decryptEndpoint(endpoint *portainer.Endpoint) error {
+	var err error
+	endpoint.AzureCredentials.AuthenticationKey, err = crypto.DecryptSecret(service.encryptionService, endpoint.AzureCredentials.AuthenticationKey)
+	return err
+}
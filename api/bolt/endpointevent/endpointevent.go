@@ -0,0 +1,141 @@
+package endpointevent
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "endpoint_events"
+)
+
+// Service represents a service for managing the events detected between endpoint snapshots.
+// Events are keyed by endpoint identifier, time and event identifier so that the events of an
+// endpoint can be iterated in chronological order.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// EndpointEvents returns the events of an endpoint recorded between from and to (inclusive).
+func (service *Service) EndpointEvents(endpointID portainer.EndpointID, from, to int64) ([]portainer.EndpointEvent, error) {
+	var events = make([]portainer.EndpointEvent, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+		prefix := internal.Itob(int(endpointID))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(eventKey(endpointID, from, 0)); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			if eventTime(k) > to {
+				break
+			}
+
+			var event portainer.EndpointEvent
+			err := internal.UnmarshalObject(v, &event)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// CreateEndpointEvents assigns an identifier to each event and saves them.
+func (service *Service) CreateEndpointEvents(events []portainer.EndpointEvent) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		for idx := range events {
+			id, _ := bucket.NextSequence()
+			events[idx].ID = portainer.EndpointEventID(id)
+
+			data, err := internal.MarshalObject(&events[idx])
+			if err != nil {
+				return err
+			}
+
+			err = bucket.Put(eventKey(events[idx].EndpointID, events[idx].Time, events[idx].ID), data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteEndpointEvents deletes the events of an endpoint.
+func (service *Service) DeleteEndpointEvents(endpointID portainer.EndpointID) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+		prefix := internal.Itob(int(endpointID))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+			keys = append(keys, k)
+		}
+
+		return deleteKeys(bucket, keys)
+	})
+}
+
+// PruneEndpointEvents deletes the events of all the endpoints recorded before the specified time.
+func (service *Service) PruneEndpointEvents(before int64) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			if eventTime(k) < before {
+				keys = append(keys, k)
+			}
+		}
+
+		return deleteKeys(bucket, keys)
+	})
+}
+
+func deleteKeys(bucket *bolt.Bucket, keys [][]byte) error {
+	for _, k := range keys {
+		err := bucket.Delete(k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eventKey(endpointID portainer.EndpointID, timestamp int64, id portainer.EndpointEventID) []byte {
+	key := make([]byte, 24)
+	binary.BigEndian.PutUint64(key[:8], uint64(endpointID))
+	binary.BigEndian.PutUint64(key[8:16], uint64(timestamp))
+	binary.BigEndian.PutUint64(key[16:], uint64(id))
+	return key
+}
+
+func eventTime(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[8:16]))
+}
@@ -35,6 +35,7 @@ func (store *Store) Init() error {
 			Name:           "Endpoint administrator",
 			Description:    "Full control of all resources in an endpoint",
 			Priority:       1,
+			IsBuiltIn:      true,
 			Authorizations: portainer.DefaultEndpointAuthorizationsForEndpointAdministratorRole(),
 		}
 
@@ -47,6 +48,7 @@ func (store *Store) Init() error {
 			Name:           "Helpdesk",
 			Description:    "Read-only access of all resources in an endpoint",
 			Priority:       2,
+			IsBuiltIn:      true,
 			Authorizations: portainer.DefaultEndpointAuthorizationsForHelpDeskRole(false),
 		}
 
@@ -59,6 +61,7 @@ func (store *Store) Init() error {
 			Name:           "Standard user",
 			Description:    "Full control of assigned resources in an endpoint",
 			Priority:       3,
+			IsBuiltIn:      true,
 			Authorizations: portainer.DefaultEndpointAuthorizationsForStandardUserRole(false),
 		}
 
@@ -71,6 +74,7 @@ func (store *Store) Init() error {
 			Name:           "Read-only user",
 			Description:    "Read-only access of assigned resources in an endpoint",
 			Priority:       4,
+			IsBuiltIn:      true,
 			Authorizations: portainer.DefaultEndpointAuthorizationsForReadOnlyUserRole(false),
 		}
 
@@ -0,0 +1,75 @@
+package ldapsync
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "ldap_sync_reports"
+)
+
+// Service represents a service for managing LDAP synchronization report data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// LDAPSyncReports returns an array containing all the LDAP synchronization reports, ordered by creation.
+func (service *Service) LDAPSyncReports() ([]portainer.LDAPSyncReport, error) {
+	var reports = make([]portainer.LDAPSyncReport, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var report portainer.LDAPSyncReport
+			err := internal.UnmarshalObject(v, &report)
+			if err != nil {
+				return err
+			}
+			reports = append(reports, report)
+		}
+
+		return nil
+	})
+
+	return reports, err
+}
+
+// CreateLDAPSyncReport assigns an ID to a new LDAP synchronization report and saves it.
+func (service *Service) CreateLDAPSyncReport(report *portainer.LDAPSyncReport) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		report.ID = portainer.LDAPSyncReportID(id)
+
+		data, err := internal.MarshalObject(report)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(report.ID)), data)
+	})
+}
+
+// DeleteLDAPSyncReport deletes a LDAP synchronization report.
+func (service *Service) DeleteLDAPSyncReport(ID portainer.LDAPSyncReportID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
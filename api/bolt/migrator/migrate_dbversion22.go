@@ -0,0 +1,157 @@
+package migrator
+
+import (
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+func (m *Migrator) updateSettingsToDBVersion23() error {
+	legacySettings, err := m.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	legacySettings.StackVersionRetention = portainer.DefaultStackVersionRetention
+	legacySettings.UserSessionTimeout = portainer.DefaultUserSessionTimeout
+	legacySettings.SessionRecordingRetention = portainer.DefaultSessionRecordingRetention
+	legacySettings.AuditLogRetention = portainer.DefaultAuditLogRetention
+	legacySettings.AccountLockout.LockoutDuration = portainer.DefaultAccountLockoutDuration
+	legacySettings.SnapshotHistory = portainer.SnapshotHistorySettings{
+		Retention:            portainer.DefaultSnapshotHistoryRetention,
+		DownsamplingAge:      portainer.DefaultSnapshotHistoryDownsamplingAge,
+		DownsamplingInterval: portainer.DefaultSnapshotHistoryDownsamplingInterval,
+	}
+
+	return m.settingsService.UpdateSettings(legacySettings)
+}
+
+func (m *Migrator) updateUsersToDBVersion23() error {
+	legacyUsers, err := m.userService.Users()
+	if err != nil {
+		return err
+	}
+
+	settings, err := m.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	// The passwords of existing users are considered as changed during the migration so that
+	// enabling a maximum password age does not expire all of them at once.
+	// Users without a password were created by the external authentication method in use.
+	now := time.Now().Unix()
+	for _, user := range legacyUsers {
+		if user.Password != "" {
+			user.PasswordChangedAt = now
+			user.AuthenticationMethod = portainer.AuthenticationInternal
+		} else if settings.AuthenticationMethod == portainer.AuthenticationOAuth {
+			user.AuthenticationMethod = portainer.AuthenticationOAuth
+		} else {
+			user.AuthenticationMethod = portainer.AuthenticationLDAP
+		}
+
+		err = m.userService.UpdateUser(user.ID, &user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encryptSecretsToDBVersion23 rewrites the objects containing secrets so that the
+// secrets stored in plain text are encrypted by the services.
+func (m *Migrator) encryptSecretsToDBVersion23() error {
+	dockerhub, err := m.dockerHubService.DockerHub()
+	if err == nil {
+		err = m.dockerHubService.UpdateDockerHub(dockerhub)
+		if err != nil {
+			return err
+		}
+	} else if err != portainer.ErrObjectNotFound {
+		return err
+	}
+
+	settings, err := m.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	err = m.settingsService.UpdateSettings(settings)
+	if err != nil {
+		return err
+	}
+
+	registries, err := m.registryService.Registries()
+	if err != nil {
+		return err
+	}
+
+	for _, registry := range registries {
+		err = m.registryService.UpdateRegistry(registry.ID, &registry)
+		if err != nil {
+			return err
+		}
+	}
+
+	endpoints, err := m.endpointService.Endpoints()
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		err = m.endpointService.UpdateEndpoint(endpoint.ID, &endpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	stacks, err := m.stackService.Stacks()
+	if err != nil {
+		return err
+	}
+
+	for _, stack := range stacks {
+		err = m.stackService.UpdateStack(stack.ID, &stack)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateRolesToDBVersion23 flags the roles created by Portainer so that they cannot be
+// modified or removed now that custom roles can be managed. The authorizations of the users
+// are computed again as the access policies without a role now grant the standard user authorizations.
+func (m *Migrator) updateRolesToDBVersion23() error {
+	legacyRoles, err := m.roleService.Roles()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range legacyRoles {
+		if role.ID > portainer.RoleID(4) {
+			continue
+		}
+
+		role.IsBuiltIn = true
+		err = m.roleService.UpdateRole(role.ID, &role)
+		if err != nil {
+			return err
+		}
+	}
+
+	authorizationServiceParameters := &portainer.AuthorizationServiceParameters{
+		EndpointService:       m.endpointService,
+		EndpointGroupService:  m.endpointGroupService,
+		RegistryService:       m.registryService,
+		RoleService:           m.roleService,
+		TeamMembershipService: m.teamMembershipService,
+		UserService:           m.userService,
+	}
+
+	authorizationService := portainer.NewAuthorizationService(authorizationServiceParameters)
+	return authorizationService.UpdateUsersAuthorizations()
+}
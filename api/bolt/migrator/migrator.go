@@ -3,6 +3,7 @@ package migrator
 import (
 	"github.com/boltdb/bolt"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/dockerhub"
 	"github.com/portainer/portainer/api/bolt/endpoint"
 	"github.com/portainer/portainer/api/bolt/endpointgroup"
 	"github.com/portainer/portainer/api/bolt/extension"
@@ -23,6 +24,7 @@ type (
 	Migrator struct {
 		currentDBVersion       int
 		db                     *bolt.DB
+		dockerHubService       *dockerhub.Service
 		endpointGroupService   *endpointgroup.Service
 		endpointService        *endpoint.Service
 		extensionService       *extension.Service
@@ -43,6 +45,7 @@ type (
 	Parameters struct {
 		DB                     *bolt.DB
 		DatabaseVersion        int
+		DockerHubService       *dockerhub.Service
 		EndpointGroupService   *endpointgroup.Service
 		EndpointService        *endpoint.Service
 		ExtensionService       *extension.Service
@@ -65,6 +68,7 @@ func NewMigrator(parameters *Parameters) *Migrator {
 	return &Migrator{
 		db:                     parameters.DB,
 		currentDBVersion:       parameters.DatabaseVersion,
+		dockerHubService:       parameters.DockerHubService,
 		endpointGroupService:   parameters.EndpointGroupService,
 		endpointService:        parameters.EndpointService,
 		extensionService:       parameters.ExtensionService,
@@ -301,5 +305,28 @@ func (m *Migrator) Migrate() error {
 		}
 	}
 
+	// Portainer 1.24.0
+	if m.currentDBVersion < 23 {
+		err := m.updateSettingsToDBVersion23()
+		if err != nil {
+			return err
+		}
+
+		err = m.updateUsersToDBVersion23()
+		if err != nil {
+			return err
+		}
+
+		err = m.encryptSecretsToDBVersion23()
+		if err != nil {
+			return err
+		}
+
+		err = m.updateRolesToDBVersion23()
+		if err != nil {
+			return err
+		}
+	}
+
 	return m.versionService.StoreDBVersion(portainer.DBVersion)
 }
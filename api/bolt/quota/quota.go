@@ -0,0 +1,94 @@
+package quota
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "quotas"
+)
+
+// Service represents a service for managing quota data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// Quota returns a quota by ID.
+func (service *Service) Quota(ID portainer.QuotaID) (*portainer.Quota, error) {
+	var quota portainer.Quota
+	identifier := internal.Itob(int(ID))
+
+	err := internal.GetObject(service.db, BucketName, identifier, &quota)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quota, nil
+}
+
+// Quotas returns an array containing all the quotas.
+func (service *Service) Quotas() ([]portainer.Quota, error) {
+	var quotas = make([]portainer.Quota, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var quota portainer.Quota
+			err := internal.UnmarshalObject(v, &quota)
+			if err != nil {
+				return err
+			}
+			quotas = append(quotas, quota)
+		}
+
+		return nil
+	})
+
+	return quotas, err
+}
+
+// CreateQuota assigns an ID to a new quota and saves it.
+func (service *Service) CreateQuota(quota *portainer.Quota) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		id, _ := bucket.NextSequence()
+		quota.ID = portainer.QuotaID(id)
+
+		data, err := internal.MarshalObject(quota)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(internal.Itob(int(quota.ID)), data)
+	})
+}
+
+// UpdateQuota updates a quota.
+func (service *Service) UpdateQuota(ID portainer.QuotaID, quota *portainer.Quota) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, quota)
+}
+
+// DeleteQuota deletes a quota.
+func (service *Service) DeleteQuota(ID portainer.QuotaID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
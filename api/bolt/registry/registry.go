@@ -3,6 +3,7 @@ package registry
 import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
 
 	"github.com/boltdb/bolt"
 )
@@ -14,18 +15,21 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
 }
 
 // NewService creates a new instance of a service.
-func NewService(db *bolt.DB) (*Service, error) {
+// The secrets of the objects are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Service{
-		db: db,
+		db:                db,
+		encryptionService: encryptionService,
 	}, nil
 }
 
@@ -39,6 +43,11 @@ func (service *Service) Registry(ID portainer.RegistryID) (*portainer.Registry,
 		return nil, err
 	}
 
+	err = service.decryptRegistry(&registry)
+	if err != nil {
+		return nil, err
+	}
+
 	return &registry, nil
 }
 
@@ -56,6 +65,12 @@ func (service *Service) Registries() ([]portainer.Registry, error) {
 			if err != nil {
 				return err
 			}
+
+			err = service.decryptRegistry(&registry)
+			if err != nil {
+				return err
+			}
+
 			registries = append(registries, registry)
 		}
 
@@ -73,7 +88,12 @@ func (service *Service) CreateRegistry(registry *portainer.Registry) error {
 		id, _ := bucket.NextSequence()
 		registry.ID = portainer.RegistryID(id)
 
-		data, err := internal.MarshalObject(registry)
+		encryptedRegistry, err := service.encryptRegistry(registry)
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(encryptedRegistry)
 		if err != nil {
 			return err
 		}
@@ -84,8 +104,13 @@ func (service *Service) CreateRegistry(registry *portainer.Registry) error {
 
 // UpdateRegistry updates an registry.
 func (service *Service) UpdateRegistry(ID portainer.RegistryID, registry *portainer.Registry) error {
+	encryptedRegistry, err := service.encryptRegistry(registry)
+	if err != nil {
+		return err
+	}
+
 	identifier := internal.Itob(int(ID))
-	return internal.UpdateObject(service.db, BucketName, identifier, registry)
+	return internal.UpdateObject(service.db, BucketName, identifier, encryptedRegistry)
 }
 
 // DeleteRegistry deletes an registry.
@@ -93,3 +118,42 @@ func (service *Service) DeleteRegistry(ID portainer.RegistryID) error {
 	identifier := internal.Itob(int(ID))
 	return internal.DeleteObject(service.db, BucketName, identifier)
 }
+
+// encryptRegistry returns a copy of the registry where the passwords are encrypted.
+func (service *Service) encryptRegistry(registry *portainer.Registry) (*portainer.Registry, error) {
+	encryptedRegistry := *registry
+
+	var err error
+	encryptedRegistry.Password, err = crypto.EncryptSecret(service.encryptionService, registry.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	if registry.ManagementConfiguration != nil {
+		configuration := *registry.ManagementConfiguration
+		configuration.Password, err = crypto.EncryptSecret(service.encryptionService, configuration.Password)
+		if err != nil {
+			return nil, err
+		}
+		encryptedRegistry.ManagementConfiguration = &configuration
+	}
+
+	return &encryptedRegistry, nil
+}
+
+func (service *Service) decryptRegistry(registry *portainer.Registry) error {
+	var err error
+	registry.Password, err = crypto.DecryptSecret(service.encryptionService, registry.Password)
+	if err != nil {
+		return err
+	}
+
+	if registry.ManagementConfiguration != nil {
+		registry.ManagementConfiguration.Password, err = crypto.DecryptSecret(service.encryptionService, registry.ManagementConfiguration.Password)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -87,3 +87,9 @@ func (service *Service) UpdateRole(ID portainer.RoleID, role *portainer.Role) er
 	identifier := internal.Itob(int(ID))
 	return internal.UpdateObject(service.db, BucketName, identifier, role)
 }
+
+// DeleteRole deletes a role.
+func (service *Service) DeleteRole(ID portainer.RoleID) error {
+	identifier := internal.Itob(int(ID))
+	return internal.DeleteObject(service.db, BucketName, identifier)
+}
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores the
+	// signing key and the user sessions revocation dates.
+	BucketName = "sessions"
+	// RevokedTokensBucketName represents the name of the bucket where this service stores revoked tokens.
+	RevokedTokensBucketName = "revoked_tokens"
+	signingKeyKey           = "SIGNING_KEY"
+	userRevocationKeyPrefix = "USER_REVOCATION_"
+)
+
+type signingKey struct {
+	Key []byte `json:"Key"`
+}
+
+type userSessionsRevocation struct {
+	Timestamp int64 `json:"Timestamp"`
+}
+
+// Service represents a service for managing session data.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = internal.CreateBucket(db, RevokedTokensBucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// SigningKey retrieves the key used to sign JWT tokens.
+func (service *Service) SigningKey() ([]byte, error) {
+	var key signingKey
+
+	err := internal.GetObject(service.db, BucketName, []byte(signingKeyKey), &key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Key, nil
+}
+
+// UpdateSigningKey persists the key used to sign JWT tokens.
+func (service *Service) UpdateSigningKey(key []byte) error {
+	return internal.UpdateObject(service.db, BucketName, []byte(signingKeyKey), &signingKey{Key: key})
+}
+
+// RevokedToken returns a revoked token by ID.
+func (service *Service) RevokedToken(ID string) (*portainer.RevokedToken, error) {
+	var token portainer.RevokedToken
+
+	err := internal.GetObject(service.db, RevokedTokensBucketName, []byte(ID), &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateRevokedToken saves a revoked token.
+func (service *Service) CreateRevokedToken(token *portainer.RevokedToken) error {
+	return internal.UpdateObject(service.db, RevokedTokensBucketName, []byte(token.ID), token)
+}
+
+// DeleteExpiredRevokedTokens removes the revoked tokens that expired before the specified timestamp.
+func (service *Service) DeleteExpiredRevokedTokens(timestamp int64) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(RevokedTokensBucketName))
+
+		expiredKeys := make([][]byte, 0)
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			var token portainer.RevokedToken
+			err := internal.UnmarshalObject(v, &token)
+			if err != nil {
+				return err
+			}
+
+			if token.ExpiresAt < timestamp {
+				key := make([]byte, len(k))
+				copy(key, k)
+				expiredKeys = append(expiredKeys, key)
+			}
+		}
+
+		for _, key := range expiredKeys {
+			err := bucket.Delete(key)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// UserSessionsRevocationDate returns the date, in nanoseconds, of the last revocation of all the sessions of a user.
+// It returns 0 if the sessions of the user were never revoked.
+func (service *Service) UserSessionsRevocationDate(userID portainer.UserID) (int64, error) {
+	var revocation userSessionsRevocation
+
+	err := internal.GetObject(service.db, BucketName, userRevocationKey(userID), &revocation)
+	if err == portainer.ErrObjectNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return revocation.Timestamp, nil
+}
+
+// RevokeUserSessions revokes all the sessions of a user created before the specified timestamp, in nanoseconds.
+func (service *Service) RevokeUserSessions(userID portainer.UserID, timestamp int64) error {
+	return internal.UpdateObject(service.db, BucketName, userRevocationKey(userID), &userSessionsRevocation{Timestamp: timestamp})
+}
+
+func userRevocationKey(userID portainer.UserID) []byte {
+	return []byte(userRevocationKeyPrefix + strconv.Itoa(int(userID)))
+}
@@ -3,6 +3,7 @@ package settings
 import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
 
 	"github.com/boltdb/bolt"
 )
@@ -15,18 +16,21 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
 }
 
 // NewService creates a new instance of a service.
-func NewService(db *bolt.DB) (*Service, error) {
+// The secrets of the objects are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Service{
-		db: db,
+		db:                db,
+		encryptionService: encryptionService,
 	}, nil
 }
 
@@ -39,10 +43,33 @@ func (service *Service) Settings() (*portainer.Settings, error) {
 		return nil, err
 	}
 
+	settings.LDAPSettings.Password, err = crypto.DecryptSecret(service.encryptionService, settings.LDAPSettings.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.OAuthSettings.ClientSecret, err = crypto.DecryptSecret(service.encryptionService, settings.OAuthSettings.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	return &settings, nil
 }
 
 // UpdateSettings persists a Settings object.
 func (service *Service) UpdateSettings(settings *portainer.Settings) error {
-	return internal.UpdateObject(service.db, BucketName, []byte(settingsKey), settings)
+	encryptedSettings := *settings
+
+	var err error
+	encryptedSettings.LDAPSettings.Password, err = crypto.EncryptSecret(service.encryptionService, settings.LDAPSettings.Password)
+	if err != nil {
+		return err
+	}
+
+	encryptedSettings.OAuthSettings.ClientSecret, err = crypto.EncryptSecret(service.encryptionService, settings.OAuthSettings.ClientSecret)
+	if err != nil {
+		return err
+	}
+
+	return internal.UpdateObject(service.db, BucketName, []byte(settingsKey), &encryptedSettings)
 }
@@ -0,0 +1,224 @@
+package snapshothistory
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/bolt/internal"
+)
+
+const (
+	// BucketName represents the name of the bucket where this service stores data.
+	BucketName = "snapshot_history"
+)
+
+// Service represents a service for managing the snapshot history of endpoints.
+// Records are keyed by endpoint identifier, time and a sequence number so that the history of an endpoint
+// can be iterated in chronological order and that records created during the same second are all kept.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a new instance of a service.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := internal.CreateBucket(db, BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		db: db,
+	}, nil
+}
+
+// SnapshotRecords returns the snapshot records of an endpoint created between from and to (inclusive).
+func (service *Service) SnapshotRecords(endpointID portainer.EndpointID, from, to int64) ([]portainer.SnapshotRecord, error) {
+	var records = make([]portainer.SnapshotRecord, 0)
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+		prefix := internal.Itob(int(endpointID))
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(recordKey(endpointID, from)); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			if recordTime(k) > to {
+				break
+			}
+
+			var record portainer.SnapshotRecord
+			err := internal.UnmarshalObject(v, &record)
+			if err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+
+		return nil
+	})
+
+	return records, err
+}
+
+// CreateSnapshotRecord saves a snapshot record.
+func (service *Service) CreateSnapshotRecord(record *portainer.SnapshotRecord) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		data, err := internal.MarshalObject(record)
+		if err != nil {
+			return err
+		}
+
+		key, err := uniqueRecordKey(bucket, record.EndpointID, record.Time)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key, data)
+	})
+}
+
+// DeleteSnapshotRecords deletes the snapshot history of an endpoint.
+func (service *Service) DeleteSnapshotRecords(endpointID portainer.EndpointID) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+		prefix := internal.Itob(int(endpointID))
+
+		keys := make([][]byte, 0)
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+			keys = append(keys, k)
+		}
+
+		for _, k := range keys {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CompactSnapshotRecords removes the records created before retentionBefore and merges the records
+// created before downsamplingBefore into a single record per downsamplingInterval (in seconds).
+// A zero value disables the associated operation.
+func (service *Service) CompactSnapshotRecords(downsamplingBefore, downsamplingInterval, retentionBefore int64) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		expiredKeys := make([][]byte, 0)
+		downsampledKeys := make([][]byte, 0)
+		downsampledRecords := make(map[string]*portainer.SnapshotRecord)
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			timestamp := recordTime(k)
+
+			if retentionBefore > 0 && timestamp < retentionBefore {
+				expiredKeys = append(expiredKeys, k)
+				continue
+			}
+
+			if downsamplingBefore == 0 || downsamplingInterval <= 0 || timestamp >= downsamplingBefore {
+				continue
+			}
+
+			var record portainer.SnapshotRecord
+			err := internal.UnmarshalObject(v, &record)
+			if err != nil {
+				return err
+			}
+
+			bucketTime := timestamp - timestamp%downsamplingInterval
+			bucketKey := string(recordKey(record.EndpointID, bucketTime))
+
+			downsampled, ok := downsampledRecords[bucketKey]
+			if !ok {
+				downsampled = &portainer.SnapshotRecord{EndpointID: record.EndpointID, Time: bucketTime}
+				downsampledRecords[bucketKey] = downsampled
+			}
+			mergeSnapshotRecord(downsampled, &record)
+
+			downsampledKeys = append(downsampledKeys, k)
+		}
+
+		for _, k := range append(expiredKeys, downsampledKeys...) {
+			err := bucket.Delete(k)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, record := range downsampledRecords {
+			data, err := internal.MarshalObject(record)
+			if err != nil {
+				return err
+			}
+
+			key, err := uniqueRecordKey(bucket, record.EndpointID, record.Time)
+			if err != nil {
+				return err
+			}
+
+			err = bucket.Put(key, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// mergeSnapshotRecord merges a record inside a downsampled record. The counters of the downsampled
+// record are the average of the merged records, weighted by their number of samples.
+func mergeSnapshotRecord(downsampled, record *portainer.SnapshotRecord) {
+	samples := record.Samples
+	if samples == 0 {
+		samples = 1
+	}
+
+	total := downsampled.Samples + samples
+	average := func(current, value int64) int64 {
+		return (current*int64(downsampled.Samples) + value*int64(samples)) / int64(total)
+	}
+
+	downsampled.TotalCPU = int(average(int64(downsampled.TotalCPU), int64(record.TotalCPU)))
+	downsampled.TotalMemory = average(downsampled.TotalMemory, record.TotalMemory)
+	downsampled.RunningContainerCount = int(average(int64(downsampled.RunningContainerCount), int64(record.RunningContainerCount)))
+	downsampled.StoppedContainerCount = int(average(int64(downsampled.StoppedContainerCount), int64(record.StoppedContainerCount)))
+	downsampled.VolumeCount = int(average(int64(downsampled.VolumeCount), int64(record.VolumeCount)))
+	downsampled.ImageCount = int(average(int64(downsampled.ImageCount), int64(record.ImageCount)))
+	downsampled.ServiceCount = int(average(int64(downsampled.ServiceCount), int64(record.ServiceCount)))
+	downsampled.StackCount = int(average(int64(downsampled.StackCount), int64(record.StackCount)))
+	downsampled.Samples = total
+}
+
+func recordKey(endpointID portainer.EndpointID, timestamp int64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(endpointID))
+	binary.BigEndian.PutUint64(key[8:], uint64(timestamp))
+	return key
+}
+
+// uniqueRecordKey returns the key of a new record. The record key is suffixed with the next sequence
+// of the bucket so that it does not replace a record created at the same time.
+func uniqueRecordKey(bucket *bolt.Bucket, endpointID portainer.EndpointID, timestamp int64) ([]byte, error) {
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 24)
+	copy(key, recordKey(endpointID, timestamp))
+	binary.BigEndian.PutUint64(key[16:], sequence)
+	return key, nil
+}
+
+func recordTime(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key[8:]))
+}
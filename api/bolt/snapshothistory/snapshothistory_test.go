@@ -0,0 +1,164 @@
+package snapshothistory
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/portainer/portainer/api"
+)
+
+func newTestService(t *testing.T) (*Service, func()) {
+	dir, err := ioutil.TempDir("", "snapshothistory-")
+	if err != nil {
+		t.Fatalf("unable to create directory: %s", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dir, "portainer.db"), 0600, nil)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to open database: %s", err)
+	}
+
+	service, err := NewService(db)
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		t.Fatalf("unable to create service: %s", err)
+	}
+
+	return service, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func createTestRecords(t *testing.T, service *Service, records []portainer.SnapshotRecord) {
+	for _, record := range records {
+		err := service.CreateSnapshotRecord(&record)
+		if err != nil {
+			t.Fatalf("unable to create record: %s", err)
+		}
+	}
+}
+
+func TestCreateSnapshotRecordSameTime(t *testing.T) {
+	service, cleanup := newTestService(t)
+	defer cleanup()
+
+	createTestRecords(t, service, []portainer.SnapshotRecord{
+		{EndpointID: 1, Time: 100, RunningContainerCount: 1},
+		{EndpointID: 1, Time: 100, RunningContainerCount: 2},
+		{EndpointID: 2, Time: 100, RunningContainerCount: 3},
+	})
+
+	records, err := service.SnapshotRecords(1, 0, 1000)
+	if err != nil {
+		t.Fatalf("unable to retrieve records: %s", err)
+	}
+
+	if len(records) != 2 || records[0].RunningContainerCount != 1 || records[1].RunningContainerCount != 2 {
+		t.Errorf("expected both records created at the same time to be kept, got %+v", records)
+	}
+}
+
+func TestCompactSnapshotRecords(t *testing.T) {
+	service, cleanup := newTestService(t)
+	defer cleanup()
+
+	createTestRecords(t, service, []portainer.SnapshotRecord{
+		// expired
+		{EndpointID: 1, Time: 50, RunningContainerCount: 100},
+		// downsampled in the [100, 200) interval
+		{EndpointID: 1, Time: 110, RunningContainerCount: 2, TotalMemory: 10},
+		{EndpointID: 1, Time: 110, RunningContainerCount: 4, TotalMemory: 20},
+		{EndpointID: 1, Time: 150, RunningContainerCount: 6, TotalMemory: 30},
+		// downsampled in the [200, 300) interval
+		{EndpointID: 1, Time: 250, RunningContainerCount: 8},
+		// left untouched
+		{EndpointID: 1, Time: 300, RunningContainerCount: 10},
+		{EndpointID: 1, Time: 300, RunningContainerCount: 12},
+		{EndpointID: 2, Time: 120, RunningContainerCount: 20},
+	})
+
+	err := service.CompactSnapshotRecords(300, 100, 100)
+	if err != nil {
+		t.Fatalf("unable to compact records: %s", err)
+	}
+
+	records, err := service.SnapshotRecords(1, 0, 1000)
+	if err != nil {
+		t.Fatalf("unable to retrieve records: %s", err)
+	}
+
+	expected := []portainer.SnapshotRecord{
+		{EndpointID: 1, Time: 100, RunningContainerCount: 4, TotalMemory: 20, Samples: 3},
+		{EndpointID: 1, Time: 200, RunningContainerCount: 8, Samples: 1},
+		{EndpointID: 1, Time: 300, RunningContainerCount: 10},
+		{EndpointID: 1, Time: 300, RunningContainerCount: 12},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %+v", len(expected), records)
+	}
+
+	for idx := range expected {
+		if records[idx] != expected[idx] {
+			t.Errorf("expected record %+v, got %+v", expected[idx], records[idx])
+		}
+	}
+
+	records, err = service.SnapshotRecords(2, 0, 1000)
+	if err != nil {
+		t.Fatalf("unable to retrieve records: %s", err)
+	}
+
+	if len(records) != 1 || records[0].Time != 100 || records[0].RunningContainerCount != 20 || records[0].Samples != 1 {
+		t.Errorf("expected the records of each endpoint to be downsampled separately, got %+v", records)
+	}
+
+	// compacting again must merge the downsampled records with their number of samples
+	createTestRecords(t, service, []portainer.SnapshotRecord{
+		{EndpointID: 1, Time: 190, RunningContainerCount: 8, TotalMemory: 40},
+	})
+
+	err = service.CompactSnapshotRecords(300, 100, 100)
+	if err != nil {
+		t.Fatalf("unable to compact records: %s", err)
+	}
+
+	records, err = service.SnapshotRecords(1, 100, 100)
+	if err != nil {
+		t.Fatalf("unable to retrieve records: %s", err)
+	}
+
+	if len(records) != 1 || records[0].RunningContainerCount != 5 || records[0].TotalMemory != 25 || records[0].Samples != 4 {
+		t.Errorf("unexpected downsampled record: %+v", records)
+	}
+}
+
+func TestCompactSnapshotRecordsDisabled(t *testing.T) {
+	service, cleanup := newTestService(t)
+	defer cleanup()
+
+	createTestRecords(t, service, []portainer.SnapshotRecord{
+		{EndpointID: 1, Time: 10},
+		{EndpointID: 1, Time: 20},
+	})
+
+	err := service.CompactSnapshotRecords(0, 0, 0)
+	if err != nil {
+		t.Fatalf("unable to compact records: %s", err)
+	}
+
+	records, err := service.SnapshotRecords(1, 0, 1000)
+	if err != nil {
+		t.Fatalf("unable to retrieve records: %s", err)
+	}
+
+	if len(records) != 2 || records[0].Samples != 0 || records[1].Samples != 0 {
+		t.Errorf("expected the records to be left untouched, got %+v", records)
+	}
+}
@@ -3,6 +3,7 @@ package stack
 import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/bolt/internal"
+	"github.com/portainer/portainer/api/crypto"
 
 	"github.com/boltdb/bolt"
 )
@@ -14,18 +15,21 @@ const (
 
 // Service represents a service for managing endpoint data.
 type Service struct {
-	db *bolt.DB
+	db                *bolt.DB
+	encryptionService portainer.EncryptionService
 }
 
 // NewService creates a new instance of a service.
-func NewService(db *bolt.DB) (*Service, error) {
+// The secrets of the objects are encrypted with the encryption service before being stored.
+func NewService(db *bolt.DB, encryptionService portainer.EncryptionService) (*Service, error) {
 	err := internal.CreateBucket(db, BucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Service{
-		db: db,
+		db:                db,
+		encryptionService: encryptionService,
 	}, nil
 }
 
@@ -39,6 +43,11 @@ func (service *Service) Stack(ID portainer.StackID) (*portainer.Stack, error) {
 		return nil, err
 	}
 
+	err = service.decryptStack(&stack)
+	if err != nil {
+		return nil, err
+	}
+
 	return &stack, nil
 }
 
@@ -67,7 +76,7 @@ func (service *Service) StackByName(name string) (*portainer.Stack, error) {
 			return portainer.ErrObjectNotFound
 		}
 
-		return nil
+		return service.decryptStack(stack)
 	})
 
 	return stack, err
@@ -87,6 +96,12 @@ func (service *Service) Stacks() ([]portainer.Stack, error) {
 			if err != nil {
 				return err
 			}
+
+			err = service.decryptStack(&stack)
+			if err != nil {
+				return err
+			}
+
 			stacks = append(stacks, stack)
 		}
 
@@ -112,7 +127,12 @@ func (service *Service) CreateStack(stack *portainer.Stack) error {
 			return err
 		}
 
-		data, err := internal.MarshalObject(stack)
+		encryptedStack, err := service.encryptStack(stack)
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(encryptedStack)
 		if err != nil {
 			return err
 		}
@@ -123,8 +143,53 @@ func (service *Service) CreateStack(stack *portainer.Stack) error {
 
 // UpdateStack updates a stack.
 func (service *Service) UpdateStack(ID portainer.StackID, stack *portainer.Stack) error {
+	encryptedStack, err := service.encryptStack(stack)
+	if err != nil {
+		return err
+	}
+
 	identifier := internal.Itob(int(ID))
-	return internal.UpdateObject(service.db, BucketName, identifier, stack)
+	return internal.UpdateObject(service.db, BucketName, identifier, encryptedStack)
+}
+
+// UpdateStackFunc applies updateFunc to the stored stack and saves the result inside a single
+// transaction, so that the changes made to the stack by concurrent operations are not lost.
+func (service *Service) UpdateStackFunc(ID portainer.StackID, updateFunc func(stack *portainer.Stack)) error {
+	identifier := internal.Itob(int(ID))
+
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		value := bucket.Get(identifier)
+		if value == nil {
+			return portainer.ErrObjectNotFound
+		}
+
+		var stack portainer.Stack
+		err := internal.UnmarshalObject(value, &stack)
+		if err != nil {
+			return err
+		}
+
+		err = service.decryptStack(&stack)
+		if err != nil {
+			return err
+		}
+
+		updateFunc(&stack)
+
+		encryptedStack, err := service.encryptStack(&stack)
+		if err != nil {
+			return err
+		}
+
+		data, err := internal.MarshalObject(encryptedStack)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(identifier, data)
+	})
 }
 
 // DeleteStack deletes a stack.
@@ -132,3 +197,53 @@ func (service *Service) DeleteStack(ID portainer.StackID) error {
 	identifier := internal.Itob(int(ID))
 	return internal.DeleteObject(service.db, BucketName, identifier)
 }
+
+// encryptStack returns a copy of the stack where the values of the environment variables
+// and the Git repository password are encrypted.
+func (service *Service) encryptStack(stack *portainer.Stack) (*portainer.Stack, error) {
+	encryptedStack := *stack
+
+	if stack.Env != nil {
+		encryptedStack.Env = make([]portainer.Pair, len(stack.Env))
+		for idx, pair := range stack.Env {
+			value, err := crypto.EncryptSecret(service.encryptionService, pair.Value)
+			if err != nil {
+				return nil, err
+			}
+			encryptedStack.Env[idx] = portainer.Pair{Name: pair.Name, Value: value}
+		}
+	}
+
+	if stack.GitConfig != nil {
+		gitConfig := *stack.GitConfig
+
+		var err error
+		gitConfig.Password, err = crypto.EncryptSecret(service.encryptionService, gitConfig.Password)
+		if err != nil {
+			return nil, err
+		}
+		encryptedStack.GitConfig = &gitConfig
+	}
+
+	return &encryptedStack, nil
+}
+
+func (service *Service) decryptStack(stack *portainer.Stack) error {
+	for idx := range stack.Env {
+		value, err := crypto.DecryptSecret(service.encryptionService, stack.Env[idx].Value)
+		if err != nil {
+			return err
+		}
+		stack.Env[idx].Value = value
+	}
+
+	if stack.GitConfig != nil {
+		var err error
+		stack.GitConfig.Password, err = crypto.DecryptSecret(service.encryptionService, stack.GitConfig.Password)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -125,6 +125,36 @@ func (service *Service) UpdateUser(ID portainer.UserID, user *portainer.User) er
 	return internal.UpdateObject(service.db, BucketName, identifier, user)
 }
 
+// UpdateUserFunc applies updateFunc to the stored user and saves the result inside a single
+// transaction, so that concurrent updates of the same fields are not lost.
+func (service *Service) UpdateUserFunc(ID portainer.UserID, updateFunc func(user *portainer.User)) error {
+	identifier := internal.Itob(int(ID))
+
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		value := bucket.Get(identifier)
+		if value == nil {
+			return portainer.ErrObjectNotFound
+		}
+
+		var user portainer.User
+		err := internal.UnmarshalObject(value, &user)
+		if err != nil {
+			return err
+		}
+
+		updateFunc(&user)
+
+		data, err := internal.MarshalObject(&user)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(identifier, data)
+	})
+}
+
 // CreateUser creates a new user.
 func (service *Service) CreateUser(user *portainer.User) error {
 	return service.db.Update(func(tx *bolt.Tx) error {
@@ -127,6 +127,12 @@ func (service *Service) WebhookByToken(token string) (*portainer.Webhook, error)
 	return webhook, err
 }
 
+// UpdateWebhook updates a webhook.
+func (service *Service) UpdateWebhook(ID portainer.WebhookID, webhook *portainer.Webhook) error {
+	identifier := internal.Itob(int(ID))
+	return internal.UpdateObject(service.db, BucketName, identifier, webhook)
+}
+
 // DeleteWebhook deletes a webhook.
 func (service *Service) DeleteWebhook(ID portainer.WebhookID) error {
 	identifier := internal.Itob(int(ID))
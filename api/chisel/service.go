@@ -18,8 +18,19 @@ const (
 	tunnelCleanupInterval = 10 * time.Second
 	requiredTimeout       = 15 * time.Second
 	activeTimeout         = 4*time.Minute + 30*time.Second
+	// missedCheckinCount is the number of consecutive check-ins an Edge agent can miss before an alert is sent
+	missedCheckinCount = 3
+	// minimumCheckinTimeout is the minimum duration without check-in before an alert is sent
+	minimumCheckinTimeout = 1 * time.Minute
 )
 
+// edgeCheckin holds the date of the last check-in of an Edge agent
+type edgeCheckin struct {
+	date     time.Time
+	interval time.Duration
+	missed   bool
+}
+
 // Service represents a service to manage the state of multiple reverse tunnels.
 // It is used to start a reverse tunnel server and to manage the connection status of each tunnel
 // connected to the tunnel server.
@@ -27,18 +38,23 @@ type Service struct {
 	serverFingerprint   string
 	serverPort          string
 	tunnelDetailsMap    cmap.ConcurrentMap
+	checkinMap          cmap.ConcurrentMap
 	endpointService     portainer.EndpointService
 	tunnelServerService portainer.TunnelServerService
 	snapshotter         portainer.Snapshotter
+	alerter             portainer.Alerter
 	chiselServer        *chserver.Server
 }
 
-// NewService returns a pointer to a new instance of Service
-func NewService(endpointService portainer.EndpointService, tunnelServerService portainer.TunnelServerService) *Service {
+// NewService returns a pointer to a new instance of Service.
+// The alerter is used to notify missed Edge agents check-ins.
+func NewService(endpointService portainer.EndpointService, tunnelServerService portainer.TunnelServerService, alerter portainer.Alerter) *Service {
 	return &Service{
 		tunnelDetailsMap:    cmap.New(),
+		checkinMap:          cmap.New(),
 		endpointService:     endpointService,
 		tunnelServerService: tunnelServerService,
+		alerter:             alerter,
 	}
 }
 
@@ -117,6 +133,7 @@ func (service *Service) startTunnelVerificationLoop() {
 		select {
 		case <-ticker.C:
 			service.checkTunnels()
+			service.checkCheckins()
 		case <-stopSignal:
 			ticker.Stop()
 			return
@@ -172,6 +189,59 @@ func (service *Service) checkTunnels() {
 	}
 }
 
+// checkCheckins sends an alert for each Edge agent that missed several consecutive check-ins.
+// A single alert is sent until the agent checks in again.
+func (service *Service) checkCheckins() {
+	for item := range service.checkinMap.IterBuffered() {
+		checkin := item.Val.(*edgeCheckin)
+		if checkin.missed {
+			continue
+		}
+
+		timeout := checkin.interval * missedCheckinCount
+		if timeout < minimumCheckinTimeout {
+			timeout = minimumCheckinTimeout
+		}
+
+		elapsed := time.Since(checkin.date)
+		if elapsed < timeout {
+			continue
+		}
+
+		log.Printf("[DEBUG] [chisel,monitoring] [endpoint_id: %s] [checkin_time_seconds: %f] [timeout_seconds: %f] [message: Edge agent check-in missed]", item.Key, elapsed.Seconds(), timeout.Seconds())
+		checkin.missed = true
+		service.notifyMissedCheckin(item.Key, elapsed)
+	}
+}
+
+func (service *Service) notifyMissedCheckin(endpointKey string, elapsed time.Duration) {
+	if service.alerter == nil {
+		return
+	}
+
+	endpointID, err := strconv.Atoi(endpointKey)
+	if err != nil {
+		log.Printf("[ERROR] [chisel,alert,conversion] Invalid endpoint identifier (id: %s): %s", endpointKey, err)
+		return
+	}
+
+	endpoint, err := service.endpointService.Endpoint(portainer.EndpointID(endpointID))
+	if err == portainer.ErrObjectNotFound {
+		service.checkinMap.Remove(endpointKey)
+		return
+	} else if err != nil {
+		log.Printf("[ERROR] [chisel,alert] Unable to retrieve Edge endpoint (id: %s): %s", endpointKey, err)
+		return
+	}
+
+	service.alerter.Notify(&portainer.AlertEvent{
+		Type:         portainer.EdgeMissedCheckinAlertEvent,
+		EndpointID:   endpoint.ID,
+		EndpointName: endpoint.Name,
+		Message:      fmt.Sprintf("Edge agent did not check in for %d seconds", int(elapsed.Seconds())),
+	})
+}
+
 func (service *Service) snapshotEnvironment(endpointID portainer.EndpointID, tunnelPort int) error {
 	endpoint, err := service.endpointService.Endpoint(portainer.EndpointID(endpointID))
 	if err != nil {
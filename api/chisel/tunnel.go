@@ -56,6 +56,16 @@ func (service *Service) GetTunnelDetails(endpointID portainer.EndpointID) *porta
 	}
 }
 
+// SetLastCheckin records a check-in of the Edge agent associated to the specified endpoint.
+// The check-in interval of the agent, in seconds, is used to detect missed check-ins.
+func (service *Service) SetLastCheckin(endpointID portainer.EndpointID, checkinInterval int) {
+	key := strconv.Itoa(int(endpointID))
+	service.checkinMap.Set(key, &edgeCheckin{
+		date:     time.Now(),
+		interval: time.Duration(checkinInterval) * time.Second,
+	})
+}
+
 // SetTunnelStatusToActive update the status of the tunnel associated to the specified endpoint.
 // It sets the status to ACTIVE.
 func (service *Service) SetTunnelStatusToActive(endpointID portainer.EndpointID) {
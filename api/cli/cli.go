@@ -22,9 +22,15 @@ const (
 	errTemplateFileNotFound          = portainer.Error("Unable to locate template file on disk")
 	errInvalidSyncInterval           = portainer.Error("Invalid synchronization interval")
 	errInvalidSnapshotInterval       = portainer.Error("Invalid snapshot interval")
+	errInvalidStackUpdateInterval    = portainer.Error("Invalid stack update interval")
+	errInvalidLDAPSyncInterval       = portainer.Error("Invalid LDAP synchronization interval")
+	errInvalidBackupInterval         = portainer.Error("Invalid backup interval")
+	errInvalidBackupRetention        = portainer.Error("Invalid backup retention")
+	errBackupPasswordRequired        = portainer.Error("A password file is required to create automatic backups")
 	errEndpointExcludeExternal       = portainer.Error("Cannot use the -H flag mutually with --external-endpoints")
 	errNoAuthExcludeAdminPassword    = portainer.Error("Cannot use --no-auth with --admin-password or --admin-password-file")
 	errAdminPassExcludeAdminPassFile = portainer.Error("Cannot use --admin-password with --admin-password-file")
+	errEncryptionKeyExcludeKeyFile   = portainer.Error("Cannot use --encryption-key with --encryption-key-file")
 )
 
 // ParseFlags parse the CLI flags and return a portainer.Flags struct
@@ -32,32 +38,43 @@ func (*Service) ParseFlags(version string) (*portainer.CLIFlags, error) {
 	kingpin.Version(version)
 
 	flags := &portainer.CLIFlags{
-		Addr:              kingpin.Flag("bind", "Address and port to serve Portainer").Default(defaultBindAddress).Short('p').String(),
-		TunnelAddr:        kingpin.Flag("tunnel-addr", "Address to serve the tunnel server").Default(defaultTunnelServerAddress).String(),
-		TunnelPort:        kingpin.Flag("tunnel-port", "Port to serve the tunnel server").Default(defaultTunnelServerPort).String(),
-		Assets:            kingpin.Flag("assets", "Path to the assets").Default(defaultAssetsDirectory).Short('a').String(),
-		Data:              kingpin.Flag("data", "Path to the folder where the data is stored").Default(defaultDataDirectory).Short('d').String(),
-		EndpointURL:       kingpin.Flag("host", "Endpoint URL").Short('H').String(),
-		ExternalEndpoints: kingpin.Flag("external-endpoints", "Path to a file defining available endpoints").String(),
-		NoAuth:            kingpin.Flag("no-auth", "Disable authentication").Default(defaultNoAuth).Bool(),
-		NoAnalytics:       kingpin.Flag("no-analytics", "Disable Analytics in app").Default(defaultNoAnalytics).Bool(),
-		TLS:               kingpin.Flag("tlsverify", "TLS support").Default(defaultTLS).Bool(),
-		TLSSkipVerify:     kingpin.Flag("tlsskipverify", "Disable TLS server verification").Default(defaultTLSSkipVerify).Bool(),
-		TLSCacert:         kingpin.Flag("tlscacert", "Path to the CA").Default(defaultTLSCACertPath).String(),
-		TLSCert:           kingpin.Flag("tlscert", "Path to the TLS certificate file").Default(defaultTLSCertPath).String(),
-		TLSKey:            kingpin.Flag("tlskey", "Path to the TLS key").Default(defaultTLSKeyPath).String(),
-		SSL:               kingpin.Flag("ssl", "Secure Portainer instance using SSL").Default(defaultSSL).Bool(),
-		SSLCert:           kingpin.Flag("sslcert", "Path to the SSL certificate used to secure the Portainer instance").Default(defaultSSLCertPath).String(),
-		SSLKey:            kingpin.Flag("sslkey", "Path to the SSL key used to secure the Portainer instance").Default(defaultSSLKeyPath).String(),
-		SyncInterval:      kingpin.Flag("sync-interval", "Duration between each synchronization via the external endpoints source").Default(defaultSyncInterval).String(),
-		Snapshot:          kingpin.Flag("snapshot", "Start a background job to create endpoint snapshots").Default(defaultSnapshot).Bool(),
-		SnapshotInterval:  kingpin.Flag("snapshot-interval", "Duration between each endpoint snapshot job").Default(defaultSnapshotInterval).String(),
-		AdminPassword:     kingpin.Flag("admin-password", "Hashed admin password").String(),
-		AdminPasswordFile: kingpin.Flag("admin-password-file", "Path to the file containing the password for the admin user").String(),
-		Labels:            pairs(kingpin.Flag("hide-label", "Hide containers with a specific label in the UI").Short('l')),
-		Logo:              kingpin.Flag("logo", "URL for the logo displayed in the UI").String(),
-		Templates:         kingpin.Flag("templates", "URL to the templates definitions.").Short('t').String(),
-		TemplateFile:      kingpin.Flag("template-file", "Path to the templates (app) definitions on the filesystem").Default(defaultTemplateFile).String(),
+		Addr:                kingpin.Flag("bind", "Address and port to serve Portainer").Default(defaultBindAddress).Short('p').String(),
+		TunnelAddr:          kingpin.Flag("tunnel-addr", "Address to serve the tunnel server").Default(defaultTunnelServerAddress).String(),
+		TunnelPort:          kingpin.Flag("tunnel-port", "Port to serve the tunnel server").Default(defaultTunnelServerPort).String(),
+		Assets:              kingpin.Flag("assets", "Path to the assets").Default(defaultAssetsDirectory).Short('a').String(),
+		Data:                kingpin.Flag("data", "Path to the folder where the data is stored").Default(defaultDataDirectory).Short('d').String(),
+		EndpointURL:         kingpin.Flag("host", "Endpoint URL").Short('H').String(),
+		ExternalEndpoints:   kingpin.Flag("external-endpoints", "Path to a file defining available endpoints").String(),
+		NoAuth:              kingpin.Flag("no-auth", "Disable authentication").Default(defaultNoAuth).Bool(),
+		NoAnalytics:         kingpin.Flag("no-analytics", "Disable Analytics in app").Default(defaultNoAnalytics).Bool(),
+		TLS:                 kingpin.Flag("tlsverify", "TLS support").Default(defaultTLS).Bool(),
+		TLSSkipVerify:       kingpin.Flag("tlsskipverify", "Disable TLS server verification").Default(defaultTLSSkipVerify).Bool(),
+		TLSCacert:           kingpin.Flag("tlscacert", "Path to the CA").Default(defaultTLSCACertPath).String(),
+		TLSCert:             kingpin.Flag("tlscert", "Path to the TLS certificate file").Default(defaultTLSCertPath).String(),
+		TLSKey:              kingpin.Flag("tlskey", "Path to the TLS key").Default(defaultTLSKeyPath).String(),
+		SSL:                 kingpin.Flag("ssl", "Secure Portainer instance using SSL").Default(defaultSSL).Bool(),
+		SSLCert:             kingpin.Flag("sslcert", "Path to the SSL certificate used to secure the Portainer instance").Default(defaultSSLCertPath).String(),
+		SSLKey:              kingpin.Flag("sslkey", "Path to the SSL key used to secure the Portainer instance").Default(defaultSSLKeyPath).String(),
+		SyncInterval:        kingpin.Flag("sync-interval", "Duration between each synchronization via the external endpoints source").Default(defaultSyncInterval).String(),
+		Snapshot:            kingpin.Flag("snapshot", "Start a background job to create endpoint snapshots").Default(defaultSnapshot).Bool(),
+		SnapshotInterval:    kingpin.Flag("snapshot-interval", "Duration between each endpoint snapshot job").Default(defaultSnapshotInterval).String(),
+		StackUpdateInterval: kingpin.Flag("stack-update-interval", "Duration between each check for updates of stacks created from a Git repository").Default(defaultStackUpdateInterval).String(),
+		LDAPSyncInterval:    kingpin.Flag("ldap-sync-interval", "Duration between each synchronization of the users and team memberships with the LDAP server").Default(defaultLDAPSyncInterval).String(),
+		BackupInterval:      kingpin.Flag("backup-interval", "Duration between each automatic backup of the Portainer data (disabled when not specified)").String(),
+		BackupDirectory:     kingpin.Flag("backup-dir", "Path to the folder where the automatic backups are stored (defaults to a backups folder inside the data folder)").String(),
+		BackupRetention:     kingpin.Flag("backup-retention", "Number of automatic backups to keep").Default(defaultBackupRetention).Int(),
+		BackupPasswordFile:  kingpin.Flag("backup-password-file", "Path to the file containing the password used to encrypt the automatic backups").String(),
+		AdminPassword:       kingpin.Flag("admin-password", "Hashed admin password").String(),
+		AdminPasswordFile:   kingpin.Flag("admin-password-file", "Path to the file containing the password for the admin user").String(),
+		JWTSecretFile:       kingpin.Flag("jwt-secret-file", "Path to the file containing the key used to sign authentication tokens").String(),
+		EncryptionKey:       kingpin.Flag("encryption-key", "Key used to protect the key encrypting the secrets stored in the database").String(),
+		EncryptionKeyFile:   kingpin.Flag("encryption-key-file", "Path to the file containing the key used to protect the key encrypting the secrets stored in the database").String(),
+		RotateEncryptionKey: kingpin.Flag("rotate-encryption-key-file", "Protect the key encrypting the secrets with the key contained in the specified file and exit").String(),
+		MetricsToken:        kingpin.Flag("metrics-token", "Token allowing access to the metrics endpoint without an administrator account").String(),
+		Labels:              pairs(kingpin.Flag("hide-label", "Hide containers with a specific label in the UI").Short('l')),
+		Logo:                kingpin.Flag("logo", "URL for the logo displayed in the UI").String(),
+		Templates:           kingpin.Flag("templates", "URL to the templates definitions.").Short('t').String(),
+		TemplateFile:        kingpin.Flag("template-file", "Path to the templates (app) definitions on the filesystem").Default(defaultTemplateFile).String(),
 	}
 
 	kingpin.Parse()
@@ -105,6 +122,29 @@ func (*Service) ValidateFlags(flags *portainer.CLIFlags) error {
 		return err
 	}
 
+	err = validateStackUpdateInterval(*flags.StackUpdateInterval)
+	if err != nil {
+		return err
+	}
+
+	err = validateLDAPSyncInterval(*flags.LDAPSyncInterval)
+	if err != nil {
+		return err
+	}
+
+	err = validateBackupInterval(*flags.BackupInterval)
+	if err != nil {
+		return err
+	}
+
+	if *flags.BackupRetention < 1 {
+		return errInvalidBackupRetention
+	}
+
+	if *flags.BackupInterval != "" && *flags.BackupPasswordFile == "" {
+		return errBackupPasswordRequired
+	}
+
 	if *flags.NoAuth && (*flags.AdminPassword != "" || *flags.AdminPasswordFile != "") {
 		return errNoAuthExcludeAdminPassword
 	}
@@ -113,6 +153,10 @@ func (*Service) ValidateFlags(flags *portainer.CLIFlags) error {
 		return errAdminPassExcludeAdminPassFile
 	}
 
+	if *flags.EncryptionKey != "" && *flags.EncryptionKeyFile != "" {
+		return errEncryptionKeyExcludeKeyFile
+	}
+
 	return nil
 }
 
@@ -177,3 +221,33 @@ func validateSnapshotInterval(snapshotInterval string) error {
 	}
 	return nil
 }
+
+func validateStackUpdateInterval(stackUpdateInterval string) error {
+	if stackUpdateInterval != defaultStackUpdateInterval {
+		_, err := time.ParseDuration(stackUpdateInterval)
+		if err != nil {
+			return errInvalidStackUpdateInterval
+		}
+	}
+	return nil
+}
+
+func validateLDAPSyncInterval(ldapSyncInterval string) error {
+	if ldapSyncInterval != defaultLDAPSyncInterval {
+		_, err := time.ParseDuration(ldapSyncInterval)
+		if err != nil {
+			return errInvalidLDAPSyncInterval
+		}
+	}
+	return nil
+}
+
+func validateBackupInterval(backupInterval string) error {
+	if backupInterval != "" {
+		_, err := time.ParseDuration(backupInterval)
+		if err != nil {
+			return errInvalidBackupInterval
+		}
+	}
+	return nil
+}
@@ -19,5 +19,8 @@ const (
 	defaultSyncInterval        = "60s"
 	defaultSnapshot            = "true"
 	defaultSnapshotInterval    = "5m"
+	defaultStackUpdateInterval = "5m"
+	defaultLDAPSyncInterval    = "1h"
+	defaultBackupRetention     = "7"
 	defaultTemplateFile        = "/templates.json"
 )
@@ -2,14 +2,18 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/portainer/portainer/api/chisel"
 
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/alert"
+	"github.com/portainer/portainer/api/backup"
 	"github.com/portainer/portainer/api/bolt"
 	"github.com/portainer/portainer/api/cli"
 	"github.com/portainer/portainer/api/cron"
@@ -23,6 +27,10 @@ import (
 	"github.com/portainer/portainer/api/jwt"
 	"github.com/portainer/portainer/api/ldap"
 	"github.com/portainer/portainer/api/libcompose"
+	"github.com/portainer/portainer/api/metrics"
+	"github.com/portainer/portainer/api/oidc"
+	"github.com/portainer/portainer/api/stacks"
+	"github.com/portainer/portainer/api/totp"
 )
 
 func initCLI() *portainer.CLIFlags {
@@ -47,8 +55,8 @@ func initFileService(dataStorePath string) portainer.FileService {
 	return fileService
 }
 
-func initStore(dataStorePath string, fileService portainer.FileService) *bolt.Store {
-	store, err := bolt.NewStore(dataStorePath, fileService)
+func initStore(dataStorePath string, fileService portainer.FileService, encryptionService portainer.EncryptionService) *bolt.Store {
+	store, err := bolt.NewStore(dataStorePath, fileService, encryptionService)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -78,9 +86,28 @@ func initSwarmStackManager(assetsPath string, dataStorePath string, signatureSer
 	return exec.NewSwarmStackManager(assetsPath, dataStorePath, signatureService, fileService, reverseTunnelService)
 }
 
-func initJWTService(authenticationEnabled bool) portainer.JWTService {
+func initJWTService(authenticationEnabled bool, secretFile string, fileService portainer.FileService, sessionService portainer.SessionService, settingsService portainer.SettingsService) portainer.JWTService {
 	if authenticationEnabled {
-		jwtService, err := jwt.NewService()
+		var secret []byte
+		if secretFile != "" {
+			content, err := fileService.GetFileContent(secretFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			secret = []byte(strings.TrimSuffix(string(content), "\n"))
+		}
+
+		settings, err := settingsService.Settings()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		userSessionTimeout := settings.UserSessionTimeout
+		if userSessionTimeout == "" {
+			userSessionTimeout = portainer.DefaultUserSessionTimeout
+		}
+
+		jwtService, err := jwt.NewService(secret, sessionService, userSessionTimeout)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -109,15 +136,15 @@ func initClientFactory(signatureService portainer.DigitalSignatureService, rever
 	return docker.NewClientFactory(signatureService, reverseTunnelService)
 }
 
-func initSnapshotter(clientFactory *docker.ClientFactory) portainer.Snapshotter {
-	return docker.NewSnapshotter(clientFactory)
+func initSnapshotter(clientFactory *docker.ClientFactory, metricsRegistry *metrics.Registry, snapshotHistoryService portainer.SnapshotHistoryService, endpointEventService portainer.EndpointEventService) portainer.Snapshotter {
+	return docker.NewSnapshotter(clientFactory, metricsRegistry, snapshotHistoryService, endpointEventService)
 }
 
 func initJobScheduler() portainer.JobScheduler {
 	return cron.NewJobScheduler()
 }
 
-func loadSnapshotSystemSchedule(jobScheduler portainer.JobScheduler, snapshotter portainer.Snapshotter, scheduleService portainer.ScheduleService, endpointService portainer.EndpointService, settingsService portainer.SettingsService) error {
+func loadSnapshotSystemSchedule(jobScheduler portainer.JobScheduler, snapshotter portainer.Snapshotter, scheduleService portainer.ScheduleService, endpointService portainer.EndpointService, settingsService portainer.SettingsService, alerter portainer.Alerter) error {
 	settings, err := settingsService.Settings()
 	if err != nil {
 		return err
@@ -144,7 +171,7 @@ func loadSnapshotSystemSchedule(jobScheduler portainer.JobScheduler, snapshotter
 		snapshotSchedule = &schedules[0]
 	}
 
-	snapshotJobContext := cron.NewSnapshotJobContext(endpointService, snapshotter)
+	snapshotJobContext := cron.NewSnapshotJobContext(endpointService, snapshotter, alerter)
 	snapshotJobRunner := cron.NewSnapshotJobRunner(snapshotSchedule, snapshotJobContext)
 
 	err = jobScheduler.ScheduleJob(snapshotJobRunner)
@@ -197,6 +224,219 @@ func loadEndpointSyncSystemSchedule(jobScheduler portainer.JobScheduler, schedul
 	return scheduleService.CreateSchedule(endpointSyncSchedule)
 }
 
+func loadStackGitUpdateSystemSchedule(jobScheduler portainer.JobScheduler, scheduleService portainer.ScheduleService, jobContext *cron.StackGitUpdateJobContext, flags *portainer.CLIFlags) error {
+	schedules, err := scheduleService.SchedulesByJobType(portainer.StackGitUpdateJobType)
+	if err != nil {
+		return err
+	}
+
+	cronExpression := "@every " + *flags.StackUpdateInterval
+
+	var stackGitUpdateSchedule *portainer.Schedule
+	if len(schedules) == 0 {
+		stackGitUpdateJob := &portainer.StackGitUpdateJob{}
+		stackGitUpdateSchedule = &portainer.Schedule{
+			ID:                portainer.ScheduleID(scheduleService.GetNextIdentifier()),
+			Name:              "system_stackgitupdate",
+			CronExpression:    cronExpression,
+			Recurring:         true,
+			JobType:           portainer.StackGitUpdateJobType,
+			StackGitUpdateJob: stackGitUpdateJob,
+			Created:           time.Now().Unix(),
+		}
+
+		err = scheduleService.CreateSchedule(stackGitUpdateSchedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		stackGitUpdateSchedule = &schedules[0]
+		if stackGitUpdateSchedule.CronExpression != cronExpression {
+			stackGitUpdateSchedule.CronExpression = cronExpression
+			err = scheduleService.UpdateSchedule(stackGitUpdateSchedule.ID, stackGitUpdateSchedule)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	stackGitUpdateJobRunner := cron.NewStackGitUpdateJobRunner(stackGitUpdateSchedule, jobContext)
+	return jobScheduler.ScheduleJob(stackGitUpdateJobRunner)
+}
+
+func loadLDAPSyncSystemSchedule(jobScheduler portainer.JobScheduler, scheduleService portainer.ScheduleService, jobContext *cron.LDAPSyncJobContext, flags *portainer.CLIFlags) error {
+	schedules, err := scheduleService.SchedulesByJobType(portainer.LDAPSyncJobType)
+	if err != nil {
+		return err
+	}
+
+	cronExpression := "@every " + *flags.LDAPSyncInterval
+
+	var ldapSyncSchedule *portainer.Schedule
+	if len(schedules) == 0 {
+		ldapSyncJob := &portainer.LDAPSyncJob{}
+		ldapSyncSchedule = &portainer.Schedule{
+			ID:             portainer.ScheduleID(scheduleService.GetNextIdentifier()),
+			Name:           "system_ldapsync",
+			CronExpression: cronExpression,
+			Recurring:      true,
+			JobType:        portainer.LDAPSyncJobType,
+			LDAPSyncJob:    ldapSyncJob,
+			Created:        time.Now().Unix(),
+		}
+
+		err = scheduleService.CreateSchedule(ldapSyncSchedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		ldapSyncSchedule = &schedules[0]
+		if ldapSyncSchedule.CronExpression != cronExpression {
+			ldapSyncSchedule.CronExpression = cronExpression
+			err = scheduleService.UpdateSchedule(ldapSyncSchedule.ID, ldapSyncSchedule)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	ldapSyncJobRunner := cron.NewLDAPSyncJobRunner(ldapSyncSchedule, jobContext)
+	return jobScheduler.ScheduleJob(ldapSyncJobRunner)
+}
+
+func loadAuditLogPruneSystemSchedule(jobScheduler portainer.JobScheduler, scheduleService portainer.ScheduleService, jobContext *cron.AuditLogPruneJobContext) error {
+	schedules, err := scheduleService.SchedulesByJobType(portainer.AuditLogPruneJobType)
+	if err != nil {
+		return err
+	}
+
+	var auditLogPruneSchedule *portainer.Schedule
+	if len(schedules) == 0 {
+		auditLogPruneSchedule = &portainer.Schedule{
+			ID:               portainer.ScheduleID(scheduleService.GetNextIdentifier()),
+			Name:             "system_auditlogprune",
+			CronExpression:   "@every 1h",
+			Recurring:        true,
+			JobType:          portainer.AuditLogPruneJobType,
+			AuditLogPruneJob: &portainer.AuditLogPruneJob{},
+			Created:          time.Now().Unix(),
+		}
+
+		err = scheduleService.CreateSchedule(auditLogPruneSchedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		auditLogPruneSchedule = &schedules[0]
+	}
+
+	auditLogPruneJobRunner := cron.NewAuditLogPruneJobRunner(auditLogPruneSchedule, jobContext)
+	return jobScheduler.ScheduleJob(auditLogPruneJobRunner)
+}
+
+func loadSnapshotHistoryCompactSystemSchedule(jobScheduler portainer.JobScheduler, scheduleService portainer.ScheduleService, jobContext *cron.SnapshotHistoryCompactJobContext) error {
+	schedules, err := scheduleService.SchedulesByJobType(portainer.SnapshotHistoryCompactJobType)
+	if err != nil {
+		return err
+	}
+
+	var snapshotHistoryCompactSchedule *portainer.Schedule
+	if len(schedules) == 0 {
+		snapshotHistoryCompactSchedule = &portainer.Schedule{
+			ID:                        portainer.ScheduleID(scheduleService.GetNextIdentifier()),
+			Name:                      "system_snapshothistorycompact",
+			CronExpression:            "@every 1h",
+			Recurring:                 true,
+			JobType:                   portainer.SnapshotHistoryCompactJobType,
+			SnapshotHistoryCompactJob: &portainer.SnapshotHistoryCompactJob{},
+			Created:                   time.Now().Unix(),
+		}
+
+		err = scheduleService.CreateSchedule(snapshotHistoryCompactSchedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		snapshotHistoryCompactSchedule = &schedules[0]
+	}
+
+	snapshotHistoryCompactJobRunner := cron.NewSnapshotHistoryCompactJobRunner(snapshotHistoryCompactSchedule, jobContext)
+	return jobScheduler.ScheduleJob(snapshotHistoryCompactJobRunner)
+}
+
+func loadBackupPassword(passwordFile string, fileService portainer.FileService) (string, error) {
+	if passwordFile == "" {
+		return "", nil
+	}
+
+	content, err := fileService.GetFileContent(passwordFile)
+	if err != nil {
+		return "", err
+	}
+
+	password := strings.TrimSuffix(string(content), "\n")
+	if password == "" {
+		return "", errors.New("the backup password file is empty")
+	}
+
+	return password, nil
+}
+
+func loadBackupSystemSchedule(jobScheduler portainer.JobScheduler, scheduleService portainer.ScheduleService, jobContext *cron.BackupJobContext, flags *portainer.CLIFlags) error {
+	schedules, err := scheduleService.SchedulesByJobType(portainer.BackupJobType)
+	if err != nil {
+		return err
+	}
+
+	if *flags.BackupInterval == "" {
+		for _, schedule := range schedules {
+			err = scheduleService.DeleteSchedule(schedule.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cronExpression := "@every " + *flags.BackupInterval
+
+	backupJob := &portainer.BackupJob{
+		Directory: *flags.BackupDirectory,
+		Retention: *flags.BackupRetention,
+	}
+
+	var backupSchedule *portainer.Schedule
+	if len(schedules) == 0 {
+		backupSchedule = &portainer.Schedule{
+			ID:             portainer.ScheduleID(scheduleService.GetNextIdentifier()),
+			Name:           "system_backup",
+			CronExpression: cronExpression,
+			Recurring:      true,
+			JobType:        portainer.BackupJobType,
+			BackupJob:      backupJob,
+			Created:        time.Now().Unix(),
+		}
+
+		err = scheduleService.CreateSchedule(backupSchedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		backupSchedule = &schedules[0]
+		if backupSchedule.CronExpression != cronExpression || backupSchedule.BackupJob == nil || *backupSchedule.BackupJob != *backupJob {
+			backupSchedule.CronExpression = cronExpression
+			backupSchedule.BackupJob = backupJob
+			err = scheduleService.UpdateSchedule(backupSchedule.ID, backupSchedule)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	backupJobRunner := cron.NewBackupJobRunner(backupSchedule, jobContext)
+	return jobScheduler.ScheduleJob(backupJobRunner)
+}
+
 func loadSchedulesFromDatabase(jobScheduler portainer.JobScheduler, jobService portainer.JobService, scheduleService portainer.ScheduleService, endpointService portainer.EndpointService, fileService portainer.FileService, reverseTunnelService portainer.ReverseTunnelService) error {
 	schedules, err := scheduleService.Schedules()
 	if err != nil {
@@ -275,6 +515,18 @@ func initSettings(settingsService portainer.SettingsService, flags *portainer.CL
 			EnableHostManagementFeatures:       false,
 			SnapshotInterval:                   *flags.SnapshotInterval,
 			EdgeAgentCheckinInterval:           portainer.DefaultEdgeAgentCheckinIntervalInSeconds,
+			StackVersionRetention:              portainer.DefaultStackVersionRetention,
+			UserSessionTimeout:                 portainer.DefaultUserSessionTimeout,
+			SessionRecordingRetention:          portainer.DefaultSessionRecordingRetention,
+			AuditLogRetention:                  portainer.DefaultAuditLogRetention,
+			AccountLockout: portainer.AccountLockoutSettings{
+				LockoutDuration: portainer.DefaultAccountLockoutDuration,
+			},
+			SnapshotHistory: portainer.SnapshotHistorySettings{
+				Retention:            portainer.DefaultSnapshotHistoryRetention,
+				DownsamplingAge:      portainer.DefaultSnapshotHistoryDownsamplingAge,
+				DownsamplingInterval: portainer.DefaultSnapshotHistoryDownsamplingInterval,
+			},
 		}
 
 		if *flags.Templates != "" {
@@ -371,6 +623,104 @@ func initKeyPair(fileService portainer.FileService, signatureService portainer.D
 	return generateAndStoreKeyPair(fileService, signatureService)
 }
 
+// loadEncryptionSecret returns the secret used to protect the data encryption key, or nil when
+// no encryption key was specified.
+func loadEncryptionSecret(key, keyFile string, fileService portainer.FileService) ([]byte, error) {
+	if keyFile != "" {
+		return fileService.GetFileContent(keyFile)
+	}
+
+	if key != "" {
+		return []byte(key), nil
+	}
+
+	return nil, nil
+}
+
+// loadDataEncryptionKey returns the key used to encrypt the secrets stored in the database.
+// When an encryption secret is specified, the data encryption key is stored encrypted with a key derived from it.
+func loadDataEncryptionKey(fileService portainer.FileService, secret []byte) ([]byte, error) {
+	existingKey, err := fileService.EncryptionKeyFileExists()
+	if err != nil {
+		return nil, err
+	}
+
+	if !existingKey {
+		key, err := crypto.GenerateAESKey()
+		if err != nil {
+			return nil, err
+		}
+		return key, storeDataEncryptionKey(fileService, key, secret)
+	}
+
+	data, err := fileService.LoadEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if crypto.IsWrappedKey(data) {
+		if secret == nil {
+			return nil, errors.New("the data encryption key is protected by an encryption key. Use the --encryption-key-file or --encryption-key flag")
+		}
+		return crypto.UnwrapKey(secret, data)
+	}
+
+	if secret != nil {
+		log.Println("Protecting the data encryption key with the specified encryption key.")
+		return data, storeDataEncryptionKey(fileService, data, secret)
+	}
+
+	return data, nil
+}
+
+func storeDataEncryptionKey(fileService portainer.FileService, key, secret []byte) error {
+	if secret == nil {
+		return fileService.StoreEncryptionKey(key)
+	}
+
+	wrappedKey, err := crypto.WrapKey(secret, key)
+	if err != nil {
+		return err
+	}
+
+	return fileService.StoreEncryptionKey(wrappedKey)
+}
+
+func initEncryptionService(flags *portainer.CLIFlags, fileService portainer.FileService) (portainer.EncryptionService, error) {
+	secret, err := loadEncryptionSecret(*flags.EncryptionKey, *flags.EncryptionKeyFile, fileService)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadDataEncryptionKey(fileService, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.NewAESService(key)
+}
+
+// rotateEncryptionKey protects the data encryption key with the key contained in the specified file.
+// The secrets stored in the database do not need to be encrypted again.
+func rotateEncryptionKey(flags *portainer.CLIFlags, fileService portainer.FileService) error {
+	secret, err := loadEncryptionSecret(*flags.EncryptionKey, *flags.EncryptionKeyFile, fileService)
+	if err != nil {
+		return err
+	}
+
+	key, err := loadDataEncryptionKey(fileService, secret)
+	if err != nil {
+		return err
+	}
+
+	newSecret, err := loadEncryptionSecret("", *flags.RotateEncryptionKey, fileService)
+	if err != nil {
+		return err
+	}
+
+	return storeDataEncryptionKey(fileService, key, newSecret)
+}
+
 func createTLSSecuredEndpoint(flags *portainer.CLIFlags, endpointService portainer.EndpointService, snapshotter portainer.Snapshotter) error {
 	tlsConfiguration := portainer.TLSConfiguration{
 		TLS:           *flags.TLS,
@@ -517,10 +867,22 @@ func main() {
 
 	fileService := initFileService(*flags.Data)
 
-	store := initStore(*flags.Data, fileService)
-	defer store.Close()
+	if *flags.RotateEncryptionKey != "" {
+		err := rotateEncryptionKey(flags, fileService)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("Encryption key rotated. Restart Portainer using the new encryption key.")
+		return
+	}
+
+	encryptionService, err := initEncryptionService(flags, fileService)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	jwtService := initJWTService(!*flags.NoAuth)
+	store := initStore(*flags.Data, fileService, encryptionService)
+	defer store.Close()
 
 	ldapService := initLDAPService()
 
@@ -530,7 +892,7 @@ func main() {
 
 	digitalSignatureService := initDigitalSignatureService()
 
-	err := initKeyPair(fileService, digitalSignatureService)
+	err = initKeyPair(fileService, digitalSignatureService)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -540,13 +902,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	reverseTunnelService := chisel.NewService(store.EndpointService, store.TunnelServerService)
+	alerter := alert.NewService(store.AlertRuleService, store.AlertNotifierService)
+
+	reverseTunnelService := chisel.NewService(store.EndpointService, store.TunnelServerService, alerter)
 
 	clientFactory := initClientFactory(digitalSignatureService, reverseTunnelService)
 
 	jobService := initJobService(clientFactory)
 
-	snapshotter := initSnapshotter(clientFactory)
+	metricsRegistry := metrics.NewRegistry()
+
+	snapshotter := initSnapshotter(clientFactory, metricsRegistry, store.SnapshotHistoryService, store.EndpointEventService)
+
+	quotaChecker := docker.NewQuotaChecker(clientFactory, store.QuotaService, store.TeamMembershipService, store.ResourceControlService)
 
 	endpointManagement := true
 	if *flags.ExternalEndpoints != "" {
@@ -560,6 +928,9 @@ func main() {
 
 	composeStackManager := initComposeStackManager(*flags.Data, reverseTunnelService)
 
+	stackDeployer := stacks.NewStackDeployer(swarmStackManager, composeStackManager)
+	stackVersionManager := stacks.NewVersionManager(fileService, store.SettingsService, encryptionService)
+
 	err = initTemplates(store.TemplateService, fileService, *flags.Templates, *flags.TemplateFile)
 	if err != nil {
 		log.Fatal(err)
@@ -570,6 +941,8 @@ func main() {
 		log.Fatal(err)
 	}
 
+	jwtService := initJWTService(!*flags.NoAuth, *flags.JWTSecretFile, fileService, store.SessionService, store.SettingsService)
+
 	jobScheduler := initJobScheduler()
 
 	err = loadSchedulesFromDatabase(jobScheduler, jobService, store.ScheduleService, store.EndpointService, fileService, reverseTunnelService)
@@ -582,8 +955,55 @@ func main() {
 		log.Fatal(err)
 	}
 
+	stackGitUpdateJobContext := cron.NewStackGitUpdateJobContext(store.StackService, store.EndpointService, store.RegistryService, store.DockerHubService, gitService, fileService, stackDeployer, stackVersionManager)
+	err = loadStackGitUpdateSystemSchedule(jobScheduler, store.ScheduleService, stackGitUpdateJobContext, flags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ldapSyncAuthorizationService := portainer.NewAuthorizationService(&portainer.AuthorizationServiceParameters{
+		EndpointService:       store.EndpointService,
+		EndpointGroupService:  store.EndpointGroupService,
+		RegistryService:       store.RegistryService,
+		RoleService:           store.RoleService,
+		TeamMembershipService: store.TeamMembershipService,
+		UserService:           store.UserService,
+	})
+
+	ldapSyncJobContext := cron.NewLDAPSyncJobContext(store.SettingsService, store.UserService, store.TeamService, store.TeamMembershipService, ldapService, store.LDAPSyncReportService, jwtService, ldapSyncAuthorizationService)
+	err = loadLDAPSyncSystemSchedule(jobScheduler, store.ScheduleService, ldapSyncJobContext, flags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = loadAuditLogPruneSystemSchedule(jobScheduler, store.ScheduleService, cron.NewAuditLogPruneJobContext(store.SettingsService, store.AuditService))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = loadSnapshotHistoryCompactSystemSchedule(jobScheduler, store.ScheduleService, cron.NewSnapshotHistoryCompactJobContext(store.SettingsService, store.SnapshotHistoryService, store.EndpointEventService))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *flags.BackupDirectory == "" {
+		*flags.BackupDirectory = filepath.Join(*flags.Data, "backups")
+	}
+
+	backupService := backup.NewService(store, *flags.Data)
+
+	backupPassword, err := loadBackupPassword(*flags.BackupPasswordFile, fileService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = loadBackupSystemSchedule(jobScheduler, store.ScheduleService, cron.NewBackupJobContext(backupService, backupPassword), flags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	if *flags.Snapshot {
-		err = loadSnapshotSystemSchedule(jobScheduler, snapshotter, store.ScheduleService, store.EndpointService, store.SettingsService)
+		err = loadSnapshotSystemSchedule(jobScheduler, snapshotter, store.ScheduleService, store.EndpointService, store.SettingsService, alerter)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -630,6 +1050,8 @@ func main() {
 				Role:                    portainer.AdministratorRole,
 				Password:                adminPasswordHash,
 				PortainerAuthorizations: portainer.DefaultPortainerAuthorizations(),
+				PasswordChangedAt:       time.Now().Unix(),
+				AuthenticationMethod:    portainer.AuthenticationInternal,
 			}
 			err := store.UserService.CreateUser(user)
 			if err != nil {
@@ -650,44 +1072,64 @@ func main() {
 	}
 
 	var server portainer.Server = &http.Server{
-		ReverseTunnelService:   reverseTunnelService,
-		Status:                 applicationStatus,
-		BindAddress:            *flags.Addr,
-		AssetsPath:             *flags.Assets,
-		AuthDisabled:           *flags.NoAuth,
-		EndpointManagement:     endpointManagement,
-		RoleService:            store.RoleService,
-		UserService:            store.UserService,
-		TeamService:            store.TeamService,
-		TeamMembershipService:  store.TeamMembershipService,
-		EndpointService:        store.EndpointService,
-		EndpointGroupService:   store.EndpointGroupService,
-		ExtensionService:       store.ExtensionService,
-		ResourceControlService: store.ResourceControlService,
-		SettingsService:        store.SettingsService,
-		RegistryService:        store.RegistryService,
-		DockerHubService:       store.DockerHubService,
-		StackService:           store.StackService,
-		ScheduleService:        store.ScheduleService,
-		TagService:             store.TagService,
-		TemplateService:        store.TemplateService,
-		WebhookService:         store.WebhookService,
-		SwarmStackManager:      swarmStackManager,
-		ComposeStackManager:    composeStackManager,
-		ExtensionManager:       extensionManager,
-		CryptoService:          cryptoService,
-		JWTService:             jwtService,
-		FileService:            fileService,
-		LDAPService:            ldapService,
-		GitService:             gitService,
-		SignatureService:       digitalSignatureService,
-		JobScheduler:           jobScheduler,
-		Snapshotter:            snapshotter,
-		SSL:                    *flags.SSL,
-		SSLCert:                *flags.SSLCert,
-		SSLKey:                 *flags.SSLKey,
-		DockerClientFactory:    clientFactory,
-		JobService:             jobService,
+		ReverseTunnelService:    reverseTunnelService,
+		Status:                  applicationStatus,
+		BindAddress:             *flags.Addr,
+		AssetsPath:              *flags.Assets,
+		AuthDisabled:            *flags.NoAuth,
+		EndpointManagement:      endpointManagement,
+		RoleService:             store.RoleService,
+		UserService:             store.UserService,
+		TeamService:             store.TeamService,
+		TeamMembershipService:   store.TeamMembershipService,
+		EndpointService:         store.EndpointService,
+		EdgeJoinTokenService:    store.EdgeJoinTokenService,
+		EdgeStackService:        store.EdgeStackService,
+		EndpointEventService:    store.EndpointEventService,
+		EndpointGroupService:    store.EndpointGroupService,
+		ExtensionService:        store.ExtensionService,
+		ContainerPolicyService:  store.ContainerPolicyService,
+		QuotaService:            store.QuotaService,
+		QuotaChecker:            quotaChecker,
+		ResourceControlService:  store.ResourceControlService,
+		SettingsService:         store.SettingsService,
+		SnapshotHistoryService:  store.SnapshotHistoryService,
+		RegistryService:         store.RegistryService,
+		DockerHubService:        store.DockerHubService,
+		StackService:            store.StackService,
+		StackDeployer:           stackDeployer,
+		StackVersionManager:     stackVersionManager,
+		ScheduleService:         store.ScheduleService,
+		TagService:              store.TagService,
+		TemplateService:         store.TemplateService,
+		WebhookService:          store.WebhookService,
+		SwarmStackManager:       swarmStackManager,
+		ComposeStackManager:     composeStackManager,
+		ExtensionManager:        extensionManager,
+		CryptoService:           cryptoService,
+		JWTService:              jwtService,
+		FileService:             fileService,
+		LDAPService:             ldapService,
+		LDAPSyncReportService:   store.LDAPSyncReportService,
+		OIDCService:             oidc.NewService(),
+		TOTPService:             totp.NewService(encryptionService, cryptoService),
+		GitService:              gitService,
+		SignatureService:        digitalSignatureService,
+		JobScheduler:            jobScheduler,
+		AuditService:            store.AuditService,
+		BackupService:           backupService,
+		SessionRecordingService: store.SessionRecordingService,
+		AlertRuleService:        store.AlertRuleService,
+		AlertNotifierService:    store.AlertNotifierService,
+		MetricsRegistry:         metricsRegistry,
+		MetricsToken:            *flags.MetricsToken,
+		APIKeyService:           store.APIKeyService,
+		Snapshotter:             snapshotter,
+		SSL:                     *flags.SSL,
+		SSLCert:                 *flags.SSLCert,
+		SSLKey:                  *flags.SSLKey,
+		DockerClientFactory:     clientFactory,
+		JobService:              jobService,
 	}
 
 	log.Printf("Starting Portainer %s on %s", portainer.APIVersion, *flags.Addr)
@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// AuditLogPruneJobRunner is used to run a AuditLogPruneJob
+type AuditLogPruneJobRunner struct {
+	schedule *portainer.Schedule
+	context  *AuditLogPruneJobContext
+}
+
+// AuditLogPruneJobContext represents the context of execution of a AuditLogPruneJob
+type AuditLogPruneJobContext struct {
+	settingsService portainer.SettingsService
+	auditService    portainer.AuditService
+}
+
+// NewAuditLogPruneJobContext returns a new context that can be used to execute a AuditLogPruneJob
+func NewAuditLogPruneJobContext(settingsService portainer.SettingsService, auditService portainer.AuditService) *AuditLogPruneJobContext {
+	return &AuditLogPruneJobContext{
+		settingsService: settingsService,
+		auditService:    auditService,
+	}
+}
+
+// NewAuditLogPruneJobRunner returns a new runner that can be scheduled
+func NewAuditLogPruneJobRunner(schedule *portainer.Schedule, context *AuditLogPruneJobContext) *AuditLogPruneJobRunner {
+	return &AuditLogPruneJobRunner{
+		schedule: schedule,
+		context:  context,
+	}
+}
+
+// GetSchedule returns the schedule associated to the runner
+func (runner *AuditLogPruneJobRunner) GetSchedule() *portainer.Schedule {
+	return runner.schedule
+}
+
+// Run triggers the execution of the schedule.
+// It will remove the audit logs older than the audit log retention defined in the settings.
+func (runner *AuditLogPruneJobRunner) Run() {
+	go func() {
+		settings, err := runner.context.settingsService.Settings()
+		if err != nil {
+			log.Printf("background schedule error (audit log prune). Unable to retrieve settings (err=%s)\n", err)
+			return
+		}
+
+		if settings.AuditLogRetention <= 0 {
+			return
+		}
+
+		before := time.Now().AddDate(0, 0, -settings.AuditLogRetention).Unix()
+		err = runner.context.auditService.PruneAuditLogs(before)
+		if err != nil {
+			log.Printf("background schedule error (audit log prune). Unable to remove audit logs (err=%s)\n", err)
+		}
+	}()
+}
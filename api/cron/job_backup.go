@@ -0,0 +1,128 @@
+package cron
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	backupFilePrefix = "portainer-backup-"
+	backupFileSuffix = ".tar.gz"
+)
+
+// BackupJobRunner is used to run a BackupJob
+type BackupJobRunner struct {
+	schedule *portainer.Schedule
+	context  *BackupJobContext
+}
+
+// BackupJobContext represents the context of execution of a BackupJob
+type BackupJobContext struct {
+	backupService portainer.BackupService
+	password      string
+}
+
+// NewBackupJobContext returns a new context that can be used to execute a BackupJob.
+// The backups are encrypted with the specified password.
+func NewBackupJobContext(backupService portainer.BackupService, password string) *BackupJobContext {
+	return &BackupJobContext{
+		backupService: backupService,
+		password:      password,
+	}
+}
+
+// NewBackupJobRunner returns a new runner that can be scheduled
+func NewBackupJobRunner(schedule *portainer.Schedule, context *BackupJobContext) *BackupJobRunner {
+	return &BackupJobRunner{
+		schedule: schedule,
+		context:  context,
+	}
+}
+
+// GetSchedule returns the schedule associated to the runner
+func (runner *BackupJobRunner) GetSchedule() *portainer.Schedule {
+	return runner.schedule
+}
+
+// Run triggers the execution of the schedule.
+// It will create a backup archive inside the directory associated to the job and
+// remove the oldest archives when more archives than the retention are available.
+func (runner *BackupJobRunner) Run() {
+	go func() {
+		job := runner.schedule.BackupJob
+
+		err := runner.createBackup(job.Directory)
+		if err != nil {
+			log.Printf("background schedule error (backup). Unable to create backup (directory=%s) (err=%s)\n", job.Directory, err)
+			return
+		}
+
+		err = pruneBackups(job.Directory, job.Retention)
+		if err != nil {
+			log.Printf("background schedule error (backup). Unable to remove outdated backups (directory=%s) (err=%s)\n", job.Directory, err)
+		}
+	}()
+}
+
+func (runner *BackupJobRunner) createBackup(directory string) error {
+	err := os.MkdirAll(directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	fileName := backupFilePrefix + time.Now().UTC().Format("20060102-150405") + backupFileSuffix
+	tempPath := filepath.Join(directory, "."+fileName)
+
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = runner.context.backupService.CreateBackup(file, runner.context.password)
+	file.Close()
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, filepath.Join(directory, fileName))
+}
+
+func pruneBackups(directory string, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return err
+	}
+
+	backups := make([]string, 0)
+	for _, file := range files {
+		if file.Mode().IsRegular() && strings.HasPrefix(file.Name(), backupFilePrefix) && strings.HasSuffix(file.Name(), backupFileSuffix) {
+			backups = append(backups, file.Name())
+		}
+	}
+
+	if len(backups) <= retention {
+		return nil
+	}
+
+	sort.Strings(backups)
+	for _, name := range backups[:len(backups)-retention] {
+		err := os.Remove(filepath.Join(directory, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
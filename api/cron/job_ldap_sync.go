@@ -0,0 +1,325 @@
+package cron
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// ldapSyncReportRetention is the number of LDAP synchronization reports kept in the database
+const ldapSyncReportRetention = 100
+
+// LDAPSyncJobRunner is used to run a LDAPSyncJob
+type LDAPSyncJobRunner struct {
+	schedule *portainer.Schedule
+	context  *LDAPSyncJobContext
+}
+
+// LDAPSyncJobContext represents the context of execution of a LDAPSyncJob
+type LDAPSyncJobContext struct {
+	settingsService       portainer.SettingsService
+	userService           portainer.UserService
+	teamService           portainer.TeamService
+	teamMembershipService portainer.TeamMembershipService
+	ldapService           portainer.LDAPService
+	ldapSyncReportService portainer.LDAPSyncReportService
+	jwtService            portainer.JWTService
+	authorizationService  *portainer.AuthorizationService
+}
+
+// NewLDAPSyncJobContext returns a new context that can be used to execute a LDAPSyncJob
+func NewLDAPSyncJobContext(settingsService portainer.SettingsService, userService portainer.UserService, teamService portainer.TeamService, teamMembershipService portainer.TeamMembershipService, ldapService portainer.LDAPService, ldapSyncReportService portainer.LDAPSyncReportService, jwtService portainer.JWTService, authorizationService *portainer.AuthorizationService) *LDAPSyncJobContext {
+	return &LDAPSyncJobContext{
+		settingsService:       settingsService,
+		userService:           userService,
+		teamService:           teamService,
+		teamMembershipService: teamMembershipService,
+		ldapService:           ldapService,
+		ldapSyncReportService: ldapSyncReportService,
+		jwtService:            jwtService,
+		authorizationService:  authorizationService,
+	}
+}
+
+// NewLDAPSyncJobRunner returns a new runner that can be scheduled
+func NewLDAPSyncJobRunner(schedule *portainer.Schedule, context *LDAPSyncJobContext) *LDAPSyncJobRunner {
+	return &LDAPSyncJobRunner{
+		schedule: schedule,
+		context:  context,
+	}
+}
+
+// GetSchedule returns the schedule associated to the runner
+func (runner *LDAPSyncJobRunner) GetSchedule() *portainer.Schedule {
+	return runner.schedule
+}
+
+// Run triggers the execution of the schedule.
+// It will only synchronize when the LDAP authentication is enabled. For each user managed by LDAP
+// (users created through the LDAP authentication), the memberships of the teams associated to a LDAP group are reconciled
+// with the groups of the user. Users that cannot be found anymore are disabled, users matching several
+// LDAP entries are left untouched and reported as an error.
+// A report describing the changes is stored after each execution.
+func (runner *LDAPSyncJobRunner) Run() {
+	go func() {
+		settings, err := runner.context.settingsService.Settings()
+		if err != nil {
+			log.Printf("background schedule error (LDAP synchronization). Unable to retrieve settings (err=%s)\n", err)
+			return
+		}
+
+		if settings.AuthenticationMethod != portainer.AuthenticationLDAP {
+			return
+		}
+
+		report := &portainer.LDAPSyncReport{
+			StartedAt:          time.Now().Unix(),
+			CreatedTeams:       make([]string, 0),
+			AddedMemberships:   make([]portainer.LDAPSyncMembershipChange, 0),
+			RemovedMemberships: make([]portainer.LDAPSyncMembershipChange, 0),
+			DisabledUsers:      make([]string, 0),
+			EnabledUsers:       make([]string, 0),
+		}
+
+		err = runner.synchronize(&settings.LDAPSettings, report)
+		if err != nil {
+			log.Printf("background schedule error (LDAP synchronization). Unable to synchronize with the LDAP server (err=%s)\n", err)
+			report.Error = err.Error()
+		}
+		report.EndedAt = time.Now().Unix()
+
+		err = runner.storeReport(report)
+		if err != nil {
+			log.Printf("background schedule error (LDAP synchronization). Unable to store synchronization report (err=%s)\n", err)
+		}
+	}()
+}
+
+func (runner *LDAPSyncJobRunner) synchronize(settings *portainer.LDAPSettings, report *portainer.LDAPSyncReport) error {
+	groups, err := runner.context.ldapService.GetGroups(settings)
+	if err != nil {
+		return err
+	}
+
+	teams, err := runner.context.teamService.Teams()
+	if err != nil {
+		return err
+	}
+
+	if settings.AutoCreateTeams {
+		teams, err = runner.createTeams(settings, groups, teams, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Only the memberships of the teams associated to a LDAP group are managed by the synchronization
+	ldapTeams := make([]portainer.Team, 0)
+	for _, team := range teams {
+		if containsGroup(groups, team.Name) {
+			ldapTeams = append(ldapTeams, team)
+		}
+	}
+
+	users, err := runner.context.userService.Users()
+	if err != nil {
+		return err
+	}
+
+	ldapUsers := make([]portainer.User, 0)
+	for _, user := range users {
+		if user.AuthenticationMethod == portainer.AuthenticationLDAP {
+			ldapUsers = append(ldapUsers, user)
+		}
+	}
+
+	missingUsers := make([]portainer.User, 0)
+	ambiguousUsers := make([]string, 0)
+	for idx := range ldapUsers {
+		user := &ldapUsers[idx]
+
+		userGroups, err := runner.context.ldapService.GetUserGroups(user.Username, settings)
+		if err == portainer.ErrLDAPUserNotFound {
+			missingUsers = append(missingUsers, *user)
+			continue
+		} else if err == portainer.ErrLDAPTooManyEntries {
+			// the user cannot be identified on the LDAP server, it is neither synchronized nor disabled
+			ambiguousUsers = append(ambiguousUsers, user.Username)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = runner.synchronizeMemberships(user, userGroups, ldapTeams, report)
+		if err != nil {
+			return err
+		}
+
+		if user.Disabled {
+			user.Disabled = false
+			err = runner.context.userService.UpdateUser(user.ID, user)
+			if err != nil {
+				return err
+			}
+			report.EnabledUsers = append(report.EnabledUsers, user.Username)
+		}
+
+		report.SynchronizedUsers++
+	}
+
+	if len(ldapUsers) > 0 && len(missingUsers) == len(ldapUsers) {
+		return portainer.Error("None of the users could be found on the LDAP server, users were not disabled. Check the LDAP search settings")
+	}
+
+	for idx := range missingUsers {
+		user := &missingUsers[idx]
+		if user.Disabled {
+			continue
+		}
+
+		user.Disabled = true
+		err = runner.context.userService.UpdateUser(user.ID, user)
+		if err != nil {
+			return err
+		}
+
+		if runner.context.jwtService != nil {
+			err = runner.context.jwtService.RevokeUserSessions(user.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		report.DisabledUsers = append(report.DisabledUsers, user.Username)
+	}
+
+	if len(report.AddedMemberships) > 0 || len(report.RemovedMemberships) > 0 {
+		err = runner.context.authorizationService.UpdateUsersAuthorizations()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(ambiguousUsers) > 0 {
+		return fmt.Errorf("Too many entries returned by the LDAP server for users %s, these users were not synchronized. Check the LDAP search settings", strings.Join(ambiguousUsers, ", "))
+	}
+
+	return nil
+}
+
+// createTeams creates a team for each LDAP group matching the team filter that is not
+// associated to a team yet. It returns the updated list of teams.
+func (runner *LDAPSyncJobRunner) createTeams(settings *portainer.LDAPSettings, groups []string, teams []portainer.Team, report *portainer.LDAPSyncReport) ([]portainer.Team, error) {
+	filter, err := regexp.Compile(settings.AutoCreateTeamsFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		if !filter.MatchString(group) || teamExists(teams, group) {
+			continue
+		}
+
+		team := &portainer.Team{
+			Name: group,
+		}
+
+		err := runner.context.teamService.CreateTeam(team)
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, *team)
+		report.CreatedTeams = append(report.CreatedTeams, team.Name)
+	}
+
+	return teams, nil
+}
+
+func (runner *LDAPSyncJobRunner) synchronizeMemberships(user *portainer.User, userGroups []string, ldapTeams []portainer.Team, report *portainer.LDAPSyncReport) error {
+	memberships, err := runner.context.teamMembershipService.TeamMembershipsByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, team := range ldapTeams {
+		membership := findMembership(memberships, team.ID)
+		isGroupMember := containsGroup(userGroups, team.Name)
+
+		if isGroupMember && membership == nil {
+			membership := &portainer.TeamMembership{
+				UserID: user.ID,
+				TeamID: team.ID,
+				Role:   portainer.TeamMember,
+			}
+
+			err := runner.context.teamMembershipService.CreateTeamMembership(membership)
+			if err != nil {
+				return err
+			}
+
+			report.AddedMemberships = append(report.AddedMemberships, portainer.LDAPSyncMembershipChange{Username: user.Username, Team: team.Name})
+		} else if !isGroupMember && membership != nil {
+			err := runner.context.teamMembershipService.DeleteTeamMembership(membership.ID)
+			if err != nil {
+				return err
+			}
+
+			report.RemovedMemberships = append(report.RemovedMemberships, portainer.LDAPSyncMembershipChange{Username: user.Username, Team: team.Name})
+		}
+	}
+
+	return nil
+}
+
+func (runner *LDAPSyncJobRunner) storeReport(report *portainer.LDAPSyncReport) error {
+	err := runner.context.ldapSyncReportService.CreateLDAPSyncReport(report)
+	if err != nil {
+		return err
+	}
+
+	reports, err := runner.context.ldapSyncReportService.LDAPSyncReports()
+	if err != nil {
+		return err
+	}
+
+	for idx := 0; idx < len(reports)-ldapSyncReportRetention; idx++ {
+		err := runner.context.ldapSyncReportService.DeleteLDAPSyncReport(reports[idx].ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func containsGroup(groups []string, name string) bool {
+	for _, group := range groups {
+		if strings.EqualFold(group, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func teamExists(teams []portainer.Team, name string) bool {
+	for _, team := range teams {
+		if strings.EqualFold(team.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func findMembership(memberships []portainer.TeamMembership, teamID portainer.TeamID) *portainer.TeamMembership {
+	for idx := range memberships {
+		if memberships[idx].TeamID == teamID {
+			return &memberships[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+// testLDAPService returns the groups of the users from memory, the users without groups are not found.
+type testLDAPService struct {
+	portainer.LDAPService
+	groups     []string
+	userGroups map[string][]string
+	userErrors map[string]error
+}
+
+func (service *testLDAPService) GetGroups(settings *portainer.LDAPSettings) ([]string, error) {
+	return service.groups, nil
+}
+
+func (service *testLDAPService) GetUserGroups(username string, settings *portainer.LDAPSettings) ([]string, error) {
+	if err, ok := service.userErrors[username]; ok {
+		return nil, err
+	}
+
+	groups, ok := service.userGroups[username]
+	if !ok {
+		return nil, portainer.ErrLDAPUserNotFound
+	}
+	return groups, nil
+}
+
+type testUserService struct {
+	portainer.UserService
+	users []portainer.User
+}
+
+func (service *testUserService) User(ID portainer.UserID) (*portainer.User, error) {
+	for _, user := range service.users {
+		if user.ID == ID {
+			return &user, nil
+		}
+	}
+	return nil, portainer.ErrObjectNotFound
+}
+
+func (service *testUserService) Users() ([]portainer.User, error) {
+	return append([]portainer.User{}, service.users...), nil
+}
+
+func (service *testUserService) UpdateUser(ID portainer.UserID, user *portainer.User) error {
+	for idx := range service.users {
+		if service.users[idx].ID == ID {
+			service.users[idx] = *user
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+type testTeamService struct {
+	portainer.TeamService
+	teams []portainer.Team
+}
+
+func (service *testTeamService) Teams() ([]portainer.Team, error) {
+	return append([]portainer.Team{}, service.teams...), nil
+}
+
+// testTeamMembershipService stores the team memberships in memory.
+type testTeamMembershipService struct {
+	portainer.TeamMembershipService
+	memberships []portainer.TeamMembership
+}
+
+func (service *testTeamMembershipService) TeamMembershipsByUserID(userID portainer.UserID) ([]portainer.TeamMembership, error) {
+	memberships := make([]portainer.TeamMembership, 0)
+	for _, membership := range service.memberships {
+		if membership.UserID == userID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (service *testTeamMembershipService) CreateTeamMembership(membership *portainer.TeamMembership) error {
+	membership.ID = portainer.TeamMembershipID(len(service.memberships) + 100)
+	service.memberships = append(service.memberships, *membership)
+	return nil
+}
+
+func (service *testTeamMembershipService) DeleteTeamMembership(ID portainer.TeamMembershipID) error {
+	for idx := range service.memberships {
+		if service.memberships[idx].ID == ID {
+			service.memberships = append(service.memberships[:idx], service.memberships[idx+1:]...)
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+func (service *testTeamMembershipService) isMember(userID portainer.UserID, teamID portainer.TeamID) bool {
+	for _, membership := range service.memberships {
+		if membership.UserID == userID && membership.TeamID == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+type testEndpointService struct {
+	portainer.EndpointService
+}
+
+func (service *testEndpointService) Endpoints() ([]portainer.Endpoint, error) {
+	return []portainer.Endpoint{}, nil
+}
+
+type testEndpointGroupService struct {
+	portainer.EndpointGroupService
+}
+
+func (service *testEndpointGroupService) EndpointGroups() ([]portainer.EndpointGroup, error) {
+	return []portainer.EndpointGroup{}, nil
+}
+
+type testRoleService struct {
+	portainer.RoleService
+}
+
+func (service *testRoleService) Roles() ([]portainer.Role, error) {
+	return []portainer.Role{}, nil
+}
+
+func newTestLDAPSyncJobRunner(ldapService *testLDAPService, userService *testUserService, teamMembershipService *testTeamMembershipService) *LDAPSyncJobRunner {
+	teamService := &testTeamService{teams: []portainer.Team{{ID: 1, Name: "dev"}, {ID: 2, Name: "ops"}, {ID: 3, Name: "local"}}}
+
+	authorizationService := portainer.NewAuthorizationService(&portainer.AuthorizationServiceParameters{
+		EndpointService:       &testEndpointService{},
+		EndpointGroupService:  &testEndpointGroupService{},
+		RoleService:           &testRoleService{},
+		TeamMembershipService: teamMembershipService,
+		UserService:           userService,
+	})
+
+	context := NewLDAPSyncJobContext(nil, userService, teamService, teamMembershipService, ldapService, nil, nil, authorizationService)
+	return NewLDAPSyncJobRunner(&portainer.Schedule{}, context)
+}
+
+func newTestLDAPSyncReport() *portainer.LDAPSyncReport {
+	return &portainer.LDAPSyncReport{
+		AddedMemberships:   make([]portainer.LDAPSyncMembershipChange, 0),
+		RemovedMemberships: make([]portainer.LDAPSyncMembershipChange, 0),
+		DisabledUsers:      make([]string, 0),
+		EnabledUsers:       make([]string, 0),
+	}
+}
+
+func TestLDAPSyncMemberships(t *testing.T) {
+	ldapService := &testLDAPService{
+		groups: []string{"dev", "ops"},
+		userGroups: map[string][]string{
+			"alice": {"dev"},
+			"bob":   {"OPS", "dev", "unknown"},
+			"erin":  {},
+		},
+	}
+
+	userService := &testUserService{users: []portainer.User{
+		{ID: 1, Username: "alice", AuthenticationMethod: portainer.AuthenticationLDAP},
+		{ID: 2, Username: "bob", AuthenticationMethod: portainer.AuthenticationLDAP},
+		{ID: 3, Username: "carol", AuthenticationMethod: portainer.AuthenticationInternal},
+		{ID: 4, Username: "dave", AuthenticationMethod: portainer.AuthenticationLDAP},
+		{ID: 5, Username: "erin", AuthenticationMethod: portainer.AuthenticationLDAP, Disabled: true},
+	}}
+
+	teamMembershipService := &testTeamMembershipService{memberships: []portainer.TeamMembership{
+		{ID: 1, UserID: 1, TeamID: 2},
+		{ID: 2, UserID: 1, TeamID: 3},
+		{ID: 3, UserID: 3, TeamID: 2},
+	}}
+
+	runner := newTestLDAPSyncJobRunner(ldapService, userService, teamMembershipService)
+	report := newTestLDAPSyncReport()
+
+	err := runner.synchronize(&portainer.LDAPSettings{}, report)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	memberships := []struct {
+		userID portainer.UserID
+		teamID portainer.TeamID
+		member bool
+	}{
+		{userID: 1, teamID: 1, member: true},
+		{userID: 1, teamID: 2, member: false},
+		// the memberships of the teams that are not associated to a LDAP group are not managed
+		{userID: 1, teamID: 3, member: true},
+		{userID: 2, teamID: 1, member: true},
+		{userID: 2, teamID: 2, member: true},
+		// the memberships of the internal users are not managed
+		{userID: 3, teamID: 2, member: true},
+	}
+
+	for _, m := range memberships {
+		if teamMembershipService.isMember(m.userID, m.teamID) != m.member {
+			t.Errorf("expected membership of user %d in team %d to be %t", m.userID, m.teamID, m.member)
+		}
+	}
+
+	if len(report.AddedMemberships) != 3 || len(report.RemovedMemberships) != 1 || report.SynchronizedUsers != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if len(report.DisabledUsers) != 1 || report.DisabledUsers[0] != "dave" || !userService.users[3].Disabled {
+		t.Errorf("expected the missing user to be disabled, got %v", report.DisabledUsers)
+	}
+
+	if len(report.EnabledUsers) != 1 || report.EnabledUsers[0] != "erin" || userService.users[4].Disabled {
+		t.Errorf("expected the user found again to be enabled, got %v", report.EnabledUsers)
+	}
+}
+
+func TestLDAPSyncUsersNotDisabled(t *testing.T) {
+	cases := []struct {
+		name       string
+		userErrors map[string]error
+		userGroups map[string][]string
+	}{
+		{
+			name: "single missing user",
+		},
+		{
+			name:       "too many entries",
+			userErrors: map[string]error{"alice": portainer.ErrLDAPTooManyEntries},
+			userGroups: map[string][]string{"bob": {"dev"}},
+		},
+	}
+
+	for _, c := range cases {
+		ldapService := &testLDAPService{groups: []string{"dev"}, userGroups: c.userGroups, userErrors: c.userErrors}
+		userService := &testUserService{users: []portainer.User{
+			{ID: 1, Username: "alice", AuthenticationMethod: portainer.AuthenticationLDAP},
+		}}
+		if c.userGroups != nil {
+			userService.users = append(userService.users, portainer.User{ID: 2, Username: "bob", AuthenticationMethod: portainer.AuthenticationLDAP})
+		}
+
+		runner := newTestLDAPSyncJobRunner(ldapService, userService, &testTeamMembershipService{})
+		report := newTestLDAPSyncReport()
+
+		err := runner.synchronize(&portainer.LDAPSettings{}, report)
+		if err == nil {
+			t.Errorf("expected an error (%s)", c.name)
+		}
+
+		if len(report.DisabledUsers) != 0 || userService.users[0].Disabled {
+			t.Errorf("expected the user not to be disabled (%s)", c.name)
+		}
+	}
+}
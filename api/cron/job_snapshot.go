@@ -1,6 +1,7 @@
 package cron
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/portainer/portainer/api"
@@ -16,13 +17,15 @@ type SnapshotJobRunner struct {
 type SnapshotJobContext struct {
 	endpointService portainer.EndpointService
 	snapshotter     portainer.Snapshotter
+	alerter         portainer.Alerter
 }
 
 // NewSnapshotJobContext returns a new context that can be used to execute a SnapshotJob
-func NewSnapshotJobContext(endpointService portainer.EndpointService, snapshotter portainer.Snapshotter) *SnapshotJobContext {
+func NewSnapshotJobContext(endpointService portainer.EndpointService, snapshotter portainer.Snapshotter, alerter portainer.Alerter) *SnapshotJobContext {
 	return &SnapshotJobContext{
 		endpointService: endpointService,
 		snapshotter:     snapshotter,
+		alerter:         alerter,
 	}
 }
 
@@ -44,6 +47,8 @@ func (runner *SnapshotJobRunner) GetSchedule() *portainer.Schedule {
 // create a snapshot of each one of them.
 // As a snapshot can be a long process, to avoid any concurrency issue we
 // retrieve the latest version of the endpoint right after a snapshot.
+// Alerts are sent when the status of an endpoint changes or when the number
+// of stopped containers increases.
 func (runner *SnapshotJobRunner) Run() {
 	go func() {
 		endpoints, err := runner.context.endpointService.Endpoints()
@@ -65,6 +70,9 @@ func (runner *SnapshotJobRunner) Run() {
 				continue
 			}
 
+			previousStatus := latestEndpointReference.Status
+			previousSnapshots := latestEndpointReference.Snapshots
+
 			latestEndpointReference.Status = portainer.EndpointStatusUp
 			if snapshotError != nil {
 				log.Printf("background schedule error (endpoint snapshot). Unable to create snapshot (endpoint=%s, URL=%s) (err=%s)\n", endpoint.Name, endpoint.URL, snapshotError)
@@ -75,6 +83,8 @@ func (runner *SnapshotJobRunner) Run() {
 				latestEndpointReference.Snapshots = []portainer.Snapshot{*snapshot}
 			}
 
+			runner.notifyChanges(latestEndpointReference, previousStatus, previousSnapshots, snapshot)
+
 			err = runner.context.endpointService.UpdateEndpoint(latestEndpointReference.ID, latestEndpointReference)
 			if err != nil {
 				log.Printf("background schedule error (endpoint snapshot). Unable to update endpoint (endpoint=%s, URL=%s) (err=%s)\n", endpoint.Name, endpoint.URL, err)
@@ -83,3 +93,37 @@ func (runner *SnapshotJobRunner) Run() {
 		}
 	}()
 }
+
+func (runner *SnapshotJobRunner) notifyChanges(endpoint *portainer.Endpoint, previousStatus portainer.EndpointStatus, previousSnapshots []portainer.Snapshot, snapshot *portainer.Snapshot) {
+	if runner.context.alerter == nil {
+		return
+	}
+
+	if previousStatus != endpoint.Status {
+		event := &portainer.AlertEvent{
+			Type:         portainer.EndpointUpAlertEvent,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			Message:      "Endpoint is up",
+		}
+
+		if endpoint.Status == portainer.EndpointStatusDown {
+			event.Type = portainer.EndpointDownAlertEvent
+			event.Message = "Endpoint is down"
+		}
+
+		runner.context.alerter.Notify(event)
+	}
+
+	if snapshot != nil && len(previousSnapshots) > 0 {
+		previousSnapshot := previousSnapshots[len(previousSnapshots)-1]
+		if snapshot.StoppedContainerCount > previousSnapshot.StoppedContainerCount {
+			runner.context.alerter.Notify(&portainer.AlertEvent{
+				Type:         portainer.StoppedContainersIncreaseAlertEvent,
+				EndpointID:   endpoint.ID,
+				EndpointName: endpoint.Name,
+				Message:      fmt.Sprintf("Stopped containers increased from %d to %d", previousSnapshot.StoppedContainerCount, snapshot.StoppedContainerCount),
+			})
+		}
+	}
+}
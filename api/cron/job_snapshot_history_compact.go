@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// SnapshotHistoryCompactJobRunner is used to run a SnapshotHistoryCompactJob
+type SnapshotHistoryCompactJobRunner struct {
+	schedule *portainer.Schedule
+	context  *SnapshotHistoryCompactJobContext
+}
+
+// SnapshotHistoryCompactJobContext represents the context of execution of a SnapshotHistoryCompactJob
+type SnapshotHistoryCompactJobContext struct {
+	settingsService        portainer.SettingsService
+	snapshotHistoryService portainer.SnapshotHistoryService
+	endpointEventService   portainer.EndpointEventService
+}
+
+// NewSnapshotHistoryCompactJobContext returns a new context that can be used to execute a SnapshotHistoryCompactJob
+func NewSnapshotHistoryCompactJobContext(settingsService portainer.SettingsService, snapshotHistoryService portainer.SnapshotHistoryService, endpointEventService portainer.EndpointEventService) *SnapshotHistoryCompactJobContext {
+	return &SnapshotHistoryCompactJobContext{
+		settingsService:        settingsService,
+		snapshotHistoryService: snapshotHistoryService,
+		endpointEventService:   endpointEventService,
+	}
+}
+
+// NewSnapshotHistoryCompactJobRunner returns a new runner that can be scheduled
+func NewSnapshotHistoryCompactJobRunner(schedule *portainer.Schedule, context *SnapshotHistoryCompactJobContext) *SnapshotHistoryCompactJobRunner {
+	return &SnapshotHistoryCompactJobRunner{
+		schedule: schedule,
+		context:  context,
+	}
+}
+
+// GetSchedule returns the schedule associated to the runner
+func (runner *SnapshotHistoryCompactJobRunner) GetSchedule() *portainer.Schedule {
+	return runner.schedule
+}
+
+// Run triggers the execution of the schedule.
+// It will apply the retention and the downsampling defined in the settings to the snapshot history.
+// The retention also applies to the endpoint events. The history is compacted independently of the
+// snapshot job as records are also created by manual snapshots and by the Edge endpoints.
+func (runner *SnapshotHistoryCompactJobRunner) Run() {
+	go func() {
+		err := runner.compactSnapshotHistory(time.Now())
+		if err != nil {
+			log.Printf("background schedule error (snapshot history compaction). Unable to compact snapshot history (err=%s)\n", err)
+		}
+	}()
+}
+
+func (runner *SnapshotHistoryCompactJobRunner) compactSnapshotHistory(now time.Time) error {
+	settings, err := runner.context.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	var downsamplingBefore, downsamplingInterval, retentionBefore int64
+	if settings.SnapshotHistory.DownsamplingAge != "" && settings.SnapshotHistory.DownsamplingInterval != "" {
+		age, err := time.ParseDuration(settings.SnapshotHistory.DownsamplingAge)
+		if err != nil {
+			return err
+		}
+
+		interval, err := time.ParseDuration(settings.SnapshotHistory.DownsamplingInterval)
+		if err != nil {
+			return err
+		}
+
+		downsamplingBefore = now.Add(-age).Unix()
+		downsamplingInterval = int64(interval.Seconds())
+	}
+
+	if settings.SnapshotHistory.Retention != "" {
+		retention, err := time.ParseDuration(settings.SnapshotHistory.Retention)
+		if err != nil {
+			return err
+		}
+
+		retentionBefore = now.Add(-retention).Unix()
+
+		err = runner.context.endpointEventService.PruneEndpointEvents(retentionBefore)
+		if err != nil {
+			return err
+		}
+	}
+
+	return runner.context.snapshotHistoryService.CompactSnapshotRecords(downsamplingBefore, downsamplingInterval, retentionBefore)
+}
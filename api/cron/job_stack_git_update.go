@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/stacks"
+)
+
+// StackGitUpdateJobRunner is used to run a StackGitUpdateJob
+type StackGitUpdateJobRunner struct {
+	schedule *portainer.Schedule
+	context  *StackGitUpdateJobContext
+	// running is set while an execution is in progress
+	running int32
+}
+
+// StackGitUpdateJobContext represents the context of execution of a StackGitUpdateJob
+type StackGitUpdateJobContext struct {
+	stackService     portainer.StackService
+	endpointService  portainer.EndpointService
+	registryService  portainer.RegistryService
+	dockerHubService portainer.DockerHubService
+	gitService       portainer.GitService
+	fileService      portainer.FileService
+	stackDeployer    portainer.StackDeployer
+	versionManager   portainer.StackVersionManager
+}
+
+// NewStackGitUpdateJobContext returns a new context that can be used to execute a StackGitUpdateJob
+func NewStackGitUpdateJobContext(stackService portainer.StackService, endpointService portainer.EndpointService, registryService portainer.RegistryService, dockerHubService portainer.DockerHubService, gitService portainer.GitService, fileService portainer.FileService, stackDeployer portainer.StackDeployer, versionManager portainer.StackVersionManager) *StackGitUpdateJobContext {
+	return &StackGitUpdateJobContext{
+		stackService:     stackService,
+		endpointService:  endpointService,
+		registryService:  registryService,
+		dockerHubService: dockerHubService,
+		gitService:       gitService,
+		fileService:      fileService,
+		stackDeployer:    stackDeployer,
+		versionManager:   versionManager,
+	}
+}
+
+// NewStackGitUpdateJobRunner returns a new runner that can be scheduled
+func NewStackGitUpdateJobRunner(schedule *portainer.Schedule, context *StackGitUpdateJobContext) *StackGitUpdateJobRunner {
+	return &StackGitUpdateJobRunner{
+		schedule: schedule,
+		context:  context,
+	}
+}
+
+// GetSchedule returns the schedule associated to the runner
+func (runner *StackGitUpdateJobRunner) GetSchedule() *portainer.Schedule {
+	return runner.schedule
+}
+
+// Run triggers the execution of the schedule.
+// It will iterate through all the stacks created from a Git repository with automatic
+// updates enabled and redeploy each stack for which a new commit is available on
+// the tracked reference. The execution is skipped while the previous one is still in progress.
+func (runner *StackGitUpdateJobRunner) Run() {
+	if !atomic.CompareAndSwapInt32(&runner.running, 0, 1) {
+		log.Println("background schedule warning (stack git update). Previous execution still in progress, skipping")
+		return
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&runner.running, 0)
+
+		stacks, err := runner.context.stackService.Stacks()
+		if err != nil {
+			log.Printf("background schedule error (stack git update). Unable to retrieve stack list (err=%s)\n", err)
+			return
+		}
+
+		for _, stack := range stacks {
+			if stack.GitConfig == nil || !stack.GitConfig.AutoUpdate {
+				continue
+			}
+
+			err := runner.updateStack(&stack)
+			if err != nil {
+				log.Printf("background schedule error (stack git update). Unable to update stack (stack=%s, repository=%s) (err=%s)\n", stack.Name, stack.GitConfig.URL, err)
+			}
+		}
+	}()
+}
+
+func (runner *StackGitUpdateJobRunner) updateStack(stack *portainer.Stack) error {
+	endpoint, err := runner.context.endpointService.Endpoint(stack.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	unlock := runner.context.stackDeployer.LockStack(stack.ID)
+	defer unlock()
+
+	// the stack may have been refreshed by a webhook while the lock was being acquired
+	stack, err = runner.context.stackService.Stack(stack.ID)
+	if err != nil || stack.GitConfig == nil || !stack.GitConfig.AutoUpdate {
+		return err
+	}
+
+	updated, err := stacks.RefreshGitRepository(stack, runner.context.gitService, runner.context.fileService)
+	if err != nil || !updated {
+		return err
+	}
+
+	registries, err := runner.context.registryService.Registries()
+	if err != nil {
+		return err
+	}
+
+	dockerhub, err := runner.context.dockerHubService.DockerHub()
+	if err != nil {
+		return err
+	}
+
+	switch stack.Type {
+	case portainer.DockerSwarmStack:
+		err = runner.context.stackDeployer.DeploySwarmStack(stack, endpoint, registries, dockerhub, false)
+	case portainer.DockerComposeStack:
+		err = runner.context.stackDeployer.DeployComposeStack(stack, endpoint, registries, dockerhub, false)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = runner.context.versionManager.CreateStackVersion(stack, "git auto-update")
+	if err != nil {
+		return err
+	}
+
+	return runner.updateStackCommitHash(stack.ID, stack.GitConfig.CommitHash)
+}
+
+// updateStackCommitHash only persists the deployed commit hash on the stack stored in the database,
+// so that the changes made to the stack while it was being redeployed are not overwritten.
+func (runner *StackGitUpdateJobRunner) updateStackCommitHash(stackID portainer.StackID, commitHash string) error {
+	return runner.context.stackService.UpdateStackFunc(stackID, func(stack *portainer.Stack) {
+		if stack.GitConfig != nil {
+			stack.GitConfig.CommitHash = commitHash
+		}
+	})
+}
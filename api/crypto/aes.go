@@ -0,0 +1,79 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// AESKeySize represents the size in bytes of the keys used by the AESService
+const AESKeySize = 32
+
+// AESService represents a service used to encrypt and decrypt data using AES-256 in GCM mode.
+type AESService struct {
+	aead cipher.AEAD
+}
+
+// NewAESService returns a pointer to a new instance of AESService using the specified key.
+func NewAESService(key []byte) (*AESService, error) {
+	if len(key) != AESKeySize {
+		return nil, errors.New("invalid encryption key size")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESService{aead: aead}, nil
+}
+
+// GenerateAESKey generates a random key that can be used with the AESService.
+func GenerateAESKey() ([]byte, error) {
+	key := make([]byte, AESKeySize)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt encrypts the data and returns the nonce and the cipher text encoded in base64.
+func (service *AESService) Encrypt(data string) (string, error) {
+	nonce := make([]byte, service.aead.NonceSize())
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := service.aead.Seal(nonce, nonce, []byte(data), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts data previously encrypted with Encrypt.
+func (service *AESService) Decrypt(data string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+
+	nonceSize := service.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("invalid encrypted data")
+	}
+
+	plain, err := service.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
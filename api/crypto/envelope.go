@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// wrappedKeyHeader is prepended to a data encryption key encrypted with a key encryption key.
+// It is followed by the base64 encoded salt used to derive the key encryption key, a separator
+// and the encrypted data encryption key.
+var wrappedKeyHeader = []byte("PORTAINER_WRAPPED_KEY_V2:")
+
+const (
+	wrappedKeySeparator = ':'
+	keySaltLength       = 16
+)
+
+// ErrInvalidKeyEncryptionKey is returned when a wrapped key cannot be decrypted with the specified secret
+var ErrInvalidKeyEncryptionKey = errors.New("unable to decrypt the data encryption key. Invalid encryption key")
+
+// deriveKeyEncryptionKey derives the key used to wrap a data encryption key
+// from a secret supplied by the user and a random salt.
+func deriveKeyEncryptionKey(secret, salt []byte) ([]byte, error) {
+	return scrypt.Key(bytes.TrimSpace(secret), salt, 32768, 8, 1, 32)
+}
+
+// WrapKey encrypts a data encryption key with a key derived from the specified secret.
+// The salt used to derive the key is stored alongside the wrapped key.
+func WrapKey(secret, key []byte) ([]byte, error) {
+	salt := make([]byte, keySaltLength)
+	_, err := io.ReadFull(rand.Reader, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	keyEncryptionKey, err := deriveKeyEncryptionKey(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := NewAESService(keyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	wrappedKey, err := service.Encrypt(string(key))
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte{}, wrappedKeyHeader...)
+	data = append(data, base64.StdEncoding.EncodeToString(salt)...)
+	data = append(data, wrappedKeySeparator)
+	return append(data, wrappedKey...), nil
+}
+
+// IsWrappedKey returns true when the data contains a key encrypted with WrapKey.
+func IsWrappedKey(data []byte) bool {
+	return bytes.HasPrefix(data, wrappedKeyHeader)
+}
+
+// UnwrapKey decrypts a data encryption key previously encrypted with WrapKey.
+func UnwrapKey(secret, data []byte) ([]byte, error) {
+	parts := bytes.SplitN(bytes.TrimPrefix(data, wrappedKeyHeader), []byte{wrappedKeySeparator}, 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidKeyEncryptionKey
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(string(parts[0]))
+	if err != nil || len(salt) != keySaltLength {
+		return nil, ErrInvalidKeyEncryptionKey
+	}
+
+	keyEncryptionKey, err := deriveKeyEncryptionKey(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := NewAESService(keyEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := service.Decrypt(string(parts[1]))
+	if err != nil {
+		return nil, ErrInvalidKeyEncryptionKey
+	}
+
+	return []byte(key), nil
+}
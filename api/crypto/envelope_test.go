@@ -0,0 +1,52 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrapKey(t *testing.T) {
+	key, err := GenerateAESKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	wrappedKey, err := WrapKey([]byte("secret\n"), key)
+	if err != nil {
+		t.Fatalf("unable to wrap key: %s", err)
+	}
+
+	if !IsWrappedKey(wrappedKey) || IsWrappedKey(key) {
+		t.Fatalf("unable to distinguish wrapped keys from plain keys")
+	}
+
+	unwrappedKey, err := UnwrapKey([]byte("secret"), wrappedKey)
+	if err != nil {
+		t.Fatalf("unable to unwrap key: %s", err)
+	}
+
+	if !bytes.Equal(unwrappedKey, key) {
+		t.Errorf("unwrapped key does not match the original key")
+	}
+
+	_, err = UnwrapKey([]byte("other"), wrappedKey)
+	if err != ErrInvalidKeyEncryptionKey {
+		t.Errorf("expected a wrong secret to be rejected, got %v", err)
+	}
+
+	otherWrappedKey, err := WrapKey([]byte("secret"), key)
+	if err != nil {
+		t.Fatalf("unable to wrap key: %s", err)
+	}
+
+	if bytes.Equal(otherWrappedKey, wrappedKey) {
+		t.Errorf("expected a random salt to be used for each wrapped key")
+	}
+}
+
+func TestUnwrapKeyInvalidData(t *testing.T) {
+	_, err := UnwrapKey([]byte("secret"), append([]byte{}, wrappedKeyHeader...))
+	if err != ErrInvalidKeyEncryptionKey {
+		t.Errorf("expected a malformed wrapped key to be rejected, got %v", err)
+	}
+}
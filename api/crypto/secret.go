@@ -0,0 +1,36 @@
+package crypto
+
+import (
+	"strings"
+
+	"github.com/portainer/portainer/api"
+)
+
+// encryptedSecretPrefix is prepended to the encrypted secrets so that they can be distinguished
+// from the secrets stored in plain text before encryption was introduced.
+const encryptedSecretPrefix = "enc:v1:"
+
+// EncryptSecret encrypts a secret before it is stored inside the database.
+// Empty secrets and secrets that are already encrypted are returned as is.
+func EncryptSecret(encryptionService portainer.EncryptionService, secret string) (string, error) {
+	if encryptionService == nil || secret == "" || strings.HasPrefix(secret, encryptedSecretPrefix) {
+		return secret, nil
+	}
+
+	encryptedSecret, err := encryptionService.Encrypt(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedSecretPrefix + encryptedSecret, nil
+}
+
+// DecryptSecret decrypts a secret encrypted with EncryptSecret.
+// Secrets stored in plain text are returned as is.
+func DecryptSecret(encryptionService portainer.EncryptionService, secret string) (string, error) {
+	if encryptionService == nil || !strings.HasPrefix(secret, encryptedSecretPrefix) {
+		return secret, nil
+	}
+
+	return encryptionService.Decrypt(strings.TrimPrefix(secret, encryptedSecretPrefix))
+}
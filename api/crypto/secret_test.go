@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestAESService(t *testing.T) *AESService {
+	key, err := GenerateAESKey()
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	service, err := NewAESService(key)
+	if err != nil {
+		t.Fatalf("unable to create service: %s", err)
+	}
+
+	return service
+}
+
+func TestEncryptSecret(t *testing.T) {
+	service := newTestAESService(t)
+
+	encrypted, err := EncryptSecret(service, "password")
+	if err != nil {
+		t.Fatalf("unable to encrypt secret: %s", err)
+	}
+
+	if !strings.HasPrefix(encrypted, encryptedSecretPrefix) || strings.Contains(encrypted, "password") {
+		t.Fatalf("unexpected encrypted secret: %s", encrypted)
+	}
+
+	decrypted, err := DecryptSecret(service, encrypted)
+	if err != nil {
+		t.Fatalf("unable to decrypt secret: %s", err)
+	}
+
+	if decrypted != "password" {
+		t.Errorf("unexpected decrypted secret: got %s, want password", decrypted)
+	}
+
+	again, err := EncryptSecret(service, encrypted)
+	if err != nil || again != encrypted {
+		t.Errorf("expected an encrypted secret not to be encrypted again")
+	}
+
+	empty, err := EncryptSecret(service, "")
+	if err != nil || empty != "" {
+		t.Errorf("expected an empty secret to be returned as is")
+	}
+}
+
+func TestDecryptSecret(t *testing.T) {
+	service := newTestAESService(t)
+
+	plain, err := DecryptSecret(service, "password")
+	if err != nil || plain != "password" {
+		t.Errorf("expected a plain text secret to be returned as is")
+	}
+
+	encrypted, err := EncryptSecret(service, "password")
+	if err != nil {
+		t.Fatalf("unable to encrypt secret: %s", err)
+	}
+
+	_, err = DecryptSecret(newTestAESService(t), encrypted)
+	if err == nil {
+		t.Errorf("expected a secret encrypted with another key to be rejected")
+	}
+}
@@ -0,0 +1,369 @@
+package docker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/quota"
+)
+
+const (
+	swarmStackNameLabel   = "com.docker.stack.namespace"
+	swarmServiceIDLabel   = "com.docker.swarm.service.id"
+	composeStackNameLabel = "com.docker.compose.project"
+)
+
+type (
+	// QuotaChecker verifies that the resources requested on an endpoint by a user do not exceed
+	// the quotas associated to the user and to the teams of the user.
+	QuotaChecker struct {
+		clientFactory          *ClientFactory
+		quotaService           portainer.QuotaService
+		teamMembershipService  portainer.TeamMembershipService
+		resourceControlService portainer.ResourceControlService
+		mu                     sync.Mutex
+		// containerResources caches the resources of the containers of each endpoint, so that only the
+		// containers created since the last check need to be inspected. The container replaced by a request
+		// is removed from the cache. The cached maps are replaced and never modified.
+		containerResources map[portainer.EndpointID]map[string]quota.Resources
+		endpointLocksMutex sync.Mutex
+		endpointLocks      map[portainer.EndpointID]*sync.Mutex
+	}
+
+	// ownedResources associates the resources consumed by a Docker resource to its resource control
+	ownedResources struct {
+		resourceControl *portainer.ResourceControl
+		resources       quota.Resources
+	}
+)
+
+// NewQuotaChecker returns a new instance of QuotaChecker.
+func NewQuotaChecker(clientFactory *ClientFactory, quotaService portainer.QuotaService, teamMembershipService portainer.TeamMembershipService, resourceControlService portainer.ResourceControlService) *QuotaChecker {
+	return &QuotaChecker{
+		clientFactory:          clientFactory,
+		quotaService:           quotaService,
+		teamMembershipService:  teamMembershipService,
+		resourceControlService: resourceControlService,
+		containerResources:     make(map[portainer.EndpointID]map[string]quota.Resources),
+		endpointLocks:          make(map[portainer.EndpointID]*sync.Mutex),
+	}
+}
+
+// LockEndpoint acquires the quota lock of an endpoint. The lock must be held from the quota check of a request
+// until the requested resources are created, so that concurrent requests cannot exceed a quota together.
+// It returns the function releasing the lock.
+func (checker *QuotaChecker) LockEndpoint(endpointID portainer.EndpointID) func() {
+	checker.endpointLocksMutex.Lock()
+	endpointLock, ok := checker.endpointLocks[endpointID]
+	if !ok {
+		endpointLock = &sync.Mutex{}
+		checker.endpointLocks[endpointID] = endpointLock
+	}
+	checker.endpointLocksMutex.Unlock()
+
+	endpointLock.Lock()
+	return endpointLock.Unlock
+}
+
+// Check returns a description of the quota exceeded by the resources requested by the user on the endpoint,
+// or an empty string when the request respects the quotas associated to the user and to the teams of the user.
+// The caller must hold the lock of the endpoint, see LockEndpoint.
+func (checker *QuotaChecker) Check(endpoint *portainer.Endpoint, userID portainer.UserID, request *quota.Request) (string, error) {
+	teamMemberships, err := checker.teamMembershipService.TeamMembershipsByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+
+	quotas, err := checker.quotaService.Quotas()
+	if err != nil {
+		return "", err
+	}
+
+	applicableQuotas := quota.ApplicableQuotas(quotas, endpoint, userID, teamMemberships)
+	if len(applicableQuotas) == 0 {
+		return "", nil
+	}
+
+	cli, err := checker.clientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return "", err
+	}
+	defer cli.Close()
+
+	requested := request.Resources
+	if request.PerNodeResources != (quota.Resources{}) {
+		nodes, err := cli.NodeList(context.Background(), types.NodeListOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		perNodeResources := request.PerNodeResources.Multiply(len(nodes))
+		requested.Add(&perNodeResources)
+	}
+
+	resourceControls, err := checker.resourceControlService.ResourceControls()
+	if err != nil {
+		return "", err
+	}
+
+	resources, err := checker.ownedEndpointResources(cli, endpoint, request, &requested, resourceControls)
+	if err != nil {
+		return "", err
+	}
+
+	for _, applicableQuota := range applicableQuotas {
+		teamMembers, err := checker.teamMembers(&applicableQuota)
+		if err != nil {
+			return "", err
+		}
+
+		usage := quota.Resources{}
+		for _, resource := range resources {
+			if quota.OwnsResource(&applicableQuota, resource.resourceControl, teamMembers) {
+				usage.Add(&resource.resources)
+			}
+		}
+
+		violation := quota.Violation(&applicableQuota, &usage, &requested)
+		if violation != "" {
+			return violation, nil
+		}
+	}
+
+	return "", nil
+}
+
+// ReleasesResource returns true when the update of a resource control by the user removes the resource
+// from the resources counted by one of the quotas associated to the user or to the teams of the user,
+// for instance when the resource is made public.
+func (checker *QuotaChecker) ReleasesResource(userID portainer.UserID, previous, updated *portainer.ResourceControl) (bool, error) {
+	teamMemberships, err := checker.teamMembershipService.TeamMembershipsByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+
+	quotas, err := checker.quotaService.Quotas()
+	if err != nil {
+		return false, err
+	}
+
+	for _, userQuota := range quota.UserQuotas(quotas, userID, teamMemberships) {
+		teamMembers, err := checker.teamMembers(&userQuota)
+		if err != nil {
+			return false, err
+		}
+
+		if quota.OwnsResource(&userQuota, previous, teamMembers) && !quota.OwnsResource(&userQuota, updated, teamMembers) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (checker *QuotaChecker) teamMembers(userQuota *portainer.Quota) (map[portainer.UserID]bool, error) {
+	teamMembers := make(map[portainer.UserID]bool)
+	if userQuota.TeamID == 0 {
+		return teamMembers, nil
+	}
+
+	memberships, err := checker.teamMembershipService.TeamMembershipsByTeamID(userQuota.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, membership := range memberships {
+		teamMembers[membership.UserID] = true
+	}
+
+	return teamMembers, nil
+}
+
+// ownedEndpointResources returns the resources of the endpoint associated to a resource control, directly
+// or through the stack they belong to. Only the type of resources matching the request are retrieved and
+// the resources replaced by the request are ignored.
+func (checker *QuotaChecker) ownedEndpointResources(cli *client.Client, endpoint *portainer.Endpoint, request *quota.Request, requested *quota.Resources, resourceControls []portainer.ResourceControl) ([]ownedResources, error) {
+	resources := make([]ownedResources, 0)
+
+	if requested.Containers > 0 {
+		containerResources, err := checker.containerOwnedResources(cli, endpoint, request, resourceControls)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, containerResources...)
+
+		serviceResources, err := serviceOwnedResources(cli, request, resourceControls)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, serviceResources...)
+	}
+
+	if requested.Volumes > 0 {
+		volumes, err := cli.VolumeList(context.Background(), filters.Args{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, volume := range volumes.Volumes {
+			if replacedResource(request, volume.Name, volume.Labels) {
+				continue
+			}
+
+			resourceControl := findResourceControl(volume.Name, portainer.VolumeResourceControl, volume.Labels, resourceControls)
+			if resourceControl == nil {
+				continue
+			}
+
+			resources = append(resources, ownedResources{
+				resourceControl: resourceControl,
+				resources:       quota.Resources{Volumes: 1},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// containerOwnedResources returns the resources of the standalone containers of the endpoint. The containers
+// created by a Swarm service are counted with their service. The resources of the containers are cached.
+func (checker *QuotaChecker) containerOwnedResources(cli *client.Client, endpoint *portainer.Endpoint, request *quota.Request, resourceControls []portainer.ResourceControl) ([]ownedResources, error) {
+	containers, err := cli.ContainerList(context.Background(), types.ContainerListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	checker.mu.Lock()
+	cachedResources := checker.containerResources[endpoint.ID]
+	checker.mu.Unlock()
+
+	endpointResources := make(map[string]quota.Resources)
+	resources := make([]ownedResources, 0)
+	for _, container := range containers {
+		if container.Labels[swarmServiceIDLabel] != "" || replacedResource(request, container.ID, container.Labels) {
+			continue
+		}
+
+		resourceControl := findResourceControl(container.ID, portainer.ContainerResourceControl, container.Labels, resourceControls)
+		if resourceControl == nil {
+			continue
+		}
+
+		containerResources, ok := cachedResources[container.ID]
+		if !ok {
+			containerResources = quota.Resources{Containers: 1}
+
+			containerDetails, err := cli.ContainerInspect(context.Background(), container.ID)
+			if err == nil {
+				containerResources.Memory = containerDetails.HostConfig.Memory
+				containerResources.NanoCPUs = quota.ContainerNanoCPUs(containerDetails.HostConfig.NanoCPUs, containerDetails.HostConfig.CPUQuota, containerDetails.HostConfig.CPUPeriod)
+			}
+		}
+		endpointResources[container.ID] = containerResources
+
+		resources = append(resources, ownedResources{
+			resourceControl: resourceControl,
+			resources:       containerResources,
+		})
+	}
+
+	checker.mu.Lock()
+	checker.containerResources[endpoint.ID] = endpointResources
+	checker.mu.Unlock()
+
+	return resources, nil
+}
+
+// serviceOwnedResources returns the resources of the services of the endpoint. A global service
+// is counted once for each node of the cluster.
+func serviceOwnedResources(cli *client.Client, request *quota.Request, resourceControls []portainer.ResourceControl) ([]ownedResources, error) {
+	resources := make([]ownedResources, 0)
+
+	info, err := cli.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Swarm.ControlAvailable {
+		return resources, nil
+	}
+
+	services, err := cli.ServiceList(context.Background(), types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	nodeCount := -1
+	for _, service := range services {
+		if replacedResource(request, service.ID, service.Spec.Labels) {
+			continue
+		}
+
+		resourceControl := findResourceControl(service.ID, portainer.ServiceResourceControl, service.Spec.Labels, resourceControls)
+		if resourceControl == nil {
+			continue
+		}
+
+		replicas := 1
+		if service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil {
+			replicas = int(*service.Spec.Mode.Replicated.Replicas)
+		} else if service.Spec.Mode.Global != nil {
+			if nodeCount == -1 {
+				nodes, err := cli.NodeList(context.Background(), types.NodeListOptions{})
+				if err != nil {
+					return nil, err
+				}
+				nodeCount = len(nodes)
+			}
+			replicas = nodeCount
+		}
+
+		serviceResources := quota.Resources{Containers: 1}
+		if service.Spec.TaskTemplate.Resources != nil && service.Spec.TaskTemplate.Resources.Limits != nil {
+			serviceResources.Memory = service.Spec.TaskTemplate.Resources.Limits.MemoryBytes
+			serviceResources.NanoCPUs = service.Spec.TaskTemplate.Resources.Limits.NanoCPUs
+		}
+
+		resources = append(resources, ownedResources{
+			resourceControl: resourceControl,
+			resources:       serviceResources.Multiply(replicas),
+		})
+	}
+
+	return resources, nil
+}
+
+// findResourceControl returns the resource control associated to a Docker resource or to the stack it belongs to.
+func findResourceControl(resourceID string, resourceType portainer.ResourceControlType, labels map[string]string, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	resourceControl := portainer.GetResourceControlByResourceIDAndType(resourceID, resourceType, resourceControls)
+	if resourceControl != nil {
+		return resourceControl
+	}
+
+	if labels[swarmStackNameLabel] != "" {
+		return portainer.GetResourceControlByResourceIDAndType(labels[swarmStackNameLabel], portainer.StackResourceControl, resourceControls)
+	}
+
+	if labels[composeStackNameLabel] != "" {
+		return portainer.GetResourceControlByResourceIDAndType(labels[composeStackNameLabel], portainer.StackResourceControl, resourceControls)
+	}
+
+	return nil
+}
+
+// replacedResource returns true when the Docker resource is replaced by the request.
+func replacedResource(request *quota.Request, resourceID string, labels map[string]string) bool {
+	if request.ReplacedResourceID != "" && resourceID == request.ReplacedResourceID {
+		return true
+	}
+
+	if request.ReplacedStack != "" {
+		return labels[swarmStackNameLabel] == request.ReplacedStack || labels[composeStackNameLabel] == request.ReplacedStack
+	}
+
+	return false
+}
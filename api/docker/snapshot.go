@@ -115,6 +115,7 @@ func snapshotSwarmServices(snapshot *portainer.Snapshot, cli *client.Client) err
 
 	snapshot.ServiceCount = len(services)
 	snapshot.StackCount += len(stacks)
+	snapshot.SnapshotRaw.Services = services
 	return nil
 }
 
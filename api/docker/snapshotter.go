@@ -1,23 +1,94 @@
 package docker
 
 import (
+	"log"
+	"time"
+
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/drift"
+	"github.com/portainer/portainer/api/metrics"
 )
 
 // Snapshotter represents a service used to create endpoint snapshots
 type Snapshotter struct {
-	clientFactory *ClientFactory
+	clientFactory          *ClientFactory
+	metricsRegistry        *metrics.Registry
+	snapshotHistoryService portainer.SnapshotHistoryService
+	endpointEventService   portainer.EndpointEventService
 }
 
 // NewSnapshotter returns a new Snapshotter instance
-func NewSnapshotter(clientFactory *ClientFactory) *Snapshotter {
+func NewSnapshotter(clientFactory *ClientFactory, metricsRegistry *metrics.Registry, snapshotHistoryService portainer.SnapshotHistoryService, endpointEventService portainer.EndpointEventService) *Snapshotter {
 	return &Snapshotter{
-		clientFactory: clientFactory,
+		clientFactory:          clientFactory,
+		metricsRegistry:        metricsRegistry,
+		snapshotHistoryService: snapshotHistoryService,
+		endpointEventService:   endpointEventService,
 	}
 }
 
-// CreateSnapshot creates a snapshot of a specific endpoint
+// CreateSnapshot creates a snapshot of a specific endpoint.
+// The duration and the result of the snapshot are recorded in the metrics registry
+// and the summary of the snapshot is added to the snapshot history of the endpoint.
+// The changes since the previous snapshot of the endpoint are recorded as endpoint events.
 func (snapshotter *Snapshotter) CreateSnapshot(endpoint *portainer.Endpoint) (*portainer.Snapshot, error) {
+	start := time.Now()
+
+	endpointSnapshot, err := snapshotter.createSnapshot(endpoint)
+	if snapshotter.metricsRegistry != nil {
+		snapshotter.metricsRegistry.ObserveSnapshot(endpoint.ID, time.Since(start), err)
+	}
+
+	if err == nil && snapshotter.snapshotHistoryService != nil {
+		snapshotter.recordSnapshot(endpoint, endpointSnapshot)
+	}
+
+	if err == nil && snapshotter.endpointEventService != nil && len(endpoint.Snapshots) > 0 {
+		snapshotter.recordEvents(endpoint, &endpoint.Snapshots[0], endpointSnapshot)
+	}
+
+	return endpointSnapshot, err
+}
+
+func (snapshotter *Snapshotter) recordSnapshot(endpoint *portainer.Endpoint, snapshot *portainer.Snapshot) {
+	record := &portainer.SnapshotRecord{
+		EndpointID:            endpoint.ID,
+		Time:                  snapshot.Time,
+		TotalCPU:              snapshot.TotalCPU,
+		TotalMemory:           snapshot.TotalMemory,
+		RunningContainerCount: snapshot.RunningContainerCount,
+		StoppedContainerCount: snapshot.StoppedContainerCount,
+		VolumeCount:           snapshot.VolumeCount,
+		ImageCount:            snapshot.ImageCount,
+		ServiceCount:          snapshot.ServiceCount,
+		StackCount:            snapshot.StackCount,
+		Samples:               1,
+	}
+
+	err := snapshotter.snapshotHistoryService.CreateSnapshotRecord(record)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to persist snapshot record] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+}
+
+func (snapshotter *Snapshotter) recordEvents(endpoint *portainer.Endpoint, previous, current *portainer.Snapshot) {
+	events, err := drift.Detect(endpoint.ID, previous, current)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to compare snapshots] [endpoint: %s] [err: %s]", endpoint.Name, err)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	err = snapshotter.endpointEventService.CreateEndpointEvents(events)
+	if err != nil {
+		log.Printf("[WARN] [docker,snapshot] [message: unable to persist endpoint events] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+}
+
+func (snapshotter *Snapshotter) createSnapshot(endpoint *portainer.Endpoint) (*portainer.Snapshot, error) {
 	cli, err := snapshotter.clientFactory.CreateClient(endpoint, "")
 	if err != nil {
 		return nil, err
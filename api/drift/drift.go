@@ -0,0 +1,385 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+type (
+	snapshotContainer struct {
+		ID     string   `json:"Id"`
+		Names  []string `json:"Names"`
+		Image  string   `json:"Image"`
+		State  string   `json:"State"`
+		Status string   `json:"Status"`
+		Mounts []struct {
+			Type string `json:"Type"`
+			Name string `json:"Name"`
+		} `json:"Mounts"`
+	}
+
+	snapshotImage struct {
+		ID       string   `json:"Id"`
+		RepoTags []string `json:"RepoTags"`
+	}
+
+	snapshotVolumes struct {
+		Volumes []struct {
+			Name string `json:"Name"`
+		} `json:"Volumes"`
+	}
+
+	snapshotService struct {
+		ID   string `json:"ID"`
+		Spec struct {
+			Name string `json:"Name"`
+			Mode struct {
+				Replicated *struct {
+					Replicas *uint64 `json:"Replicas"`
+				} `json:"Replicated"`
+			} `json:"Mode"`
+		} `json:"Spec"`
+	}
+)
+
+// Detect returns the events describing the changes between two consecutive snapshots of an endpoint.
+// The raw data of a snapshot can either come from the Docker API or from the database, it is decoded
+// through its JSON representation. A resource category missing from one of the snapshots is ignored.
+func Detect(endpointID portainer.EndpointID, previous, current *portainer.Snapshot) ([]portainer.EndpointEvent, error) {
+	events := make([]portainer.EndpointEvent, 0)
+
+	containerEvents, err := detectContainerChanges(previous, current)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, containerEvents...)
+
+	imageEvents, err := detectImageChanges(previous, current)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, imageEvents...)
+
+	volumeEvents, err := detectOrphanedVolumes(previous, current)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, volumeEvents...)
+
+	serviceEvents, err := detectServiceScaling(previous, current)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, serviceEvents...)
+
+	for idx := range events {
+		events[idx].EndpointID = endpointID
+		events[idx].Time = current.Time
+	}
+
+	return events, nil
+}
+
+func detectContainerChanges(previous, current *portainer.Snapshot) ([]portainer.EndpointEvent, error) {
+	var previousContainers, currentContainers []snapshotContainer
+	ok, err := decodeRaw(previous.SnapshotRaw.Containers, current.SnapshotRaw.Containers, &previousContainers, &currentContainers)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	events := make([]portainer.EndpointEvent, 0)
+	elapsed := time.Duration(current.Time-previous.Time) * time.Second
+
+	previousByID := make(map[string]snapshotContainer)
+	for _, container := range previousContainers {
+		previousByID[container.ID] = container
+	}
+
+	currentByID := make(map[string]snapshotContainer)
+	for _, container := range currentContainers {
+		currentByID[container.ID] = container
+
+		previousContainer, exists := previousByID[container.ID]
+		if !exists {
+			events = append(events, containerEvent(portainer.ContainerCreatedEndpointEvent, container, fmt.Sprintf("container %s created from image %s", containerName(container), container.Image)))
+			continue
+		}
+
+		if containerRestarted(previousContainer, container, elapsed) {
+			events = append(events, containerEvent(portainer.ContainerRestartedEndpointEvent, container, fmt.Sprintf("container %s restarted", containerName(container))))
+		}
+	}
+
+	for _, container := range previousContainers {
+		if _, exists := currentByID[container.ID]; !exists {
+			events = append(events, containerEvent(portainer.ContainerRemovedEndpointEvent, container, fmt.Sprintf("container %s removed", containerName(container))))
+		}
+	}
+
+	return events, nil
+}
+
+func containerEvent(eventType portainer.EndpointEventType, container snapshotContainer, message string) portainer.EndpointEvent {
+	return portainer.EndpointEvent{
+		Type:         eventType,
+		ResourceID:   container.ID,
+		ResourceName: containerName(container),
+		Message:      message,
+	}
+}
+
+// containerRestarted returns true when a container was started again since the previous snapshot:
+// either it was not running and is now running, or it has been running for less time than
+// the time elapsed between the two snapshots.
+func containerRestarted(previous, current snapshotContainer, elapsed time.Duration) bool {
+	if current.State != "running" {
+		return false
+	}
+
+	if previous.State != "running" {
+		return true
+	}
+
+	uptime, ok := containerUptime(current.Status)
+	return ok && uptime <= elapsed
+}
+
+// containerUptime returns the upper bound of the uptime displayed inside the status of a running
+// container (e.g. "Up 5 minutes (healthy)"), as formatted by the Docker engine.
+func containerUptime(status string) (time.Duration, bool) {
+	if !strings.HasPrefix(status, "Up ") {
+		return 0, false
+	}
+
+	uptime := strings.TrimPrefix(status, "Up ")
+	if idx := strings.Index(uptime, " ("); idx != -1 {
+		uptime = uptime[:idx]
+	}
+
+	switch uptime {
+	case "Less than a second":
+		return time.Second, true
+	case "About a minute":
+		return 2 * time.Minute, true
+	case "About an hour":
+		return 2 * time.Hour, true
+	}
+
+	fields := strings.Fields(uptime)
+	if len(fields) != 2 {
+		return 0, false
+	}
+
+	value, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, false
+	}
+
+	units := map[string]time.Duration{
+		"second": time.Second,
+		"minute": time.Minute,
+		"hour":   time.Hour,
+		"day":    24 * time.Hour,
+		"week":   7 * 24 * time.Hour,
+		"month":  30 * 24 * time.Hour,
+		"year":   365 * 24 * time.Hour,
+	}
+
+	unit, ok := units[strings.TrimSuffix(fields[1], "s")]
+	if !ok {
+		return 0, false
+	}
+
+	return time.Duration(value+1) * unit, true
+}
+
+func containerName(container snapshotContainer) string {
+	if len(container.Names) == 0 {
+		return shortID(container.ID)
+	}
+	return strings.TrimPrefix(container.Names[0], "/")
+}
+
+func detectImageChanges(previous, current *portainer.Snapshot) ([]portainer.EndpointEvent, error) {
+	var previousImages, currentImages []snapshotImage
+	ok, err := decodeRaw(previous.SnapshotRaw.Images, current.SnapshotRaw.Images, &previousImages, &currentImages)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	events := make([]portainer.EndpointEvent, 0)
+
+	previousIDs := make(map[string]bool)
+	for _, image := range previousImages {
+		previousIDs[image.ID] = true
+	}
+
+	currentIDs := make(map[string]bool)
+	for _, image := range currentImages {
+		currentIDs[image.ID] = true
+
+		if !previousIDs[image.ID] {
+			events = append(events, imageEvent(portainer.ImageAddedEndpointEvent, image, fmt.Sprintf("image %s added", imageName(image))))
+		}
+	}
+
+	for _, image := range previousImages {
+		if !currentIDs[image.ID] {
+			events = append(events, imageEvent(portainer.ImageRemovedEndpointEvent, image, fmt.Sprintf("image %s removed", imageName(image))))
+		}
+	}
+
+	return events, nil
+}
+
+func imageEvent(eventType portainer.EndpointEventType, image snapshotImage, message string) portainer.EndpointEvent {
+	return portainer.EndpointEvent{
+		Type:         eventType,
+		ResourceID:   image.ID,
+		ResourceName: imageName(image),
+		Message:      message,
+	}
+}
+
+func imageName(image snapshotImage) string {
+	if len(image.RepoTags) == 0 || image.RepoTags[0] == "<none>:<none>" {
+		return shortID(image.ID)
+	}
+	return image.RepoTags[0]
+}
+
+// detectOrphanedVolumes returns an event for each volume used by at least one container in the
+// previous snapshot that is still present but no longer used by any container.
+func detectOrphanedVolumes(previous, current *portainer.Snapshot) ([]portainer.EndpointEvent, error) {
+	var previousContainers, currentContainers []snapshotContainer
+	ok, err := decodeRaw(previous.SnapshotRaw.Containers, current.SnapshotRaw.Containers, &previousContainers, &currentContainers)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	var previousVolumes, currentVolumes snapshotVolumes
+	ok, err = decodeRaw(previous.SnapshotRaw.Volumes, current.SnapshotRaw.Volumes, &previousVolumes, &currentVolumes)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	previouslyUsed := usedVolumes(previousContainers)
+	currentlyUsed := usedVolumes(currentContainers)
+
+	events := make([]portainer.EndpointEvent, 0)
+	for _, volume := range currentVolumes.Volumes {
+		if previouslyUsed[volume.Name] && !currentlyUsed[volume.Name] {
+			events = append(events, portainer.EndpointEvent{
+				Type:         portainer.VolumeOrphanedEndpointEvent,
+				ResourceID:   volume.Name,
+				ResourceName: volume.Name,
+				Message:      fmt.Sprintf("volume %s is no longer used by any container", volume.Name),
+			})
+		}
+	}
+
+	return events, nil
+}
+
+func usedVolumes(containers []snapshotContainer) map[string]bool {
+	volumes := make(map[string]bool)
+	for _, container := range containers {
+		for _, mount := range container.Mounts {
+			if mount.Type == "volume" {
+				volumes[mount.Name] = true
+			}
+		}
+	}
+	return volumes
+}
+
+func detectServiceScaling(previous, current *portainer.Snapshot) ([]portainer.EndpointEvent, error) {
+	var previousServices, currentServices []snapshotService
+	ok, err := decodeRaw(previous.SnapshotRaw.Services, current.SnapshotRaw.Services, &previousServices, &currentServices)
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	previousReplicas := make(map[string]uint64)
+	for _, service := range previousServices {
+		if replicas, ok := serviceReplicas(service); ok {
+			previousReplicas[service.ID] = replicas
+		}
+	}
+
+	events := make([]portainer.EndpointEvent, 0)
+	for _, service := range currentServices {
+		replicas, ok := serviceReplicas(service)
+		if !ok {
+			continue
+		}
+
+		before, exists := previousReplicas[service.ID]
+		if !exists || before == replicas {
+			continue
+		}
+
+		events = append(events, portainer.EndpointEvent{
+			Type:         portainer.ServiceScaledEndpointEvent,
+			ResourceID:   service.ID,
+			ResourceName: service.Spec.Name,
+			Message:      fmt.Sprintf("service %s scaled from %d to %d replicas", service.Spec.Name, before, replicas),
+		})
+	}
+
+	return events, nil
+}
+
+func serviceReplicas(service snapshotService) (uint64, bool) {
+	replicated := service.Spec.Mode.Replicated
+	if replicated == nil || replicated.Replicas == nil {
+		return 0, false
+	}
+	return *replicated.Replicas, true
+}
+
+// decodeRaw decodes the same raw resource category of two snapshots. It returns false when
+// the category is missing from one of the snapshots.
+func decodeRaw(previousRaw, currentRaw interface{}, previous, current interface{}) (bool, error) {
+	ok, err := decodeSnapshotRaw(previousRaw, previous)
+	if !ok || err != nil {
+		return false, err
+	}
+
+	return decodeSnapshotRaw(currentRaw, current)
+}
+
+func decodeSnapshotRaw(raw interface{}, target interface{}) (bool, error) {
+	if raw == nil {
+		return false, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return false, err
+	}
+
+	if string(data) == "null" {
+		return false, nil
+	}
+
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,126 @@
+package drift
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+func rawValue(t *testing.T, data string) interface{} {
+	var value interface{}
+	err := json.Unmarshal([]byte(data), &value)
+	if err != nil {
+		t.Fatalf("unable to decode raw value: %s", err)
+	}
+	return value
+}
+
+func TestDetect(t *testing.T) {
+	previous := &portainer.Snapshot{
+		Time: 1000,
+		SnapshotRaw: portainer.SnapshotRaw{
+			Containers: rawValue(t, `[
+				{"Id": "a", "Names": ["/web"], "State": "running", "Status": "Up 2 hours", "Mounts": [{"Type": "volume", "Name": "data"}]},
+				{"Id": "b", "Names": ["/worker"], "State": "exited", "Status": "Exited (1) 2 minutes ago"},
+				{"Id": "c", "Names": ["/cache"], "State": "running", "Status": "Up 3 days"},
+				{"Id": "d", "Names": ["/db"], "State": "running", "Status": "Up 3 days"}
+			]`),
+			Images:   rawValue(t, `[{"Id": "sha256:1111111111111111", "RepoTags": ["nginx:latest"]}]`),
+			Volumes:  rawValue(t, `{"Volumes": [{"Name": "data"}, {"Name": "unused"}]}`),
+			Services: rawValue(t, `[{"ID": "s1", "Spec": {"Name": "api", "Mode": {"Replicated": {"Replicas": 2}}}}]`),
+		},
+	}
+
+	current := &portainer.Snapshot{
+		Time: 1300,
+		SnapshotRaw: portainer.SnapshotRaw{
+			Containers: rawValue(t, `[
+				{"Id": "b", "Names": ["/worker"], "State": "running", "Status": "Up 10 seconds"},
+				{"Id": "c", "Names": ["/cache"], "State": "running", "Status": "Up 2 minutes (healthy)"},
+				{"Id": "d", "Names": ["/db"], "State": "running", "Status": "Up 3 days"},
+				{"Id": "e", "Names": ["/proxy"], "Image": "traefik", "State": "created", "Status": "Created"}
+			]`),
+			Images:   rawValue(t, `[{"Id": "sha256:2222222222222222", "RepoTags": ["<none>:<none>"]}]`),
+			Volumes:  rawValue(t, `{"Volumes": [{"Name": "data"}, {"Name": "unused"}]}`),
+			Services: rawValue(t, `[{"ID": "s1", "Spec": {"Name": "api", "Mode": {"Replicated": {"Replicas": 5}}}}]`),
+		},
+	}
+
+	events, err := Detect(portainer.EndpointID(3), previous, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []struct {
+		eventType    portainer.EndpointEventType
+		resourceName string
+	}{
+		{portainer.ContainerRestartedEndpointEvent, "worker"},
+		{portainer.ContainerRestartedEndpointEvent, "cache"},
+		{portainer.ContainerCreatedEndpointEvent, "proxy"},
+		{portainer.ContainerRemovedEndpointEvent, "web"},
+		{portainer.ImageAddedEndpointEvent, "222222222222"},
+		{portainer.ImageRemovedEndpointEvent, "nginx:latest"},
+		{portainer.VolumeOrphanedEndpointEvent, "data"},
+		{portainer.ServiceScaledEndpointEvent, "api"},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+
+	for idx, event := range events {
+		if event.Type != expected[idx].eventType || event.ResourceName != expected[idx].resourceName {
+			t.Errorf("event %d: expected type %d for %s, got type %d for %s", idx, expected[idx].eventType, expected[idx].resourceName, event.Type, event.ResourceName)
+		}
+
+		if event.EndpointID != 3 || event.Time != 1300 {
+			t.Errorf("event %d: unexpected endpoint or time: %+v", idx, event)
+		}
+	}
+}
+
+func TestDetectIgnoresMissingCategories(t *testing.T) {
+	previous := &portainer.Snapshot{Time: 1000}
+	current := &portainer.Snapshot{
+		Time: 1300,
+		SnapshotRaw: portainer.SnapshotRaw{
+			Containers: rawValue(t, `[{"Id": "a", "Names": ["/web"], "State": "running", "Status": "Up 2 hours"}]`),
+		},
+	}
+
+	events, err := Detect(portainer.EndpointID(1), previous, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("expected no events, got %+v", events)
+	}
+}
+
+func TestContainerUptime(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"Up Less than a second", time.Second, true},
+		{"Up 45 seconds", 46 * time.Second, true},
+		{"Up About a minute (healthy)", 2 * time.Minute, true},
+		{"Up 5 minutes (Paused)", 6 * time.Minute, true},
+		{"Up 1 hour", 2 * time.Hour, true},
+		{"Up 2 weeks", 3 * 7 * 24 * time.Hour, true},
+		{"Exited (0) 5 minutes ago", 0, false},
+		{"Created", 0, false},
+	}
+
+	for _, test := range tests {
+		uptime, ok := containerUptime(test.status)
+		if ok != test.ok || uptime != test.expected {
+			t.Errorf("%q: expected (%s, %t), got (%s, %t)", test.status, test.expected, test.ok, uptime, ok)
+		}
+	}
+}
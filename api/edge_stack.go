@@ -0,0 +1,26 @@
+package portainer
+
+// EdgeStackRelatedToEndpoint returns true when an Edge stack must be deployed on an endpoint:
+// the endpoint is an approved Edge endpoint that belongs to one of the endpoint groups
+// of the stack or that is associated to one of its tags.
+func EdgeStackRelatedToEndpoint(edgeStack *EdgeStack, endpoint *Endpoint) bool {
+	if endpoint.Type != EdgeAgentEnvironment || endpoint.EdgePendingApproval {
+		return false
+	}
+
+	for _, groupID := range edgeStack.EndpointGroups {
+		if endpoint.GroupID == groupID {
+			return true
+		}
+	}
+
+	for _, tag := range edgeStack.Tags {
+		for _, endpointTag := range endpoint.Tags {
+			if tag == endpointTag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
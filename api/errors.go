@@ -16,6 +16,22 @@ const (
 	ErrAdminAlreadyInitialized    = Error("An administrator user already exists")
 	ErrAdminCannotRemoveSelf      = Error("Cannot remove your own user account. Contact another administrator")
 	ErrCannotRemoveLastLocalAdmin = Error("Cannot remove the last local administrator account")
+	ErrUserDisabled               = Error("User account is disabled")
+	ErrUserLocked                 = Error("User account is locked after too many failed authentication attempts")
+	ErrPasswordPolicyViolation    = Error("Password does not comply with the password policy")
+	ErrInvalidAuthChallenge       = Error("Invalid or expired authentication challenge")
+)
+
+// API key errors.
+const (
+	ErrInvalidAPIKey = Error("Invalid API key")
+	ErrAPIKeyExpired = Error("API key has expired")
+)
+
+// LDAP errors.
+const (
+	ErrLDAPUserNotFound   = Error("User not found")
+	ErrLDAPTooManyEntries = Error("Too many entries returned for the user")
 )
 
 // Team errors.
@@ -36,7 +52,24 @@ const (
 
 // Endpoint errors.
 const (
-	ErrEndpointAccessDenied = Error("Access denied to endpoint")
+	ErrEndpointAccessDenied       = Error("Access denied to endpoint")
+	ErrEndpointApprovalPending    = Error("Edge endpoint is pending approval")
+	ErrEndpointNotPendingApproval = Error("Endpoint is not pending approval")
+	ErrEdgeIDAlreadyRegistered    = Error("An endpoint is already registered with this Edge identifier")
+)
+
+// Edge stack errors.
+const (
+	ErrEdgeStackAlreadyExists     = Error("An Edge stack already exists with this name")
+	ErrEdgeStackNotRelatedToAgent = Error("The Edge stack is not deployed on this endpoint")
+	ErrEdgeStackVersionUnknown    = Error("The Edge stack version is greater than the current version of the Edge stack")
+)
+
+// Edge join token errors.
+const (
+	ErrInvalidEdgeJoinToken = Error("Invalid Edge join token")
+	ErrEdgeJoinTokenExpired = Error("Edge join token has expired")
+	ErrEdgeJoinTokenRevoked = Error("Edge join token has been revoked")
 )
 
 // Azure environment errors
@@ -59,6 +92,8 @@ const (
 	ErrStackAlreadyExists              = Error("A stack already exists with this name")
 	ErrComposeFileNotFoundInRepository = Error("Unable to find a Compose file in the repository")
 	ErrStackNotExternal                = Error("Not an external stack")
+	ErrGitReferenceNotFound            = Error("Unable to find the reference in the repository")
+	ErrStackVersionNotFound            = Error("Unable to find the specified stack version")
 )
 
 // Tag errors
@@ -81,6 +116,7 @@ const (
 const (
 	ErrSecretGeneration   = Error("Unable to generate secret key")
 	ErrInvalidJWTToken    = Error("Invalid JWT token")
+	ErrRevokedJWTToken    = Error("JWT token has been revoked")
 	ErrMissingContextData = Error("Unable to find JWT data in request context")
 )
 
@@ -110,8 +146,60 @@ type Error string
 // Error returns the error message.
 func (e Error) Error() string { return string(e) }
 
+// Backup errors
+const (
+	ErrBackupInvalidArchive        = Error("Invalid backup archive")
+	ErrBackupInvalidPassword       = Error("Unable to decrypt the backup archive. Invalid password")
+	ErrBackupPasswordRequired      = Error("The backup archive is encrypted. A password is required")
+	ErrBackupDBVersionNotSupported = Error("The backup archive was created by a more recent version of Portainer")
+	ErrRestartRequired             = Error("Portainer data restored. A restart is required to load the restored data")
+)
+
+// OpenID Connect errors
+const (
+	ErrOIDCInvalidState   = Error("Invalid or expired OpenID Connect authorization state")
+	ErrOIDCInvalidIDToken = Error("Invalid OpenID Connect ID token")
+	ErrOIDCMissingClaim   = Error("Unable to find the user identifier claim inside the OpenID Connect ID token")
+)
+
+// Two-factor authentication errors
+const (
+	ErrTwoFactorInvalidCode    = Error("Invalid two-factor authentication code")
+	ErrTwoFactorAlreadyEnabled = Error("Two-factor authentication is already enabled for this user")
+	ErrTwoFactorNotEnrolled    = Error("Two-factor authentication enrolment not found for this user")
+	ErrTwoFactorNotSupported   = Error("Two-factor authentication is only available for internal users")
+)
+
+// Alert errors
+const (
+	ErrAlertNotifierInUse = Error("Alert notifier is used by at least one alert rule")
+)
+
 // Webhook errors
 const (
 	ErrWebhookAlreadyExists   = Error("A webhook for this resource already exists")
 	ErrUnsupportedWebhookType = Error("Webhooks for this resource are not currently supported")
 )
+
+// Quota errors
+const (
+	ErrQuotaExceeded        = Error("Resource quota exceeded")
+	ErrQuotaResourceRelease = Error("Resources counted by a quota cannot be released from their owner")
+)
+
+// Container policy errors
+const (
+	ErrContainerPolicyViolation = Error("Operation denied by container policy")
+)
+
+// Image restriction errors
+const (
+	ErrImageNotAllowed = Error("Image not allowed on this endpoint")
+)
+
+// Role errors
+const (
+	ErrRoleAlreadyExists   = Error("A role already exists with this name")
+	ErrBuiltInRoleReadOnly = Error("Built-in roles cannot be modified or removed")
+	ErrRoleInUse           = Error("Role is used by at least one access policy")
+)
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"io/ioutil"
+	"sort"
+	"strconv"
 
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/archive"
@@ -29,10 +31,14 @@ const (
 	ComposeStorePath = "compose"
 	// ComposeFileDefaultName represents the default name of a compose file.
 	ComposeFileDefaultName = "docker-compose.yml"
+	// StackVersionsFolder represents the subfolder of a stack project folder where the stack versions are stored.
+	StackVersionsFolder = ".versions"
 	// PrivateKeyFile represents the name on disk of the file containing the private key.
 	PrivateKeyFile = "portainer.key"
 	// PublicKeyFile represents the name on disk of the file containing the public key.
 	PublicKeyFile = "portainer.pub"
+	// EncryptionKeyFile represents the name on disk of the file containing the key used to encrypt data.
+	EncryptionKeyFile = "portainer.enc"
 	// BinaryStorePath represents the subfolder where binaries are stored in the file store folder.
 	BinaryStorePath = "bin"
 	// ScheduleStorePath represents the subfolder where schedule files are stored.
@@ -40,6 +46,10 @@ const (
 	// ExtensionRegistryManagementStorePath represents the subfolder where files related to the
 	// registry management extension are stored.
 	ExtensionRegistryManagementStorePath = "extensions"
+	// SessionRecordingStorePath represents the subfolder where the recordings of the console sessions are stored.
+	SessionRecordingStorePath = "recordings"
+	// EdgeStackStorePath represents the subfolder where Edge stack files are stored in the file store folder.
+	EdgeStackStorePath = "edge_stacks"
 )
 
 // Service represents a service for managing files and directories.
@@ -101,6 +111,32 @@ func (service *Service) GetStackProjectPath(stackIdentifier string) string {
 	return path.Join(service.fileStorePath, ComposeStorePath, stackIdentifier)
 }
 
+// GetEdgeStackProjectPath returns the absolute path on the FS for an Edge stack based
+// on its identifier.
+func (service *Service) GetEdgeStackProjectPath(edgeStackIdentifier string) string {
+	return path.Join(service.fileStorePath, EdgeStackStorePath, edgeStackIdentifier)
+}
+
+// StoreEdgeStackFileFromBytes creates a subfolder in the EdgeStackStorePath and stores a new file from bytes.
+// It returns the path to the folder where the file is stored.
+func (service *Service) StoreEdgeStackFileFromBytes(edgeStackIdentifier, fileName string, data []byte) (string, error) {
+	edgeStackStorePath := path.Join(EdgeStackStorePath, edgeStackIdentifier)
+	err := service.createDirectoryInStore(edgeStackStorePath)
+	if err != nil {
+		return "", err
+	}
+
+	composeFilePath := path.Join(edgeStackStorePath, fileName)
+	r := bytes.NewReader(data)
+
+	err = service.createFileInStore(composeFilePath, r)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(service.fileStorePath, edgeStackStorePath), nil
+}
+
 // StoreStackFileFromBytes creates a subfolder in the ComposeStorePath and stores a new file from bytes.
 // It returns the path to the folder where the file is stored.
 func (service *Service) StoreStackFileFromBytes(stackIdentifier, fileName string, data []byte) (string, error) {
@@ -121,6 +157,68 @@ func (service *Service) StoreStackFileFromBytes(stackIdentifier, fileName string
 	return path.Join(service.fileStorePath, stackStorePath), nil
 }
 
+// StoreStackVersion stores a version of a stack as a JSON file inside the StackVersionsFolder
+// of the stack project folder.
+func (service *Service) StoreStackVersion(stackIdentifier string, version *portainer.StackVersion) error {
+	versionsStorePath := path.Join(ComposeStorePath, stackIdentifier, StackVersionsFolder)
+	err := service.createDirectoryInStore(versionsStorePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+
+	versionFilePath := path.Join(versionsStorePath, createStackVersionFileName(version.Version))
+	return service.createFileInStore(versionFilePath, bytes.NewReader(data))
+}
+
+// GetStackVersions returns the versions of a stack stored on the filesystem, ordered by version number.
+func (service *Service) GetStackVersions(stackIdentifier string) ([]portainer.StackVersion, error) {
+	versions := make([]portainer.StackVersion, 0)
+
+	versionsPath := path.Join(service.fileStorePath, ComposeStorePath, stackIdentifier, StackVersionsFolder)
+	files, err := ioutil.ReadDir(versionsPath)
+	if os.IsNotExist(err) {
+		return versions, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(path.Join(versionsPath, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var version portainer.StackVersion
+		err = json.Unmarshal(data, &version)
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// DeleteStackVersion removes a version of a stack from the filesystem.
+func (service *Service) DeleteStackVersion(stackIdentifier string, version int) error {
+	versionFilePath := path.Join(service.fileStorePath, ComposeStorePath, stackIdentifier, StackVersionsFolder, createStackVersionFileName(version))
+	return os.Remove(versionFilePath)
+}
+
+func createStackVersionFileName(version int) string {
+	return "version_" + strconv.Itoa(version) + ".json"
+}
+
 // StoreRegistryManagementFileFromBytes creates a subfolder in the
 // ExtensionRegistryManagementStorePath and stores a new file from bytes.
 // It returns the path to the folder where the file is stored.
@@ -310,6 +408,47 @@ func (service *Service) LoadKeyPair() ([]byte, []byte, error) {
 	return privateKey, publicKey, nil
 }
 
+// EncryptionKeyFileExists checks for the existence of the encryption key file.
+func (service *Service) EncryptionKeyFileExists() (bool, error) {
+	return service.FileExists(path.Join(service.dataStorePath, EncryptionKeyFile))
+}
+
+// StoreEncryptionKey stores the specified encryption key on disk.
+// The key is written to a temporary file that replaces the existing key file once fully written,
+// so that the existing key is never lost if the write is interrupted.
+func (service *Service) StoreEncryptionKey(key []byte) error {
+	file, err := ioutil.TempFile(service.dataStorePath, EncryptionKeyFile+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(key)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path.Join(service.dataStorePath, EncryptionKeyFile))
+}
+
+// LoadEncryptionKey retrieves the content of the encryption key file on disk.
+func (service *Service) LoadEncryptionKey() ([]byte, error) {
+	keyPath := path.Join(service.dataStorePath, EncryptionKeyFile)
+	return ioutil.ReadFile(keyPath)
+}
+
 // createDirectoryInStore creates a new directory in the file store
 func (service *Service) createDirectoryInStore(name string) error {
 	path := path.Join(service.fileStorePath, name)
@@ -392,3 +531,33 @@ func (service *Service) StoreScheduledJobFileFromBytes(identifier string, data [
 func createScheduledJobFileName(identifier string) string {
 	return "job_" + identifier + ".sh"
 }
+
+// GetSessionRecordingPath returns the absolute path on the filesystem for a session recording
+// based on its identifier.
+func (service *Service) GetSessionRecordingPath(identifier string) string {
+	return path.Join(service.fileStorePath, SessionRecordingStorePath, createSessionRecordingFileName(identifier))
+}
+
+// CreateSessionRecordingFile creates a new empty file in the SessionRecordingStorePath and
+// returns it so that the content of a session can be written while the session is running.
+func (service *Service) CreateSessionRecordingFile(identifier string) (io.WriteCloser, error) {
+	err := service.createDirectoryInStore(SessionRecordingStorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(service.GetSessionRecordingPath(identifier), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+}
+
+// DeleteSessionRecordingFile removes the file associated to a session recording.
+func (service *Service) DeleteSessionRecordingFile(identifier string) error {
+	err := os.Remove(service.GetSessionRecordingPath(identifier))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func createSessionRecordingFileName(identifier string) string {
+	return "session_" + identifier + ".cast"
+}
@@ -7,10 +7,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/portainer/portainer/api"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/client"
 	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
 
 // Service represents a service for managing Git.
@@ -43,11 +46,69 @@ func (service *Service) ClonePublicRepository(repositoryURL, referenceName strin
 // ClonePrivateRepositoryWithBasicAuth clones a private git repository using the specified URL in the specified
 // destination folder. It will use the specified username and password for basic HTTP authentication.
 func (service *Service) ClonePrivateRepositoryWithBasicAuth(repositoryURL, referenceName string, destination, username, password string) error {
-	credentials := username + ":" + url.PathEscape(password)
-	repositoryURL = strings.Replace(repositoryURL, "://", "://"+credentials+"@", 1)
+	repositoryURL = buildAuthenticatedURL(repositoryURL, username, password)
 	return cloneRepository(repositoryURL, referenceName, destination)
 }
 
+// LatestCommitID returns the hash of the commit referenced by referenceName in a remote git repository
+// without cloning it. HEAD is used when referenceName is empty.
+// The specified username and password are used for basic HTTP authentication when username is not empty.
+func (service *Service) LatestCommitID(repositoryURL, referenceName, username, password string) (string, error) {
+	if username != "" {
+		repositoryURL = buildAuthenticatedURL(repositoryURL, username, password)
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{repositoryURL},
+	})
+
+	references, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if referenceName == "" {
+		referenceName = string(plumbing.HEAD)
+	}
+
+	referencesByName := make(map[plumbing.ReferenceName]*plumbing.Reference)
+	for _, reference := range references {
+		referencesByName[reference.Name()] = reference
+	}
+
+	reference, ok := referencesByName[plumbing.ReferenceName(referenceName)]
+	if ok && reference.Type() == plumbing.SymbolicReference {
+		reference, ok = referencesByName[reference.Target()]
+	}
+
+	if !ok {
+		return "", portainer.ErrGitReferenceNotFound
+	}
+
+	return reference.Hash().String(), nil
+}
+
+// HeadCommitID returns the hash of the commit checked out in a local clone of a git repository.
+func (service *Service) HeadCommitID(repositoryPath string) (string, error) {
+	repository, err := git.PlainOpen(repositoryPath)
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return "", err
+	}
+
+	return head.Hash().String(), nil
+}
+
+func buildAuthenticatedURL(repositoryURL, username, password string) string {
+	credentials := username + ":" + url.PathEscape(password)
+	return strings.Replace(repositoryURL, "://", "://"+credentials+"@", 1)
+}
+
 func cloneRepository(repositoryURL, referenceName, destination string) error {
 	options := &git.CloneOptions{
 		URL: repositoryURL,
@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type alertNotifierCreatePayload struct {
+	Name         string
+	Type         int
+	URL          string
+	SMTPSettings *portainer.AlertSMTPSettings
+}
+
+func (payload *alertNotifierCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid alert notifier name")
+	}
+	return validateNotifierSettings(portainer.AlertNotifierType(payload.Type), payload.URL, payload.SMTPSettings)
+}
+
+// POST request on /api/alerts/notifiers
+func (handler *Handler) alertNotifierCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload alertNotifierCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	notifier := &portainer.AlertNotifier{
+		Name: payload.Name,
+		Type: portainer.AlertNotifierType(payload.Type),
+	}
+
+	if notifier.Type == portainer.EmailAlertNotifier {
+		notifier.SMTPSettings = *payload.SMTPSettings
+	} else {
+		notifier.URL = payload.URL
+	}
+
+	err = handler.AlertNotifierService.CreateAlertNotifier(notifier)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the alert notifier inside the database", err}
+	}
+
+	hideNotifierFields(notifier)
+	return response.JSON(w, notifier)
+}
@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/alerts/notifiers/:id
+func (handler *Handler) alertNotifierDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	notifierID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert notifier identifier route variable", err}
+	}
+
+	_, err = handler.AlertNotifierService.AlertNotifier(portainer.AlertNotifierID(notifierID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	}
+
+	rules, err := handler.AlertRuleService.AlertRules()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve alert rules from the database", err}
+	}
+
+	for _, rule := range rules {
+		for _, id := range rule.NotifierIDs {
+			if id == portainer.AlertNotifierID(notifierID) {
+				return &httperror.HandlerError{http.StatusConflict, "The alert notifier is used by at least one alert rule", portainer.ErrAlertNotifierInUse}
+			}
+		}
+	}
+
+	err = handler.AlertNotifierService.DeleteAlertNotifier(portainer.AlertNotifierID(notifierID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the alert notifier from the database", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,28 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/alerts/notifiers/:id
+func (handler *Handler) alertNotifierInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	notifierID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert notifier identifier route variable", err}
+	}
+
+	notifier, err := handler.AlertNotifierService.AlertNotifier(portainer.AlertNotifierID(notifierID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	}
+
+	hideNotifierFields(notifier)
+	return response.JSON(w, notifier)
+}
@@ -0,0 +1,22 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/alerts/notifiers
+func (handler *Handler) alertNotifierList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	notifiers, err := handler.AlertNotifierService.AlertNotifiers()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve alert notifiers from the database", err}
+	}
+
+	for idx := range notifiers {
+		hideNotifierFields(&notifiers[idx])
+	}
+
+	return response.JSON(w, notifiers)
+}
@@ -0,0 +1,74 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type alertNotifierUpdatePayload struct {
+	Name         *string
+	URL          *string
+	SMTPSettings *portainer.AlertSMTPSettings
+}
+
+func (payload *alertNotifierUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return portainer.Error("Invalid alert notifier name")
+	}
+	return nil
+}
+
+// PUT request on /api/alerts/notifiers/:id
+func (handler *Handler) alertNotifierUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	notifierID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert notifier identifier route variable", err}
+	}
+
+	var payload alertNotifierUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	notifier, err := handler.AlertNotifierService.AlertNotifier(portainer.AlertNotifierID(notifierID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		notifier.Name = *payload.Name
+	}
+
+	if payload.URL != nil && notifier.Type != portainer.EmailAlertNotifier {
+		notifier.URL = *payload.URL
+	}
+
+	if payload.SMTPSettings != nil && notifier.Type == portainer.EmailAlertNotifier {
+		password := notifier.SMTPSettings.Password
+		if payload.SMTPSettings.Password != "" {
+			password = payload.SMTPSettings.Password
+		}
+		notifier.SMTPSettings = *payload.SMTPSettings
+		notifier.SMTPSettings.Password = password
+	}
+
+	err = validateNotifierSettings(notifier.Type, notifier.URL, &notifier.SMTPSettings)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert notifier settings", err}
+	}
+
+	err = handler.AlertNotifierService.UpdateAlertNotifier(notifier.ID, notifier)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist alert notifier changes inside the database", err}
+	}
+
+	hideNotifierFields(notifier)
+	return response.JSON(w, notifier)
+}
@@ -0,0 +1,68 @@
+package alerts
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type alertRuleCreatePayload struct {
+	Name        string
+	Enabled     *bool
+	EventTypes  []portainer.AlertEventType
+	EndpointIDs []portainer.EndpointID
+	NotifierIDs []portainer.AlertNotifierID
+}
+
+func (payload *alertRuleCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid alert rule name")
+	}
+	if len(payload.NotifierIDs) == 0 {
+		return portainer.Error("Invalid notifiers. At least one notifier is required")
+	}
+	return validateEventTypes(payload.EventTypes)
+}
+
+// POST request on /api/alerts/rules
+func (handler *Handler) alertRuleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload alertRuleCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	err = handler.validateNotifierIDs(payload.NotifierIDs)
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert notifier with the specified identifier inside the database", err}
+	}
+
+	rule := &portainer.AlertRule{
+		Name:        payload.Name,
+		Enabled:     true,
+		EventTypes:  payload.EventTypes,
+		EndpointIDs: payload.EndpointIDs,
+		NotifierIDs: payload.NotifierIDs,
+	}
+
+	if payload.Enabled != nil {
+		rule.Enabled = *payload.Enabled
+	}
+
+	if rule.EndpointIDs == nil {
+		rule.EndpointIDs = []portainer.EndpointID{}
+	}
+
+	err = handler.AlertRuleService.CreateAlertRule(rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the alert rule inside the database", err}
+	}
+
+	return response.JSON(w, rule)
+}
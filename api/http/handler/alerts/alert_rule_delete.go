@@ -0,0 +1,32 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/alerts/rules/:id
+func (handler *Handler) alertRuleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ruleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert rule identifier route variable", err}
+	}
+
+	_, err = handler.AlertRuleService.AlertRule(portainer.AlertRuleID(ruleID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an alert rule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert rule with the specified identifier inside the database", err}
+	}
+
+	err = handler.AlertRuleService.DeleteAlertRule(portainer.AlertRuleID(ruleID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the alert rule from the database", err}
+	}
+
+	return response.Empty(w)
+}
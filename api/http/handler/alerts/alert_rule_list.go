@@ -0,0 +1,18 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/alerts/rules
+func (handler *Handler) alertRuleList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	rules, err := handler.AlertRuleService.AlertRules()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve alert rules from the database", err}
+	}
+
+	return response.JSON(w, rules)
+}
@@ -0,0 +1,86 @@
+package alerts
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type alertRuleUpdatePayload struct {
+	Name        *string
+	Enabled     *bool
+	EventTypes  []portainer.AlertEventType
+	EndpointIDs []portainer.EndpointID
+	NotifierIDs []portainer.AlertNotifierID
+}
+
+func (payload *alertRuleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return portainer.Error("Invalid alert rule name")
+	}
+	if payload.NotifierIDs != nil && len(payload.NotifierIDs) == 0 {
+		return portainer.Error("Invalid notifiers. At least one notifier is required")
+	}
+	if payload.EventTypes != nil {
+		return validateEventTypes(payload.EventTypes)
+	}
+	return nil
+}
+
+// PUT request on /api/alerts/rules/:id
+func (handler *Handler) alertRuleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	ruleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid alert rule identifier route variable", err}
+	}
+
+	var payload alertRuleUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	rule, err := handler.AlertRuleService.AlertRule(portainer.AlertRuleID(ruleID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an alert rule with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert rule with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		rule.Name = *payload.Name
+	}
+
+	if payload.Enabled != nil {
+		rule.Enabled = *payload.Enabled
+	}
+
+	if payload.EventTypes != nil {
+		rule.EventTypes = payload.EventTypes
+	}
+
+	if payload.EndpointIDs != nil {
+		rule.EndpointIDs = payload.EndpointIDs
+	}
+
+	if payload.NotifierIDs != nil {
+		err = handler.validateNotifierIDs(payload.NotifierIDs)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an alert notifier with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an alert notifier with the specified identifier inside the database", err}
+		}
+
+		rule.NotifierIDs = payload.NotifierIDs
+	}
+
+	err = handler.AlertRuleService.UpdateAlertRule(rule.ID, rule)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist alert rule changes inside the database", err}
+	}
+
+	return response.JSON(w, rule)
+}
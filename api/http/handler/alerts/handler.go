@@ -0,0 +1,50 @@
+package alerts
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+func hideNotifierFields(notifier *portainer.AlertNotifier) {
+	notifier.SMTPSettings.Password = ""
+}
+
+// Handler is the HTTP handler used to handle alert rule and alert notifier operations.
+type Handler struct {
+	*mux.Router
+	AlertRuleService     portainer.AlertRuleService
+	AlertNotifierService portainer.AlertNotifierService
+}
+
+// NewHandler creates a handler to manage alert rule and alert notifier operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/alerts/rules",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertRuleCreate))).Methods(http.MethodPost)
+	h.Handle("/alerts/rules",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertRuleList))).Methods(http.MethodGet)
+	h.Handle("/alerts/rules/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertRuleInspect))).Methods(http.MethodGet)
+	h.Handle("/alerts/rules/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertRuleUpdate))).Methods(http.MethodPut)
+	h.Handle("/alerts/rules/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertRuleDelete))).Methods(http.MethodDelete)
+	h.Handle("/alerts/notifiers",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertNotifierCreate))).Methods(http.MethodPost)
+	h.Handle("/alerts/notifiers",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertNotifierList))).Methods(http.MethodGet)
+	h.Handle("/alerts/notifiers/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertNotifierInspect))).Methods(http.MethodGet)
+	h.Handle("/alerts/notifiers/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertNotifierUpdate))).Methods(http.MethodPut)
+	h.Handle("/alerts/notifiers/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.alertNotifierDelete))).Methods(http.MethodDelete)
+
+	return h
+}
@@ -0,0 +1,61 @@
+package alerts
+
+import (
+	"github.com/asaskevich/govalidator"
+	"github.com/portainer/portainer/api"
+)
+
+func validateEventTypes(eventTypes []portainer.AlertEventType) error {
+	if len(eventTypes) == 0 {
+		return portainer.Error("Invalid event types. At least one event type is required")
+	}
+
+	for _, eventType := range eventTypes {
+		if eventType < portainer.EndpointDownAlertEvent || eventType > portainer.StoppedContainersIncreaseAlertEvent {
+			return portainer.Error("Invalid event type. Value must be one of: 1 (endpoint down), 2 (endpoint up), 3 (Edge tunnel timeout) or 4 (stopped containers increase)")
+		}
+	}
+
+	return nil
+}
+
+func validateNotifierSettings(notifierType portainer.AlertNotifierType, URL string, smtpSettings *portainer.AlertSMTPSettings) error {
+	switch notifierType {
+	case portainer.WebhookAlertNotifier, portainer.SlackAlertNotifier:
+		if govalidator.IsNull(URL) || !govalidator.IsURL(URL) {
+			return portainer.Error("Invalid notifier URL. Must correspond to a valid URL format")
+		}
+	case portainer.EmailAlertNotifier:
+		if smtpSettings == nil || govalidator.IsNull(smtpSettings.Host) {
+			return portainer.Error("Invalid SMTP host")
+		}
+		if smtpSettings.Port <= 0 {
+			return portainer.Error("Invalid SMTP port")
+		}
+		if !govalidator.IsEmail(smtpSettings.From) {
+			return portainer.Error("Invalid sender email address")
+		}
+		if len(smtpSettings.To) == 0 {
+			return portainer.Error("Invalid recipients. At least one recipient is required")
+		}
+		for _, recipient := range smtpSettings.To {
+			if !govalidator.IsEmail(recipient) {
+				return portainer.Error("Invalid recipient email address")
+			}
+		}
+	default:
+		return portainer.Error("Invalid notifier type. Value must be one of: 1 (webhook), 2 (Slack) or 3 (email)")
+	}
+
+	return nil
+}
+
+func (handler *Handler) validateNotifierIDs(notifierIDs []portainer.AlertNotifierID) error {
+	for _, notifierID := range notifierIDs {
+		_, err := handler.AlertNotifierService.AlertNotifier(notifierID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+)
+
+// GET request on /api/audit/export?userId=<userId>&endpointId=<endpointId>&from=<timestamp>&to=<timestamp>&operation=<operation>
+// The audit logs are exported using the JSON lines format, one entry per line.
+// They are read from the database one page at a time while the response is streamed.
+func (handler *Handler) auditLogExport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	filter, handlerErr := retrieveAuditLogFilter(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	auditLogs, err := handler.AuditService.AuditLogs(0, auditLogMaxLimit, filter.match)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve audit logs from the database", err}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", "attachment; filename=audit.jsonl")
+
+	// the response has already started, errors can only be logged from now on
+	encoder := json.NewEncoder(w)
+	for len(auditLogs) > 0 {
+		for _, auditLog := range auditLogs {
+			err := encoder.Encode(auditLog)
+			if err != nil {
+				log.Printf("[WARN] [http,audit,export] [message: unable to export audit logs] [err: %s]", err)
+				return nil
+			}
+		}
+
+		if len(auditLogs) < auditLogMaxLimit {
+			break
+		}
+
+		auditLogs, err = handler.AuditService.AuditLogs(auditLogs[len(auditLogs)-1].ID, auditLogMaxLimit, filter.match)
+		if err != nil {
+			log.Printf("[WARN] [http,audit,export] [message: unable to retrieve audit logs from the database] [err: %s]", err)
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	// auditLogDefaultLimit is the number of audit logs returned when no limit is specified
+	auditLogDefaultLimit = 100
+	// auditLogMaxLimit is the maximum number of audit logs returned by a single request
+	auditLogMaxLimit = 1000
+)
+
+// GET request on /api/audit?userId=<userId>&endpointId=<endpointId>&from=<timestamp>&to=<timestamp>&operation=<operation>&after=<auditLogId>&limit=<limit>
+// The audit logs are returned ordered by creation, one page at a time. The identifier of the last audit log
+// of a page must be specified as the after parameter to retrieve the next page.
+func (handler *Handler) auditLogList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	filter, handlerErr := retrieveAuditLogFilter(r)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	after, err := request.RetrieveNumericQueryParameter(r, "after", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: after", err}
+	}
+
+	limit, err := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: limit", err}
+	}
+
+	if limit <= 0 {
+		limit = auditLogDefaultLimit
+	} else if limit > auditLogMaxLimit {
+		limit = auditLogMaxLimit
+	}
+
+	auditLogs, err := handler.AuditService.AuditLogs(portainer.AuditLogID(after), limit, filter.match)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve audit logs from the database", err}
+	}
+
+	return response.JSON(w, auditLogs)
+}
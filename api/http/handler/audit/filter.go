@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"net/http"
+	"strings"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+type auditLogFilter struct {
+	userID     int
+	endpointID int
+	from       int
+	to         int
+	operation  string
+}
+
+func retrieveAuditLogFilter(r *http.Request) (*auditLogFilter, *httperror.HandlerError) {
+	filter := &auditLogFilter{}
+
+	parameters := map[string]*int{
+		"userId":     &filter.userID,
+		"endpointId": &filter.endpointID,
+		"from":       &filter.from,
+		"to":         &filter.to,
+	}
+
+	for name, value := range parameters {
+		var err error
+		*value, err = request.RetrieveNumericQueryParameter(r, name, true)
+		if err != nil {
+			return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: " + name, err}
+		}
+	}
+
+	operation, _ := request.RetrieveQueryParameter(r, "operation", true)
+	filter.operation = strings.ToLower(operation)
+
+	return filter, nil
+}
+
+func (filter *auditLogFilter) match(auditLog *portainer.AuditLog) bool {
+	if filter.userID != 0 && auditLog.UserID != portainer.UserID(filter.userID) {
+		return false
+	}
+
+	if filter.endpointID != 0 && auditLog.EndpointID != portainer.EndpointID(filter.endpointID) {
+		return false
+	}
+
+	if filter.from != 0 && auditLog.Timestamp < int64(filter.from) {
+		return false
+	}
+
+	if filter.to != 0 && auditLog.Timestamp > int64(filter.to) {
+		return false
+	}
+
+	if filter.operation != "" && !strings.Contains(strings.ToLower(auditLog.Operation), filter.operation) {
+		return false
+	}
+
+	return true
+}
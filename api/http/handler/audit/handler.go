@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle audit log operations.
+type Handler struct {
+	*mux.Router
+	AuditService portainer.AuditService
+}
+
+// NewHandler creates a handler to manage audit log operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/audit",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.auditLogList))).Methods(http.MethodGet)
+	h.Handle("/audit/export",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.auditLogExport))).Methods(http.MethodGet)
+
+	return h
+}
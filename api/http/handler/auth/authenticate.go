@@ -10,6 +10,7 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
 )
 
 type authenticatePayload struct {
@@ -42,6 +43,8 @@ func (handler *Handler) authenticate(w http.ResponseWriter, r *http.Request) *ht
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
+	security.SetAuditUsername(r, payload.Username)
+
 	settings, err := handler.SettingsService.Settings()
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
@@ -62,19 +65,19 @@ func (handler *Handler) authenticate(w http.ResponseWriter, r *http.Request) *ht
 		} else if u == nil && !settings.LDAPSettings.AutoCreateUsers {
 			return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", portainer.ErrUnauthorized}
 		}
-		return handler.authenticateLDAP(w, u, payload.Password, &settings.LDAPSettings)
+		return handler.authenticateLDAP(w, u, payload.Password, settings)
 	}
 
-	return handler.authenticateInternal(w, u, payload.Password)
+	return handler.authenticateInternal(w, u, payload.Password, settings)
 }
 
-func (handler *Handler) authenticateLDAP(w http.ResponseWriter, user *portainer.User, password string, ldapSettings *portainer.LDAPSettings) *httperror.HandlerError {
-	err := handler.LDAPService.AuthenticateUser(user.Username, password, ldapSettings)
+func (handler *Handler) authenticateLDAP(w http.ResponseWriter, user *portainer.User, password string, settings *portainer.Settings) *httperror.HandlerError {
+	err := handler.LDAPService.AuthenticateUser(user.Username, password, &settings.LDAPSettings)
 	if err != nil {
-		return handler.authenticateInternal(w, user, password)
+		return handler.authenticateInternal(w, user, password, settings)
 	}
 
-	err = handler.addUserIntoTeams(user, ldapSettings)
+	err = handler.addLDAPUserIntoTeams(user, &settings.LDAPSettings)
 	if err != nil {
 		log.Printf("Warning: unable to automatically add user into teams: %s\n", err.Error())
 	}
@@ -82,13 +85,30 @@ func (handler *Handler) authenticateLDAP(w http.ResponseWriter, user *portainer.
 	return handler.writeToken(w, user)
 }
 
-func (handler *Handler) authenticateInternal(w http.ResponseWriter, user *portainer.User, password string) *httperror.HandlerError {
+func (handler *Handler) authenticateInternal(w http.ResponseWriter, user *portainer.User, password string, settings *portainer.Settings) *httperror.HandlerError {
+	if accountLocked(user) {
+		return &httperror.HandlerError{http.StatusForbidden, "User account is locked", portainer.ErrUserLocked}
+	}
+
 	err := handler.CryptoService.CompareHashAndData(user.Password, password)
 	if err != nil {
+		err = handler.registerFailedAttempt(user, &settings.AccountLockout)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+		}
 		return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid credentials", portainer.ErrUnauthorized}
 	}
 
-	return handler.writeToken(w, user)
+	// The failed attempts of users with two-factor authentication are only cleared once
+	// the second factor is verified so that failed codes keep counting towards the lockout
+	if !user.TOTPEnabled {
+		err = handler.resetFailedAttempts(user)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+		}
+	}
+
+	return handler.writeTokenOrChallenge(w, user, settings)
 }
 
 func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, username, password string, ldapSettings *portainer.LDAPSettings) *httperror.HandlerError {
@@ -101,6 +121,7 @@ func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, use
 		Username:                username,
 		Role:                    portainer.StandardUserRole,
 		PortainerAuthorizations: portainer.DefaultPortainerAuthorizations(),
+		AuthenticationMethod:    portainer.AuthenticationLDAP,
 	}
 
 	err = handler.UserService.CreateUser(user)
@@ -108,7 +129,7 @@ func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, use
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user inside the database", err}
 	}
 
-	err = handler.addUserIntoTeams(user, ldapSettings)
+	err = handler.addLDAPUserIntoTeams(user, ldapSettings)
 	if err != nil {
 		log.Printf("Warning: unable to automatically add user into teams: %s\n", err.Error())
 	}
@@ -122,6 +143,10 @@ func (handler *Handler) authenticateLDAPAndCreateUser(w http.ResponseWriter, use
 }
 
 func (handler *Handler) writeToken(w http.ResponseWriter, user *portainer.User) *httperror.HandlerError {
+	if user.Disabled {
+		return &httperror.HandlerError{http.StatusForbidden, "User account is disabled", portainer.ErrUserDisabled}
+	}
+
 	tokenData := &portainer.TokenData{
 		ID:       user.ID,
 		Username: user.Username,
@@ -140,13 +165,18 @@ func (handler *Handler) persistAndWriteToken(w http.ResponseWriter, tokenData *p
 	return response.JSON(w, &authenticateResponse{JWT: token})
 }
 
-func (handler *Handler) addUserIntoTeams(user *portainer.User, settings *portainer.LDAPSettings) error {
-	teams, err := handler.TeamService.Teams()
+func (handler *Handler) addLDAPUserIntoTeams(user *portainer.User, settings *portainer.LDAPSettings) error {
+	userGroups, err := handler.LDAPService.GetUserGroups(user.Username, settings)
 	if err != nil {
 		return err
 	}
 
-	userGroups, err := handler.LDAPService.GetUserGroups(user.Username, settings)
+	return handler.addUserIntoTeams(user, userGroups)
+}
+
+// addUserIntoTeams adds the user into each team matching one of the specified groups.
+func (handler *Handler) addUserIntoTeams(user *portainer.User, userGroups []string) error {
+	teams, err := handler.TeamService.Teams()
 	if err != nil {
 		return err
 	}
@@ -178,8 +208,8 @@ func (handler *Handler) addUserIntoTeams(user *portainer.User, settings *portain
 	return nil
 }
 
-func teamExists(teamName string, ldapGroups []string) bool {
-	for _, group := range ldapGroups {
+func teamExists(teamName string, groups []string) bool {
+	for _, group := range groups {
 		if strings.ToLower(group) == strings.ToLower(teamName) {
 			return true
 		}
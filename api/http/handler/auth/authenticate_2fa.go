@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type twoFactorChallengeResponse struct {
+	ChallengeToken    string `json:"challengeToken"`
+	EnrolmentRequired bool   `json:"enrolmentRequired"`
+}
+
+type twoFactorAuthenticatePayload struct {
+	ChallengeToken string
+	Code           string
+}
+
+func (payload *twoFactorAuthenticatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.ChallengeToken) {
+		return portainer.Error("Invalid challenge token")
+	}
+	if govalidator.IsNull(payload.Code) {
+		return portainer.Error("Invalid code")
+	}
+	return nil
+}
+
+type twoFactorEnrolPayload struct {
+	ChallengeToken string
+}
+
+func (payload *twoFactorEnrolPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.ChallengeToken) {
+		return portainer.Error("Invalid challenge token")
+	}
+	return nil
+}
+
+// writeTokenOrChallenge writes a JWT token for the user unless a second authentication factor
+// is required, in which case a challenge token that must be redeemed on /api/auth/2fa is returned.
+func (handler *Handler) writeTokenOrChallenge(w http.ResponseWriter, user *portainer.User, settings *portainer.Settings) *httperror.HandlerError {
+	if user.Disabled {
+		return &httperror.HandlerError{http.StatusForbidden, "User account is disabled", portainer.ErrUserDisabled}
+	}
+
+	if user.TOTPEnabled {
+		token := handler.challenges.create(user.ID, twoFactorChallenge)
+		return response.JSON(w, &twoFactorChallengeResponse{ChallengeToken: token})
+	}
+
+	if user.Role == portainer.AdministratorRole && settings.EnforceTwoFactorForAdministrators {
+		token := handler.challenges.create(user.ID, twoFactorEnrolmentChallenge)
+		return response.JSON(w, &twoFactorChallengeResponse{ChallengeToken: token, EnrolmentRequired: true})
+	}
+
+	return handler.writeTokenOrPasswordChallenge(w, user, settings)
+}
+
+// POST request on /api/auth/2fa
+func (handler *Handler) authenticateTwoFactor(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload twoFactorAuthenticatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	user, handlerErr := handler.retrieveChallengeUser(payload.ChallengeToken, twoFactorChallenge, twoFactorEnrolmentChallenge)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if accountLocked(user) {
+		handler.challenges.remove(payload.ChallengeToken)
+		return &httperror.HandlerError{http.StatusForbidden, "User account is locked", portainer.ErrUserLocked}
+	}
+
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = handler.TOTPService.Verify(user, payload.Code)
+	if err == portainer.ErrTwoFactorInvalidCode {
+		handler.challenges.registerFailure(payload.ChallengeToken)
+
+		err = handler.registerFailedAttempt(user, &settings.AccountLockout)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+		}
+
+		if accountLocked(user) {
+			handler.challenges.remove(payload.ChallengeToken)
+		}
+
+		return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid two-factor authentication code", portainer.ErrTwoFactorInvalidCode}
+	} else if err == portainer.ErrTwoFactorNotEnrolled {
+		return &httperror.HandlerError{http.StatusBadRequest, "Two-factor authentication enrolment is required", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify two-factor authentication code", err}
+	}
+
+	handler.challenges.remove(payload.ChallengeToken)
+
+	user.TOTPEnabled = true
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = 0
+	err = handler.UserService.UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return handler.writeTokenOrPasswordChallenge(w, user, settings)
+}
+
+// POST request on /api/auth/2fa/enrol
+// Used by the administrators that must enrol before being able to authenticate when
+// two-factor authentication is enforced.
+func (handler *Handler) authenticateTwoFactorEnrol(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload twoFactorEnrolPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	user, handlerErr := handler.retrieveChallengeUser(payload.ChallengeToken, twoFactorEnrolmentChallenge)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	if user.TOTPEnabled {
+		return &httperror.HandlerError{http.StatusConflict, "Two-factor authentication is already enabled for this user", portainer.ErrTwoFactorAlreadyEnabled}
+	}
+
+	enrolment, err := handler.TOTPService.Enrol(user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create two-factor authentication enrolment", err}
+	}
+
+	err = handler.UserService.UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return response.JSON(w, enrolment)
+}
@@ -1,10 +1,12 @@
 package auth
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -12,8 +14,15 @@ import (
 	"github.com/portainer/portainer/api"
 )
 
+// oidcStateCookieName is the name of the cookie binding an OpenID Connect authorization
+// request to the browser that initiated it.
+const oidcStateCookieName = "portainer_oidc_state"
+
+const oidcStateCookieDuration = 10 * time.Minute
+
 type oauthPayload struct {
-	Code string
+	Code  string
+	State string
 }
 
 func (payload *oauthPayload) Validate(r *http.Request) error {
@@ -87,17 +96,36 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 		return &httperror.HandlerError{http.StatusForbidden, "OAuth authentication is not enabled", portainer.Error("OAuth authentication is not enabled")}
 	}
 
-	extension, err := handler.ExtensionService.Extension(portainer.OAuthAuthenticationExtension)
-	if err == portainer.ErrObjectNotFound {
-		return &httperror.HandlerError{http.StatusNotFound, "Oauth authentication extension is not enabled", err}
-	} else if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a extension with the specified identifier inside the database", err}
-	}
+	var username string
+	var groups []string
 
-	username, err := handler.authenticateThroughExtension(payload.Code, extension.License.LicenseKey, &settings.OAuthSettings)
-	if err != nil {
-		log.Printf("[DEBUG] - OAuth authentication error: %s", err)
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to authenticate through OAuth", portainer.ErrUnauthorized}
+	if settings.OAuthSettings.OIDCIssuerURL != "" {
+		cookie, err := r.Cookie(oidcStateCookieName)
+		clearOIDCStateCookie(w, r)
+		if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(payload.State)) != 1 {
+			return &httperror.HandlerError{http.StatusUnauthorized, "Unable to authenticate through OpenID Connect", portainer.ErrOIDCInvalidState}
+		}
+
+		identity, err := handler.OIDCService.Authenticate(payload.Code, payload.State, &settings.OAuthSettings)
+		if err != nil {
+			log.Printf("[DEBUG] - OpenID Connect authentication error: %s", err)
+			return &httperror.HandlerError{http.StatusUnauthorized, "Unable to authenticate through OpenID Connect", portainer.ErrUnauthorized}
+		}
+		username = identity.Username
+		groups = identity.Groups
+	} else {
+		extension, err := handler.ExtensionService.Extension(portainer.OAuthAuthenticationExtension)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Oauth authentication extension is not enabled", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a extension with the specified identifier inside the database", err}
+		}
+
+		username, err = handler.authenticateThroughExtension(payload.Code, extension.License.LicenseKey, &settings.OAuthSettings)
+		if err != nil {
+			log.Printf("[DEBUG] - OAuth authentication error: %s", err)
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to authenticate through OAuth", portainer.ErrUnauthorized}
+		}
 	}
 
 	user, err := handler.UserService.UserByUsername(username)
@@ -114,6 +142,7 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 			Username:                username,
 			Role:                    portainer.StandardUserRole,
 			PortainerAuthorizations: portainer.DefaultPortainerAuthorizations(),
+			AuthenticationMethod:    portainer.AuthenticationOAuth,
 		}
 
 		err = handler.UserService.CreateUser(user)
@@ -140,5 +169,56 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 		}
 	}
 
+	if settings.OAuthSettings.GroupsClaim != "" {
+		err = handler.addUserIntoTeams(user, groups)
+		if err != nil {
+			log.Printf("Warning: unable to automatically add user into teams: %s\n", err.Error())
+		}
+	}
+
 	return handler.writeToken(w, user)
 }
+
+// GET request on /api/auth/oauth/login
+// Redirects the user to the authorization endpoint of the OpenID Connect provider.
+// The state of the authorization request is stored inside a cookie that must be sent back
+// when the authorization code is validated.
+func (handler *Handler) oauthLogin(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	if settings.AuthenticationMethod != portainer.AuthenticationOAuth || settings.OAuthSettings.OIDCIssuerURL == "" {
+		return &httperror.HandlerError{http.StatusForbidden, "OpenID Connect authentication is not enabled", portainer.Error("OpenID Connect authentication is not enabled")}
+	}
+
+	authorizationURL, state, err := handler.OIDCService.AuthorizationURL(&settings.OAuthSettings)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the OpenID Connect provider configuration", err}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    state,
+		Path:     "/api/auth",
+		MaxAge:   int(oidcStateCookieDuration.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authorizationURL, http.StatusFound)
+	return nil
+}
+
+func clearOIDCStateCookie(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Path:     "/api/auth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
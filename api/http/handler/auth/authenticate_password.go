@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type passwordChangeChallengeResponse struct {
+	ChallengeToken         string `json:"challengeToken"`
+	PasswordChangeRequired bool   `json:"passwordChangeRequired"`
+}
+
+type passwordChangePayload struct {
+	ChallengeToken string
+	NewPassword    string
+}
+
+func (payload *passwordChangePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.ChallengeToken) {
+		return portainer.Error("Invalid challenge token")
+	}
+	if govalidator.IsNull(payload.NewPassword) {
+		return portainer.Error("Invalid new password")
+	}
+	return nil
+}
+
+// writeTokenOrPasswordChallenge writes a JWT token for the user unless the password of the user
+// has expired, in which case a challenge token that must be redeemed on /api/auth/passwd is returned.
+func (handler *Handler) writeTokenOrPasswordChallenge(w http.ResponseWriter, user *portainer.User, settings *portainer.Settings) *httperror.HandlerError {
+	if !security.PasswordExpired(user, &settings.PasswordPolicy) {
+		return handler.writeToken(w, user)
+	}
+
+	token := handler.challenges.create(user.ID, passwordChangeChallenge)
+
+	return response.JSON(w, &passwordChangeChallengeResponse{ChallengeToken: token, PasswordChangeRequired: true})
+}
+
+// POST request on /api/auth/passwd
+// Used by the users that must change their expired password before being able to authenticate.
+func (handler *Handler) authenticatePasswordChange(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload passwordChangePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	user, handlerErr := handler.retrieveChallengeUser(payload.ChallengeToken, passwordChangeChallenge)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = security.SetUserPassword(user, payload.NewPassword, &settings.PasswordPolicy, handler.CryptoService)
+	if policyError, ok := err.(*security.PasswordPolicyError); ok {
+		return security.WritePasswordPolicyError(w, policyError)
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", portainer.ErrCryptoHashFailure}
+	}
+
+	handler.challenges.remove(payload.ChallengeToken)
+
+	err = handler.UserService.UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return handler.writeToken(w, user)
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	challengeTimeout     = 5 * time.Minute
+	challengeMaxAttempts = 5
+)
+
+type challengeKind int
+
+const (
+	_ challengeKind = iota
+	// twoFactorChallenge requires the user to provide a second authentication factor
+	twoFactorChallenge
+	// twoFactorEnrolmentChallenge requires the user to enrol in two-factor authentication
+	twoFactorEnrolmentChallenge
+	// passwordChangeChallenge requires the user to change an expired password
+	passwordChangeChallenge
+)
+
+// authenticationChallenge represents a pending authentication of a user that successfully
+// provided their password but still needs to complete another step before receiving a JWT token.
+type authenticationChallenge struct {
+	kind      challengeKind
+	userID    portainer.UserID
+	attempts  int
+	expiresAt time.Time
+}
+
+// challengeStore keeps the pending authentication challenges in memory.
+type challengeStore struct {
+	mutex      sync.Mutex
+	challenges map[string]*authenticationChallenge
+}
+
+func newChallengeStore() *challengeStore {
+	return &challengeStore{
+		challenges: make(map[string]*authenticationChallenge),
+	}
+}
+
+// create registers a new challenge for the user and returns the associated token.
+func (store *challengeStore) create(userID portainer.UserID, kind challengeKind) string {
+	token := base64.RawURLEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	for key, challenge := range store.challenges {
+		if now.After(challenge.expiresAt) {
+			delete(store.challenges, key)
+		}
+	}
+
+	store.challenges[token] = &authenticationChallenge{
+		kind:      kind,
+		userID:    userID,
+		expiresAt: now.Add(challengeTimeout),
+	}
+
+	return token
+}
+
+// retrieve returns the challenge associated to the token if it has not expired.
+func (store *challengeStore) retrieve(token string) (authenticationChallenge, bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	challenge, ok := store.challenges[token]
+	if !ok {
+		return authenticationChallenge{}, false
+	}
+
+	if time.Now().After(challenge.expiresAt) {
+		delete(store.challenges, token)
+		return authenticationChallenge{}, false
+	}
+
+	return *challenge, true
+}
+
+// registerFailure records a failed attempt. The challenge is removed once the maximum
+// number of attempts is reached, the user must then authenticate again.
+func (store *challengeStore) registerFailure(token string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	challenge, ok := store.challenges[token]
+	if !ok {
+		return
+	}
+
+	challenge.attempts++
+	if challenge.attempts >= challengeMaxAttempts {
+		delete(store.challenges, token)
+	}
+}
+
+func (store *challengeStore) remove(token string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.challenges, token)
+}
+
+// retrieveChallengeUser returns the user associated to a pending challenge of one of the specified kinds.
+func (handler *Handler) retrieveChallengeUser(token string, kinds ...challengeKind) (*portainer.User, *httperror.HandlerError) {
+	challenge, ok := handler.challenges.retrieve(token)
+	if !ok || !containsChallengeKind(kinds, challenge.kind) {
+		return nil, &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid authentication challenge", portainer.ErrInvalidAuthChallenge}
+	}
+
+	user, err := handler.UserService.User(challenge.userID)
+	if err == portainer.ErrObjectNotFound {
+		handler.challenges.remove(token)
+		return nil, &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid authentication challenge", portainer.ErrInvalidAuthChallenge}
+	} else if err != nil {
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	return user, nil
+}
+
+func containsChallengeKind(kinds []challengeKind, kind challengeKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
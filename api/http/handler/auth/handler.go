@@ -22,10 +22,13 @@ const (
 type Handler struct {
 	*mux.Router
 	authDisabled          bool
+	challenges            *challengeStore
 	UserService           portainer.UserService
 	CryptoService         portainer.CryptoService
 	JWTService            portainer.JWTService
 	LDAPService           portainer.LDAPService
+	OIDCService           portainer.OIDCService
+	TOTPService           portainer.TOTPService
 	SettingsService       portainer.SettingsService
 	TeamService           portainer.TeamService
 	TeamMembershipService portainer.TeamMembershipService
@@ -42,12 +45,23 @@ func NewHandler(bouncer *security.RequestBouncer, rateLimiter *security.RateLimi
 	h := &Handler{
 		Router:       mux.NewRouter(),
 		authDisabled: authDisabled,
+		challenges:   newChallengeStore(),
 	}
 
+	h.Handle("/auth/oauth/login",
+		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.oauthLogin)))).Methods(http.MethodGet)
 	h.Handle("/auth/oauth/validate",
 		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.validateOAuth)))).Methods(http.MethodPost)
 	h.Handle("/auth",
 		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.authenticate)))).Methods(http.MethodPost)
+	h.Handle("/auth/2fa",
+		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.authenticateTwoFactor)))).Methods(http.MethodPost)
+	h.Handle("/auth/2fa/enrol",
+		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.authenticateTwoFactorEnrol)))).Methods(http.MethodPost)
+	h.Handle("/auth/passwd",
+		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.authenticatePasswordChange)))).Methods(http.MethodPost)
+	h.Handle("/auth/logout",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.logout))).Methods(http.MethodPost)
 
 	return h
 }
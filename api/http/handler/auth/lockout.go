@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"log"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// accountLocked returns true when the account of the user is locked after too many
+// failed authentication attempts and the lockout duration has not elapsed yet.
+func accountLocked(user *portainer.User) bool {
+	return user.LockedUntil > time.Now().Unix()
+}
+
+// registerFailedAttempt records a failed authentication attempt for the user and locks the
+// account once the maximum number of failed attempts defined in the settings is reached.
+// The counter is incremented on the stored user inside a single transaction so that concurrent
+// attempts are all counted, user is then refreshed with the stored values.
+func (handler *Handler) registerFailedAttempt(user *portainer.User, settings *portainer.AccountLockoutSettings) error {
+	if settings.MaxFailedAttempts <= 0 {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(settings.LockoutDuration)
+	if err != nil {
+		duration, _ = time.ParseDuration(portainer.DefaultAccountLockoutDuration)
+	}
+
+	locked := false
+	err = handler.UserService.UpdateUserFunc(user.ID, func(storedUser *portainer.User) {
+		storedUser.FailedLoginAttempts++
+		if storedUser.FailedLoginAttempts >= settings.MaxFailedAttempts {
+			storedUser.FailedLoginAttempts = 0
+			storedUser.LockedUntil = time.Now().Add(duration).Unix()
+			locked = true
+		}
+
+		user.FailedLoginAttempts = storedUser.FailedLoginAttempts
+		user.LockedUntil = storedUser.LockedUntil
+	})
+	if err != nil {
+		return err
+	}
+
+	if locked {
+		log.Printf("[WARN] [http,auth] [message: user account locked after too many failed authentication attempts] [user: %s] [duration: %s]", user.Username, duration)
+	}
+
+	return nil
+}
+
+// resetFailedAttempts clears the failed authentication attempts recorded for the user.
+func (handler *Handler) resetFailedAttempts(user *portainer.User) error {
+	if user.FailedLoginAttempts == 0 && user.LockedUntil == 0 {
+		return nil
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = 0
+
+	return handler.UserService.UpdateUserFunc(user.ID, func(storedUser *portainer.User) {
+		storedUser.FailedLoginAttempts = 0
+		storedUser.LockedUntil = 0
+	})
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// POST request on /api/auth/logout
+// The token used to authenticate the request is revoked until its expiry.
+func (handler *Handler) logout(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if handler.authDisabled {
+		return &httperror.HandlerError{http.StatusServiceUnavailable, "Cannot logout user. Portainer was started with the --no-auth flag", ErrAuthDisabled}
+	}
+
+	token := security.RetrieveRawToken(r)
+	if token == "" {
+		return response.Empty(w)
+	}
+
+	err := handler.JWTService.RevokeToken(token)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to revoke authentication token", err}
+	}
+
+	return response.Empty(w)
+}
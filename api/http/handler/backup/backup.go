@@ -0,0 +1,64 @@
+package backup
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+)
+
+type backupPayload struct {
+	Password string
+}
+
+func (payload *backupPayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// backupResponseWriter only sends the archive headers when the first bytes of the archive are
+// written, so that an error occurring before can still be returned as a regular error response.
+type backupResponseWriter struct {
+	http.ResponseWriter
+	fileName string
+	started  bool
+}
+
+func (w *backupResponseWriter) Write(data []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", "attachment; filename="+w.fileName)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// POST request on /api/backup
+// The archive is encrypted when a password is specified.
+// POST is used instead of GET so that the password is sent inside the request body and never
+// appears in the URL, where it would be recorded by proxies and access logs.
+func (handler *Handler) backup(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload backupPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	writer := &backupResponseWriter{
+		ResponseWriter: w,
+		fileName:       "portainer-backup-" + time.Now().UTC().Format("20060102-150405") + ".tar.gz",
+	}
+
+	err = handler.BackupService.CreateBackup(writer, payload.Password)
+	if err != nil && !writer.started {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create backup", err}
+	} else if err != nil {
+		// the archive is partially sent, the connection is aborted so that the client
+		// does not mistake the truncated archive for a complete one
+		log.Printf("[ERROR] [http,backup] [message: unable to create backup] [err: %s]", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package backup
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle backup operations.
+type Handler struct {
+	*mux.Router
+	BackupService portainer.BackupService
+	// ShutdownTrigger is called after a restore to stop the instance
+	ShutdownTrigger func()
+}
+
+// NewHandler creates a handler to manage backup operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/backup",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.backup))).Methods(http.MethodPost)
+	h.Handle("/restore",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.restore))).Methods(http.MethodPost)
+
+	return h
+}
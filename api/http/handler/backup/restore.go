@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type restorePayload struct {
+	Archive  multipart.File
+	Password string
+}
+
+// Validate retrieves the uploaded archive without loading it in memory, large
+// uploads are stored inside temporary files by the multipart parser.
+func (payload *restorePayload) Validate(r *http.Request) error {
+	archive, _, err := r.FormFile("file")
+	if err != nil {
+		return portainer.Error("Invalid backup archive file. Ensure that the file is uploaded correctly")
+	}
+	payload.Archive = archive
+
+	password, _ := request.RetrieveMultiPartFormValue(r, "Password", true)
+	payload.Password = password
+
+	return nil
+}
+
+// POST request on /api/restore
+// The instance is shut down once the data is restored so that the restored keys and settings
+// are loaded on the next start.
+func (handler *Handler) restore(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	payload := &restorePayload{}
+	err := payload.Validate(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+	defer payload.Archive.Close()
+
+	err = handler.BackupService.RestoreBackup(payload.Archive, payload.Password)
+	switch err {
+	case nil:
+	case portainer.ErrBackupInvalidArchive, portainer.ErrBackupPasswordRequired, portainer.ErrBackupDBVersionNotSupported:
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to restore backup", err}
+	case portainer.ErrBackupInvalidPassword:
+		return &httperror.HandlerError{http.StatusForbidden, "Unable to restore backup", err}
+	default:
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to restore backup", err}
+	}
+
+	if handler.ShutdownTrigger != nil {
+		handler.ShutdownTrigger()
+	}
+
+	return response.Empty(w)
+}
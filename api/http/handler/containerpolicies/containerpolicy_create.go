@@ -0,0 +1,79 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type containerPolicyCreatePayload struct {
+	Name                     string
+	EndpointGroupID          int
+	Mode                     int
+	AllowedRegistries        []string
+	ForbiddenCapabilities    []string
+	DenyHostNetwork          bool
+	DenyHostPID              bool
+	RequiredLabels           []string
+	RequireMemoryLimit       bool
+	AllowedBindMountPrefixes []string
+	DeniedDevices            []string
+}
+
+func (payload *containerPolicyCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid container policy name")
+	}
+	if payload.EndpointGroupID == 0 {
+		return portainer.Error("Invalid endpoint group identifier")
+	}
+	if !validMode(portainer.ContainerPolicyMode(payload.Mode)) {
+		return portainer.Error("Invalid container policy mode. Valid values are: 1 (warn) or 2 (enforce)")
+	}
+	return nil
+}
+
+// POST request on /api/container_policies
+func (handler *Handler) containerPolicyCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload containerPolicyCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	_, err = handler.EndpointGroupService.EndpointGroup(portainer.EndpointGroupID(payload.EndpointGroupID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	}
+
+	containerPolicy := &portainer.ContainerPolicy{
+		Name:                     payload.Name,
+		EndpointGroupID:          portainer.EndpointGroupID(payload.EndpointGroupID),
+		Mode:                     portainer.ContainerPolicyMode(payload.Mode),
+		AllowedRegistries:        payload.AllowedRegistries,
+		ForbiddenCapabilities:    payload.ForbiddenCapabilities,
+		DenyHostNetwork:          payload.DenyHostNetwork,
+		DenyHostPID:              payload.DenyHostPID,
+		RequiredLabels:           payload.RequiredLabels,
+		RequireMemoryLimit:       payload.RequireMemoryLimit,
+		AllowedBindMountPrefixes: payload.AllowedBindMountPrefixes,
+		DeniedDevices:            payload.DeniedDevices,
+	}
+
+	err = handler.ContainerPolicyService.CreateContainerPolicy(containerPolicy)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the container policy inside the database", err}
+	}
+
+	return response.JSON(w, containerPolicy)
+}
+
+func validMode(mode portainer.ContainerPolicyMode) bool {
+	return mode == portainer.ContainerPolicyWarn || mode == portainer.ContainerPolicyEnforce
+}
@@ -0,0 +1,32 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/container_policies/:id
+func (handler *Handler) containerPolicyDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container policy identifier route variable", err}
+	}
+
+	_, err = handler.ContainerPolicyService.ContainerPolicy(portainer.ContainerPolicyID(policyID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a container policy with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a container policy with the specified identifier inside the database", err}
+	}
+
+	err = handler.ContainerPolicyService.DeleteContainerPolicy(portainer.ContainerPolicyID(policyID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the container policy from the database", err}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,75 @@
+package containerpolicies
+
+import (
+	"encoding/json"
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/policy"
+)
+
+type containerPolicyDryRunPayload struct {
+	EndpointGroupID int
+	// Type of the payload to evaluate. Valid values are: container, service
+	Type    string
+	Payload json.RawMessage
+}
+
+type containerPolicyDryRunResponse struct {
+	Allowed    bool               `json:"Allowed"`
+	Violations []policy.Violation `json:"Violations"`
+}
+
+func (payload *containerPolicyDryRunPayload) Validate(r *http.Request) error {
+	if payload.EndpointGroupID == 0 {
+		return portainer.Error("Invalid endpoint group identifier")
+	}
+	if payload.Type != "container" && payload.Type != "service" {
+		return portainer.Error("Invalid payload type. Valid values are: container or service")
+	}
+	if len(payload.Payload) == 0 {
+		return portainer.Error("Invalid creation payload")
+	}
+	return nil
+}
+
+// POST request on /api/container_policies/dry_run
+func (handler *Handler) containerPolicyDryRun(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload containerPolicyDryRunPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	var spec *policy.ContainerSpec
+	if payload.Type == "service" {
+		spec, err = policy.ContainerSpecFromServiceSpec(payload.Payload)
+	} else {
+		spec, err = policy.ContainerSpecFromContainerCreate(payload.Payload)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid creation payload", err}
+	}
+
+	policies, err := handler.ContainerPolicyService.ContainerPolicies()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve container policies from the database", err}
+	}
+
+	groupPolicies := make([]portainer.ContainerPolicy, 0)
+	for _, containerPolicy := range policies {
+		if containerPolicy.EndpointGroupID == portainer.EndpointGroupID(payload.EndpointGroupID) {
+			groupPolicies = append(groupPolicies, containerPolicy)
+		}
+	}
+
+	violations := policy.Evaluate(groupPolicies, spec)
+
+	return response.JSON(w, &containerPolicyDryRunResponse{
+		Allowed:    !policy.Enforced(violations),
+		Violations: violations,
+	})
+}
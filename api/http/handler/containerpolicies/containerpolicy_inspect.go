@@ -0,0 +1,27 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/container_policies/:id
+func (handler *Handler) containerPolicyInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container policy identifier route variable", err}
+	}
+
+	containerPolicy, err := handler.ContainerPolicyService.ContainerPolicy(portainer.ContainerPolicyID(policyID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a container policy with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a container policy with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, containerPolicy)
+}
@@ -0,0 +1,18 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/container_policies
+func (handler *Handler) containerPolicyList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policies, err := handler.ContainerPolicyService.ContainerPolicies()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve container policies from the database", err}
+	}
+
+	return response.JSON(w, policies)
+}
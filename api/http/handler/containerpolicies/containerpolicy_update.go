@@ -0,0 +1,101 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type containerPolicyUpdatePayload struct {
+	Name                     *string
+	Mode                     *int
+	AllowedRegistries        []string
+	ForbiddenCapabilities    []string
+	DenyHostNetwork          *bool
+	DenyHostPID              *bool
+	RequiredLabels           []string
+	RequireMemoryLimit       *bool
+	AllowedBindMountPrefixes []string
+	DeniedDevices            []string
+}
+
+func (payload *containerPolicyUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return portainer.Error("Invalid container policy name")
+	}
+	if payload.Mode != nil && !validMode(portainer.ContainerPolicyMode(*payload.Mode)) {
+		return portainer.Error("Invalid container policy mode. Valid values are: 1 (warn) or 2 (enforce)")
+	}
+	return nil
+}
+
+// PUT request on /api/container_policies/:id
+func (handler *Handler) containerPolicyUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid container policy identifier route variable", err}
+	}
+
+	var payload containerPolicyUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	containerPolicy, err := handler.ContainerPolicyService.ContainerPolicy(portainer.ContainerPolicyID(policyID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a container policy with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a container policy with the specified identifier inside the database", err}
+	}
+
+	if payload.Name != nil {
+		containerPolicy.Name = *payload.Name
+	}
+
+	if payload.Mode != nil {
+		containerPolicy.Mode = portainer.ContainerPolicyMode(*payload.Mode)
+	}
+
+	if payload.AllowedRegistries != nil {
+		containerPolicy.AllowedRegistries = payload.AllowedRegistries
+	}
+
+	if payload.ForbiddenCapabilities != nil {
+		containerPolicy.ForbiddenCapabilities = payload.ForbiddenCapabilities
+	}
+
+	if payload.DenyHostNetwork != nil {
+		containerPolicy.DenyHostNetwork = *payload.DenyHostNetwork
+	}
+
+	if payload.DenyHostPID != nil {
+		containerPolicy.DenyHostPID = *payload.DenyHostPID
+	}
+
+	if payload.RequiredLabels != nil {
+		containerPolicy.RequiredLabels = payload.RequiredLabels
+	}
+
+	if payload.RequireMemoryLimit != nil {
+		containerPolicy.RequireMemoryLimit = *payload.RequireMemoryLimit
+	}
+
+	if payload.AllowedBindMountPrefixes != nil {
+		containerPolicy.AllowedBindMountPrefixes = payload.AllowedBindMountPrefixes
+	}
+
+	if payload.DeniedDevices != nil {
+		containerPolicy.DeniedDevices = payload.DeniedDevices
+	}
+
+	err = handler.ContainerPolicyService.UpdateContainerPolicy(containerPolicy.ID, containerPolicy)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist container policy changes inside the database", err}
+	}
+
+	return response.JSON(w, containerPolicy)
+}
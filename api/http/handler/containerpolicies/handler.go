@@ -0,0 +1,38 @@
+package containerpolicies
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle container policy operations.
+type Handler struct {
+	*mux.Router
+	ContainerPolicyService portainer.ContainerPolicyService
+	EndpointGroupService   portainer.EndpointGroupService
+}
+
+// NewHandler creates a handler to manage container policy operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/container_policies",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyCreate))).Methods(http.MethodPost)
+	h.Handle("/container_policies",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyList))).Methods(http.MethodGet)
+	h.Handle("/container_policies/dry_run",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyDryRun))).Methods(http.MethodPost)
+	h.Handle("/container_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyInspect))).Methods(http.MethodGet)
+	h.Handle("/container_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyUpdate))).Methods(http.MethodPut)
+	h.Handle("/container_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.containerPolicyDelete))).Methods(http.MethodDelete)
+
+	return h
+}
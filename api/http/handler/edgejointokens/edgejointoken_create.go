@@ -0,0 +1,97 @@
+package edgejointokens
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/securecookie"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type edgeJoinTokenCreatePayload struct {
+	Name            string
+	PortainerURL    string
+	EndpointGroupID int
+	Tags            []string
+	AutoApprove     bool
+	ExpiryDate      int64
+}
+
+func (payload *edgeJoinTokenCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid Edge join token name")
+	}
+	if govalidator.IsNull(payload.PortainerURL) || !govalidator.IsURL(payload.PortainerURL) {
+		return portainer.Error("Invalid Portainer URL")
+	}
+	portainerURL, err := url.Parse(payload.PortainerURL)
+	if err != nil || portainerURL.Hostname() == "localhost" {
+		return portainer.Error("Invalid Portainer URL. Cannot use localhost as Portainer URL")
+	}
+	if payload.EndpointGroupID == 0 {
+		payload.EndpointGroupID = 1
+	}
+	if payload.Tags == nil {
+		payload.Tags = make([]string, 0)
+	}
+	if payload.ExpiryDate != 0 && payload.ExpiryDate <= time.Now().Unix() {
+		return portainer.Error("Invalid Edge join token expiry date. Must be a timestamp in the future")
+	}
+	return nil
+}
+
+type edgeJoinTokenCreateResponse struct {
+	RawToken      string                   `json:"RawToken"`
+	EdgeJoinToken *portainer.EdgeJoinToken `json:"EdgeJoinToken"`
+}
+
+// POST request on /api/edge_join_tokens
+// The raw token is only returned by this operation, only its digest is persisted.
+func (handler *Handler) edgeJoinTokenCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeJoinTokenCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	_, err = handler.EndpointGroupService.EndpointGroup(portainer.EndpointGroupID(payload.EndpointGroupID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	}
+
+	rawToken := portainer.EdgeJoinTokenPrefix + base64.RawURLEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+
+	digest, err := handler.CryptoService.Hash(rawToken)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash Edge join token", portainer.ErrCryptoHashFailure}
+	}
+
+	token := &portainer.EdgeJoinToken{
+		Name:            payload.Name,
+		Prefix:          security.EdgeJoinTokenPrefix(rawToken),
+		Digest:          digest,
+		PortainerURL:    payload.PortainerURL,
+		EndpointGroupID: portainer.EndpointGroupID(payload.EndpointGroupID),
+		Tags:            payload.Tags,
+		AutoApprove:     payload.AutoApprove,
+		CreationDate:    time.Now().Unix(),
+		ExpiryDate:      payload.ExpiryDate,
+	}
+
+	err = handler.EdgeJoinTokenService.CreateEdgeJoinToken(token)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the Edge join token inside the database", err}
+	}
+
+	hideEdgeJoinTokenFields(token)
+	return response.JSON(w, &edgeJoinTokenCreateResponse{RawToken: rawToken, EdgeJoinToken: token})
+}
@@ -0,0 +1,32 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/edge_join_tokens/:id
+func (handler *Handler) edgeJoinTokenDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge join token identifier route variable", err}
+	}
+
+	_, err = handler.EdgeJoinTokenService.EdgeJoinToken(portainer.EdgeJoinTokenID(tokenID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	}
+
+	err = handler.EdgeJoinTokenService.DeleteEdgeJoinToken(portainer.EdgeJoinTokenID(tokenID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the Edge join token from the database", err}
+	}
+
+	return response.Empty(w)
+}
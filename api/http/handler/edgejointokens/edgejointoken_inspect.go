@@ -0,0 +1,56 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type edgeJoinTokenEndpoint struct {
+	ID                  portainer.EndpointID `json:"Id"`
+	Name                string               `json:"Name"`
+	EdgePendingApproval bool                 `json:"EdgePendingApproval"`
+}
+
+type edgeJoinTokenInspectResponse struct {
+	*portainer.EdgeJoinToken
+	Endpoints []edgeJoinTokenEndpoint `json:"Endpoints"`
+}
+
+// GET request on /api/edge_join_tokens/:id
+// The response contains the endpoints that registered themselves with the token.
+func (handler *Handler) edgeJoinTokenInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge join token identifier route variable", err}
+	}
+
+	token, err := handler.EdgeJoinTokenService.EdgeJoinToken(portainer.EdgeJoinTokenID(tokenID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	}
+
+	endpoints, err := handler.EndpointService.Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	tokenEndpoints := make([]edgeJoinTokenEndpoint, 0)
+	for _, endpoint := range endpoints {
+		if endpoint.EdgeJoinTokenID == token.ID {
+			tokenEndpoints = append(tokenEndpoints, edgeJoinTokenEndpoint{
+				ID:                  endpoint.ID,
+				Name:                endpoint.Name,
+				EdgePendingApproval: endpoint.EdgePendingApproval,
+			})
+		}
+	}
+
+	hideEdgeJoinTokenFields(token)
+	return response.JSON(w, &edgeJoinTokenInspectResponse{EdgeJoinToken: token, Endpoints: tokenEndpoints})
+}
@@ -0,0 +1,22 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/edge_join_tokens
+func (handler *Handler) edgeJoinTokenList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokens, err := handler.EdgeJoinTokenService.EdgeJoinTokens()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Edge join tokens from the database", err}
+	}
+
+	for idx := range tokens {
+		hideEdgeJoinTokenFields(&tokens[idx])
+	}
+
+	return response.JSON(w, tokens)
+}
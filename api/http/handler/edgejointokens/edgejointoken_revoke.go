@@ -0,0 +1,36 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// POST request on /api/edge_join_tokens/:id/revoke
+// A revoked token can no longer be used to register endpoints, its usage is kept for auditing.
+func (handler *Handler) edgeJoinTokenRevoke(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge join token identifier route variable", err}
+	}
+
+	token, err := handler.EdgeJoinTokenService.EdgeJoinToken(portainer.EdgeJoinTokenID(tokenID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge join token with the specified identifier inside the database", err}
+	}
+
+	token.Revoked = true
+
+	err = handler.EdgeJoinTokenService.UpdateEdgeJoinToken(token.ID, token)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist Edge join token changes inside the database", err}
+	}
+
+	hideEdgeJoinTokenFields(token)
+	return response.JSON(w, token)
+}
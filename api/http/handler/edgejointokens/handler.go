@@ -0,0 +1,42 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+func hideEdgeJoinTokenFields(token *portainer.EdgeJoinToken) {
+	token.Digest = ""
+}
+
+// Handler is the HTTP handler used to handle Edge join token operations.
+type Handler struct {
+	*mux.Router
+	EdgeJoinTokenService portainer.EdgeJoinTokenService
+	EndpointService      portainer.EndpointService
+	EndpointGroupService portainer.EndpointGroupService
+	CryptoService        portainer.CryptoService
+}
+
+// NewHandler creates a handler to manage Edge join token operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/edge_join_tokens",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenCreate))).Methods(http.MethodPost)
+	h.Handle("/edge_join_tokens",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenList))).Methods(http.MethodGet)
+	h.Handle("/edge_join_tokens/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenInspect))).Methods(http.MethodGet)
+	h.Handle("/edge_join_tokens/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenDelete))).Methods(http.MethodDelete)
+	h.Handle("/edge_join_tokens/{id}/revoke",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenRevoke))).Methods(http.MethodPost)
+
+	return h
+}
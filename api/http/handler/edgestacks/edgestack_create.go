@@ -0,0 +1,93 @@
+package edgestacks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+type edgeStackCreatePayload struct {
+	Name             string
+	StackFileContent string
+	EndpointGroups   []int
+	Tags             []string
+}
+
+func (payload *edgeStackCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid Edge stack name")
+	}
+	if govalidator.IsNull(payload.StackFileContent) {
+		return portainer.Error("Invalid stack file content")
+	}
+	if len(payload.EndpointGroups) == 0 && len(payload.Tags) == 0 {
+		return portainer.Error("Invalid Edge stack targets. At least one endpoint group or tag is required")
+	}
+	if payload.Tags == nil {
+		payload.Tags = make([]string, 0)
+	}
+	return nil
+}
+
+// POST request on /api/edge_stacks
+func (handler *Handler) edgeStackCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeStackCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	edgeStacks, err := handler.EdgeStackService.EdgeStacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Edge stacks from the database", err}
+	}
+
+	for _, edgeStack := range edgeStacks {
+		if strings.EqualFold(edgeStack.Name, payload.Name) {
+			return &httperror.HandlerError{http.StatusConflict, "An Edge stack with this name already exists", portainer.ErrEdgeStackAlreadyExists}
+		}
+	}
+
+	endpointGroups := toEndpointGroupIDs(payload.EndpointGroups)
+
+	err = handler.validateEdgeStackTargets(endpointGroups)
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+	}
+
+	edgeStackID := handler.EdgeStackService.GetNextIdentifier()
+	edgeStack := &portainer.EdgeStack{
+		ID:             portainer.EdgeStackID(edgeStackID),
+		Name:           payload.Name,
+		EndpointGroups: endpointGroups,
+		Tags:           payload.Tags,
+		EntryPoint:     filesystem.ComposeFileDefaultName,
+		Version:        1,
+		Status:         make(map[portainer.EndpointID]portainer.EdgeStackStatus),
+		CreationDate:   time.Now().Unix(),
+	}
+
+	edgeStackFolder := strconv.Itoa(int(edgeStack.ID))
+	projectPath, err := handler.FileService.StoreEdgeStackFileFromBytes(edgeStackFolder, edgeStack.EntryPoint, []byte(payload.StackFileContent))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist Compose file on disk", err}
+	}
+	edgeStack.ProjectPath = projectPath
+
+	err = handler.EdgeStackService.CreateEdgeStack(edgeStack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the Edge stack inside the database", err}
+	}
+
+	return response.JSON(w, edgeStack)
+}
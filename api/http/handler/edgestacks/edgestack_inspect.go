@@ -0,0 +1,75 @@
+package edgestacks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type edgeStackInspectResponse struct {
+	*portainer.EdgeStack
+	Deployments  []portainer.EdgeStackStatus `json:"Deployments"`
+	PendingCount int                         `json:"PendingCount"`
+	OkCount      int                         `json:"OkCount"`
+	ErrorCount   int                         `json:"ErrorCount"`
+}
+
+// GET request on /api/edge_stacks/:id
+// The response aggregates the deployment status of the stack on each endpoint it targets.
+// An endpoint that did not report the deployment of the latest version of the stack is pending.
+func (handler *Handler) edgeStackInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeStackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge stack identifier route variable", err}
+	}
+
+	edgeStack, err := handler.EdgeStackService.EdgeStack(portainer.EdgeStackID(edgeStackID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	}
+
+	endpoints, err := handler.EndpointService.Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	inspectResponse := &edgeStackInspectResponse{
+		EdgeStack:   edgeStack,
+		Deployments: make([]portainer.EdgeStackStatus, 0),
+	}
+
+	for idx := range endpoints {
+		endpoint := &endpoints[idx]
+		if !portainer.EdgeStackRelatedToEndpoint(edgeStack, endpoint) {
+			continue
+		}
+
+		status, ok := edgeStack.Status[endpoint.ID]
+		if !ok || status.Version < edgeStack.Version {
+			status = portainer.EdgeStackStatus{
+				EndpointID: endpoint.ID,
+				Type:       portainer.EdgeStackStatusPending,
+				Version:    edgeStack.Version,
+				UpdateDate: status.UpdateDate,
+			}
+		}
+
+		switch status.Type {
+		case portainer.EdgeStackStatusOk:
+			inspectResponse.OkCount++
+		case portainer.EdgeStackStatusError:
+			inspectResponse.ErrorCount++
+		default:
+			inspectResponse.PendingCount++
+		}
+
+		inspectResponse.Deployments = append(inspectResponse.Deployments, status)
+	}
+
+	return response.JSON(w, inspectResponse)
+}
@@ -0,0 +1,96 @@
+package edgestacks
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type edgeStackStatusUpdatePayload struct {
+	EndpointID int
+	Status     int
+	Error      string
+	Version    int
+}
+
+func (payload *edgeStackStatusUpdatePayload) Validate(r *http.Request) error {
+	if payload.EndpointID == 0 {
+		return portainer.Error("Invalid endpoint identifier")
+	}
+	status := portainer.EdgeStackStatusType(payload.Status)
+	if status != portainer.EdgeStackStatusOk && status != portainer.EdgeStackStatusError {
+		return portainer.Error("Invalid status. Valid values are: 2 (ok) or 3 (error)")
+	}
+	if payload.Version == 0 {
+		return portainer.Error("Invalid Edge stack version")
+	}
+	return nil
+}
+
+// PUT request on /api/edge_stacks/:id/status
+// Used by the Edge agents to report the deployment status of a stack on their endpoint.
+// The agent is identified by the X-PortainerAgent-EdgeID header.
+func (handler *Handler) edgeStackStatusUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeStackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge stack identifier route variable", err}
+	}
+
+	var payload edgeStackStatusUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	endpoint, err := handler.EndpointService.Endpoint(portainer.EndpointID(payload.EndpointID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	edgeIdentifier := r.Header.Get(portainer.PortainerAgentEdgeIDHeader)
+	if edgeIdentifier == "" || endpoint.EdgeID != edgeIdentifier {
+		return &httperror.HandlerError{http.StatusForbidden, "Invalid Edge identifier", portainer.Error("invalid Edge identifier")}
+	}
+
+	edgeStack, err := handler.EdgeStackService.EdgeStack(portainer.EdgeStackID(edgeStackID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	}
+
+	if !portainer.EdgeStackRelatedToEndpoint(edgeStack, endpoint) {
+		return &httperror.HandlerError{http.StatusForbidden, "The Edge stack is not deployed on this endpoint", portainer.ErrEdgeStackNotRelatedToAgent}
+	}
+
+	if payload.Version > edgeStack.Version {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge stack version", portainer.ErrEdgeStackVersionUnknown}
+	}
+
+	err = handler.EdgeStackService.UpdateEdgeStackFunc(edgeStack.ID, func(storedEdgeStack *portainer.EdgeStack) {
+		if storedEdgeStack.Status == nil {
+			storedEdgeStack.Status = make(map[portainer.EndpointID]portainer.EdgeStackStatus)
+		}
+
+		storedEdgeStack.Status[endpoint.ID] = portainer.EdgeStackStatus{
+			EndpointID: endpoint.ID,
+			Type:       portainer.EdgeStackStatusType(payload.Status),
+			Error:      payload.Error,
+			Version:    payload.Version,
+			UpdateDate: time.Now().Unix(),
+		}
+	})
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the Edge stack changes inside the database", err}
+	}
+
+	return response.Empty(w)
+}
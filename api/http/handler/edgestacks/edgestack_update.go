@@ -0,0 +1,94 @@
+package edgestacks
+
+import (
+	"net/http"
+	"strconv"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type edgeStackUpdatePayload struct {
+	StackFileContent *string
+	EndpointGroups   []int
+	Tags             []string
+}
+
+func (payload *edgeStackUpdatePayload) Validate(r *http.Request) error {
+	if payload.StackFileContent != nil && *payload.StackFileContent == "" {
+		return portainer.Error("Invalid stack file content")
+	}
+	return nil
+}
+
+// PUT request on /api/edge_stacks/:id
+// Updating the stack file increments the version of the stack so that the agents deploy it again.
+func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeStackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid Edge stack identifier route variable", err}
+	}
+
+	var payload edgeStackUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	handler.edgeStackMutex.Lock()
+	defer handler.edgeStackMutex.Unlock()
+
+	edgeStack, err := handler.EdgeStackService.EdgeStack(portainer.EdgeStackID(edgeStackID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	}
+
+	if payload.EndpointGroups != nil {
+		endpointGroups := toEndpointGroupIDs(payload.EndpointGroups)
+
+		err = handler.validateEdgeStackTargets(endpointGroups)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		}
+
+		edgeStack.EndpointGroups = endpointGroups
+	}
+
+	if payload.Tags != nil {
+		edgeStack.Tags = payload.Tags
+	}
+
+	if len(edgeStack.EndpointGroups) == 0 && len(edgeStack.Tags) == 0 {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", portainer.Error("Invalid Edge stack targets. At least one endpoint group or tag is required")}
+	}
+
+	if payload.StackFileContent != nil {
+		edgeStackFolder := strconv.Itoa(int(edgeStack.ID))
+		_, err = handler.FileService.StoreEdgeStackFileFromBytes(edgeStackFolder, edgeStack.EntryPoint, []byte(*payload.StackFileContent))
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist updated Compose file on disk", err}
+		}
+
+		edgeStack.Version++
+	}
+
+	err = handler.EdgeStackService.UpdateEdgeStackFunc(edgeStack.ID, func(storedEdgeStack *portainer.EdgeStack) {
+		storedEdgeStack.EndpointGroups = edgeStack.EndpointGroups
+		storedEdgeStack.Tags = edgeStack.Tags
+		storedEdgeStack.Version = edgeStack.Version
+		*edgeStack = *storedEdgeStack
+	})
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an Edge stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the Edge stack changes inside the database", err}
+	}
+
+	return response.JSON(w, edgeStack)
+}
@@ -0,0 +1,24 @@
+package edgestacks
+
+import (
+	"github.com/portainer/portainer/api"
+)
+
+// validateEdgeStackTargets verifies that the endpoint groups targeted by an Edge stack exist.
+func (handler *Handler) validateEdgeStackTargets(endpointGroups []portainer.EndpointGroupID) error {
+	for _, groupID := range endpointGroups {
+		_, err := handler.EndpointGroupService.EndpointGroup(groupID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toEndpointGroupIDs(identifiers []int) []portainer.EndpointGroupID {
+	groupIDs := make([]portainer.EndpointGroupID, 0)
+	for _, identifier := range identifiers {
+		groupIDs = append(groupIDs, portainer.EndpointGroupID(identifier))
+	}
+	return groupIDs
+}
@@ -15,6 +15,7 @@ type endpointGroupCreatePayload struct {
 	Description         string
 	AssociatedEndpoints []portainer.EndpointID
 	Tags                []string
+	ImageRestrictions   portainer.ImageRestrictions
 }
 
 func (payload *endpointGroupCreatePayload) Validate(r *http.Request) error {
@@ -41,6 +42,7 @@ func (handler *Handler) endpointGroupCreate(w http.ResponseWriter, r *http.Reque
 		UserAccessPolicies: portainer.UserAccessPolicies{},
 		TeamAccessPolicies: portainer.TeamAccessPolicies{},
 		Tags:               payload.Tags,
+		ImageRestrictions:  payload.ImageRestrictions,
 	}
 
 	err = handler.EndpointGroupService.CreateEndpointGroup(endpointGroup)
@@ -16,6 +16,7 @@ type endpointGroupUpdatePayload struct {
 	Tags               []string
 	UserAccessPolicies portainer.UserAccessPolicies
 	TeamAccessPolicies portainer.TeamAccessPolicies
+	ImageRestrictions  *portainer.ImageRestrictions
 }
 
 func (payload *endpointGroupUpdatePayload) Validate(r *http.Request) error {
@@ -54,6 +55,10 @@ func (handler *Handler) endpointGroupUpdate(w http.ResponseWriter, r *http.Reque
 		endpointGroup.Tags = payload.Tags
 	}
 
+	if payload.ImageRestrictions != nil {
+		endpointGroup.ImageRestrictions = *payload.ImageRestrictions
+	}
+
 	updateAuthorizations := false
 	if payload.UserAccessPolicies != nil && !reflect.DeepEqual(payload.UserAccessPolicies, endpointGroup.UserAccessPolicies) {
 		endpointGroup.UserAccessPolicies = payload.UserAccessPolicies
@@ -35,6 +35,10 @@ func (handler *Handler) proxyRequestsToDockerAPI(w http.ResponseWriter, r *http.
 			return &httperror.HandlerError{http.StatusInternalServerError, "No Edge agent registered with the endpoint", errors.New("No agent available")}
 		}
 
+		if endpoint.EdgePendingApproval {
+			return &httperror.HandlerError{http.StatusForbidden, "Edge endpoint is pending approval", portainer.ErrEndpointApprovalPending}
+		}
+
 		tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
 		if tunnel.Status == portainer.EdgeAgentIdle {
 			handler.ProxyManager.DeleteEndpointProxy(endpoint)
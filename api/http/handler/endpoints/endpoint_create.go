@@ -201,30 +201,35 @@ func (handler *Handler) createAzureEndpoint(payload *endpointCreatePayload) (*po
 }
 
 func (handler *Handler) createEdgeAgentEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
-	endpointType := portainer.EdgeAgentEnvironment
-	endpointID := handler.EndpointService.GetNextIdentifier()
-
-	portainerURL, err := url.Parse(payload.URL)
+	endpoint, err := handler.newEdgeAgentEndpoint(payload)
 	if err != nil {
 		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint URL", err}
 	}
 
-	portainerHost, _, err := net.SplitHostPort(portainerURL.Host)
+	err = handler.saveEndpointAndUpdateAuthorizations(endpoint)
 	if err != nil {
-		portainerHost = portainerURL.Host
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "An error occured while trying to create the endpoint", err}
 	}
 
-	if portainerHost == "localhost" {
-		return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint URL", errors.New("cannot use localhost as endpoint URL")}
+	return endpoint, nil
+}
+
+// newEdgeAgentEndpoint returns the Edge endpoint described by a creation payload, associated to a new
+// identifier and to a new Edge key. The URL of the payload is the URL of the Portainer instance.
+func (handler *Handler) newEdgeAgentEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, error) {
+	portainerHost, err := edgeEndpointHost(payload.URL)
+	if err != nil {
+		return nil, err
 	}
 
+	endpointID := handler.EndpointService.GetNextIdentifier()
 	edgeKey := handler.ReverseTunnelService.GenerateEdgeKey(payload.URL, portainerHost, endpointID)
 
-	endpoint := &portainer.Endpoint{
+	return &portainer.Endpoint{
 		ID:      portainer.EndpointID(endpointID),
 		Name:    payload.Name,
 		URL:     portainerHost,
-		Type:    endpointType,
+		Type:    portainer.EdgeAgentEnvironment,
 		GroupID: portainer.EndpointGroupID(payload.GroupID),
 		TLSConfig: portainer.TLSConfiguration{
 			TLS: false,
@@ -236,14 +241,30 @@ func (handler *Handler) createEdgeAgentEndpoint(payload *endpointCreatePayload)
 		Status:          portainer.EndpointStatusUp,
 		Snapshots:       []portainer.Snapshot{},
 		EdgeKey:         edgeKey,
+	}, nil
+}
+
+// edgeEndpointHost returns the host of the Portainer instance URL used by an Edge agent.
+func edgeEndpointHost(portainerURL string) (string, error) {
+	parsedURL, err := url.Parse(portainerURL)
+	if err != nil {
+		return "", err
 	}
 
-	err = handler.saveEndpointAndUpdateAuthorizations(endpoint)
+	portainerHost, _, err := net.SplitHostPort(parsedURL.Host)
 	if err != nil {
-		return nil, &httperror.HandlerError{http.StatusInternalServerError, "An error occured while trying to create the endpoint", err}
+		portainerHost = parsedURL.Host
 	}
 
-	return endpoint, nil
+	if portainerHost == "" {
+		return "", errors.New("missing host in endpoint URL")
+	}
+
+	if portainerHost == "localhost" {
+		return "", errors.New("cannot use localhost as endpoint URL")
+	}
+
+	return portainerHost, nil
 }
 
 func (handler *Handler) createUnsecuredEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
@@ -264,24 +285,7 @@ func (handler *Handler) createUnsecuredEndpoint(payload *endpointCreatePayload)
 		}
 	}
 
-	endpointID := handler.EndpointService.GetNextIdentifier()
-	endpoint := &portainer.Endpoint{
-		ID:        portainer.EndpointID(endpointID),
-		Name:      payload.Name,
-		URL:       payload.URL,
-		Type:      endpointType,
-		GroupID:   portainer.EndpointGroupID(payload.GroupID),
-		PublicURL: payload.PublicURL,
-		TLSConfig: portainer.TLSConfiguration{
-			TLS: false,
-		},
-		UserAccessPolicies: portainer.UserAccessPolicies{},
-		TeamAccessPolicies: portainer.TeamAccessPolicies{},
-		Extensions:         []portainer.EndpointExtension{},
-		Tags:               payload.Tags,
-		Status:             portainer.EndpointStatusUp,
-		Snapshots:          []portainer.Snapshot{},
-	}
+	endpoint := handler.newDockerEndpoint(payload, endpointType)
 
 	err := handler.snapshotAndPersistEndpoint(endpoint)
 	if err != nil {
@@ -307,8 +311,27 @@ func (handler *Handler) createTLSSecuredEndpoint(payload *endpointCreatePayload)
 		endpointType = portainer.AgentOnDockerEnvironment
 	}
 
+	endpoint := handler.newDockerEndpoint(payload, endpointType)
+
+	filesystemError := handler.storeTLSFiles(endpoint, payload)
+	if filesystemError != nil {
+		return nil, filesystemError
+	}
+
+	endpointCreationError := handler.snapshotAndPersistEndpoint(endpoint)
+	if endpointCreationError != nil {
+		return nil, endpointCreationError
+	}
+
+	return endpoint, nil
+}
+
+// newDockerEndpoint returns the Docker or agent endpoint described by a creation payload,
+// associated to a new identifier.
+func (handler *Handler) newDockerEndpoint(payload *endpointCreatePayload, endpointType portainer.EndpointType) *portainer.Endpoint {
 	endpointID := handler.EndpointService.GetNextIdentifier()
-	endpoint := &portainer.Endpoint{
+
+	return &portainer.Endpoint{
 		ID:        portainer.EndpointID(endpointID),
 		Name:      payload.Name,
 		URL:       payload.URL,
@@ -317,7 +340,7 @@ func (handler *Handler) createTLSSecuredEndpoint(payload *endpointCreatePayload)
 		PublicURL: payload.PublicURL,
 		TLSConfig: portainer.TLSConfiguration{
 			TLS:           payload.TLS,
-			TLSSkipVerify: payload.TLSSkipVerify,
+			TLSSkipVerify: payload.TLS && payload.TLSSkipVerify,
 		},
 		UserAccessPolicies: portainer.UserAccessPolicies{},
 		TeamAccessPolicies: portainer.TeamAccessPolicies{},
@@ -326,18 +349,6 @@ func (handler *Handler) createTLSSecuredEndpoint(payload *endpointCreatePayload)
 		Status:             portainer.EndpointStatusUp,
 		Snapshots:          []portainer.Snapshot{},
 	}
-
-	filesystemError := handler.storeTLSFiles(endpoint, payload)
-	if err != nil {
-		return nil, filesystemError
-	}
-
-	endpointCreationError := handler.snapshotAndPersistEndpoint(endpoint)
-	if endpointCreationError != nil {
-		return nil, endpointCreationError
-	}
-
-	return endpoint, nil
 }
 
 func (handler *Handler) snapshotAndPersistEndpoint(endpoint *portainer.Endpoint) *httperror.HandlerError {
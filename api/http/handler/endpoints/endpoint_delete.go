@@ -43,6 +43,35 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 
 	handler.ProxyManager.DeleteEndpointProxy(endpoint)
 
+	err = handler.SnapshotHistoryService.DeleteSnapshotRecords(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the snapshot history of the endpoint from the database", err}
+	}
+
+	err = handler.EndpointEventService.DeleteEndpointEvents(endpoint.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the events of the endpoint from the database", err}
+	}
+
+	edgeStacks, err := handler.EdgeStackService.EdgeStacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Edge stacks from the database", err}
+	}
+
+	for idx := range edgeStacks {
+		edgeStack := &edgeStacks[idx]
+		if _, ok := edgeStack.Status[endpoint.ID]; !ok {
+			continue
+		}
+
+		err = handler.EdgeStackService.UpdateEdgeStackFunc(edgeStack.ID, func(storedEdgeStack *portainer.EdgeStack) {
+			delete(storedEdgeStack.Status, endpoint.ID)
+		})
+		if err != nil && err != portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the Edge stack changes inside the database", err}
+		}
+	}
+
 	if len(endpoint.UserAccessPolicies) > 0 || len(endpoint.TeamAccessPolicies) > 0 {
 		err = handler.AuthorizationService.UpdateUsersAuthorizations()
 		if err != nil {
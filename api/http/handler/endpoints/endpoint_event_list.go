@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"net/http"
+	"time"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/endpoints/:id/events?from=<from>&to=<to>&type=<type>
+// from and to are optional Unix timestamps, all the events until now are returned by default.
+// type is an optional event type used to filter the events.
+func (handler *Handler) endpointEventList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint identifier route variable", err}
+	}
+
+	from, err := request.RetrieveNumericQueryParameter(r, "from", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: from", err}
+	}
+
+	to, err := request.RetrieveNumericQueryParameter(r, "to", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: to", err}
+	}
+
+	eventType, err := request.RetrieveNumericQueryParameter(r, "type", true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid query parameter: type", err}
+	}
+
+	if to == 0 {
+		to = int(time.Now().Unix())
+	}
+
+	if from > to {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid time range. from must be lower than to", portainer.Error("Invalid time range")}
+	}
+
+	endpoint, err := handler.EndpointService.Endpoint(portainer.EndpointID(endpointID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint, false)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	events, err := handler.EndpointEventService.EndpointEvents(endpoint.ID, int64(from), int64(to))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the events of the endpoint from the database", err}
+	}
+
+	if eventType != 0 {
+		events = filterEndpointEventsByType(events, portainer.EndpointEventType(eventType))
+	}
+
+	return response.JSON(w, events)
+}
+
+func filterEndpointEventsByType(events []portainer.EndpointEvent, eventType portainer.EndpointEventType) []portainer.EndpointEvent {
+	filteredEvents := make([]portainer.EndpointEvent, 0)
+
+	for _, event := range events {
+		if event.Type == eventType {
+			filteredEvents = append(filteredEvents, event)
+		}
+	}
+
+	return filteredEvents
+}
@@ -0,0 +1,518 @@
+package endpoints
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/http/client"
+)
+
+// endpointImportRow represents an endpoint described inside an import file.
+// Type is one of docker, agent or edge. Group is the name or the identifier of an endpoint group.
+// For Edge endpoints, URL is the URL of the Portainer instance used by the Edge agent.
+type endpointImportRow struct {
+	Name                string
+	URL                 string
+	Type                string
+	Group               string
+	Tags                []string
+	PublicURL           string
+	TLS                 bool
+	TLSSkipVerify       bool
+	TLSSkipClientVerify bool
+	TLSCACert           string
+	TLSCert             string
+	TLSKey              string
+}
+
+const (
+	// endpointImportMaxSize is the maximum size of an import file
+	endpointImportMaxSize = 10 << 20
+	// endpointImportConcurrency is the maximum number of endpoints pinged or snapshotted at the same time
+	endpointImportConcurrency = 10
+)
+
+type endpointImportResult struct {
+	Row        int                  `json:"Row"`
+	Name       string               `json:"Name"`
+	EndpointID portainer.EndpointID `json:"EndpointId,omitempty"`
+	EdgeKey    string               `json:"EdgeKey,omitempty"`
+	Error      string               `json:"Error,omitempty"`
+}
+
+type endpointImportErrorResponse struct {
+	Message string                 `json:"message"`
+	Details string                 `json:"details"`
+	Results []endpointImportResult `json:"results"`
+}
+
+// POST request on /api/endpoints/import
+// The body is either a JSON array of endpoints or a CSV file (Content-Type: text/csv) with a header row.
+// CSV columns: Name, URL, Type, Group, Tags (separated by ";"), PublicURL, TLS, TLSSkipVerify,
+// TLSSkipClientVerify, TLSCACert, TLSCert and TLSKey (PEM content).
+// All the rows are validated before any endpoint is created. When a row is invalid, no endpoint is
+// created and the result of each row is returned inside a bad request response.
+// The validation is syntactic, the Docker and agent endpoints are only pinged when the ping query
+// parameter is set to true.
+func (handler *Handler) endpointImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if !handler.authorizeEndpointManagement {
+		return &httperror.HandlerError{http.StatusServiceUnavailable, "Endpoint management is disabled", ErrEndpointManagementDisabled}
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, endpointImportMaxSize))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Unable to read request body", err}
+	}
+
+	var rows []endpointImportRow
+	if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+		rows, err = parseEndpointImportCSV(body)
+	} else {
+		err = json.Unmarshal(body, &rows)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid import file", err}
+	}
+
+	if len(rows) == 0 {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid import file", portainer.Error("The import file does not contain any endpoint")}
+	}
+
+	payloads, results, err := handler.validateEndpointImportRows(rows)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to validate the endpoints", err}
+	}
+
+	ping, _ := request.RetrieveBooleanQueryParameter(r, "ping", true)
+	if ping {
+		pingImportedEndpoints(payloads, results, pingImportedEndpoint)
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			return writeEndpointImportError(w, results)
+		}
+	}
+
+	endpoints := make([]*portainer.Endpoint, 0)
+	for idx, payload := range payloads {
+		endpoint, handlerErr := handler.importEndpoint(payload)
+		if handlerErr != nil {
+			handler.rollbackEndpointImport(endpoints)
+			return handlerErr
+		}
+
+		endpoints = append(endpoints, endpoint)
+		results[idx].EndpointID = endpoint.ID
+		results[idx].EdgeKey = endpoint.EdgeKey
+	}
+
+	err = handler.AuthorizationService.UpdateUsersAuthorizations()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update user authorizations", err}
+	}
+
+	go handler.snapshotImportedEndpoints(endpoints)
+
+	return response.JSON(w, results)
+}
+
+func writeEndpointImportError(w http.ResponseWriter, results []endpointImportResult) *httperror.HandlerError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	err := json.NewEncoder(w).Encode(&endpointImportErrorResponse{
+		Message: "Invalid import file",
+		Details: "One or more endpoints are invalid, no endpoint was created",
+		Results: results,
+	})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to write JSON response", err}
+	}
+
+	return nil
+}
+
+func parseEndpointImportCSV(data []byte) ([]endpointImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for idx, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = idx
+	}
+
+	if _, ok := columns["name"]; !ok {
+		return nil, portainer.Error("Missing Name column")
+	}
+
+	rows := make([]endpointImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		value := func(column string) string {
+			idx, ok := columns[strings.ToLower(column)]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		boolValue := func(column string) (bool, error) {
+			if value(column) == "" {
+				return false, nil
+			}
+			return strconv.ParseBool(value(column))
+		}
+
+		row := endpointImportRow{
+			Name:      value("Name"),
+			URL:       value("URL"),
+			Type:      value("Type"),
+			Group:     value("Group"),
+			PublicURL: value("PublicURL"),
+			TLSCACert: value("TLSCACert"),
+			TLSCert:   value("TLSCert"),
+			TLSKey:    value("TLSKey"),
+		}
+
+		for _, tag := range strings.Split(value("Tags"), ";") {
+			if strings.TrimSpace(tag) != "" {
+				row.Tags = append(row.Tags, strings.TrimSpace(tag))
+			}
+		}
+
+		row.TLS, err = boolValue("TLS")
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS value on row %d", len(rows)+1)
+		}
+
+		row.TLSSkipVerify, err = boolValue("TLSSkipVerify")
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLSSkipVerify value on row %d", len(rows)+1)
+		}
+
+		row.TLSSkipClientVerify, err = boolValue("TLSSkipClientVerify")
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLSSkipClientVerify value on row %d", len(rows)+1)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// validateEndpointImportRows validates each row and returns the associated creation payloads.
+// The error of an invalid row is reported inside its result. The endpoints are not pinged.
+func (handler *Handler) validateEndpointImportRows(rows []endpointImportRow) ([]*endpointCreatePayload, []endpointImportResult, error) {
+	endpointGroups, err := handler.EndpointGroupService.EndpointGroups()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoints, err := handler.EndpointService.Endpoints()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		names[strings.ToLower(endpoint.Name)] = true
+	}
+
+	payloads := make([]*endpointCreatePayload, len(rows))
+	results := make([]endpointImportResult, len(rows))
+	for idx := range rows {
+		row := &rows[idx]
+		results[idx] = endpointImportResult{Row: idx + 1, Name: row.Name}
+
+		payload, err := validateEndpointImportRow(row, endpointGroups)
+		if err == nil && names[strings.ToLower(row.Name)] {
+			err = portainer.Error("An endpoint with the same name already exists")
+		}
+
+		if err != nil {
+			results[idx].Error = err.Error()
+			continue
+		}
+
+		names[strings.ToLower(row.Name)] = true
+		payloads[idx] = payload
+	}
+
+	return payloads, results, nil
+}
+
+func validateEndpointImportRow(row *endpointImportRow, endpointGroups []portainer.EndpointGroup) (*endpointCreatePayload, error) {
+	if strings.TrimSpace(row.Name) == "" {
+		return nil, portainer.Error("Invalid endpoint name")
+	}
+
+	if row.URL == "" {
+		return nil, portainer.Error("Invalid endpoint URL")
+	}
+
+	payload := &endpointCreatePayload{
+		Name:                row.Name,
+		URL:                 row.URL,
+		PublicURL:           row.PublicURL,
+		GroupID:             1,
+		Tags:                row.Tags,
+		TLS:                 row.TLS,
+		TLSSkipVerify:       row.TLSSkipVerify,
+		TLSSkipClientVerify: row.TLSSkipClientVerify,
+	}
+
+	if payload.Tags == nil {
+		payload.Tags = make([]string, 0)
+	}
+
+	switch strings.ToLower(row.Type) {
+	case "docker", "1":
+		payload.EndpointType = int(portainer.DockerEnvironment)
+	case "agent", "2":
+		payload.EndpointType = int(portainer.AgentOnDockerEnvironment)
+	case "edge", "4":
+		payload.EndpointType = int(portainer.EdgeAgentEnvironment)
+	default:
+		return nil, portainer.Error("Invalid endpoint type value. Value must be one of: docker, agent or edge")
+	}
+
+	if row.Group != "" {
+		groupID, err := findEndpointImportGroup(row.Group, endpointGroups)
+		if err != nil {
+			return nil, err
+		}
+		payload.GroupID = int(groupID)
+	}
+
+	if portainer.EndpointType(payload.EndpointType) == portainer.EdgeAgentEnvironment {
+		_, err := edgeEndpointHost(payload.URL)
+		if err != nil {
+			return nil, portainer.Error("Invalid endpoint URL: " + err.Error())
+		}
+		return payload, nil
+	}
+
+	if payload.TLS {
+		if !payload.TLSSkipVerify {
+			if row.TLSCACert == "" {
+				return nil, portainer.Error("Invalid CA certificate")
+			}
+			payload.TLSCACertFile = []byte(row.TLSCACert)
+		}
+
+		if !payload.TLSSkipClientVerify {
+			if row.TLSCert == "" || row.TLSKey == "" {
+				return nil, portainer.Error("Invalid certificate or key")
+			}
+			payload.TLSCertFile = []byte(row.TLSCert)
+			payload.TLSKeyFile = []byte(row.TLSKey)
+		}
+
+		_, err := crypto.CreateTLSConfigurationFromBytes(payload.TLSCACertFile, payload.TLSCertFile, payload.TLSKeyFile, payload.TLSSkipClientVerify, payload.TLSSkipVerify)
+		if err != nil {
+			return nil, portainer.Error("Invalid TLS configuration: " + err.Error())
+		}
+	}
+
+	return payload, nil
+}
+
+func findEndpointImportGroup(group string, endpointGroups []portainer.EndpointGroup) (portainer.EndpointGroupID, error) {
+	groupID, err := strconv.Atoi(group)
+
+	for _, endpointGroup := range endpointGroups {
+		if (err == nil && int(endpointGroup.ID) == groupID) || strings.EqualFold(endpointGroup.Name, group) {
+			return endpointGroup.ID, nil
+		}
+	}
+
+	return 0, portainer.Error("Unable to find endpoint group " + group)
+}
+
+// pingImportedEndpoints pings the Docker and agent endpoints of the valid rows concurrently.
+// The error of an endpoint that cannot be reached is reported inside the result of its row.
+func pingImportedEndpoints(payloads []*endpointCreatePayload, results []endpointImportResult, ping func(payload *endpointCreatePayload) error) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, endpointImportConcurrency)
+
+	for idx, payload := range payloads {
+		if payload == nil || portainer.EndpointType(payload.EndpointType) == portainer.EdgeAgentEnvironment {
+			continue
+		}
+
+		wg.Add(1)
+		go func(idx int, payload *endpointCreatePayload) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			err := ping(payload)
+			if err != nil {
+				results[idx].Error = err.Error()
+			}
+		}(idx, payload)
+	}
+
+	wg.Wait()
+}
+
+// pingImportedEndpoint verifies that a Docker or agent endpoint is reachable
+// and that its type matches the type declared inside the import file.
+func pingImportedEndpoint(payload *endpointCreatePayload) error {
+	var tlsConfig *tls.Config
+	if payload.TLS {
+		config, err := crypto.CreateTLSConfigurationFromBytes(payload.TLSCACertFile, payload.TLSCertFile, payload.TLSKeyFile, payload.TLSSkipClientVerify, payload.TLSSkipVerify)
+		if err != nil {
+			return portainer.Error("Invalid TLS configuration: " + err.Error())
+		}
+		tlsConfig = config
+	}
+
+	agentOnDockerEnvironment, err := client.ExecutePingOperation(payload.URL, tlsConfig)
+	if err != nil {
+		return portainer.Error("Unable to ping Docker environment: " + err.Error())
+	}
+
+	if agentOnDockerEnvironment != (portainer.EndpointType(payload.EndpointType) == portainer.AgentOnDockerEnvironment) {
+		return portainer.Error("The endpoint type does not match the environment reachable at the endpoint URL")
+	}
+
+	return nil
+}
+
+// importEndpoint creates an endpoint of a validated row. The endpoint is not snapshotted.
+func (handler *Handler) importEndpoint(payload *endpointCreatePayload) (*portainer.Endpoint, *httperror.HandlerError) {
+	var endpoint *portainer.Endpoint
+
+	if portainer.EndpointType(payload.EndpointType) == portainer.EdgeAgentEnvironment {
+		edgeEndpoint, err := handler.newEdgeAgentEndpoint(payload)
+		if err != nil {
+			return nil, &httperror.HandlerError{http.StatusBadRequest, "Invalid endpoint URL", err}
+		}
+		endpoint = edgeEndpoint
+	} else {
+		endpoint = handler.newDockerEndpoint(payload, portainer.EndpointType(payload.EndpointType))
+
+		if payload.TLS {
+			handlerErr := handler.storeTLSFiles(endpoint, payload)
+			if handlerErr != nil {
+				handler.deleteImportedEndpointTLSFiles(endpoint)
+				return nil, handlerErr
+			}
+		}
+	}
+
+	err := handler.EndpointService.CreateEndpoint(endpoint)
+	if err != nil {
+		handler.deleteImportedEndpointTLSFiles(endpoint)
+		return nil, &httperror.HandlerError{http.StatusInternalServerError, "An error occured while trying to create the endpoint " + endpoint.Name, err}
+	}
+
+	return endpoint, nil
+}
+
+// snapshotImportedEndpoints snapshots the imported Docker and agent endpoints concurrently,
+// once the import request has been answered.
+func (handler *Handler) snapshotImportedEndpoints(endpoints []*portainer.Endpoint) {
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, endpointImportConcurrency)
+
+	for _, endpoint := range endpoints {
+		if endpoint.Type == portainer.EdgeAgentEnvironment {
+			continue
+		}
+
+		wg.Add(1)
+		go func(endpoint *portainer.Endpoint) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			handler.snapshotImportedEndpoint(endpoint)
+		}(endpoint)
+	}
+
+	wg.Wait()
+}
+
+func (handler *Handler) snapshotImportedEndpoint(endpoint *portainer.Endpoint) {
+	snapshot, err := handler.Snapshotter.CreateSnapshot(endpoint)
+	if err != nil {
+		log.Printf("[WARN] [http,endpoints,import] [message: unable to snapshot imported endpoint] [endpoint: %s] [err: %s]", endpoint.Name, err)
+		return
+	}
+
+	if snapshot == nil {
+		return
+	}
+
+	latestEndpointReference, err := handler.EndpointService.Endpoint(endpoint.ID)
+	if err != nil {
+		log.Printf("[WARN] [http,endpoints,import] [message: unable to retrieve imported endpoint] [endpoint: %s] [err: %s]", endpoint.Name, err)
+		return
+	}
+
+	latestEndpointReference.Snapshots = []portainer.Snapshot{*snapshot}
+
+	err = handler.EndpointService.UpdateEndpoint(latestEndpointReference.ID, latestEndpointReference)
+	if err != nil {
+		log.Printf("[WARN] [http,endpoints,import] [message: unable to persist the snapshot of imported endpoint] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+}
+
+// rollbackEndpointImport removes the endpoints created by an import that could not be completed.
+func (handler *Handler) rollbackEndpointImport(endpoints []*portainer.Endpoint) {
+	for _, endpoint := range endpoints {
+		err := handler.EndpointService.DeleteEndpoint(endpoint.ID)
+		if err != nil {
+			log.Printf("[WARN] [http,endpoints,import] [message: unable to remove imported endpoint] [endpoint: %s] [err: %s]", endpoint.Name, err)
+		}
+
+		handler.deleteImportedEndpointTLSFiles(endpoint)
+	}
+}
+
+// deleteImportedEndpointTLSFiles removes the TLS files stored for an imported endpoint.
+// It is also used when the endpoint could not be created, as only its TLS files exist.
+func (handler *Handler) deleteImportedEndpointTLSFiles(endpoint *portainer.Endpoint) {
+	if !endpoint.TLSConfig.TLS {
+		return
+	}
+
+	err := handler.FileService.DeleteTLSFiles(strconv.Itoa(int(endpoint.ID)))
+	if err != nil {
+		log.Printf("[WARN] [http,endpoints,import] [message: unable to remove TLS files of imported endpoint] [endpoint: %s] [err: %s]", endpoint.Name, err)
+	}
+}
@@ -0,0 +1,241 @@
+package endpoints
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+// testEndpointService stores the endpoints in memory and only implements the operations used by the handler tests.
+type testEndpointService struct {
+	portainer.EndpointService
+	endpoints []portainer.Endpoint
+	createErr error
+}
+
+func (service *testEndpointService) Endpoint(ID portainer.EndpointID) (*portainer.Endpoint, error) {
+	for _, endpoint := range service.endpoints {
+		if endpoint.ID == ID {
+			return &endpoint, nil
+		}
+	}
+	return nil, portainer.ErrObjectNotFound
+}
+
+func (service *testEndpointService) Endpoints() ([]portainer.Endpoint, error) {
+	return append([]portainer.Endpoint{}, service.endpoints...), nil
+}
+
+func (service *testEndpointService) CreateEndpoint(endpoint *portainer.Endpoint) error {
+	if service.createErr != nil {
+		return service.createErr
+	}
+	service.endpoints = append(service.endpoints, *endpoint)
+	return nil
+}
+
+func (service *testEndpointService) UpdateEndpoint(ID portainer.EndpointID, endpoint *portainer.Endpoint) error {
+	for idx := range service.endpoints {
+		if service.endpoints[idx].ID == ID {
+			service.endpoints[idx] = *endpoint
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+func (service *testEndpointService) GetNextIdentifier() int {
+	return len(service.endpoints) + 1
+}
+
+// testEndpointGroupService only implements the endpoint group operations used by the handler tests.
+type testEndpointGroupService struct {
+	portainer.EndpointGroupService
+	endpointGroups []portainer.EndpointGroup
+}
+
+func (service *testEndpointGroupService) EndpointGroup(ID portainer.EndpointGroupID) (*portainer.EndpointGroup, error) {
+	for _, endpointGroup := range service.endpointGroups {
+		if endpointGroup.ID == ID {
+			return &endpointGroup, nil
+		}
+	}
+	return nil, portainer.ErrObjectNotFound
+}
+
+func (service *testEndpointGroupService) EndpointGroups() ([]portainer.EndpointGroup, error) {
+	return service.endpointGroups, nil
+}
+
+// testFileService stores the TLS files in memory.
+type testFileService struct {
+	portainer.FileService
+	tlsFiles map[string]int
+}
+
+func (service *testFileService) StoreTLSFileFromBytes(folder string, fileType portainer.TLSFileType, data []byte) (string, error) {
+	service.tlsFiles[folder]++
+	return folder, nil
+}
+
+func (service *testFileService) DeleteTLSFiles(folder string) error {
+	delete(service.tlsFiles, folder)
+	return nil
+}
+
+func TestParseEndpointImportCSV(t *testing.T) {
+	data := []byte("name,url,type,group,tags,tls,tlsskipverify\n" +
+		"docker-1,tcp://10.0.0.1:2375,docker,production,web; db ,false,\n" +
+		"edge-1,https://portainer.example.com,edge,,,,\n")
+
+	rows, err := parseEndpointImportCSV(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+
+	if rows[0].Name != "docker-1" || rows[0].Group != "production" || len(rows[0].Tags) != 2 || rows[0].Tags[1] != "db" {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+
+	if rows[1].Type != "edge" || rows[1].TLS || len(rows[1].Tags) != 0 {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+
+	_, err = parseEndpointImportCSV([]byte("name,tls\ndocker-1,maybe\n"))
+	if err == nil {
+		t.Error("expected an invalid TLS value to be rejected")
+	}
+
+	_, err = parseEndpointImportCSV([]byte("url\ntcp://10.0.0.1:2375\n"))
+	if err == nil {
+		t.Error("expected a file without a Name column to be rejected")
+	}
+}
+
+func TestValidateEndpointImportRows(t *testing.T) {
+	handler := &Handler{
+		EndpointService: &testEndpointService{
+			endpoints: []portainer.Endpoint{{ID: 1, Name: "existing"}},
+		},
+		EndpointGroupService: &testEndpointGroupService{
+			endpointGroups: []portainer.EndpointGroup{{ID: 1, Name: "Unassigned"}, {ID: 2, Name: "Production"}},
+		},
+	}
+
+	rows := []endpointImportRow{
+		{Name: "docker-1", URL: "tcp://10.0.0.1:2375", Type: "docker", Group: "production"},
+		{Name: "edge-1", URL: "https://portainer.example.com", Type: "edge", Group: "2"},
+		{Name: "Existing", URL: "tcp://10.0.0.2:2375", Type: "docker"},
+		{Name: "docker-1", URL: "tcp://10.0.0.3:2375", Type: "docker"},
+		{Name: "edge-2", URL: "https://localhost:9000", Type: "edge"},
+		{Name: "tls-1", URL: "tcp://10.0.0.4:2376", Type: "agent", TLS: true},
+		{Name: "tls-2", URL: "tcp://10.0.0.7:2376", Type: "docker", TLS: true, TLSSkipVerify: true, TLSCert: "cert", TLSKey: "key"},
+		{Name: "other", URL: "tcp://10.0.0.5:2375", Type: "kubernetes"},
+		{Name: "grouped", URL: "tcp://10.0.0.6:2375", Type: "docker", Group: "staging"},
+	}
+
+	payloads, results, err := handler.validateEndpointImportRows(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for idx, result := range results {
+		valid := idx < 2
+		if valid && (result.Error != "" || payloads[idx] == nil) {
+			t.Errorf("expected row %d to be valid, got: %s", result.Row, result.Error)
+		} else if !valid && (result.Error == "" || payloads[idx] != nil) {
+			t.Errorf("expected row %d to be invalid", result.Row)
+		}
+	}
+
+	if payloads[0].GroupID != 2 || payloads[1].GroupID != 2 {
+		t.Errorf("expected the endpoint group to be found by name and by identifier, got %d and %d", payloads[0].GroupID, payloads[1].GroupID)
+	}
+
+	if portainer.EndpointType(payloads[1].EndpointType) != portainer.EdgeAgentEnvironment {
+		t.Errorf("expected an Edge endpoint, got type %d", payloads[1].EndpointType)
+	}
+}
+
+func TestPingImportedEndpoints(t *testing.T) {
+	payloads := []*endpointCreatePayload{
+		{Name: "docker-1", EndpointType: int(portainer.DockerEnvironment)},
+		nil,
+		{Name: "edge-1", EndpointType: int(portainer.EdgeAgentEnvironment)},
+		{Name: "agent-1", EndpointType: int(portainer.AgentOnDockerEnvironment)},
+	}
+	results := make([]endpointImportResult, len(payloads))
+
+	var mu sync.Mutex
+	pinged := make(map[string]bool)
+	ping := func(payload *endpointCreatePayload) error {
+		mu.Lock()
+		pinged[payload.Name] = true
+		mu.Unlock()
+
+		if payload.Name == "agent-1" {
+			return errors.New("unreachable")
+		}
+		return nil
+	}
+
+	pingImportedEndpoints(payloads, results, ping)
+
+	if len(pinged) != 2 || !pinged["docker-1"] || !pinged["agent-1"] {
+		t.Errorf("expected only the Docker and agent endpoints to be pinged, got %v", pinged)
+	}
+
+	if results[0].Error != "" || results[3].Error != "unreachable" {
+		t.Errorf("expected the ping error to be reported on the agent row only, got %+v", results)
+	}
+}
+
+func TestEndpointImportBodyTooLarge(t *testing.T) {
+	handler := &Handler{authorizeEndpointManagement: true}
+
+	body := bytes.Repeat([]byte("a"), endpointImportMaxSize+1)
+	r := httptest.NewRequest(http.MethodPost, "/endpoints/import", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handlerErr := handler.endpointImport(w, r)
+	if handlerErr == nil || handlerErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a bad request error, got %v", handlerErr)
+	}
+}
+
+func TestImportEndpointCreationFailure(t *testing.T) {
+	endpointService := &testEndpointService{
+		endpoints: []portainer.Endpoint{{ID: 1, Name: "existing"}},
+		createErr: errors.New("database error"),
+	}
+	fileService := &testFileService{tlsFiles: make(map[string]int)}
+	handler := &Handler{EndpointService: endpointService, FileService: fileService}
+
+	payload := &endpointCreatePayload{
+		Name:                "tls-1",
+		URL:                 "tcp://10.0.0.1:2376",
+		EndpointType:        int(portainer.DockerEnvironment),
+		TLS:                 true,
+		TLSSkipClientVerify: true,
+		TLSCACertFile:       []byte("ca"),
+	}
+
+	// testEndpointService does not implement DeleteEndpoint: the endpoint that was never created must not be removed
+	_, handlerErr := handler.importEndpoint(payload)
+	if handlerErr == nil || handlerErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected an internal server error, got %v", handlerErr)
+	}
+
+	if len(fileService.tlsFiles) != 0 {
+		t.Errorf("expected the TLS files of the endpoint to be removed, got %v", fileService.tlsFiles)
+	}
+}
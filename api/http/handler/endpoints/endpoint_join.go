@@ -0,0 +1,155 @@
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type endpointJoinPayload struct {
+	Token string
+	Name  string
+}
+
+func (payload *endpointJoinPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Token) {
+		return portainer.Error("Invalid Edge join token")
+	}
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid endpoint name")
+	}
+	return nil
+}
+
+type endpointJoinResponse struct {
+	EndpointID      portainer.EndpointID `json:"EndpointId"`
+	EdgeKey         string               `json:"EdgeKey"`
+	PendingApproval bool                 `json:"PendingApproval"`
+}
+
+// POST request on /api/endpoints/join
+// Registers the Edge agent identified by the X-PortainerAgent-EdgeID header with an Edge join token.
+// An endpoint is created inside the endpoint group and with the tags of the token, and its Edge key
+// is returned. An Edge identifier can only be registered once, the Edge key of an existing endpoint
+// is never returned.
+func (handler *Handler) endpointJoin(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeIdentifier := r.Header.Get(portainer.PortainerAgentEdgeIDHeader)
+	if edgeIdentifier == "" {
+		return &httperror.HandlerError{http.StatusForbidden, "Missing Edge identifier", portainer.Error("missing Edge identifier")}
+	}
+
+	var payload endpointJoinPayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	// the registrations are serialized so that two agents joining with the same Edge identifier
+	// cannot both create an endpoint and that the usage count of the token is not lost
+	handler.joinMutex.Lock()
+	defer handler.joinMutex.Unlock()
+
+	token, err := handler.findEdgeJoinToken(payload.Token)
+	if err == portainer.ErrInvalidEdgeJoinToken || err == portainer.ErrEdgeJoinTokenExpired || err == portainer.ErrEdgeJoinTokenRevoked {
+		return &httperror.HandlerError{http.StatusForbidden, "Invalid Edge join token", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Edge join tokens from the database", err}
+	}
+
+	endpoints, err := handler.EndpointService.Endpoints()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve endpoints from the database", err}
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.EdgeID == edgeIdentifier {
+			return &httperror.HandlerError{http.StatusConflict, "Unable to register the Edge agent", portainer.ErrEdgeIDAlreadyRegistered}
+		}
+	}
+
+	portainerHost, err := edgeEndpointHost(token.PortainerURL)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Invalid Portainer URL associated to the Edge join token", err}
+	}
+
+	endpointID := handler.EndpointService.GetNextIdentifier()
+	endpoint := &portainer.Endpoint{
+		ID:      portainer.EndpointID(endpointID),
+		Name:    payload.Name,
+		URL:     portainerHost,
+		Type:    portainer.EdgeAgentEnvironment,
+		GroupID: token.EndpointGroupID,
+		TLSConfig: portainer.TLSConfiguration{
+			TLS: false,
+		},
+		UserAccessPolicies:  portainer.UserAccessPolicies{},
+		TeamAccessPolicies:  portainer.TeamAccessPolicies{},
+		Extensions:          []portainer.EndpointExtension{},
+		Tags:                append([]string{}, token.Tags...),
+		Status:              portainer.EndpointStatusUp,
+		Snapshots:           []portainer.Snapshot{},
+		EdgeID:              edgeIdentifier,
+		EdgeKey:             handler.ReverseTunnelService.GenerateEdgeKey(token.PortainerURL, portainerHost, endpointID),
+		EdgeJoinTokenID:     token.ID,
+		EdgePendingApproval: !token.AutoApprove,
+	}
+
+	err = handler.saveEndpointAndUpdateAuthorizations(endpoint)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "An error occured while trying to create the endpoint", err}
+	}
+
+	token.UsageCount++
+	token.LastUsedDate = time.Now().Unix()
+
+	err = handler.EdgeJoinTokenService.UpdateEdgeJoinToken(token.ID, token)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist Edge join token changes inside the database", err}
+	}
+
+	return response.JSON(w, &endpointJoinResponse{EndpointID: endpoint.ID, EdgeKey: endpoint.EdgeKey, PendingApproval: endpoint.EdgePendingApproval})
+}
+
+// findEdgeJoinToken returns the Edge join token matching a raw token if it is still valid.
+func (handler *Handler) findEdgeJoinToken(rawToken string) (*portainer.EdgeJoinToken, error) {
+	if !strings.HasPrefix(rawToken, portainer.EdgeJoinTokenPrefix) {
+		return nil, portainer.ErrInvalidEdgeJoinToken
+	}
+
+	tokens, err := handler.EdgeJoinTokenService.EdgeJoinTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := security.EdgeJoinTokenPrefix(rawToken)
+
+	for _, token := range tokens {
+		if token.Prefix != prefix {
+			continue
+		}
+
+		err := handler.CryptoService.CompareHashAndData(token.Digest, rawToken)
+		if err != nil {
+			continue
+		}
+
+		if token.Revoked {
+			return nil, portainer.ErrEdgeJoinTokenRevoked
+		}
+
+		if token.ExpiryDate != 0 && token.ExpiryDate < time.Now().Unix() {
+			return nil, portainer.ErrEdgeJoinTokenExpired
+		}
+
+		return &token, nil
+	}
+
+	return nil, portainer.ErrInvalidEdgeJoinToken
+}
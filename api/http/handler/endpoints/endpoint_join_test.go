@@ -0,0 +1,219 @@
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// testEdgeJoinTokenService stores the Edge join tokens in memory.
+type testEdgeJoinTokenService struct {
+	portainer.EdgeJoinTokenService
+	tokens []portainer.EdgeJoinToken
+}
+
+func (service *testEdgeJoinTokenService) EdgeJoinTokens() ([]portainer.EdgeJoinToken, error) {
+	return append([]portainer.EdgeJoinToken{}, service.tokens...), nil
+}
+
+func (service *testEdgeJoinTokenService) UpdateEdgeJoinToken(ID portainer.EdgeJoinTokenID, token *portainer.EdgeJoinToken) error {
+	for idx := range service.tokens {
+		if service.tokens[idx].ID == ID {
+			service.tokens[idx] = *token
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+// testCryptoService uses the data as its own digest.
+type testCryptoService struct {
+	portainer.CryptoService
+}
+
+func (service *testCryptoService) CompareHashAndData(hash string, data string) error {
+	if hash != data {
+		return portainer.Error("digest mismatch")
+	}
+	return nil
+}
+
+type testReverseTunnelService struct {
+	portainer.ReverseTunnelService
+}
+
+func (service *testReverseTunnelService) GenerateEdgeKey(url, host string, endpointIdentifier int) string {
+	return fmt.Sprintf("%s|%s|%d", url, host, endpointIdentifier)
+}
+
+func newTestEdgeJoinToken(ID portainer.EdgeJoinTokenID, rawToken string) portainer.EdgeJoinToken {
+	return portainer.EdgeJoinToken{
+		ID:              ID,
+		Name:            rawToken,
+		Prefix:          security.EdgeJoinTokenPrefix(rawToken),
+		Digest:          rawToken,
+		PortainerURL:    "https://portainer.example.com:9443",
+		EndpointGroupID: 1,
+		Tags:            []string{"edge"},
+	}
+}
+
+func newTestJoinHandler(tokens ...portainer.EdgeJoinToken) *Handler {
+	return &Handler{
+		joinMutex:            &sync.Mutex{},
+		EndpointService:      &testEndpointService{},
+		EndpointGroupService: &testEndpointGroupService{endpointGroups: []portainer.EndpointGroup{{ID: 1, Name: "Unassigned"}}},
+		EdgeJoinTokenService: &testEdgeJoinTokenService{tokens: tokens},
+		CryptoService:        &testCryptoService{},
+		ReverseTunnelService: &testReverseTunnelService{},
+	}
+}
+
+func joinEndpoint(handler *Handler, edgeIdentifier, rawToken string) (*endpointJoinResponse, error) {
+	body, err := json.Marshal(&endpointJoinPayload{Token: rawToken, Name: edgeIdentifier})
+	if err != nil {
+		return nil, err
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/endpoints/join", bytes.NewReader(body))
+	r.Header.Set(portainer.PortainerAgentEdgeIDHeader, edgeIdentifier)
+	w := httptest.NewRecorder()
+
+	handlerErr := handler.endpointJoin(w, r)
+	if handlerErr != nil {
+		return nil, handlerErr.Err
+	}
+
+	var response endpointJoinResponse
+	err = json.NewDecoder(w.Body).Decode(&response)
+	return &response, err
+}
+
+func TestEndpointJoinToken(t *testing.T) {
+	valid := newTestEdgeJoinToken(1, portainer.EdgeJoinTokenPrefix+"valid-token")
+
+	expired := newTestEdgeJoinToken(2, portainer.EdgeJoinTokenPrefix+"expired-token")
+	expired.ExpiryDate = time.Now().Add(-time.Minute).Unix()
+
+	revoked := newTestEdgeJoinToken(3, portainer.EdgeJoinTokenPrefix+"revoked-token")
+	revoked.Revoked = true
+
+	autoApproved := newTestEdgeJoinToken(4, portainer.EdgeJoinTokenPrefix+"auto-token")
+	autoApproved.AutoApprove = true
+	autoApproved.ExpiryDate = time.Now().Add(time.Hour).Unix()
+
+	cases := []struct {
+		rawToken        string
+		err             error
+		pendingApproval bool
+	}{
+		{rawToken: valid.Digest, pendingApproval: true},
+		{rawToken: autoApproved.Digest, pendingApproval: false},
+		{rawToken: expired.Digest, err: portainer.ErrEdgeJoinTokenExpired},
+		{rawToken: revoked.Digest, err: portainer.ErrEdgeJoinTokenRevoked},
+		{rawToken: portainer.EdgeJoinTokenPrefix + "unknown-token", err: portainer.ErrInvalidEdgeJoinToken},
+		{rawToken: "unknown-token", err: portainer.ErrInvalidEdgeJoinToken},
+	}
+
+	for idx, c := range cases {
+		handler := newTestJoinHandler(valid, expired, revoked, autoApproved)
+
+		response, err := joinEndpoint(handler, fmt.Sprintf("edge-%d", idx), c.rawToken)
+		if err != c.err {
+			t.Errorf("expected error %v for token %s, got %v", c.err, c.rawToken, err)
+			continue
+		}
+
+		if err == nil && response.PendingApproval != c.pendingApproval {
+			t.Errorf("expected pending approval to be %t for token %s", c.pendingApproval, c.rawToken)
+		}
+	}
+}
+
+func TestEndpointJoinConcurrent(t *testing.T) {
+	token := newTestEdgeJoinToken(1, portainer.EdgeJoinTokenPrefix+"valid-token")
+	handler := newTestJoinHandler(token)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	joined := 0
+	for idx := 0; idx < 10; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := joinEndpoint(handler, "edge-agent", token.Digest)
+			if err != nil && err != portainer.ErrEdgeIDAlreadyRegistered {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+
+			if err == nil {
+				mu.Lock()
+				joined++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	endpoints, _ := handler.EndpointService.Endpoints()
+	if len(endpoints) != 1 || joined != 1 {
+		t.Fatalf("expected a single agent to join and a single endpoint to be created, got %d agents and %d endpoints", joined, len(endpoints))
+	}
+
+	tokens, _ := handler.EdgeJoinTokenService.EdgeJoinTokens()
+	if tokens[0].UsageCount != 1 {
+		t.Errorf("expected the token to be used once, got %d", tokens[0].UsageCount)
+	}
+}
+
+func TestEndpointApprove(t *testing.T) {
+	token := newTestEdgeJoinToken(1, portainer.EdgeJoinTokenPrefix+"valid-token")
+	handler := newTestJoinHandler(token)
+
+	joined, err := joinEndpoint(handler, "edge-agent", token.Digest)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	approve := func() (int, error) {
+		r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/endpoints/%d/approve", joined.EndpointID), nil)
+		r = mux.SetURLVars(r, map[string]string{"id": fmt.Sprintf("%d", joined.EndpointID)})
+
+		handlerErr := handler.endpointApprove(httptest.NewRecorder(), r)
+		if handlerErr != nil {
+			return handlerErr.StatusCode, handlerErr.Err
+		}
+		return http.StatusOK, nil
+	}
+
+	status, err := approve()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	endpoint, _ := handler.EndpointService.Endpoint(joined.EndpointID)
+	if status != http.StatusOK || endpoint.EdgePendingApproval {
+		t.Errorf("expected the endpoint to be approved")
+	}
+
+	_, err = joinEndpoint(handler, "edge-agent", token.Digest)
+	if err != portainer.ErrEdgeIDAlreadyRegistered {
+		t.Errorf("expected a registered Edge identifier to be rejected, got %v", err)
+	}
+
+	status, err = approve()
+	if status != http.StatusBadRequest || err != portainer.ErrEndpointNotPendingApproval {
+		t.Errorf("expected an endpoint that is not pending approval to be rejected, got %d (%v)", status, err)
+	}
+}
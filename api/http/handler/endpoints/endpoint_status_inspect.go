@@ -10,12 +10,18 @@ import (
 	"github.com/portainer/portainer/api"
 )
 
+type edgeStackStatus struct {
+	ID      portainer.EdgeStackID `json:"id"`
+	Version int                   `json:"version"`
+}
+
 type endpointStatusInspectResponse struct {
 	Status          string                   `json:"status"`
 	Port            int                      `json:"port"`
 	Schedules       []portainer.EdgeSchedule `json:"schedules"`
 	CheckinInterval int                      `json:"checkin"`
 	Credentials     string                   `json:"credentials"`
+	Stacks          []edgeStackStatus        `json:"stacks"`
 }
 
 // GET request on /api/endpoints/:id/status
@@ -59,6 +65,29 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
 	}
 
+	if endpoint.EdgePendingApproval {
+		return response.JSON(w, endpointStatusInspectResponse{
+			Status:          portainer.EdgeAgentIdle,
+			Schedules:       []portainer.EdgeSchedule{},
+			CheckinInterval: settings.EdgeAgentCheckinInterval,
+			Stacks:          []edgeStackStatus{},
+		})
+	}
+
+	handler.ReverseTunnelService.SetLastCheckin(endpoint.ID, settings.EdgeAgentCheckinInterval)
+
+	edgeStacks, err := handler.EdgeStackService.EdgeStacks()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve Edge stacks from the database", err}
+	}
+
+	stacks := make([]edgeStackStatus, 0)
+	for idx := range edgeStacks {
+		if portainer.EdgeStackRelatedToEndpoint(&edgeStacks[idx], endpoint) {
+			stacks = append(stacks, edgeStackStatus{ID: edgeStacks[idx].ID, Version: edgeStacks[idx].Version})
+		}
+	}
+
 	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
 
 	statusResponse := endpointStatusInspectResponse{
@@ -67,6 +96,7 @@ func (handler *Handler) endpointStatusInspect(w http.ResponseWriter, r *http.Req
 		Schedules:       tunnel.Schedules,
 		CheckinInterval: settings.EdgeAgentCheckinInterval,
 		Credentials:     tunnel.Credentials,
+		Stacks:          stacks,
 	}
 
 	if tunnel.Status == portainer.EdgeAgentManagementRequired {
@@ -7,6 +7,7 @@ import (
 	"github.com/portainer/portainer/api/http/security"
 
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/mux"
 )
@@ -29,7 +30,12 @@ type Handler struct {
 	*mux.Router
 	authorizeEndpointManagement bool
 	requestBouncer              *security.RequestBouncer
+	joinMutex                   *sync.Mutex
 	EndpointService             portainer.EndpointService
+	EndpointEventService        portainer.EndpointEventService
+	EdgeJoinTokenService        portainer.EdgeJoinTokenService
+	EdgeStackService            portainer.EdgeStackService
+	CryptoService               portainer.CryptoService
 	EndpointGroupService        portainer.EndpointGroupService
 	FileService                 portainer.FileService
 	ProxyManager                *proxy.Manager
@@ -37,19 +43,25 @@ type Handler struct {
 	JobService                  portainer.JobService
 	ReverseTunnelService        portainer.ReverseTunnelService
 	SettingsService             portainer.SettingsService
+	SnapshotHistoryService      portainer.SnapshotHistoryService
 	AuthorizationService        *portainer.AuthorizationService
 }
 
 // NewHandler creates a handler to manage endpoint operations.
-func NewHandler(bouncer *security.RequestBouncer, authorizeEndpointManagement bool) *Handler {
+func NewHandler(bouncer *security.RequestBouncer, rateLimiter *security.RateLimiter, authorizeEndpointManagement bool) *Handler {
 	h := &Handler{
 		Router:                      mux.NewRouter(),
 		authorizeEndpointManagement: authorizeEndpointManagement,
 		requestBouncer:              bouncer,
+		joinMutex:                   &sync.Mutex{},
 	}
 
 	h.Handle("/endpoints",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointCreate))).Methods(http.MethodPost)
+	h.Handle("/endpoints/join",
+		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.endpointJoin)))).Methods(http.MethodPost)
+	h.Handle("/endpoints/import",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointImport))).Methods(http.MethodPost)
 	h.Handle("/endpoints/snapshot",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointSnapshots))).Methods(http.MethodPost)
 	h.Handle("/endpoints",
@@ -60,6 +72,12 @@ func NewHandler(bouncer *security.RequestBouncer, authorizeEndpointManagement bo
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointUpdate))).Methods(http.MethodPut)
 	h.Handle("/endpoints/{id}",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDelete))).Methods(http.MethodDelete)
+	h.Handle("/endpoints/{id}/approve",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointApprove))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/edge_stacks/{stackId}",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeStackInspect))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/events",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointEventList))).Methods(http.MethodGet)
 	h.Handle("/endpoints/{id}/extensions",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointExtensionAdd))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/extensions/{extensionType}",
@@ -68,6 +86,8 @@ func NewHandler(bouncer *security.RequestBouncer, authorizeEndpointManagement bo
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointJob))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/snapshot",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointSnapshot))).Methods(http.MethodPost)
+	h.Handle("/endpoints/{id}/snapshots",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointSnapshotList))).Methods(http.MethodGet)
 	h.Handle("/endpoints/{id}/status",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointStatusInspect))).Methods(http.MethodGet)
 
@@ -0,0 +1,27 @@
+package ldap
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle LDAP synchronization operations.
+type Handler struct {
+	*mux.Router
+	LDAPSyncReportService portainer.LDAPSyncReportService
+}
+
+// NewHandler creates a handler to manage LDAP synchronization operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/ldap/sync/reports",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.ldapSyncReportList))).Methods(http.MethodGet)
+
+	return h
+}
@@ -0,0 +1,30 @@
+package ldap
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/ldap/sync/reports?limit=<limit>
+// The reports are returned from the most recent to the oldest one.
+func (handler *Handler) ldapSyncReportList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+
+	reports, err := handler.LDAPSyncReportService.LDAPSyncReports()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve LDAP synchronization reports from the database", err}
+	}
+
+	for i, j := 0, len(reports)-1; i < j; i, j = i+1, j-1 {
+		reports[i], reports[j] = reports[j], reports[i]
+	}
+
+	if limit > 0 && limit < len(reports) {
+		reports = reports[:limit]
+	}
+
+	return response.JSON(w, reports)
+}
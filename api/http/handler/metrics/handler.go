@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/metrics"
+)
+
+// Handler is the HTTP handler used to expose metrics.
+type Handler struct {
+	*mux.Router
+	metricsToken         string
+	EndpointService      portainer.EndpointService
+	ReverseTunnelService portainer.ReverseTunnelService
+	MetricsRegistry      *metrics.Registry
+}
+
+// NewHandler creates a handler to expose metrics.
+// The metrics are available to administrators, or to any client using the metrics token as
+// a bearer token when a token was specified.
+func NewHandler(bouncer *security.RequestBouncer, metricsToken string) *Handler {
+	h := &Handler{
+		Router:       mux.NewRouter(),
+		metricsToken: metricsToken,
+	}
+	h.Handle("/metrics",
+		h.metricsAccess(bouncer, httperror.LoggerHandler(h.metrics))).Methods(http.MethodGet)
+
+	return h
+}
+
+func (handler *Handler) metricsAccess(bouncer *security.RequestBouncer, next http.Handler) http.Handler {
+	publicHandler := bouncer.PublicAccess(next)
+	adminHandler := bouncer.AdminAccess(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := security.RetrieveRawToken(r)
+		if handler.metricsToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(handler.metricsToken)) == 1 {
+			publicHandler.ServeHTTP(w, r)
+			return
+		}
+		adminHandler.ServeHTTP(w, r)
+	})
+}
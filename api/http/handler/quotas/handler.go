@@ -0,0 +1,39 @@
+package quotas
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// Handler is the HTTP handler used to handle quota operations.
+type Handler struct {
+	*mux.Router
+	QuotaService         portainer.QuotaService
+	UserService          portainer.UserService
+	TeamService          portainer.TeamService
+	EndpointService      portainer.EndpointService
+	EndpointGroupService portainer.EndpointGroupService
+}
+
+// NewHandler creates a handler to manage quota operations.
+func NewHandler(bouncer *security.RequestBouncer) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/quotas",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaCreate))).Methods(http.MethodPost)
+	h.Handle("/quotas",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaList))).Methods(http.MethodGet)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaInspect))).Methods(http.MethodGet)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaUpdate))).Methods(http.MethodPut)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaDelete))).Methods(http.MethodDelete)
+
+	return h
+}
@@ -0,0 +1,63 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type quotaCreatePayload struct {
+	UserID          int
+	TeamID          int
+	EndpointID      int
+	EndpointGroupID int
+	MaxContainers   int
+	MaxMemory       int64
+	MaxNanoCPUs     int64
+	MaxVolumes      int
+}
+
+func (payload *quotaCreatePayload) Validate(r *http.Request) error {
+	if (payload.UserID == 0) == (payload.TeamID == 0) {
+		return portainer.Error("Invalid quota owner. Either a user or a team must be specified")
+	}
+	if (payload.EndpointID == 0) == (payload.EndpointGroupID == 0) {
+		return portainer.Error("Invalid quota scope. Either an endpoint or an endpoint group must be specified")
+	}
+	return validateLimits(payload.MaxContainers, payload.MaxMemory, payload.MaxNanoCPUs, payload.MaxVolumes)
+}
+
+// POST request on /api/quotas
+func (handler *Handler) quotaCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload quotaCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	quota := &portainer.Quota{
+		UserID:          portainer.UserID(payload.UserID),
+		TeamID:          portainer.TeamID(payload.TeamID),
+		EndpointID:      portainer.EndpointID(payload.EndpointID),
+		EndpointGroupID: portainer.EndpointGroupID(payload.EndpointGroupID),
+		MaxContainers:   payload.MaxContainers,
+		MaxMemory:       payload.MaxMemory,
+		MaxNanoCPUs:     payload.MaxNanoCPUs,
+		MaxVolumes:      payload.MaxVolumes,
+	}
+
+	handlerErr := handler.validateQuotaReferences(quota)
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	err = handler.QuotaService.CreateQuota(quota)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the quota inside the database", err}
+	}
+
+	return response.JSON(w, quota)
+}
@@ -0,0 +1,27 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/quotas/:id
+func (handler *Handler) quotaInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotaID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid quota identifier route variable", err}
+	}
+
+	quota, err := handler.QuotaService.Quota(portainer.QuotaID(quotaID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a quota with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a quota with the specified identifier inside the database", err}
+	}
+
+	return response.JSON(w, quota)
+}
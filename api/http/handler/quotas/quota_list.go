@@ -0,0 +1,18 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/response"
+)
+
+// GET request on /api/quotas
+func (handler *Handler) quotaList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotas, err := handler.QuotaService.Quotas()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve quotas from the database", err}
+	}
+
+	return response.JSON(w, quotas)
+}
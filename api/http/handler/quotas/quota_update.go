@@ -0,0 +1,71 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type quotaUpdatePayload struct {
+	MaxContainers *int
+	MaxMemory     *int64
+	MaxNanoCPUs   *int64
+	MaxVolumes    *int
+}
+
+func (payload *quotaUpdatePayload) Validate(r *http.Request) error {
+	if (payload.MaxContainers != nil && *payload.MaxContainers < 0) ||
+		(payload.MaxMemory != nil && *payload.MaxMemory < 0) ||
+		(payload.MaxNanoCPUs != nil && *payload.MaxNanoCPUs < 0) ||
+		(payload.MaxVolumes != nil && *payload.MaxVolumes < 0) {
+		return portainer.Error("Invalid limits. Limits cannot be negative")
+	}
+	return nil
+}
+
+// PUT request on /api/quotas/:id
+func (handler *Handler) quotaUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotaID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid quota identifier route variable", err}
+	}
+
+	var payload quotaUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	quota, err := handler.QuotaService.Quota(portainer.QuotaID(quotaID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a quota with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a quota with the specified identifier inside the database", err}
+	}
+
+	if payload.MaxContainers != nil {
+		quota.MaxContainers = *payload.MaxContainers
+	}
+
+	if payload.MaxMemory != nil {
+		quota.MaxMemory = *payload.MaxMemory
+	}
+
+	if payload.MaxNanoCPUs != nil {
+		quota.MaxNanoCPUs = *payload.MaxNanoCPUs
+	}
+
+	if payload.MaxVolumes != nil {
+		quota.MaxVolumes = *payload.MaxVolumes
+	}
+
+	err = handler.QuotaService.UpdateQuota(quota.ID, quota)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist quota changes inside the database", err}
+	}
+
+	return response.JSON(w, quota)
+}
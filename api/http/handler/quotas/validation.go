@@ -0,0 +1,57 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+)
+
+func validateLimits(maxContainers int, maxMemory, maxNanoCPUs int64, maxVolumes int) error {
+	if maxContainers < 0 || maxMemory < 0 || maxNanoCPUs < 0 || maxVolumes < 0 {
+		return portainer.Error("Invalid limits. Limits cannot be negative")
+	}
+	return nil
+}
+
+// validateQuotaReferences verifies that the user or team and the endpoint or endpoint group
+// associated to the quota exist.
+func (handler *Handler) validateQuotaReferences(quota *portainer.Quota) *httperror.HandlerError {
+	if quota.UserID != 0 {
+		_, err := handler.UserService.User(quota.UserID)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find a user with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+		}
+	}
+
+	if quota.TeamID != 0 {
+		_, err := handler.TeamService.Team(quota.TeamID)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find a team with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a team with the specified identifier inside the database", err}
+		}
+	}
+
+	if quota.EndpointID != 0 {
+		_, err := handler.EndpointService.Endpoint(quota.EndpointID)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+		}
+	}
+
+	if quota.EndpointGroupID != 0 {
+		_, err := handler.EndpointGroupService.EndpointGroup(quota.EndpointGroupID)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusBadRequest, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint group with the specified identifier inside the database", err}
+		}
+	}
+
+	return nil
+}
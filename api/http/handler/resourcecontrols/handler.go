@@ -6,6 +6,7 @@ import (
 	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 )
 
@@ -13,6 +14,7 @@ import (
 type Handler struct {
 	*mux.Router
 	ResourceControlService portainer.ResourceControlService
+	QuotaChecker           *docker.QuotaChecker
 }
 
 // NewHandler creates a handler to manage resource control operations.
@@ -58,6 +58,8 @@ func (handler *Handler) resourceControlUpdate(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access the resource control", portainer.ErrResourceAccessDenied}
 	}
 
+	previousResourceControl := *resourceControl
+
 	resourceControl.Public = payload.Public
 	resourceControl.AdministratorsOnly = payload.AdministratorsOnly
 
@@ -85,6 +87,17 @@ func (handler *Handler) resourceControlUpdate(w http.ResponseWriter, r *http.Req
 		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to update the resource control", portainer.ErrResourceAccessDenied}
 	}
 
+	if !securityContext.IsAdmin {
+		releasesResource, err := handler.QuotaChecker.ReleasesResource(securityContext.UserID, &previousResourceControl, resourceControl)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the quotas associated to the user", err}
+		}
+
+		if releasesResource {
+			return &httperror.HandlerError{http.StatusForbidden, "Unable to remove the resource from the resources counted by a quota", portainer.ErrQuotaResourceRelease}
+		}
+	}
+
 	err = handler.ResourceControlService.UpdateResourceControl(resourceControl.ID, resourceControl)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist resource control changes inside the database", err}
@@ -12,7 +12,10 @@ import (
 // Handler is the HTTP handler used to handle role operations.
 type Handler struct {
 	*mux.Router
-	RoleService portainer.RoleService
+	RoleService          portainer.RoleService
+	AuthorizationService *portainer.AuthorizationService
+	EndpointService      portainer.EndpointService
+	EndpointGroupService portainer.EndpointGroupService
 }
 
 // NewHandler creates a handler to manage role operations.
@@ -22,6 +25,40 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	}
 	h.Handle("/roles",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.roleList))).Methods(http.MethodGet)
+	h.Handle("/roles",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleCreate))).Methods(http.MethodPost)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleInspect))).Methods(http.MethodGet)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleUpdate))).Methods(http.MethodPut)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleDelete))).Methods(http.MethodDelete)
 
 	return h
 }
+
+// validateAuthorizations returns an error if one of the authorizations is not a known
+// operation authorization.
+func validateAuthorizations(authorizations portainer.Authorizations) error {
+	for authorization := range authorizations {
+		if !portainer.IsKnownAuthorization(authorization) {
+			return portainer.Error("Invalid authorization. Unknown authorization: " + string(authorization))
+		}
+	}
+	return nil
+}
+
+func (handler *Handler) roleNameExists(name string, excludedID portainer.RoleID) (bool, error) {
+	roles, err := handler.RoleService.Roles()
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.ID != excludedID && role.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
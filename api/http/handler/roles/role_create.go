@@ -0,0 +1,67 @@
+package roles
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type roleCreatePayload struct {
+	Name           string
+	Description    string
+	Authorizations portainer.Authorizations
+	Priority       int
+}
+
+func (payload *roleCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid role name")
+	}
+	if len(payload.Authorizations) == 0 {
+		return portainer.Error("Invalid authorizations. At least one authorization is required")
+	}
+	if payload.Priority <= 0 {
+		return portainer.Error("Invalid priority. Must be a positive number")
+	}
+	return validateAuthorizations(payload.Authorizations)
+}
+
+// POST request on /api/roles
+func (handler *Handler) roleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload roleCreatePayload
+	err := request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	exists, err := handler.roleNameExists(payload.Name, 0)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve roles from the database", err}
+	}
+	if exists {
+		return &httperror.HandlerError{http.StatusConflict, "This name is already associated to a role", portainer.ErrRoleAlreadyExists}
+	}
+
+	role := &portainer.Role{
+		Name:           payload.Name,
+		Description:    payload.Description,
+		Authorizations: payload.Authorizations,
+		Priority:       payload.Priority,
+	}
+
+	err = handler.RoleService.CreateRole(role)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the role inside the database", err}
+	}
+
+	err = handler.AuthorizationService.UpdateUsersAuthorizations()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update user authorizations", err}
+	}
+
+	return response.JSON(w, role)
+}
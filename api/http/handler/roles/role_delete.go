@@ -0,0 +1,93 @@
+package roles
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/roles/:id
+func (handler *Handler) roleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	roleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid role identifier route variable", err}
+	}
+
+	role, err := handler.RoleService.Role(portainer.RoleID(roleID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a role with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a role with the specified identifier inside the database", err}
+	}
+
+	if role.IsBuiltIn {
+		return &httperror.HandlerError{http.StatusForbidden, "Built-in roles cannot be removed", portainer.ErrBuiltInRoleReadOnly}
+	}
+
+	inUse, err := handler.roleInUse(role.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve access policies from the database", err}
+	}
+	if inUse {
+		return &httperror.HandlerError{http.StatusConflict, "The role is associated to at least one access policy", portainer.ErrRoleInUse}
+	}
+
+	err = handler.RoleService.DeleteRole(role.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the role from the database", err}
+	}
+
+	err = handler.AuthorizationService.UpdateUsersAuthorizations()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update user authorizations", err}
+	}
+
+	return response.Empty(w)
+}
+
+// roleInUse returns true when the role is referenced by an access policy of an endpoint
+// or of an endpoint group.
+func (handler *Handler) roleInUse(roleID portainer.RoleID) (bool, error) {
+	endpoints, err := handler.EndpointService.Endpoints()
+	if err != nil {
+		return false, err
+	}
+
+	for _, endpoint := range endpoints {
+		if accessPoliciesUseRole(endpoint.UserAccessPolicies, endpoint.TeamAccessPolicies, roleID) {
+			return true, nil
+		}
+	}
+
+	endpointGroups, err := handler.EndpointGroupService.EndpointGroups()
+	if err != nil {
+		return false, err
+	}
+
+	for _, endpointGroup := range endpointGroups {
+		if accessPoliciesUseRole(endpointGroup.UserAccessPolicies, endpointGroup.TeamAccessPolicies, roleID) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func accessPoliciesUseRole(userPolicies portainer.UserAccessPolicies, teamPolicies portainer.TeamAccessPolicies, roleID portainer.RoleID) bool {
+	for _, policy := range userPolicies {
+		if policy.RoleID == roleID {
+			return true
+		}
+	}
+
+	for _, policy := range teamPolicies {
+		if policy.RoleID == roleID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package roles
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+type roleUpdatePayload struct {
+	Name           string
+	Description    *string
+	Authorizations portainer.Authorizations
+	Priority       *int
+}
+
+func (payload *roleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Priority != nil && *payload.Priority <= 0 {
+		return portainer.Error("Invalid priority. Must be a positive number")
+	}
+	return validateAuthorizations(payload.Authorizations)
+}
+
+// PUT request on /api/roles/:id
+func (handler *Handler) roleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	roleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid role identifier route variable", err}
+	}
+
+	var payload roleUpdatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	role, err := handler.RoleService.Role(portainer.RoleID(roleID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a role with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a role with the specified identifier inside the database", err}
+	}
+
+	if role.IsBuiltIn {
+		return &httperror.HandlerError{http.StatusForbidden, "Built-in roles cannot be modified", portainer.ErrBuiltInRoleReadOnly}
+	}
+
+	if payload.Name != "" && payload.Name != role.Name {
+		exists, err := handler.roleNameExists(payload.Name, role.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve roles from the database", err}
+		}
+		if exists {
+			return &httperror.HandlerError{http.StatusConflict, "This name is already associated to a role", portainer.ErrRoleAlreadyExists}
+		}
+		role.Name = payload.Name
+	}
+
+	if payload.Description != nil {
+		role.Description = *payload.Description
+	}
+
+	if payload.Authorizations != nil {
+		role.Authorizations = payload.Authorizations
+	}
+
+	if payload.Priority != nil {
+		role.Priority = *payload.Priority
+	}
+
+	err = handler.RoleService.UpdateRole(role.ID, role)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist role changes inside the database", err}
+	}
+
+	err = handler.AuthorizationService.UpdateUsersAuthorizations()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update user authorizations", err}
+	}
+
+	return response.JSON(w, role)
+}
@@ -32,7 +32,7 @@ func (handler *Handler) scheduleDelete(w http.ResponseWriter, r *http.Request) *
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a schedule with the specified identifier inside the database", err}
 	}
 
-	if schedule.JobType == portainer.SnapshotJobType || schedule.JobType == portainer.EndpointSyncJobType {
+	if schedule.JobType == portainer.SnapshotJobType || schedule.JobType == portainer.EndpointSyncJobType || schedule.JobType == portainer.StackGitUpdateJobType || schedule.JobType == portainer.BackupJobType || schedule.JobType == portainer.LDAPSyncJobType || schedule.JobType == portainer.AuditLogPruneJobType || schedule.JobType == portainer.SnapshotHistoryCompactJobType {
 		return &httperror.HandlerError{http.StatusBadRequest, "Cannot remove system schedules", errors.New("Cannot remove system schedule")}
 	}
 
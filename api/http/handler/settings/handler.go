@@ -25,6 +25,7 @@ type Handler struct {
 	RoleService          portainer.RoleService
 	ExtensionService     portainer.ExtensionService
 	AuthorizationService *portainer.AuthorizationService
+	JWTService           portainer.JWTService
 }
 
 // NewHandler creates a handler to manage settings operations.
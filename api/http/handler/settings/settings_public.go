@@ -18,6 +18,7 @@ type publicSettingsResponse struct {
 	EnableHostManagementFeatures       bool                           `json:"EnableHostManagementFeatures"`
 	ExternalTemplates                  bool                           `json:"ExternalTemplates"`
 	OAuthLoginURI                      string                         `json:"OAuthLoginURI"`
+	PasswordPolicy                     portainer.PasswordPolicy       `json:"PasswordPolicy"`
 }
 
 // GET request on /api/settings/public
@@ -35,6 +36,7 @@ func (handler *Handler) settingsPublic(w http.ResponseWriter, r *http.Request) *
 		AllowVolumeBrowserForRegularUsers:  settings.AllowVolumeBrowserForRegularUsers,
 		EnableHostManagementFeatures:       settings.EnableHostManagementFeatures,
 		ExternalTemplates:                  false,
+		PasswordPolicy:                     settings.PasswordPolicy,
 		OAuthLoginURI: fmt.Sprintf("%s?response_type=code&client_id=%s&redirect_uri=%s&scope=%s&prompt=login",
 			settings.OAuthSettings.AuthorizationURI,
 			settings.OAuthSettings.ClientID,
@@ -42,6 +44,10 @@ func (handler *Handler) settingsPublic(w http.ResponseWriter, r *http.Request) *
 			settings.OAuthSettings.Scopes),
 	}
 
+	if settings.OAuthSettings.OIDCIssuerURL != "" {
+		publicSettings.OAuthLoginURI = "api/auth/oauth/login"
+	}
+
 	if settings.TemplatesURL != "" {
 		publicSettings.ExternalTemplates = true
 	}
@@ -2,6 +2,8 @@ package settings
 
 import (
 	"net/http"
+	"regexp"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	httperror "github.com/portainer/libhttp/error"
@@ -24,6 +26,15 @@ type settingsUpdatePayload struct {
 	SnapshotInterval                   *string
 	TemplatesURL                       *string
 	EdgeAgentCheckinInterval           *int
+	StackVersionRetention              *int
+	UserSessionTimeout                 *string
+	EnableSessionRecording             *bool
+	SessionRecordingRetention          *int
+	AuditLogRetention                  *int
+	EnforceTwoFactorForAdministrators  *bool
+	PasswordPolicy                     *portainer.PasswordPolicy
+	AccountLockout                     *portainer.AccountLockoutSettings
+	SnapshotHistory                    *portainer.SnapshotHistorySettings
 }
 
 func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
@@ -36,6 +47,56 @@ func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
 	if payload.TemplatesURL != nil && *payload.TemplatesURL != "" && !govalidator.IsURL(*payload.TemplatesURL) {
 		return portainer.Error("Invalid external templates URL. Must correspond to a valid URL format")
 	}
+	if payload.OAuthSettings != nil && payload.OAuthSettings.OIDCIssuerURL != "" && !govalidator.IsURL(payload.OAuthSettings.OIDCIssuerURL) {
+		return portainer.Error("Invalid OpenID Connect issuer URL. Must correspond to a valid URL format")
+	}
+	if payload.LDAPSettings != nil && payload.LDAPSettings.AutoCreateTeamsFilter != "" {
+		_, err := regexp.Compile(payload.LDAPSettings.AutoCreateTeamsFilter)
+		if err != nil {
+			return portainer.Error("Invalid LDAP team filter. Must be a valid regular expression")
+		}
+	}
+	if payload.StackVersionRetention != nil && *payload.StackVersionRetention < 1 {
+		return portainer.Error("Invalid stack version retention. Must be a positive number")
+	}
+	if payload.UserSessionTimeout != nil {
+		duration, err := time.ParseDuration(*payload.UserSessionTimeout)
+		if err != nil || duration <= 0 {
+			return portainer.Error("Invalid user session timeout. Must be a valid positive duration (e.g. 8h)")
+		}
+	}
+	if payload.SessionRecordingRetention != nil && *payload.SessionRecordingRetention < 1 {
+		return portainer.Error("Invalid session recording retention. Must be a positive number of days")
+	}
+	if payload.AuditLogRetention != nil && *payload.AuditLogRetention < 1 {
+		return portainer.Error("Invalid audit log retention. Must be a positive number of days")
+	}
+	if payload.PasswordPolicy != nil && (payload.PasswordPolicy.MinLength < 0 || payload.PasswordPolicy.HistorySize < 0 || payload.PasswordPolicy.MaxAge < 0) {
+		return portainer.Error("Invalid password policy. Minimum length, history size and maximum age cannot be negative")
+	}
+	if payload.AccountLockout != nil {
+		if payload.AccountLockout.MaxFailedAttempts < 0 {
+			return portainer.Error("Invalid account lockout maximum failed attempts. Cannot be negative")
+		}
+		duration, err := time.ParseDuration(payload.AccountLockout.LockoutDuration)
+		if err != nil || duration <= 0 {
+			return portainer.Error("Invalid account lockout duration. Must be a valid positive duration (e.g. 15m)")
+		}
+	}
+	if payload.SnapshotHistory != nil {
+		for _, value := range []string{payload.SnapshotHistory.Retention, payload.SnapshotHistory.DownsamplingAge, payload.SnapshotHistory.DownsamplingInterval} {
+			if value == "" {
+				continue
+			}
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration <= 0 {
+				return portainer.Error("Invalid snapshot history settings. Durations must be empty or valid positive durations (e.g. 24h)")
+			}
+		}
+		if payload.SnapshotHistory.DownsamplingAge != "" && payload.SnapshotHistory.DownsamplingInterval == "" {
+			return portainer.Error("Invalid snapshot history settings. A downsampling interval is required when a downsampling age is defined")
+		}
+	}
 	return nil
 }
 
@@ -115,6 +176,47 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		settings.EdgeAgentCheckinInterval = *payload.EdgeAgentCheckinInterval
 	}
 
+	if payload.StackVersionRetention != nil {
+		settings.StackVersionRetention = *payload.StackVersionRetention
+	}
+
+	if payload.UserSessionTimeout != nil {
+		settings.UserSessionTimeout = *payload.UserSessionTimeout
+
+		if handler.JWTService != nil {
+			userSessionDuration, _ := time.ParseDuration(*payload.UserSessionTimeout)
+			handler.JWTService.SetUserSessionDuration(userSessionDuration)
+		}
+	}
+
+	if payload.EnableSessionRecording != nil {
+		settings.EnableSessionRecording = *payload.EnableSessionRecording
+	}
+
+	if payload.SessionRecordingRetention != nil {
+		settings.SessionRecordingRetention = *payload.SessionRecordingRetention
+	}
+
+	if payload.AuditLogRetention != nil {
+		settings.AuditLogRetention = *payload.AuditLogRetention
+	}
+
+	if payload.EnforceTwoFactorForAdministrators != nil {
+		settings.EnforceTwoFactorForAdministrators = *payload.EnforceTwoFactorForAdministrators
+	}
+
+	if payload.PasswordPolicy != nil {
+		settings.PasswordPolicy = *payload.PasswordPolicy
+	}
+
+	if payload.AccountLockout != nil {
+		settings.AccountLockout = *payload.AccountLockout
+	}
+
+	if payload.SnapshotHistory != nil {
+		settings.SnapshotHistory = *payload.SnapshotHistory
+	}
+
 	tlsError := handler.updateTLS(settings)
 	if tlsError != nil {
 		return tlsError
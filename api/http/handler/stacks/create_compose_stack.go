@@ -94,6 +94,11 @@ func (handler *Handler) createComposeStackFromFileContent(w http.ResponseWriter,
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -104,6 +109,7 @@ type composeStackFromGitRepositoryPayload struct {
 	RepositoryAuthentication    bool
 	RepositoryUsername          string
 	RepositoryPassword          string
+	RepositoryAutoUpdate        bool
 	ComposeFilePathInRepository string
 	Env                         []portainer.Pair
 }
@@ -165,6 +171,21 @@ func (handler *Handler) createComposeStackFromGitRepository(w http.ResponseWrite
 		password:       payload.RepositoryPassword,
 	}
 
+	commitID, err := handler.latestCommitID(gitCloneParams)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the latest commit of the git repository", err}
+	}
+
+	stack.GitConfig = &portainer.StackGitConfig{
+		URL:            payload.RepositoryURL,
+		ReferenceName:  payload.RepositoryReferenceName,
+		Authentication: payload.RepositoryAuthentication,
+		Username:       payload.RepositoryUsername,
+		Password:       payload.RepositoryPassword,
+		CommitHash:     commitID,
+		AutoUpdate:     payload.RepositoryAutoUpdate,
+	}
+
 	doCleanUp := true
 	defer handler.cleanUp(stack, &doCleanUp)
 
@@ -189,6 +210,11 @@ func (handler *Handler) createComposeStackFromGitRepository(w http.ResponseWrite
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -274,6 +300,11 @@ func (handler *Handler) createComposeStackFromFileUpload(w http.ResponseWriter,
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -283,6 +314,7 @@ type composeStackDeploymentConfig struct {
 	dockerhub  *portainer.DockerHub
 	registries []portainer.Registry
 	isAdmin    bool
+	userID     portainer.UserID
 }
 
 func (handler *Handler) createComposeDeployConfig(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (*composeStackDeploymentConfig, *httperror.HandlerError) {
@@ -308,16 +340,12 @@ func (handler *Handler) createComposeDeployConfig(r *http.Request, stack *portai
 		dockerhub:  dockerhub,
 		registries: filteredRegistries,
 		isAdmin:    securityContext.IsAdmin,
+		userID:     securityContext.UserID,
 	}
 
 	return config, nil
 }
 
-// TODO: libcompose uses credentials store into a config.json file to pull images from
-// private registries. Right now the only solution is to re-use the embedded Docker binary
-// to login/logout, which will generate the required data in the config.json file and then
-// clean it. Hence the use of the mutex.
-// We should contribute to libcompose to support authentication without using the config.json file.
 func (handler *Handler) deployComposeStack(config *composeStackDeploymentConfig) error {
 	settings, err := handler.SettingsService.Settings()
 	if err != nil {
@@ -341,15 +369,26 @@ func (handler *Handler) deployComposeStack(config *composeStackDeploymentConfig)
 		}
 	}
 
-	handler.stackCreationMutex.Lock()
-	defer handler.stackCreationMutex.Unlock()
-
-	handler.SwarmStackManager.Login(config.dockerhub, config.registries, config.endpoint)
-
-	err = handler.ComposeStackManager.Up(config.stack, config.endpoint)
+	err = handler.checkStackImages(config.stack, config.endpoint, config.userID, config.isAdmin)
 	if err != nil {
 		return err
 	}
 
-	return handler.SwarmStackManager.Logout(config.endpoint)
+	if !config.isAdmin {
+		err = handler.checkStackPolicies(config.stack, config.endpoint, config.userID, false)
+		if err != nil {
+			return err
+		}
+
+		// the quota lock is held until the stack is deployed
+		unlock := handler.QuotaChecker.LockEndpoint(config.endpoint.ID)
+		defer unlock()
+
+		err = handler.checkStackQuotas(config.stack, config.endpoint, config.userID, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return handler.StackDeployer.DeployComposeStack(config.stack, config.endpoint, config.registries, config.dockerhub, false)
 }
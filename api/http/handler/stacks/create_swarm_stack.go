@@ -90,6 +90,11 @@ func (handler *Handler) createSwarmStackFromFileContent(w http.ResponseWriter, r
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -102,6 +107,7 @@ type swarmStackFromGitRepositoryPayload struct {
 	RepositoryAuthentication    bool
 	RepositoryUsername          string
 	RepositoryPassword          string
+	RepositoryAutoUpdate        bool
 	ComposeFilePathInRepository string
 }
 
@@ -165,6 +171,21 @@ func (handler *Handler) createSwarmStackFromGitRepository(w http.ResponseWriter,
 		password:       payload.RepositoryPassword,
 	}
 
+	commitID, err := handler.latestCommitID(gitCloneParams)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the latest commit of the git repository", err}
+	}
+
+	stack.GitConfig = &portainer.StackGitConfig{
+		URL:            payload.RepositoryURL,
+		ReferenceName:  payload.RepositoryReferenceName,
+		Authentication: payload.RepositoryAuthentication,
+		Username:       payload.RepositoryUsername,
+		Password:       payload.RepositoryPassword,
+		CommitHash:     commitID,
+		AutoUpdate:     payload.RepositoryAutoUpdate,
+	}
+
 	doCleanUp := true
 	defer handler.cleanUp(stack, &doCleanUp)
 
@@ -189,6 +210,11 @@ func (handler *Handler) createSwarmStackFromGitRepository(w http.ResponseWriter,
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -282,6 +308,11 @@ func (handler *Handler) createSwarmStackFromFileUpload(w http.ResponseWriter, r
 	}
 
 	doCleanUp = false
+
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
@@ -292,6 +323,7 @@ type swarmStackDeploymentConfig struct {
 	registries []portainer.Registry
 	prune      bool
 	isAdmin    bool
+	userID     portainer.UserID
 }
 
 func (handler *Handler) createSwarmDeployConfig(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint, prune bool) (*swarmStackDeploymentConfig, *httperror.HandlerError) {
@@ -318,6 +350,7 @@ func (handler *Handler) createSwarmDeployConfig(r *http.Request, stack *portaine
 		registries: filteredRegistries,
 		prune:      prune,
 		isAdmin:    securityContext.IsAdmin,
+		userID:     securityContext.UserID,
 	}
 
 	return config, nil
@@ -346,20 +379,26 @@ func (handler *Handler) deploySwarmStack(config *swarmStackDeploymentConfig) err
 		}
 	}
 
-	handler.stackCreationMutex.Lock()
-	defer handler.stackCreationMutex.Unlock()
-
-	handler.SwarmStackManager.Login(config.dockerhub, config.registries, config.endpoint)
-
-	err = handler.SwarmStackManager.Deploy(config.stack, config.prune, config.endpoint)
+	err = handler.checkStackImages(config.stack, config.endpoint, config.userID, config.isAdmin)
 	if err != nil {
 		return err
 	}
 
-	err = handler.SwarmStackManager.Logout(config.endpoint)
-	if err != nil {
-		return err
+	if !config.isAdmin {
+		err = handler.checkStackPolicies(config.stack, config.endpoint, config.userID, true)
+		if err != nil {
+			return err
+		}
+
+		// the quota lock is held until the stack is deployed
+		unlock := handler.QuotaChecker.LockEndpoint(config.endpoint.ID)
+		defer unlock()
+
+		err = handler.checkStackQuotas(config.stack, config.endpoint, config.userID, true)
+		if err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return handler.StackDeployer.DeploySwarmStack(config.stack, config.endpoint, config.registries, config.dockerhub, config.prune)
 }
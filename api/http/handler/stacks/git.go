@@ -15,3 +15,10 @@ func (handler *Handler) cloneGitRepository(parameters *cloneRepositoryParameters
 	}
 	return handler.GitService.ClonePublicRepository(parameters.url, parameters.referenceName, parameters.path)
 }
+
+func (handler *Handler) latestCommitID(parameters *cloneRepositoryParameters) (string, error) {
+	if parameters.authentication {
+		return handler.GitService.LatestCommitID(parameters.url, parameters.referenceName, parameters.username, parameters.password)
+	}
+	return handler.GitService.LatestCommitID(parameters.url, parameters.referenceName, "", "")
+}
@@ -7,12 +7,18 @@ import (
 	"github.com/gorilla/mux"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 )
 
+func hideFields(stack *portainer.Stack) {
+	if stack.GitConfig != nil {
+		stack.GitConfig.Password = ""
+	}
+}
+
 // Handler is the HTTP handler used to handle stack operations.
 type Handler struct {
-	stackCreationMutex *sync.Mutex
 	stackDeletionMutex *sync.Mutex
 	requestBouncer     *security.RequestBouncer
 	*mux.Router
@@ -21,20 +27,25 @@ type Handler struct {
 	StackService           portainer.StackService
 	EndpointService        portainer.EndpointService
 	ResourceControlService portainer.ResourceControlService
+	ContainerPolicyService portainer.ContainerPolicyService
+	EndpointGroupService   portainer.EndpointGroupService
+	AuditService           portainer.AuditService
 	RegistryService        portainer.RegistryService
 	DockerHubService       portainer.DockerHubService
 	SwarmStackManager      portainer.SwarmStackManager
 	ComposeStackManager    portainer.ComposeStackManager
+	StackDeployer          portainer.StackDeployer
+	StackVersionManager    portainer.StackVersionManager
 	SettingsService        portainer.SettingsService
 	UserService            portainer.UserService
 	ExtensionService       portainer.ExtensionService
+	QuotaChecker           *docker.QuotaChecker
 }
 
 // NewHandler creates a handler to manage stack operations.
 func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h := &Handler{
 		Router:             mux.NewRouter(),
-		stackCreationMutex: &sync.Mutex{},
 		stackDeletionMutex: &sync.Mutex{},
 		requestBouncer:     bouncer,
 	}
@@ -52,6 +63,10 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFile))).Methods(http.MethodGet)
 	h.Handle("/stacks/{id}/migrate",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackMigrate))).Methods(http.MethodPost)
+	h.Handle("/stacks/{id}/versions",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackVersionList))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/rollback",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackRollback))).Methods(http.MethodPost)
 	return h
 }
 
@@ -0,0 +1,55 @@
+package stacks
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/policy"
+	"github.com/portainer/portainer/api/stacks"
+)
+
+// checkStackImages verifies that the images of the services of a stack respect the image restrictions
+// of the endpoint group. Administrators are exempt unless the restrictions apply to them.
+// Violations are recorded inside the audit log and returned as an error.
+func (handler *Handler) checkStackImages(stack *portainer.Stack, endpoint *portainer.Endpoint, userID portainer.UserID, isAdmin bool) error {
+	endpointGroup, err := handler.EndpointGroupService.EndpointGroup(endpoint.GroupID)
+	if err != nil {
+		return err
+	}
+
+	restrictions := &endpointGroup.ImageRestrictions
+	if !restrictions.Enabled || (isAdmin && !restrictions.ApplyToAdministrators) {
+		return nil
+	}
+
+	registries, err := handler.RegistryService.Registries()
+	if err != nil {
+		return err
+	}
+
+	stackContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	image, violation, err := stacks.ImageRestrictionViolation(restrictions, registries, stack, stackContent)
+	if err != nil || violation == "" {
+		return err
+	}
+
+	user, err := handler.UserService.User(userID)
+	if err != nil {
+		return err
+	}
+
+	policy.RecordImageRestrictionViolation(handler.AuditService, &portainer.AuditLog{
+		UserID:     user.ID,
+		Username:   user.Username,
+		EndpointID: endpoint.ID,
+		Path:       fmt.Sprintf("/api/stacks/%d", stack.ID),
+		ResourceID: image,
+	}, violation)
+
+	return fmt.Errorf("%s: %s", portainer.ErrImageNotAllowed, violation)
+}
@@ -0,0 +1,126 @@
+package stacks
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/policy"
+	"github.com/portainer/portainer/api/stacks"
+)
+
+// checkStackPolicies evaluates each service and each volume of a stack deployed by a non-administrator user
+// against the container policies of the endpoint group. Violations of policies in warn mode are logged,
+// violations of policies in enforce mode are returned as an error.
+func (handler *Handler) checkStackPolicies(stack *portainer.Stack, endpoint *portainer.Endpoint, userID portainer.UserID, swarm bool) error {
+	policies, err := handler.ContainerPolicyService.ContainerPolicies()
+	if err != nil {
+		return err
+	}
+
+	endpointPolicies := make([]portainer.ContainerPolicy, 0)
+	for _, containerPolicy := range policies {
+		if containerPolicy.EndpointGroupID == endpoint.GroupID {
+			endpointPolicies = append(endpointPolicies, containerPolicy)
+		}
+	}
+
+	if len(endpointPolicies) == 0 {
+		return nil
+	}
+
+	stackContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	composeConfigYAML, composeConfig, err := stacks.LoadStackFile(stack, stackContent)
+	if err != nil {
+		return err
+	}
+
+	specs := make(map[string]*policy.ContainerSpec)
+	for _, service := range composeConfig.Services {
+		memory, _, err := serviceLimits(stack, composeConfigYAML, &service, swarm)
+		if err != nil {
+			return err
+		}
+
+		spec := serviceContainerSpec(composeConfig, &service)
+		spec.Memory = memory
+		specs["service "+service.Name] = spec
+	}
+
+	for name, volume := range composeConfig.Volumes {
+		if volume.External.External {
+			continue
+		}
+
+		spec := &policy.ContainerSpec{MountsOnly: true}
+		source := policy.LocalVolumeBindSource(volume.Driver, volume.DriverOpts)
+		if source != "" {
+			spec.BindMounts = append(spec.BindMounts, source)
+		}
+		specs["volume "+name] = spec
+	}
+
+	for name, spec := range specs {
+		violations := policy.Evaluate(endpointPolicies, spec)
+
+		for _, violation := range violations {
+			if violation.Mode != portainer.ContainerPolicyEnforce {
+				log.Printf("[WARN] [http,stacks,policy] [message: container policy violation] [stack: %s] [resource: %s] [user: %d] [policy: %s] [violation: %s]", stack.Name, name, userID, violation.PolicyName, violation.Message)
+			}
+		}
+
+		message := policy.EnforcedMessage(violations)
+		if message != "" {
+			return fmt.Errorf("%s: %s: %s", portainer.ErrContainerPolicyViolation, name, message)
+		}
+	}
+
+	return nil
+}
+
+// serviceContainerSpec returns the specification of the containers of a service of a stack.
+// The labels of the service and of its containers are merged.
+func serviceContainerSpec(composeConfig *types.Config, service *types.ServiceConfig) *policy.ContainerSpec {
+	spec := &policy.ContainerSpec{
+		Image:        service.Image,
+		Privileged:   service.Privileged,
+		Capabilities: service.CapAdd,
+		HostNetwork:  service.NetworkMode == "host",
+		HostPID:      service.Pid == "host",
+		Labels:       make(map[string]string),
+	}
+
+	for key, value := range service.Deploy.Labels {
+		spec.Labels[key] = value
+	}
+
+	for key, value := range service.Labels {
+		spec.Labels[key] = value
+	}
+
+	for name := range service.Networks {
+		network := composeConfig.Networks[name]
+		if name == "host" || network.Name == "host" || network.External.Name == "host" {
+			spec.HostNetwork = true
+		}
+	}
+
+	for _, volume := range service.Volumes {
+		if volume.Type == "bind" {
+			spec.BindMounts = append(spec.BindMounts, volume.Source)
+		}
+	}
+
+	for _, device := range service.Devices {
+		spec.Devices = append(spec.Devices, strings.Split(device, ":")[0])
+	}
+
+	return spec
+}
@@ -0,0 +1,194 @@
+package stacks
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/template"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/quota"
+	"github.com/portainer/portainer/api/stacks"
+)
+
+var memoryUnits = map[string]int64{
+	"":  1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+}
+
+// checkStackQuotas verifies that the resources requested by the deployment of a stack by a
+// non-administrator user do not exceed the quotas associated to the user and to the teams of the user.
+// The resources currently consumed by the stack are not counted.
+func (handler *Handler) checkStackQuotas(stack *portainer.Stack, endpoint *portainer.Endpoint, userID portainer.UserID, swarm bool) error {
+	stackContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	quotaRequest, err := stackQuotaRequest(stack, stackContent, swarm)
+	if err != nil {
+		return err
+	}
+
+	violation, err := handler.QuotaChecker.Check(endpoint, userID, quotaRequest)
+	if err != nil {
+		return err
+	}
+
+	if violation != "" {
+		return fmt.Errorf("%s: %s", portainer.ErrQuotaExceeded, violation)
+	}
+
+	return nil
+}
+
+// stackQuotaRequest returns the resources requested by the deployment of a stack.
+// The tasks of a global service are requested on each node of the cluster. When a service does not
+// limit its memory or its CPU, the stack is considered as not limited.
+func stackQuotaRequest(stack *portainer.Stack, stackFileContent []byte, swarm bool) (*quota.Request, error) {
+	composeConfigYAML, composeConfig, err := stacks.LoadStackFile(stack, stackFileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaRequest := &quota.Request{ReplacedStack: stack.Name}
+	unlimitedMemory, unlimitedCPU := false, false
+	for _, service := range composeConfig.Services {
+		memory, nanoCPUs, err := serviceLimits(stack, composeConfigYAML, &service, swarm)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceResources := quota.Resources{
+			Containers: 1,
+			Memory:     memory,
+			NanoCPUs:   nanoCPUs,
+		}
+
+		unlimitedMemory = unlimitedMemory || serviceResources.Memory == 0
+		unlimitedCPU = unlimitedCPU || serviceResources.NanoCPUs == 0
+
+		switch {
+		case swarm && service.Deploy.Mode == "global":
+			quotaRequest.PerNodeResources.Add(&serviceResources)
+		case swarm && service.Deploy.Replicas != nil:
+			replicatedResources := serviceResources.Multiply(int(*service.Deploy.Replicas))
+			quotaRequest.Resources.Add(&replicatedResources)
+		default:
+			quotaRequest.Resources.Add(&serviceResources)
+		}
+	}
+
+	if unlimitedMemory {
+		quotaRequest.Resources.Memory = 0
+		quotaRequest.PerNodeResources.Memory = 0
+	}
+
+	if unlimitedCPU {
+		quotaRequest.Resources.NanoCPUs = 0
+		quotaRequest.PerNodeResources.NanoCPUs = 0
+	}
+
+	for _, volume := range composeConfig.Volumes {
+		if !volume.External.External {
+			quotaRequest.Resources.Volumes++
+		}
+	}
+
+	return quotaRequest, nil
+}
+
+// serviceLimits returns the memory and CPU limits of a service of a stack. The limits of the services
+// of a Compose stack can also be defined with the Compose file format version 2 settings.
+func serviceLimits(stack *portainer.Stack, composeConfigYAML map[string]interface{}, service *types.ServiceConfig, swarm bool) (int64, int64, error) {
+	var memory, nanoCPUs int64
+	var err error
+
+	if service.Deploy.Resources.Limits != nil {
+		memory = int64(service.Deploy.Resources.Limits.MemoryBytes)
+		nanoCPUs, err = parseNanoCPUs(service.Deploy.Resources.Limits.NanoCPUs)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if swarm {
+		return memory, nanoCPUs, nil
+	}
+
+	mapping := func(name string) (string, bool) {
+		for _, variable := range stack.Env {
+			if variable.Name == name {
+				return variable.Value, true
+			}
+		}
+		return "", false
+	}
+
+	rawServices, _ := composeConfigYAML["services"].(map[string]interface{})
+	rawService, _ := rawServices[service.Name].(map[string]interface{})
+
+	if memory == 0 && rawService["mem_limit"] != nil {
+		value, err := template.Substitute(fmt.Sprint(rawService["mem_limit"]), mapping)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		memory, err = parseMemory(value)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if nanoCPUs == 0 && rawService["cpus"] != nil {
+		value, err := template.Substitute(fmt.Sprint(rawService["cpus"]), mapping)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		nanoCPUs, err = parseNanoCPUs(value)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return memory, nanoCPUs, nil
+}
+
+// parseNanoCPUs converts a number of CPUs, such as 0.5, to NanoCPUs.
+func parseNanoCPUs(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	cpus, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(cpus * 1e9), nil
+}
+
+// parseMemory converts a memory amount, such as 512m, to bytes.
+func parseMemory(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	number := strings.TrimRight(value, "bkmgt")
+	unit := strings.TrimSuffix(value[len(number):], "b")
+
+	multiplier, ok := memoryUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid memory amount: %s", value)
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(amount * float64(multiplier)), nil
+}
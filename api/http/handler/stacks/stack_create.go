@@ -141,5 +141,6 @@ func (handler *Handler) decorateStackResponse(w http.ResponseWriter, stack *port
 	}
 
 	stack.ResourceControl = resourceControl
+	hideFields(stack)
 	return response.JSON(w, stack)
 }
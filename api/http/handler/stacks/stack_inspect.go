@@ -58,5 +58,6 @@ func (handler *Handler) stackInspect(w http.ResponseWriter, r *http.Request) *ht
 		stack.ResourceControl = resourceControl
 	}
 
+	hideFields(stack)
 	return response.JSON(w, stack)
 }
@@ -63,6 +63,10 @@ func (handler *Handler) stackList(w http.ResponseWriter, r *http.Request) *httpe
 		stacks = portainer.FilterAuthorizedStacks(stacks, user, userTeamIDs, rbacExtensionEnabled)
 	}
 
+	for idx := range stacks {
+		hideFields(&stacks[idx])
+	}
+
 	return response.JSON(w, stacks)
 }
 
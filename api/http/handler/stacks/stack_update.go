@@ -103,6 +103,12 @@ func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *htt
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
 	}
 
+	err = handler.createStackVersion(r, stack)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
+
+	hideFields(stack)
 	return response.JSON(w, stack)
 }
 
@@ -0,0 +1,19 @@
+package stacks
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// createStackVersion stores the current stack file and environment variables of a stack
+// as a new version created by the user issuing the request.
+func (handler *Handler) createStackVersion(r *http.Request, stack *portainer.Stack) error {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return err
+	}
+
+	return handler.StackVersionManager.CreateStackVersion(stack, tokenData.Username)
+}
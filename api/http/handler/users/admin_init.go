@@ -8,6 +8,7 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
 )
 
 type adminInitPayload struct {
@@ -46,10 +47,18 @@ func (handler *Handler) adminInit(w http.ResponseWriter, r *http.Request) *httpe
 		Username:                payload.Username,
 		Role:                    portainer.AdministratorRole,
 		PortainerAuthorizations: portainer.DefaultPortainerAuthorizations(),
+		AuthenticationMethod:    portainer.AuthenticationInternal,
 	}
 
-	user.Password, err = handler.CryptoService.Hash(payload.Password)
+	settings, err := handler.SettingsService.Settings()
 	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = security.SetUserPassword(user, payload.Password, &settings.PasswordPolicy, handler.CryptoService)
+	if policyError, ok := err.(*security.PasswordPolicyError); ok {
+		return security.WritePasswordPolicyError(w, policyError)
+	} else if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", portainer.ErrCryptoHashFailure}
 	}
 
@@ -58,5 +67,6 @@ func (handler *Handler) adminInit(w http.ResponseWriter, r *http.Request) *httpe
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user inside the database", err}
 	}
 
+	hideFields(user)
 	return response.JSON(w, user)
 }
@@ -12,6 +12,14 @@ import (
 
 func hideFields(user *portainer.User) {
 	user.Password = ""
+	user.TOTPSecret = ""
+	user.TOTPRecoveryCodes = nil
+	user.TOTPLastCounter = 0
+	user.PasswordHistory = nil
+}
+
+func hideAPIKeyFields(apiKey *portainer.APIKey) {
+	apiKey.Digest = ""
 }
 
 // Handler is the HTTP handler used to handle user operations.
@@ -24,6 +32,9 @@ type Handler struct {
 	CryptoService          portainer.CryptoService
 	SettingsService        portainer.SettingsService
 	AuthorizationService   *portainer.AuthorizationService
+	APIKeyService          portainer.APIKeyService
+	JWTService             portainer.JWTService
+	TOTPService            portainer.TOTPService
 }
 
 // NewHandler creates a handler to manage user operations.
@@ -45,6 +56,20 @@ func NewHandler(bouncer *security.RequestBouncer, rateLimiter *security.RateLimi
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.userMemberships))).Methods(http.MethodGet)
 	h.Handle("/users/{id}/passwd",
 		rateLimiter.LimitAccess(bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userUpdatePassword)))).Methods(http.MethodPut)
+	h.Handle("/users/{id}/sessions",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.userSessionsRevoke))).Methods(http.MethodDelete)
+	h.Handle("/users/{id}/tokens",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userTokenCreate))).Methods(http.MethodPost)
+	h.Handle("/users/{id}/tokens",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userTokenList))).Methods(http.MethodGet)
+	h.Handle("/users/{id}/tokens/{tokenId}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userTokenDelete))).Methods(http.MethodDelete)
+	h.Handle("/users/{id}/2fa",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userTwoFactorEnrol))).Methods(http.MethodPost)
+	h.Handle("/users/{id}/2fa/verify",
+		rateLimiter.LimitAccess(bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.userTwoFactorVerify)))).Methods(http.MethodPost)
+	h.Handle("/users/{id}/2fa",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.userTwoFactorReset))).Methods(http.MethodDelete)
 	h.Handle("/users/admin/check",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.adminCheck))).Methods(http.MethodGet)
 	h.Handle("/users/admin/init",
@@ -0,0 +1,57 @@
+package users
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// POST request on /api/users/:id/2fa
+// Creates a pending two-factor authentication enrolment for the user. Two-factor authentication
+// is only enabled once a code has been verified on /api/users/:id/2fa/verify.
+func (handler *Handler) userTwoFactorEnrol(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.ID != portainer.UserID(userID) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to enrol another user in two-factor authentication", portainer.ErrUnauthorized}
+	}
+
+	user, err := handler.UserService.User(portainer.UserID(userID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	if user.Password == "" {
+		return &httperror.HandlerError{http.StatusBadRequest, "Two-factor authentication is only available for internal users", portainer.ErrTwoFactorNotSupported}
+	}
+
+	if user.TOTPEnabled {
+		return &httperror.HandlerError{http.StatusConflict, "Two-factor authentication is already enabled for this user", portainer.ErrTwoFactorAlreadyEnabled}
+	}
+
+	enrolment, err := handler.TOTPService.Enrol(user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to create two-factor authentication enrolment", err}
+	}
+
+	err = handler.UserService.UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return response.JSON(w, enrolment)
+}
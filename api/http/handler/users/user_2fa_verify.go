@@ -0,0 +1,76 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type userTwoFactorVerifyPayload struct {
+	Code string
+}
+
+func (payload *userTwoFactorVerifyPayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Code) {
+		return portainer.Error("Invalid code")
+	}
+	return nil
+}
+
+// POST request on /api/users/:id/2fa/verify
+// Enables two-factor authentication for the user once a code generated from the pending
+// enrolment has been verified.
+func (handler *Handler) userTwoFactorVerify(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.ID != portainer.UserID(userID) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to enrol another user in two-factor authentication", portainer.ErrUnauthorized}
+	}
+
+	var payload userTwoFactorVerifyPayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	user, err := handler.UserService.User(portainer.UserID(userID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	if user.TOTPEnabled {
+		return &httperror.HandlerError{http.StatusConflict, "Two-factor authentication is already enabled for this user", portainer.ErrTwoFactorAlreadyEnabled}
+	}
+
+	err = handler.TOTPService.Verify(user, payload.Code)
+	if err == portainer.ErrTwoFactorInvalidCode {
+		return &httperror.HandlerError{http.StatusUnprocessableEntity, "Invalid two-factor authentication code", err}
+	} else if err == portainer.ErrTwoFactorNotEnrolled {
+		return &httperror.HandlerError{http.StatusBadRequest, "Two-factor authentication enrolment not found for this user", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify two-factor authentication code", err}
+	}
+
+	user.TOTPEnabled = true
+	err = handler.UserService.UpdateUser(user.ID, user)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
+	}
+
+	return response.Empty(w)
+}
@@ -57,20 +57,23 @@ func (handler *Handler) userCreate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusConflict, "Another user with the same username already exists", portainer.ErrUserAlreadyExists}
 	}
 
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
 	user = &portainer.User{
 		Username:                payload.Username,
 		Role:                    portainer.UserRole(payload.Role),
 		PortainerAuthorizations: portainer.DefaultPortainerAuthorizations(),
-	}
-
-	settings, err := handler.SettingsService.Settings()
-	if err != nil {
-		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		AuthenticationMethod:    settings.AuthenticationMethod,
 	}
 
 	if settings.AuthenticationMethod == portainer.AuthenticationInternal {
-		user.Password, err = handler.CryptoService.Hash(payload.Password)
-		if err != nil {
+		err = security.SetUserPassword(user, payload.Password, &settings.PasswordPolicy, handler.CryptoService)
+		if policyError, ok := err.(*security.PasswordPolicyError); ok {
+			return security.WritePasswordPolicyError(w, policyError)
+		} else if err != nil {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", portainer.ErrCryptoHashFailure}
 		}
 	}
@@ -80,5 +80,17 @@ func (handler *Handler) deleteUser(w http.ResponseWriter, user *portainer.User)
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to clean-up user access policies", err}
 	}
 
+	apiKeys, err := handler.APIKeyService.APIKeysByUserID(user.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user API keys from the database", err}
+	}
+
+	for _, apiKey := range apiKeys {
+		err = handler.APIKeyService.DeleteAPIKey(apiKey.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove user API key from the database", err}
+		}
+	}
+
 	return response.Empty(w)
 }
@@ -0,0 +1,49 @@
+package users
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// DELETE request on /api/users/:id/sessions
+// Revokes all the authentication tokens delivered to the user so far and removes the API keys of the user.
+func (handler *Handler) userSessionsRevoke(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if handler.JWTService == nil {
+		return &httperror.HandlerError{http.StatusServiceUnavailable, "Cannot revoke user sessions. Portainer was started with the --no-auth flag", portainer.ErrUnauthorized}
+	}
+
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	_, err = handler.UserService.User(portainer.UserID(userID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	err = handler.JWTService.RevokeUserSessions(portainer.UserID(userID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to revoke user sessions", err}
+	}
+
+	apiKeys, err := handler.APIKeyService.APIKeysByUserID(portainer.UserID(userID))
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the API keys of the user from the database", err}
+	}
+
+	for _, apiKey := range apiKeys {
+		err = handler.APIKeyService.DeleteAPIKey(apiKey.ID)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the API key from the database", err}
+		}
+	}
+
+	return response.Empty(w)
+}
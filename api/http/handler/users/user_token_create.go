@@ -0,0 +1,89 @@
+package users
+
+import (
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/securecookie"
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+type userTokenCreatePayload struct {
+	Name       string
+	ExpiryDate int64
+}
+
+func (payload *userTokenCreatePayload) Validate(r *http.Request) error {
+	if govalidator.IsNull(payload.Name) {
+		return portainer.Error("Invalid token name")
+	}
+	if payload.ExpiryDate != 0 && payload.ExpiryDate <= time.Now().Unix() {
+		return portainer.Error("Invalid token expiry date. Must be a timestamp in the future")
+	}
+	return nil
+}
+
+type userTokenCreateResponse struct {
+	RawAPIKey string            `json:"RawAPIKey"`
+	APIKey    *portainer.APIKey `json:"APIKey"`
+}
+
+// POST request on /api/users/:id/tokens
+func (handler *Handler) userTokenCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != portainer.UserID(userID) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to manage user API tokens", portainer.ErrUnauthorized}
+	}
+
+	var payload userTokenCreatePayload
+	err = request.DecodeAndValidateJSONPayload(r, &payload)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
+	}
+
+	user, err := handler.UserService.User(portainer.UserID(userID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a user with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a user with the specified identifier inside the database", err}
+	}
+
+	rawAPIKey := portainer.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(securecookie.GenerateRandomKey(32))
+
+	digest, err := handler.CryptoService.Hash(rawAPIKey)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash API key", portainer.ErrCryptoHashFailure}
+	}
+
+	apiKey := &portainer.APIKey{
+		UserID:       user.ID,
+		Name:         payload.Name,
+		Prefix:       security.APIKeyPrefix(rawAPIKey),
+		Digest:       digest,
+		CreationDate: time.Now().Unix(),
+		ExpiryDate:   payload.ExpiryDate,
+	}
+
+	err = handler.APIKeyService.CreateAPIKey(apiKey)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the API key inside the database", err}
+	}
+
+	hideAPIKeyFields(apiKey)
+	return response.JSON(w, &userTokenCreateResponse{RawAPIKey: rawAPIKey, APIKey: apiKey})
+}
@@ -0,0 +1,47 @@
+package users
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// DELETE request on /api/users/:id/tokens/:tokenId
+func (handler *Handler) userTokenDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid user identifier route variable", err}
+	}
+
+	apiKeyID, err := request.RetrieveNumericRouteVariableValue(r, "tokenId")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid token identifier route variable", err}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user authentication token", err}
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != portainer.UserID(userID) {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to manage user API tokens", portainer.ErrUnauthorized}
+	}
+
+	apiKey, err := handler.APIKeyService.APIKey(portainer.APIKeyID(apiKeyID))
+	if err == portainer.ErrObjectNotFound || (err == nil && apiKey.UserID != portainer.UserID(userID)) {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an API key with the specified identifier inside the database", portainer.ErrObjectNotFound}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an API key with the specified identifier inside the database", err}
+	}
+
+	err = handler.APIKeyService.DeleteAPIKey(apiKey.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to remove the API key from the database", err}
+	}
+
+	return response.Empty(w)
+}
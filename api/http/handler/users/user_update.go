@@ -56,8 +56,15 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 	}
 
 	if payload.Password != "" {
-		user.Password, err = handler.CryptoService.Hash(payload.Password)
+		settings, err := handler.SettingsService.Settings()
 		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+		}
+
+		err = security.SetUserPassword(user, payload.Password, &settings.PasswordPolicy, handler.CryptoService)
+		if policyError, ok := err.(*security.PasswordPolicyError); ok {
+			return security.WritePasswordPolicyError(w, policyError)
+		} else if err != nil {
 			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", portainer.ErrCryptoHashFailure}
 		}
 	}
@@ -71,5 +78,6 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist user changes inside the database", err}
 	}
 
+	hideFields(user)
 	return response.JSON(w, user)
 }
@@ -60,8 +60,15 @@ func (handler *Handler) userUpdatePassword(w http.ResponseWriter, r *http.Reques
 		return &httperror.HandlerError{http.StatusForbidden, "Specified password do not match actual password", portainer.ErrUnauthorized}
 	}
 
-	user.Password, err = handler.CryptoService.Hash(payload.NewPassword)
+	settings, err := handler.SettingsService.Settings()
 	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve settings from the database", err}
+	}
+
+	err = security.SetUserPassword(user, payload.NewPassword, &settings.PasswordPolicy, handler.CryptoService)
+	if policyError, ok := err.(*security.PasswordPolicyError); ok {
+		return security.WritePasswordPolicyError(w, policyError)
+	} else if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to hash user password", portainer.ErrCryptoHashFailure}
 	}
 
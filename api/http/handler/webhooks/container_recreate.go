@@ -0,0 +1,280 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/portainer/portainer/api"
+)
+
+// recreateContainer pulls the image of a container and replaces the container with a new one
+// created from the same configuration. The container is renamed first so that it can be restored
+// if the new container cannot be created. It returns the identifier of the new container.
+// The image must respect the image restrictions of the endpoint group.
+func (handler *Handler) recreateContainer(dockerClient *client.Client, endpoint *portainer.Endpoint, containerID, imageTag string) (string, error) {
+	ctx := context.Background()
+
+	container, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+
+	image, err := recreationImage(container.Config.Image, imageTag)
+	if err != nil {
+		return "", err
+	}
+
+	err = handler.checkImageRestrictions(endpoint, image)
+	if err != nil {
+		return "", err
+	}
+
+	err = handler.pullImage(dockerClient, image)
+	if err != nil {
+		return "", err
+	}
+
+	containerName := strings.TrimPrefix(container.Name, "/")
+
+	err = dockerClient.ContainerRename(ctx, container.ID, containerName+"-"+container.ID[:12])
+	if err != nil {
+		return "", err
+	}
+
+	config := container.Config
+	config.Image = image
+	if config.Hostname == container.ID[:12] {
+		config.Hostname = ""
+	}
+
+	hostConfig := container.HostConfig
+	hostConfig.Binds = append(hostConfig.Binds, anonymousVolumeBinds(container)...)
+
+	networkingConfig, additionalNetworks := buildNetworkingConfig(container)
+
+	if container.State.Running {
+		err = dockerClient.ContainerStop(ctx, container.ID, nil)
+		if err != nil {
+			dockerClient.ContainerRename(ctx, container.ID, containerName)
+			return "", err
+		}
+	}
+
+	newContainer, err := dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, containerName)
+	if err != nil {
+		restoreContainer(dockerClient, container, containerName)
+		return "", err
+	}
+
+	for networkName, settings := range additionalNetworks {
+		err = dockerClient.NetworkConnect(ctx, networkName, newContainer.ID, settings)
+		if err != nil {
+			dockerClient.ContainerRemove(ctx, newContainer.ID, dockertypes.ContainerRemoveOptions{Force: true})
+			restoreContainer(dockerClient, container, containerName)
+			return "", err
+		}
+	}
+
+	if container.State.Running {
+		err = dockerClient.ContainerStart(ctx, newContainer.ID, dockertypes.ContainerStartOptions{})
+		if err != nil {
+			dockerClient.ContainerRemove(ctx, newContainer.ID, dockertypes.ContainerRemoveOptions{Force: true})
+			restoreContainer(dockerClient, container, containerName)
+			return "", err
+		}
+	}
+
+	// The new container is already in place at this point: a failure to remove the previous container
+	// must not prevent the caller from associating the webhook and the resource control to the new one.
+	err = dockerClient.ContainerRemove(ctx, container.ID, dockertypes.ContainerRemoveOptions{Force: true})
+	if err != nil {
+		log.Printf("[WARN] [http,webhooks] [message: unable to remove the previous container] [container: %s] [err: %s]", container.ID, err)
+	}
+
+	return newContainer.ID, nil
+}
+
+// recreationImage returns the image used to recreate a container. When a tag is specified, it
+// replaces the tag of the current image, otherwise the current tag is kept. The digest of the
+// current image is always dropped so that the latest version of the tag is pulled.
+func recreationImage(image, imageTag string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", err
+	}
+
+	if imageTag == "" {
+		if tagged, ok := named.(reference.Tagged); ok {
+			imageTag = tagged.Tag()
+		}
+	}
+
+	if imageTag == "" {
+		return reference.FamiliarString(reference.TagNameOnly(reference.TrimNamed(named))), nil
+	}
+
+	tagged, err := reference.WithTag(reference.TrimNamed(named), imageTag)
+	if err != nil {
+		return "", err
+	}
+
+	return reference.FamiliarString(tagged), nil
+}
+
+func (handler *Handler) pullImage(dockerClient *client.Client, image string) error {
+	registryAuth, err := handler.registryAuthenticationHeader(image)
+	if err != nil {
+		return err
+	}
+
+	reader, err := dockerClient.ImagePull(context.Background(), image, dockertypes.ImagePullOptions{RegistryAuth: registryAuth})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(ioutil.Discard, reader)
+	return err
+}
+
+// registryAuthenticationHeader returns the encoded credentials of the registry hosting the image.
+// The DockerHub credentials are used when the image is not hosted on a known registry.
+func (handler *Handler) registryAuthenticationHeader(image string) (string, error) {
+	registries, err := handler.RegistryService.Registries()
+	if err != nil {
+		return "", err
+	}
+
+	var authConfig *dockertypes.AuthConfig
+	for _, registry := range registries {
+		if strings.HasPrefix(image, registry.URL+"/") {
+			if registry.Authentication {
+				authConfig = &dockertypes.AuthConfig{
+					Username:      registry.Username,
+					Password:      registry.Password,
+					ServerAddress: registry.URL,
+				}
+			}
+			break
+		}
+	}
+
+	if authConfig == nil && !strings.Contains(strings.Split(image, "/")[0], ".") {
+		dockerhub, err := handler.DockerHubService.DockerHub()
+		if err != nil {
+			return "", err
+		}
+
+		if dockerhub.Authentication {
+			authConfig = &dockertypes.AuthConfig{
+				Username: dockerhub.Username,
+				Password: dockerhub.Password,
+			}
+		}
+	}
+
+	if authConfig == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(authConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+func restoreContainer(dockerClient *client.Client, container dockertypes.ContainerJSON, containerName string) {
+	ctx := context.Background()
+
+	dockerClient.ContainerRename(ctx, container.ID, containerName)
+	if container.State.Running {
+		dockerClient.ContainerStart(ctx, container.ID, dockertypes.ContainerStartOptions{})
+	}
+}
+
+// anonymousVolumeBinds returns the binds required to re-use the anonymous volumes of a container.
+// Volumes declared via the image or via the container configuration would be recreated otherwise.
+func anonymousVolumeBinds(container dockertypes.ContainerJSON) []string {
+	mountedDestinations := make(map[string]bool)
+	for _, bind := range container.HostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) > 1 {
+			mountedDestinations[parts[1]] = true
+		}
+	}
+	for _, hostMount := range container.HostConfig.Mounts {
+		mountedDestinations[hostMount.Target] = true
+	}
+
+	binds := make([]string, 0)
+	for _, containerMount := range container.Mounts {
+		if containerMount.Type != mount.TypeVolume || mountedDestinations[containerMount.Destination] {
+			continue
+		}
+
+		bind := containerMount.Name + ":" + containerMount.Destination
+		if !containerMount.RW {
+			bind += ":ro"
+		}
+		binds = append(binds, bind)
+	}
+
+	return binds
+}
+
+// buildNetworkingConfig returns the networking configuration used to create the new container.
+// Only one network can be specified at creation time, the other networks are returned
+// separately so that they can be connected once the container is created.
+func buildNetworkingConfig(container dockertypes.ContainerJSON) (*network.NetworkingConfig, map[string]*network.EndpointSettings) {
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: make(map[string]*network.EndpointSettings),
+	}
+	additionalNetworks := make(map[string]*network.EndpointSettings)
+
+	networkMode := container.HostConfig.NetworkMode
+	if networkMode.IsHost() || networkMode.IsNone() || networkMode.IsContainer() || container.NetworkSettings == nil {
+		return networkingConfig, additionalNetworks
+	}
+
+	primaryNetwork := string(networkMode)
+	if networkMode.IsDefault() {
+		primaryNetwork = "bridge"
+	}
+
+	for networkName, settings := range container.NetworkSettings.Networks {
+		aliases := make([]string, 0)
+		for _, alias := range settings.Aliases {
+			if !strings.HasPrefix(container.ID, alias) {
+				aliases = append(aliases, alias)
+			}
+		}
+
+		endpointSettings := &network.EndpointSettings{
+			IPAMConfig: settings.IPAMConfig,
+			Links:      settings.Links,
+			Aliases:    aliases,
+			DriverOpts: settings.DriverOpts,
+		}
+
+		if networkName == primaryNetwork {
+			networkingConfig.EndpointsConfig[networkName] = endpointSettings
+			continue
+		}
+
+		additionalNetworks[networkName] = endpointSettings
+	}
+
+	return networkingConfig, additionalNetworks
+}
@@ -13,15 +13,30 @@ import (
 // Handler is the HTTP handler used to handle webhook operations.
 type Handler struct {
 	*mux.Router
-	WebhookService      portainer.WebhookService
-	EndpointService     portainer.EndpointService
-	DockerClientFactory *docker.ClientFactory
+	requestBouncer         *security.RequestBouncer
+	WebhookService         portainer.WebhookService
+	EndpointService        portainer.EndpointService
+	EndpointGroupService   portainer.EndpointGroupService
+	AuditService           portainer.AuditService
+	ResourceControlService portainer.ResourceControlService
+	UserService            portainer.UserService
+	ReverseTunnelService   portainer.ReverseTunnelService
+	SettingsService        portainer.SettingsService
+	StackService           portainer.StackService
+	RegistryService        portainer.RegistryService
+	DockerHubService       portainer.DockerHubService
+	GitService             portainer.GitService
+	FileService            portainer.FileService
+	StackDeployer          portainer.StackDeployer
+	StackVersionManager    portainer.StackVersionManager
+	DockerClientFactory    *docker.ClientFactory
 }
 
 // NewHandler creates a handler to manage settings operations.
 func NewHandler(bouncer *security.RequestBouncer) *Handler {
 	h := &Handler{
-		Router: mux.NewRouter(),
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
 	}
 	h.Handle("/webhooks",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.webhookCreate))).Methods(http.MethodPost)
@@ -33,3 +48,29 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.PublicAccess(httperror.LoggerHandler(h.webhookExecute))).Methods(http.MethodPost)
 	return h
 }
+
+// userCanAccessResource returns true when the user described by the security context can manage
+// a resource deployed on the endpoint: either through the resource control associated to the resource
+// or through an authorization granting access to all the resources of the endpoint.
+func (handler *Handler) userCanAccessResource(securityContext *security.RestrictedRequestContext, endpointID portainer.EndpointID, resourceControl *portainer.ResourceControl) (bool, error) {
+	if securityContext.IsAdmin {
+		return true, nil
+	}
+
+	userTeamIDs := make([]portainer.TeamID, 0)
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	if resourceControl != nil && portainer.UserCanAccessResource(securityContext.UserID, userTeamIDs, resourceControl) {
+		return true, nil
+	}
+
+	user, err := handler.UserService.User(securityContext.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := user.EndpointAuthorizations[endpointID][portainer.EndpointResourcesAccess]
+	return ok, nil
+}
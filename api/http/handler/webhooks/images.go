@@ -0,0 +1,77 @@
+package webhooks
+
+import (
+	"path"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/policy"
+	"github.com/portainer/portainer/api/stacks"
+)
+
+// checkImageRestrictions verifies that an image pulled by a webhook respects the image restrictions of the
+// endpoint group. Webhooks are not associated to a user, the restrictions always apply when they are enabled.
+// Violations are recorded inside the audit log and ErrImageNotAllowed is returned.
+func (handler *Handler) checkImageRestrictions(endpoint *portainer.Endpoint, image string) error {
+	restrictions, registries, err := handler.imageRestrictions(endpoint)
+	if err != nil || restrictions == nil {
+		return err
+	}
+
+	violation := policy.ImageRestrictionViolation(restrictions, registries, image)
+	if violation == "" {
+		return nil
+	}
+
+	return handler.recordImageRestrictionViolation(endpoint, image, violation)
+}
+
+// checkStackImageRestrictions verifies that the images of the services of a stack redeployed by a webhook
+// respect the image restrictions of the endpoint group.
+func (handler *Handler) checkStackImageRestrictions(endpoint *portainer.Endpoint, stack *portainer.Stack) error {
+	restrictions, registries, err := handler.imageRestrictions(endpoint)
+	if err != nil || restrictions == nil {
+		return err
+	}
+
+	stackContent, err := handler.FileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	image, violation, err := stacks.ImageRestrictionViolation(restrictions, registries, stack, stackContent)
+	if err != nil || violation == "" {
+		return err
+	}
+
+	return handler.recordImageRestrictionViolation(endpoint, image, violation)
+}
+
+// imageRestrictions returns the image restrictions of the endpoint group and the registries,
+// or nil when the restrictions are not enabled.
+func (handler *Handler) imageRestrictions(endpoint *portainer.Endpoint) (*portainer.ImageRestrictions, []portainer.Registry, error) {
+	endpointGroup, err := handler.EndpointGroupService.EndpointGroup(endpoint.GroupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !endpointGroup.ImageRestrictions.Enabled {
+		return nil, nil, nil
+	}
+
+	registries, err := handler.RegistryService.Registries()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &endpointGroup.ImageRestrictions, registries, nil
+}
+
+func (handler *Handler) recordImageRestrictionViolation(endpoint *portainer.Endpoint, image, violation string) error {
+	policy.RecordImageRestrictionViolation(handler.AuditService, &portainer.AuditLog{
+		EndpointID: endpoint.ID,
+		Path:       "/api/webhooks",
+		ResourceID: image,
+	}, violation)
+
+	return portainer.ErrImageNotAllowed
+}
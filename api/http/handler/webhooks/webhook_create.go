@@ -1,7 +1,9 @@
 package webhooks
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gofrs/uuid"
@@ -9,6 +11,7 @@ import (
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
 )
 
 type webhookCreatePayload struct {
@@ -24,7 +27,7 @@ func (payload *webhookCreatePayload) Validate(r *http.Request) error {
 	if payload.EndpointID == 0 {
 		return portainer.Error("Invalid EndpointID")
 	}
-	if payload.WebhookType != 1 {
+	if payload.WebhookType != 1 && payload.WebhookType != 2 && payload.WebhookType != 3 {
 		return portainer.Error("Invalid WebhookType")
 	}
 	return nil
@@ -37,6 +40,37 @@ func (handler *Handler) webhookCreate(w http.ResponseWriter, r *http.Request) *h
 		return &httperror.HandlerError{http.StatusBadRequest, "Invalid request payload", err}
 	}
 
+	endpoint, err := handler.EndpointService.Endpoint(portainer.EndpointID(payload.EndpointID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find an endpoint with the specified identifier inside the database", err}
+	}
+
+	err = handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint, true)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusForbidden, "Permission denied to access endpoint", err}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve user info from request context", err}
+	}
+
+	switch portainer.WebhookType(payload.WebhookType) {
+	case portainer.StackWebhook:
+		handlerErr := handler.authorizeStackWebhook(securityContext, endpoint, payload.ResourceID)
+		if handlerErr != nil {
+			return handlerErr
+		}
+	case portainer.ContainerWebhook:
+		containerID, handlerErr := handler.authorizeContainerWebhook(securityContext, endpoint, payload.ResourceID)
+		if handlerErr != nil {
+			return handlerErr
+		}
+		payload.ResourceID = containerID
+	}
+
 	webhook, err := handler.WebhookService.WebhookByResourceID(payload.ResourceID)
 	if err != nil && err != portainer.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusInternalServerError, "An error occurred retrieving webhooks from the database", err}
@@ -64,3 +98,104 @@ func (handler *Handler) webhookCreate(w http.ResponseWriter, r *http.Request) *h
 
 	return response.JSON(w, webhook)
 }
+
+// authorizeStackWebhook verifies that the stack targeted by a webhook is deployed on the endpoint
+// associated to the webhook and that the user can manage it.
+func (handler *Handler) authorizeStackWebhook(securityContext *security.RestrictedRequestContext, endpoint *portainer.Endpoint, resourceID string) *httperror.HandlerError {
+	stackID, err := strconv.Atoi(resourceID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid stack identifier", err}
+	}
+
+	stack, err := handler.StackService.Stack(portainer.StackID(stackID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	if stack.EndpointID != endpoint.ID {
+		return &httperror.HandlerError{http.StatusBadRequest, "The stack is not deployed on the specified endpoint", portainer.Error("Invalid EndpointID")}
+	}
+
+	resourceControl, err := handler.ResourceControlService.ResourceControlByResourceIDAndType(stack.Name, portainer.StackResourceControl)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a resource control associated to the stack", err}
+	}
+
+	access, err := handler.userCanAccessResource(securityContext, endpoint.ID, resourceControl)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate stack access", err}
+	}
+	if !access {
+		return &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", portainer.ErrResourceAccessDenied}
+	}
+
+	return nil
+}
+
+// authorizeContainerWebhook verifies that the container targeted by a webhook exists on the endpoint
+// and that the user can manage it. It returns the full identifier of the container.
+func (handler *Handler) authorizeContainerWebhook(securityContext *security.RestrictedRequestContext, endpoint *portainer.Endpoint, resourceID string) (string, *httperror.HandlerError) {
+	if endpoint.Type == portainer.EdgeAgentEnvironment {
+		err := handler.requireEdgeTunnel(endpoint)
+		if err != nil {
+			return "", &httperror.HandlerError{http.StatusInternalServerError, "Unable to open a tunnel to the Edge agent", err}
+		}
+	}
+
+	dockerClient, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return "", &httperror.HandlerError{http.StatusInternalServerError, "Error creating docker client", err}
+	}
+	defer dockerClient.Close()
+
+	container, err := dockerClient.ContainerInspect(context.Background(), resourceID)
+	if err != nil {
+		return "", &httperror.HandlerError{http.StatusNotFound, "Unable to find a container with the specified identifier on the endpoint", err}
+	}
+
+	resourceControls, err := handler.ResourceControlService.ResourceControls()
+	if err != nil {
+		return "", &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve resource controls from the database", err}
+	}
+
+	resourceControl := containerResourceControl(container.ID, container.Config.Labels, resourceControls)
+
+	access, err := handler.userCanAccessResource(securityContext, endpoint.ID, resourceControl)
+	if err != nil {
+		return "", &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify user authorizations to validate container access", err}
+	}
+	if !access {
+		return "", &httperror.HandlerError{http.StatusForbidden, "Access denied to resource", portainer.ErrResourceAccessDenied}
+	}
+
+	return container.ID, nil
+}
+
+// containerResourceControl returns the resource control associated to a container, or the one
+// inherited from the service or the stack the container belongs to.
+func containerResourceControl(containerID string, labels map[string]string, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	resourceControl := portainer.GetResourceControlByResourceIDAndType(containerID, portainer.ContainerResourceControl, resourceControls)
+	if resourceControl != nil {
+		return resourceControl
+	}
+
+	if serviceID, ok := labels["com.docker.swarm.service.id"]; ok {
+		resourceControl = portainer.GetResourceControlByResourceIDAndType(serviceID, portainer.ServiceResourceControl, resourceControls)
+		if resourceControl != nil {
+			return resourceControl
+		}
+	}
+
+	for _, stackLabel := range []string{"com.docker.stack.namespace", "com.docker.compose.project"} {
+		if stackName, ok := labels[stackLabel]; ok {
+			resourceControl = portainer.GetResourceControlByResourceIDAndType(stackName, portainer.StackResourceControl, resourceControls)
+			if resourceControl != nil {
+				return resourceControl
+			}
+		}
+	}
+
+	return nil
+}
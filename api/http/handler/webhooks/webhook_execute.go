@@ -3,13 +3,16 @@ package webhooks
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	dockertypes "github.com/docker/docker/api/types"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/libhttp/request"
 	"github.com/portainer/libhttp/response"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/stacks"
 )
 
 // Acts on a passed in token UUID to restart the docker service
@@ -33,6 +36,19 @@ func (handler *Handler) webhookExecute(w http.ResponseWriter, r *http.Request) *
 	endpointID := webhook.EndpointID
 	webhookType := webhook.WebhookType
 
+	var stack *portainer.Stack
+	if webhookType == portainer.StackWebhook {
+		stack, err = handler.webhookStack(resourceID)
+		if err == portainer.ErrObjectNotFound {
+			return &httperror.HandlerError{http.StatusNotFound, "Unable to find a stack with the specified identifier inside the database", err}
+		} else if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find the stack associated to the webhook", err}
+		}
+
+		// The stack is always redeployed on the endpoint where it was created.
+		endpointID = stack.EndpointID
+	}
+
 	endpoint, err := handler.EndpointService.Endpoint(portainer.EndpointID(endpointID))
 	if err == portainer.ErrObjectNotFound {
 		return &httperror.HandlerError{http.StatusNotFound, "Unable to find an endpoint with the specified identifier inside the database", err}
@@ -42,9 +58,20 @@ func (handler *Handler) webhookExecute(w http.ResponseWriter, r *http.Request) *
 
 	imageTag, _ := request.RetrieveQueryParameter(r, "tag", true)
 
+	if endpoint.Type == portainer.EdgeAgentEnvironment {
+		err = handler.requireEdgeTunnel(endpoint)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to open a tunnel to the Edge agent", err}
+		}
+	}
+
 	switch webhookType {
 	case portainer.ServiceWebhook:
 		return handler.executeServiceWebhook(w, endpoint, resourceID, imageTag)
+	case portainer.StackWebhook:
+		return handler.executeStackWebhook(w, endpoint, stack)
+	case portainer.ContainerWebhook:
+		return handler.executeContainerWebhook(w, webhook, endpoint, imageTag)
 	default:
 		return &httperror.HandlerError{http.StatusInternalServerError, "Unsupported webhook type", portainer.ErrUnsupportedWebhookType}
 	}
@@ -70,6 +97,13 @@ func (handler *Handler) executeServiceWebhook(w http.ResponseWriter, endpoint *p
 		service.Spec.TaskTemplate.ContainerSpec.Image = strings.Split(service.Spec.TaskTemplate.ContainerSpec.Image, "@sha")[0]
 	}
 
+	err = handler.checkImageRestrictions(endpoint, service.Spec.TaskTemplate.ContainerSpec.Image)
+	if err == portainer.ErrImageNotAllowed {
+		return &httperror.HandlerError{http.StatusForbidden, "Image not allowed on this endpoint", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the image restrictions of the endpoint group", err}
+	}
+
 	_, err = dockerClient.ServiceUpdate(context.Background(), resourceID, service.Version, service.Spec, dockertypes.ServiceUpdateOptions{QueryRegistry: true})
 
 	if err != nil {
@@ -77,3 +111,138 @@ func (handler *Handler) executeServiceWebhook(w http.ResponseWriter, endpoint *p
 	}
 	return response.Empty(w)
 }
+
+func (handler *Handler) executeContainerWebhook(w http.ResponseWriter, webhook *portainer.Webhook, endpoint *portainer.Endpoint, imageTag string) *httperror.HandlerError {
+	dockerClient, err := handler.DockerClientFactory.CreateClient(endpoint, "")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Error creating docker client", err}
+	}
+	defer dockerClient.Close()
+
+	containerID, err := handler.recreateContainer(dockerClient, endpoint, webhook.ResourceID, imageTag)
+	if err == portainer.ErrImageNotAllowed {
+		return &httperror.HandlerError{http.StatusForbidden, "Image not allowed on this endpoint", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Error recreating container", err}
+	}
+
+	resourceControl, err := handler.ResourceControlService.ResourceControlByResourceIDAndType(webhook.ResourceID, portainer.ContainerResourceControl)
+	if err != nil && err != portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve a resource control associated to the container", err}
+	}
+
+	if resourceControl != nil {
+		resourceControl.ResourceID = containerID
+		err = handler.ResourceControlService.UpdateResourceControl(resourceControl.ID, resourceControl)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist resource control changes inside the database", err}
+		}
+	}
+
+	webhook.ResourceID = containerID
+	err = handler.WebhookService.UpdateWebhook(webhook.ID, webhook)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist webhook changes inside the database", err}
+	}
+
+	return response.Empty(w)
+}
+
+func (handler *Handler) webhookStack(resourceID string) (*portainer.Stack, error) {
+	stackID, err := strconv.Atoi(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.StackService.Stack(portainer.StackID(stackID))
+}
+
+func (handler *Handler) executeStackWebhook(w http.ResponseWriter, endpoint *portainer.Endpoint, stack *portainer.Stack) *httperror.HandlerError {
+	unlock := handler.StackDeployer.LockStack(stack.ID)
+	defer unlock()
+
+	// the stack may have been refreshed by the Git auto-update job while the lock was being acquired
+	stack, err := handler.StackService.Stack(stack.ID)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a stack with the specified identifier inside the database", err}
+	}
+
+	updated := false
+	if stack.GitConfig != nil {
+		updated, err = stacks.RefreshGitRepository(stack, handler.GitService, handler.FileService)
+		if err != nil {
+			return &httperror.HandlerError{http.StatusInternalServerError, "Unable to update the stack from its git repository", err}
+		}
+	}
+
+	err = handler.checkStackImageRestrictions(endpoint, stack)
+	if err == portainer.ErrImageNotAllowed {
+		return &httperror.HandlerError{http.StatusForbidden, "Image not allowed on this endpoint", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to verify the image restrictions of the endpoint group", err}
+	}
+
+	dockerhub, err := handler.DockerHubService.DockerHub()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve DockerHub details from the database", err}
+	}
+
+	registries, err := handler.RegistryService.Registries()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve registries from the database", err}
+	}
+
+	if stack.Type == portainer.DockerSwarmStack {
+		err = handler.StackDeployer.DeploySwarmStack(stack, endpoint, registries, dockerhub, false)
+	} else {
+		err = handler.StackDeployer.DeployComposeStack(stack, endpoint, registries, dockerhub, true)
+	}
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Error redeploying stack", err}
+	}
+
+	if !updated {
+		return response.Empty(w)
+	}
+
+	err = handler.StackVersionManager.CreateStackVersion(stack, "webhook")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack version on disk", err}
+	}
+
+	// only the deployed commit hash is persisted, so that the changes made to the stack while it was
+	// being redeployed are not overwritten
+	commitHash := stack.GitConfig.CommitHash
+	err = handler.StackService.UpdateStackFunc(stack.ID, func(storedStack *portainer.Stack) {
+		if storedStack.GitConfig != nil {
+			storedStack.GitConfig.CommitHash = commitHash
+		}
+	})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to persist the stack changes inside the database", err}
+	}
+
+	return response.Empty(w)
+}
+
+func (handler *Handler) requireEdgeTunnel(endpoint *portainer.Endpoint) error {
+	tunnel := handler.ReverseTunnelService.GetTunnelDetails(endpoint.ID)
+	if tunnel.Status != portainer.EdgeAgentIdle {
+		return nil
+	}
+
+	err := handler.ReverseTunnelService.SetTunnelStatusToRequired(endpoint.ID)
+	if err != nil {
+		return err
+	}
+
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	waitForAgentToConnect := time.Duration(settings.EdgeAgentCheckinInterval) * time.Second
+	time.Sleep(waitForAgentToConnect * 2)
+
+	return nil
+}
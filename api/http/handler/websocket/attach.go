@@ -50,6 +50,12 @@ func (handler *Handler) websocketAttach(w http.ResponseWriter, r *http.Request)
 		nodeName: r.FormValue("nodeName"),
 	}
 
+	params.recorder, err = handler.startSessionRecording(r, params, portainer.AttachSessionRecording)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start session recording", err}
+	}
+	defer handler.stopSessionRecording(params.recorder)
+
 	err = handler.handleAttachRequest(w, r, params)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "An error occured during websocket attach operation", err}
@@ -74,10 +80,10 @@ func (handler *Handler) handleAttachRequest(w http.ResponseWriter, r *http.Reque
 	}
 	defer websocketConn.Close()
 
-	return hijackAttachStartOperation(websocketConn, params.endpoint, params.ID)
+	return hijackAttachStartOperation(websocketConn, params.endpoint, params.ID, params.recorder)
 }
 
-func hijackAttachStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, attachID string) error {
+func hijackAttachStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, attachID string, recorder *sessionRecorder) error {
 	dial, err := initDial(endpoint)
 	if err != nil {
 		return err
@@ -101,7 +107,7 @@ func hijackAttachStartOperation(websocketConn *websocket.Conn, endpoint *portain
 		return err
 	}
 
-	err = hijackRequest(websocketConn, httpConn, attachStartRequest)
+	err = hijackRequest(websocketConn, httpConn, attachStartRequest, recorder)
 	if err != nil {
 		return err
 	}
@@ -57,6 +57,12 @@ func (handler *Handler) websocketExec(w http.ResponseWriter, r *http.Request) *h
 		nodeName: r.FormValue("nodeName"),
 	}
 
+	params.recorder, err = handler.startSessionRecording(r, params, portainer.ExecSessionRecording)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to start session recording", err}
+	}
+	defer handler.stopSessionRecording(params.recorder)
+
 	err = handler.handleExecRequest(w, r, params)
 	if err != nil {
 		return &httperror.HandlerError{http.StatusInternalServerError, "An error occured during websocket exec operation", err}
@@ -80,10 +86,10 @@ func (handler *Handler) handleExecRequest(w http.ResponseWriter, r *http.Request
 	}
 	defer websocketConn.Close()
 
-	return hijackExecStartOperation(websocketConn, params.endpoint, params.ID)
+	return hijackExecStartOperation(websocketConn, params.endpoint, params.ID, params.recorder)
 }
 
-func hijackExecStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, execID string) error {
+func hijackExecStartOperation(websocketConn *websocket.Conn, endpoint *portainer.Endpoint, execID string, recorder *sessionRecorder) error {
 	dial, err := initDial(endpoint)
 	if err != nil {
 		return err
@@ -107,7 +113,7 @@ func hijackExecStartOperation(websocketConn *websocket.Conn, endpoint *portainer
 		return err
 	}
 
-	err = hijackRequest(websocketConn, httpConn, execStartRequest)
+	err = hijackRequest(websocketConn, httpConn, execStartRequest, recorder)
 	if err != nil {
 		return err
 	}
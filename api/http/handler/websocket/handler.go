@@ -1,21 +1,28 @@
 package websocket
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	httperror "github.com/portainer/libhttp/error"
 	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/security"
 )
 
 // Handler is the HTTP handler used to handle websocket operations.
 type Handler struct {
 	*mux.Router
-	EndpointService      portainer.EndpointService
-	SignatureService     portainer.DigitalSignatureService
-	ReverseTunnelService portainer.ReverseTunnelService
-	requestBouncer       *security.RequestBouncer
-	connectionUpgrader   websocket.Upgrader
+	EndpointService         portainer.EndpointService
+	SignatureService        portainer.DigitalSignatureService
+	ReverseTunnelService    portainer.ReverseTunnelService
+	SettingsService         portainer.SettingsService
+	SessionRecordingService portainer.SessionRecordingService
+	FileService             portainer.FileService
+	DockerClientFactory     *docker.ClientFactory
+	requestBouncer          *security.RequestBouncer
+	connectionUpgrader      websocket.Upgrader
 }
 
 // NewHandler creates a handler to manage websocket operations.
@@ -29,5 +36,9 @@ func NewHandler(bouncer *security.RequestBouncer) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketExec)))
 	h.PathPrefix("/websocket/attach").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketAttach)))
+	h.Handle("/websocket/sessions",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.sessionRecordingList))).Methods(http.MethodGet)
+	h.Handle("/websocket/sessions/{id}/download",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.sessionRecordingDownload))).Methods(http.MethodGet)
 	return h
 }
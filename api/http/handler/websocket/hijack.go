@@ -7,7 +7,7 @@ import (
 	"net/http/httputil"
 )
 
-func hijackRequest(websocketConn *websocket.Conn, httpConn *httputil.ClientConn, request *http.Request) error {
+func hijackRequest(websocketConn *websocket.Conn, httpConn *httputil.ClientConn, request *http.Request, recorder *sessionRecorder) error {
 	// Server hijacks the connection, error 'connection closed' expected
 	resp, err := httpConn.Do(request)
 	if err != httputil.ErrPersistEOF {
@@ -24,8 +24,8 @@ func hijackRequest(websocketConn *websocket.Conn, httpConn *httputil.ClientConn,
 	defer tcpConn.Close()
 
 	errorChan := make(chan error, 1)
-	go streamFromTCPConnToWebsocketConn(websocketConn, brw, errorChan)
-	go streamFromWebsocketConnToTCPConn(websocketConn, tcpConn, errorChan)
+	go streamFromTCPConnToWebsocketConn(websocketConn, brw, recorder, errorChan)
+	go streamFromWebsocketConnToTCPConn(websocketConn, tcpConn, recorder, errorChan)
 
 	err = <-errorChan
 	if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
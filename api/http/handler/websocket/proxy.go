@@ -20,16 +20,13 @@ func (handler *Handler) proxyEdgeAgentWebsocketRequest(w http.ResponseWriter, r
 	}
 
 	endpointURL.Scheme = "ws"
-	proxy := websocketproxy.NewProxy(endpointURL)
 
-	proxy.Director = func(incoming *http.Request, out http.Header) {
-		out.Set(portainer.PortainerAgentTargetHeader, params.nodeName)
-	}
+	header := http.Header{}
+	header.Set(portainer.PortainerAgentTargetHeader, params.nodeName)
 
 	handler.ReverseTunnelService.SetTunnelStatusToActive(params.endpoint.ID)
-	proxy.ServeHTTP(w, r)
 
-	return nil
+	return handler.proxyWebsocketRequest(w, r, endpointURL, nil, header, params.recorder)
 }
 
 func (handler *Handler) proxyAgentWebsocketRequest(w http.ResponseWriter, r *http.Request, params *webSocketRequestParams) error {
@@ -39,11 +36,11 @@ func (handler *Handler) proxyAgentWebsocketRequest(w http.ResponseWriter, r *htt
 	}
 
 	agentURL.Scheme = "ws"
-	proxy := websocketproxy.NewProxy(agentURL)
+	var dialer *websocket.Dialer
 
 	if params.endpoint.TLSConfig.TLS || params.endpoint.TLSConfig.TLSSkipVerify {
 		agentURL.Scheme = "wss"
-		proxy.Dialer = &websocket.Dialer{
+		dialer = &websocket.Dialer{
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: params.endpoint.TLSConfig.TLSSkipVerify,
 			},
@@ -55,13 +52,63 @@ func (handler *Handler) proxyAgentWebsocketRequest(w http.ResponseWriter, r *htt
 		return err
 	}
 
-	proxy.Director = func(incoming *http.Request, out http.Header) {
-		out.Set(portainer.PortainerAgentPublicKeyHeader, handler.SignatureService.EncodedPublicKey())
-		out.Set(portainer.PortainerAgentSignatureHeader, signature)
-		out.Set(portainer.PortainerAgentTargetHeader, params.nodeName)
+	header := http.Header{}
+	header.Set(portainer.PortainerAgentPublicKeyHeader, handler.SignatureService.EncodedPublicKey())
+	header.Set(portainer.PortainerAgentSignatureHeader, signature)
+	header.Set(portainer.PortainerAgentTargetHeader, params.nodeName)
+
+	return handler.proxyWebsocketRequest(w, r, agentURL, dialer, header, params.recorder)
+}
+
+// proxyWebsocketRequest proxies the websocket request to the specified backend, adding the specified
+// headers to the backend request.
+// When the session is recorded, the messages are relayed by Portainer between the two websocket
+// connections instead of using a generic websocket proxy so that they can be recorded.
+func (handler *Handler) proxyWebsocketRequest(w http.ResponseWriter, r *http.Request, backendURL *url.URL, dialer *websocket.Dialer, header http.Header, recorder *sessionRecorder) error {
+	if recorder == nil {
+		proxy := websocketproxy.NewProxy(backendURL)
+		proxy.Dialer = dialer
+		proxy.Director = func(incoming *http.Request, out http.Header) {
+			for key := range header {
+				out.Set(key, header.Get(key))
+			}
+		}
+
+		proxy.ServeHTTP(w, r)
+		return nil
+	}
+
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	targetURL := *backendURL
+	targetURL.Path = r.URL.Path
+	targetURL.RawQuery = r.URL.RawQuery
+
+	backendConn, resp, err := dialer.Dial(targetURL.String(), header)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("unable to connect to the agent websocket, received %d: %s", resp.StatusCode, err)
+		}
+		return err
+	}
+	defer backendConn.Close()
+
+	websocketConn, err := handler.connectionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
 	}
+	defer websocketConn.Close()
+
+	errorChan := make(chan error, 2)
+	go streamFromWebsocketConnToWebsocketConn(backendConn, websocketConn, recorder.recordOutput, errorChan)
+	go streamFromWebsocketConnToWebsocketConn(websocketConn, backendConn, recorder.recordInput, errorChan)
 
-	proxy.ServeHTTP(w, r)
+	err = <-errorChan
+	if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,93 @@
+package websocket
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	asciicastInputEvent  = "i"
+	asciicastOutputEvent = "o"
+)
+
+type asciicastHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// sessionRecorder writes the input and the output of a console session using the
+// asciicast v2 format: a header line followed by one [time, type, data] event per line.
+// A nil recorder discards everything so that it can be used when recording is disabled.
+type sessionRecorder struct {
+	recordingID portainer.SessionRecordingID
+	mutex       sync.Mutex
+	writer      io.WriteCloser
+	encoder     *json.Encoder
+	startedAt   time.Time
+	failed      bool
+}
+
+func newSessionRecorder(writer io.WriteCloser, startedAt time.Time, title string) (*sessionRecorder, error) {
+	recorder := &sessionRecorder{
+		writer:    writer,
+		encoder:   json.NewEncoder(writer),
+		startedAt: startedAt,
+	}
+
+	header := &asciicastHeader{
+		Version:   2,
+		Width:     80,
+		Height:    24,
+		Timestamp: startedAt.Unix(),
+		Title:     title,
+	}
+
+	err := recorder.encoder.Encode(header)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	return recorder, nil
+}
+
+func (recorder *sessionRecorder) recordInput(data []byte) {
+	recorder.record(asciicastInputEvent, data)
+}
+
+func (recorder *sessionRecorder) recordOutput(data []byte) {
+	recorder.record(asciicastOutputEvent, data)
+}
+
+func (recorder *sessionRecorder) record(eventType string, data []byte) {
+	if recorder == nil || len(data) == 0 {
+		return
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if recorder.failed {
+		return
+	}
+
+	elapsed := time.Since(recorder.startedAt).Seconds()
+	err := recorder.encoder.Encode([]interface{}{elapsed, eventType, validString(string(data))})
+	if err != nil {
+		recorder.failed = true
+	}
+}
+
+func (recorder *sessionRecorder) close() error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return recorder.writer.Close()
+}
@@ -0,0 +1,94 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// testRecordingFile keeps the content of a session recording in memory.
+type testRecordingFile struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (file *testRecordingFile) Close() error {
+	file.closed = true
+	return nil
+}
+
+func TestSessionRecorder(t *testing.T) {
+	file := &testRecordingFile{}
+	startedAt := time.Now()
+
+	recorder, err := newSessionRecorder(file, startedAt, "admin@local (container: web)")
+	if err != nil {
+		t.Fatalf("unable to create recorder: %s", err)
+	}
+
+	recorder.recordInput([]byte("ls\r"))
+	recorder.recordOutput([]byte{})
+	recorder.recordOutput([]byte("file\r\n"))
+
+	err = recorder.close()
+	if err != nil || !file.closed {
+		t.Fatalf("expected the recording file to be closed, got %v", err)
+	}
+
+	scanner := bufio.NewScanner(&file.Buffer)
+	lines := make([][]byte, 0)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte{}, scanner.Bytes()...))
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 events, got %d lines", len(lines))
+	}
+
+	var header asciicastHeader
+	err = json.Unmarshal(lines[0], &header)
+	if err != nil {
+		t.Fatalf("invalid header: %s", err)
+	}
+
+	if header.Version != 2 || header.Timestamp != startedAt.Unix() || header.Title != "admin@local (container: web)" {
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	expectedEvents := []struct {
+		eventType string
+		data      string
+	}{
+		{eventType: asciicastInputEvent, data: "ls\r"},
+		{eventType: asciicastOutputEvent, data: "file\r\n"},
+	}
+
+	previous := 0.0
+	for idx, expected := range expectedEvents {
+		var event []interface{}
+		err = json.Unmarshal(lines[idx+1], &event)
+		if err != nil || len(event) != 3 {
+			t.Fatalf("invalid event %s (%v)", string(lines[idx+1]), err)
+		}
+
+		elapsed, ok := event[0].(float64)
+		if !ok || elapsed < previous {
+			t.Errorf("expected the event time to be increasing, got %v", event[0])
+		}
+		previous = elapsed
+
+		if event[1] != expected.eventType || event[2] != expected.data {
+			t.Errorf("expected event %s %q, got %v %v", expected.eventType, expected.data, event[1], event[2])
+		}
+	}
+}
+
+func TestSessionRecorderDisabled(t *testing.T) {
+	var recorder *sessionRecorder
+
+	// recording is disabled, the data must be discarded without error
+	recorder.recordInput([]byte("ls\r"))
+	recorder.recordOutput([]byte("file\r\n"))
+}
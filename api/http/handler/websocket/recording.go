@@ -0,0 +1,153 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// startSessionRecording creates a session recording and the file used to store its content when
+// session recording is enabled in the settings. It returns a nil recorder otherwise.
+func (handler *Handler) startSessionRecording(r *http.Request, params *webSocketRequestParams, recordingType portainer.SessionRecordingType) (*sessionRecorder, error) {
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.EnableSessionRecording {
+		return nil, nil
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	recording := &portainer.SessionRecording{
+		Type:       recordingType,
+		UserID:     tokenData.ID,
+		Username:   tokenData.Username,
+		EndpointID: params.endpoint.ID,
+		NodeName:   params.nodeName,
+		StartedAt:  startedAt.Unix(),
+	}
+
+	if recordingType == portainer.AttachSessionRecording {
+		recording.ContainerID = params.ID
+	} else {
+		recording.ExecID = params.ID
+		recording.ContainerID = handler.execContainerID(params)
+	}
+
+	err = handler.SessionRecordingService.CreateSessionRecording(recording)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := strconv.Itoa(int(recording.ID))
+	file, err := handler.FileService.CreateSessionRecordingFile(fileName)
+	if err != nil {
+		handler.SessionRecordingService.DeleteSessionRecording(recording.ID)
+		return nil, err
+	}
+
+	title := fmt.Sprintf("%s@%s (container: %s)", recording.Username, params.endpoint.Name, recording.ContainerID)
+	recorder, err := newSessionRecorder(file, startedAt, title)
+	if err != nil {
+		handler.FileService.DeleteSessionRecordingFile(fileName)
+		handler.SessionRecordingService.DeleteSessionRecording(recording.ID)
+		return nil, err
+	}
+	recorder.recordingID = recording.ID
+
+	return recorder, nil
+}
+
+// stopSessionRecording closes the recording file, stores the end date of the session and
+// removes the recordings that are older than the retention defined in the settings.
+func (handler *Handler) stopSessionRecording(recorder *sessionRecorder) {
+	if recorder == nil {
+		return
+	}
+
+	err := recorder.close()
+	if err != nil {
+		log.Printf("[WARN] [websocket,recording] [message: unable to close session recording file] [err: %s]", err)
+	}
+
+	recording, err := handler.SessionRecordingService.SessionRecording(recorder.recordingID)
+	if err != nil {
+		log.Printf("[WARN] [websocket,recording] [message: unable to retrieve session recording] [err: %s]", err)
+		return
+	}
+
+	recording.EndedAt = time.Now().Unix()
+	err = handler.SessionRecordingService.UpdateSessionRecording(recording.ID, recording)
+	if err != nil {
+		log.Printf("[WARN] [websocket,recording] [message: unable to update session recording] [err: %s]", err)
+	}
+
+	err = handler.pruneSessionRecordings()
+	if err != nil {
+		log.Printf("[WARN] [websocket,recording] [message: unable to remove outdated session recordings] [err: %s]", err)
+	}
+}
+
+func (handler *Handler) pruneSessionRecordings() error {
+	settings, err := handler.SettingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	if settings.SessionRecordingRetention <= 0 {
+		return nil
+	}
+
+	recordings, err := handler.SessionRecordingService.SessionRecordings()
+	if err != nil {
+		return err
+	}
+
+	limit := time.Now().AddDate(0, 0, -settings.SessionRecordingRetention).Unix()
+	for _, recording := range recordings {
+		if recording.EndedAt == 0 || recording.StartedAt >= limit {
+			continue
+		}
+
+		err = handler.FileService.DeleteSessionRecordingFile(strconv.Itoa(int(recording.ID)))
+		if err != nil {
+			return err
+		}
+
+		err = handler.SessionRecordingService.DeleteSessionRecording(recording.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// execContainerID returns the identifier of the container associated to an exec instance.
+// The recording is not prevented when the container cannot be retrieved.
+func (handler *Handler) execContainerID(params *webSocketRequestParams) string {
+	cli, err := handler.DockerClientFactory.CreateClient(params.endpoint, params.nodeName)
+	if err != nil {
+		return ""
+	}
+	defer cli.Close()
+
+	execInspect, err := cli.ContainerExecInspect(context.Background(), params.ID)
+	if err != nil {
+		return ""
+	}
+
+	return execInspect.ContainerID
+}
@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+type testSettingsService struct {
+	portainer.SettingsService
+	settings portainer.Settings
+}
+
+func (service *testSettingsService) Settings() (*portainer.Settings, error) {
+	settings := service.settings
+	return &settings, nil
+}
+
+// testSessionRecordingService stores the session recordings in memory.
+type testSessionRecordingService struct {
+	portainer.SessionRecordingService
+	recordings []portainer.SessionRecording
+}
+
+func (service *testSessionRecordingService) SessionRecordings() ([]portainer.SessionRecording, error) {
+	return append([]portainer.SessionRecording{}, service.recordings...), nil
+}
+
+func (service *testSessionRecordingService) DeleteSessionRecording(ID portainer.SessionRecordingID) error {
+	for idx := range service.recordings {
+		if service.recordings[idx].ID == ID {
+			service.recordings = append(service.recordings[:idx], service.recordings[idx+1:]...)
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+// testFileService keeps track of the deleted session recording files.
+type testFileService struct {
+	portainer.FileService
+	deletedFiles []string
+}
+
+func (service *testFileService) DeleteSessionRecordingFile(identifier string) error {
+	service.deletedFiles = append(service.deletedFiles, identifier)
+	return nil
+}
+
+func TestPruneSessionRecordings(t *testing.T) {
+	now := time.Now()
+	outdated := now.AddDate(0, 0, -8).Unix()
+	recent := now.AddDate(0, 0, -1).Unix()
+
+	recordings := []portainer.SessionRecording{
+		{ID: 1, StartedAt: outdated, EndedAt: outdated},
+		{ID: 2, StartedAt: recent, EndedAt: recent},
+		// a session started before the retention limit is kept while it is in progress
+		{ID: 3, StartedAt: outdated},
+		{ID: 4, StartedAt: outdated, EndedAt: recent},
+	}
+
+	cases := []struct {
+		retention int
+		deleted   []portainer.SessionRecordingID
+	}{
+		{retention: 0},
+		{retention: 7, deleted: []portainer.SessionRecordingID{1, 4}},
+		{retention: 30},
+	}
+
+	for _, c := range cases {
+		recordingService := &testSessionRecordingService{recordings: append([]portainer.SessionRecording{}, recordings...)}
+		fileService := &testFileService{}
+		handler := &Handler{
+			SettingsService:         &testSettingsService{settings: portainer.Settings{SessionRecordingRetention: c.retention}},
+			SessionRecordingService: recordingService,
+			FileService:             fileService,
+		}
+
+		err := handler.pruneSessionRecordings()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(fileService.deletedFiles) != len(c.deleted) || len(recordingService.recordings) != len(recordings)-len(c.deleted) {
+			t.Errorf("expected %d recordings to be removed with a %d days retention, got files %v", len(c.deleted), c.retention, fileService.deletedFiles)
+			continue
+		}
+
+		for idx, ID := range c.deleted {
+			if fileService.deletedFiles[idx] != strconv.Itoa(int(ID)) {
+				t.Errorf("expected the file of recording %d to be removed, got %s", ID, fileService.deletedFiles[idx])
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package websocket
+
+import (
+	"net/http"
+	"strconv"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/websocket/sessions/:id/download
+// The recording is returned using the asciicast v2 format.
+func (handler *Handler) sessionRecordingDownload(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	recordingID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return &httperror.HandlerError{http.StatusBadRequest, "Invalid session recording identifier route variable", err}
+	}
+
+	recording, err := handler.SessionRecordingService.SessionRecording(portainer.SessionRecordingID(recordingID))
+	if err == portainer.ErrObjectNotFound {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find a session recording with the specified identifier inside the database", err}
+	} else if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to find a session recording with the specified identifier inside the database", err}
+	}
+
+	identifier := strconv.Itoa(int(recording.ID))
+	recordingPath := handler.FileService.GetSessionRecordingPath(identifier)
+
+	exists, err := handler.FileService.FileExists(recordingPath)
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve the session recording file", err}
+	} else if !exists {
+		return &httperror.HandlerError{http.StatusNotFound, "Unable to find the session recording file", portainer.ErrObjectNotFound}
+	}
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	w.Header().Set("Content-Disposition", "attachment; filename=session-"+identifier+".cast")
+	http.ServeFile(w, r, recordingPath)
+	return nil
+}
@@ -0,0 +1,36 @@
+package websocket
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/libhttp/request"
+	"github.com/portainer/libhttp/response"
+	"github.com/portainer/portainer/api"
+)
+
+// GET request on /api/websocket/sessions?endpointId=<endpointId>&userId=<userId>
+func (handler *Handler) sessionRecordingList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, _ := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+	userID, _ := request.RetrieveNumericQueryParameter(r, "userId", true)
+
+	recordings, err := handler.SessionRecordingService.SessionRecordings()
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to retrieve session recordings from the database", err}
+	}
+
+	filteredRecordings := make([]portainer.SessionRecording, 0)
+	for _, recording := range recordings {
+		if endpointID != 0 && recording.EndpointID != portainer.EndpointID(endpointID) {
+			continue
+		}
+
+		if userID != 0 && recording.UserID != portainer.UserID(userID) {
+			continue
+		}
+
+		filteredRecordings = append(filteredRecordings, recording)
+	}
+
+	return response.JSON(w, filteredRecordings)
+}
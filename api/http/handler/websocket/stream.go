@@ -7,7 +7,7 @@ import (
 	"unicode/utf8"
 )
 
-func streamFromWebsocketConnToTCPConn(websocketConn *websocket.Conn, tcpConn net.Conn, errorChan chan error) {
+func streamFromWebsocketConnToTCPConn(websocketConn *websocket.Conn, tcpConn net.Conn, recorder *sessionRecorder, errorChan chan error) {
 	for {
 		_, in, err := websocketConn.ReadMessage()
 		if err != nil {
@@ -20,19 +20,23 @@ func streamFromWebsocketConnToTCPConn(websocketConn *websocket.Conn, tcpConn net
 			errorChan <- err
 			break
 		}
+
+		recorder.recordInput(in)
 	}
 }
 
-func streamFromTCPConnToWebsocketConn(websocketConn *websocket.Conn, br *bufio.Reader, errorChan chan error) {
+func streamFromTCPConnToWebsocketConn(websocketConn *websocket.Conn, br *bufio.Reader, recorder *sessionRecorder, errorChan chan error) {
 	for {
 		out := make([]byte, 2048)
-		_, err := br.Read(out)
+		n, err := br.Read(out)
 		if err != nil {
 			errorChan <- err
 			break
 		}
 
-		processedOutput := validString(string(out[:]))
+		recorder.recordOutput(out[:n])
+
+		processedOutput := validString(string(out[:n]))
 		err = websocketConn.WriteMessage(websocket.TextMessage, []byte(processedOutput))
 		if err != nil {
 			errorChan <- err
@@ -41,6 +45,24 @@ func streamFromTCPConnToWebsocketConn(websocketConn *websocket.Conn, br *bufio.R
 	}
 }
 
+func streamFromWebsocketConnToWebsocketConn(source, target *websocket.Conn, record func([]byte), errorChan chan error) {
+	for {
+		messageType, data, err := source.ReadMessage()
+		if err != nil {
+			errorChan <- err
+			break
+		}
+
+		err = target.WriteMessage(messageType, data)
+		if err != nil {
+			errorChan <- err
+			break
+		}
+
+		record(data)
+	}
+}
+
 func validString(s string) string {
 	if !utf8.ValidString(s) {
 		v := make([]rune, 0, len(s))
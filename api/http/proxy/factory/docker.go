@@ -57,6 +57,10 @@ func (factory *ProxyFactory) newDockerHTTPProxy(endpoint *portainer.Endpoint) (h
 
 	transportParameters := &docker.TransportParameters{
 		Endpoint:               endpoint,
+		ContainerPolicyService: factory.containerPolicyService,
+		AuditService:           factory.auditService,
+		EndpointGroupService:   factory.endpointGroupService,
+		QuotaChecker:           factory.quotaChecker,
 		ResourceControlService: factory.resourceControlService,
 		UserService:            factory.userService,
 		TeamService:            factory.teamService,
@@ -0,0 +1,115 @@
+package docker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/policy"
+)
+
+// imageReferenceFunc returns the image referenced by a request
+type imageReferenceFunc func(request *http.Request) (string, error)
+
+// checkImageRestrictions verifies that the image referenced by a pull, creation or tag request respects
+// the image restrictions of the endpoint group. Images cannot be built, loaded or imported when the
+// restrictions are enabled. Administrators are exempt unless the restrictions apply to them.
+// Violations are recorded inside the audit log and a forbidden response is returned.
+func (transport *Transport) checkImageRestrictions(request *http.Request, imageReference imageReferenceFunc) (*http.Response, error) {
+	endpointGroup, err := transport.endpointGroupService.EndpointGroup(transport.endpoint.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions := &endpointGroup.ImageRestrictions
+	if !restrictions.Enabled {
+		return nil, nil
+	}
+
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenData.Role == portainer.AdministratorRole && !restrictions.ApplyToAdministrators {
+		return nil, nil
+	}
+
+	image, err := imageReference(request)
+	if err != nil {
+		return nil, err
+	}
+
+	registries, err := transport.registryService.Registries()
+	if err != nil {
+		return nil, err
+	}
+
+	violation := policy.ImageRestrictionViolation(restrictions, registries, image)
+	if violation == "" {
+		return nil, nil
+	}
+
+	policy.RecordImageRestrictionViolation(transport.auditService, &portainer.AuditLog{
+		UserID:     tokenData.ID,
+		Username:   tokenData.Username,
+		EndpointID: transport.endpoint.ID,
+		Method:     request.Method,
+		Path:       request.URL.Path,
+		ResourceID: image,
+	}, violation)
+
+	return responseutils.WriteForbiddenResponse(fmt.Sprintf("%s: %s", portainer.ErrImageNotAllowed, violation))
+}
+
+// imagePullReference returns the image pulled by an image creation request. Images referenced by
+// digest are sent by the Docker client with the digest inside the tag parameter.
+// It returns an empty string when the image is imported from a source instead of being pulled.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ImageCreate
+func imagePullReference(request *http.Request) (string, error) {
+	fromImage := request.URL.Query().Get("fromImage")
+	tag := request.URL.Query().Get("tag")
+
+	if fromImage == "" || tag == "" {
+		return fromImage, nil
+	}
+
+	if strings.Contains(tag, ":") {
+		return fromImage + "@" + tag, nil
+	}
+
+	return fromImage + ":" + tag, nil
+}
+
+// imageTagReference returns the source image of an image tag request, which is part of the path
+// of the request and can contain slashes.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ImageTag
+func imageTagReference(request *http.Request) (string, error) {
+	return strings.TrimSuffix(strings.TrimPrefix(request.URL.Path, "/images/"), "/tag"), nil
+}
+
+// noImageReference is used for the requests that build or load images instead of pulling them.
+func noImageReference(request *http.Request) (string, error) {
+	return "", nil
+}
+
+// containerSpecImage returns a function extracting the image from the payload of a container
+// or service creation/update request.
+func containerSpecImage(containerSpec containerSpecFunc) imageReferenceFunc {
+	return func(request *http.Request) (string, error) {
+		body, err := readRequestBody(request)
+		if err != nil {
+			return "", err
+		}
+
+		spec, err := containerSpec(body)
+		if err != nil {
+			return "", err
+		}
+
+		return spec.Image, nil
+	}
+}
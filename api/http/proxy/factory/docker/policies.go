@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/policy"
+)
+
+// containerSpecFunc returns the container specification described by the payload of a request
+type containerSpecFunc func(body []byte) (*policy.ContainerSpec, error)
+
+// checkContainerPolicies evaluates the payload of a container or service creation/update request, or of a volume
+// creation request, made by a non-administrator user against the container policies of the endpoint group.
+// Violations of policies in warn mode are logged, violations of policies in enforce mode
+// are returned inside a forbidden response.
+func (transport *Transport) checkContainerPolicies(request *http.Request, containerSpec containerSpecFunc) (*http.Response, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenData.Role == portainer.AdministratorRole {
+		return nil, nil
+	}
+
+	policies, err := transport.containerPolicyService.ContainerPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	endpointPolicies := make([]portainer.ContainerPolicy, 0)
+	for _, containerPolicy := range policies {
+		if containerPolicy.EndpointGroupID == transport.endpoint.GroupID {
+			endpointPolicies = append(endpointPolicies, containerPolicy)
+		}
+	}
+
+	if len(endpointPolicies) == 0 {
+		return nil, nil
+	}
+
+	body, err := readRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := containerSpec(body)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := policy.Evaluate(endpointPolicies, spec)
+
+	for _, violation := range violations {
+		if violation.Mode != portainer.ContainerPolicyEnforce {
+			log.Printf("[WARN] [http,docker,policy] [message: container policy violation] [endpoint: %s] [user: %s] [policy: %s] [violation: %s]", transport.endpoint.Name, tokenData.Username, violation.PolicyName, violation.Message)
+		}
+	}
+
+	message := policy.EnforcedMessage(violations)
+	if message != "" {
+		return responseutils.WriteForbiddenResponse(fmt.Sprintf("%s: %s", portainer.ErrContainerPolicyViolation, message))
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,224 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+
+	"github.com/docker/docker/api/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/quota"
+)
+
+type (
+	// requestedResourcesFunc returns the resources requested by a creation or an update request
+	requestedResourcesFunc func(transport *Transport, request *http.Request) (*quota.Request, error)
+
+	containerCreationQuotaPayload struct {
+		HostConfig containerResourcesQuotaPayload
+	}
+
+	containerResourcesQuotaPayload struct {
+		Memory    int64
+		NanoCpus  int64
+		CpuQuota  int64
+		CpuPeriod int64
+	}
+
+	serviceQuotaPayload struct {
+		TaskTemplate struct {
+			Resources struct {
+				Limits struct {
+					NanoCPUs    int64
+					MemoryBytes int64
+				}
+			}
+		}
+		Mode struct {
+			Replicated *struct {
+				Replicas *uint64
+			}
+			Global *struct{}
+		}
+	}
+)
+
+// checkQuotas verifies that the resources requested by a non-administrator user do not exceed
+// the quotas associated to the user and to the teams of the user on the endpoint.
+// It returns a forbidden response describing the exceeded quota when the request must be rejected.
+// Otherwise the request is executed through operation while the quota lock of the endpoint is held,
+// so that the resources are created before another request is checked.
+func (transport *Transport) checkQuotas(request *http.Request, requestedResources requestedResourcesFunc, operation func() (*http.Response, error)) (*http.Response, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokenData.Role == portainer.AdministratorRole {
+		return operation()
+	}
+
+	quotaRequest, err := requestedResources(transport, request)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := transport.quotaChecker.LockEndpoint(transport.endpoint.ID)
+	defer unlock()
+
+	violation, err := transport.quotaChecker.Check(transport.endpoint, tokenData.ID, quotaRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if violation != "" {
+		return responseutils.WriteForbiddenResponse(fmt.Sprintf("%s: %s", portainer.ErrQuotaExceeded, violation))
+	}
+
+	return operation()
+}
+
+// readRequestBody returns the body of the request and restores it so that the request can still be forwarded.
+func readRequestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return []byte{}, nil
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body.Close()
+
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// containerCreationResources returns the resources requested by a container creation request.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ContainerCreate
+func containerCreationResources(transport *Transport, request *http.Request) (*quota.Request, error) {
+	body, err := readRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload containerCreationQuotaPayload
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quota.Request{
+		Resources: quota.Resources{
+			Containers: 1,
+			Memory:     payload.HostConfig.Memory,
+			NanoCPUs:   quota.ContainerNanoCPUs(payload.HostConfig.NanoCpus, payload.HostConfig.CpuQuota, payload.HostConfig.CpuPeriod),
+		},
+	}, nil
+}
+
+// containerUpdateResources returns the resources requested by a container update request.
+// The limits that are not part of the request keep their current value and the resources
+// currently consumed by the container are not counted.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ContainerUpdate
+func containerUpdateResources(transport *Transport, request *http.Request) (*quota.Request, error) {
+	body, err := readRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload containerResourcesQuotaPayload
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	containerID := path.Base(path.Dir(request.URL.Path))
+	container, err := transport.dockerClient.ContainerInspect(context.Background(), containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	memory := payload.Memory
+	if memory == 0 {
+		memory = container.HostConfig.Memory
+	}
+
+	nanoCPUs := quota.ContainerNanoCPUs(payload.NanoCpus, payload.CpuQuota, payload.CpuPeriod)
+	if nanoCPUs == 0 {
+		nanoCPUs = quota.ContainerNanoCPUs(container.HostConfig.NanoCPUs, container.HostConfig.CPUQuota, container.HostConfig.CPUPeriod)
+	}
+
+	return &quota.Request{
+		Resources: quota.Resources{
+			Containers: 1,
+			Memory:     memory,
+			NanoCPUs:   nanoCPUs,
+		},
+		ReplacedResourceID: container.ID,
+	}, nil
+}
+
+// serviceCreationResources returns the resources requested by a service creation request.
+// A global service is counted once for each node of the cluster.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ServiceCreate
+func serviceCreationResources(transport *Transport, request *http.Request) (*quota.Request, error) {
+	body, err := readRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload serviceQuotaPayload
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := payload.TaskTemplate.Resources.Limits
+	taskResources := quota.Resources{
+		Containers: 1,
+		Memory:     limits.MemoryBytes,
+		NanoCPUs:   limits.NanoCPUs,
+	}
+
+	if payload.Mode.Global != nil {
+		return &quota.Request{PerNodeResources: taskResources}, nil
+	}
+
+	replicas := 1
+	if payload.Mode.Replicated != nil && payload.Mode.Replicated.Replicas != nil {
+		replicas = int(*payload.Mode.Replicated.Replicas)
+	}
+
+	return &quota.Request{Resources: taskResources.Multiply(replicas)}, nil
+}
+
+// serviceUpdateResources returns the resources requested by a service update request, which is
+// also used to scale a service. The resources currently consumed by the service are not counted.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ServiceUpdate
+func serviceUpdateResources(transport *Transport, request *http.Request) (*quota.Request, error) {
+	quotaRequest, err := serviceCreationResources(transport, request)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceID := path.Base(path.Dir(request.URL.Path))
+	service, _, err := transport.dockerClient.ServiceInspectWithRaw(context.Background(), serviceID, types.ServiceInspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	quotaRequest.ReplacedResourceID = service.ID
+	return quotaRequest, nil
+}
+
+// volumeCreationResources returns the resources requested by a volume creation request.
+func volumeCreationResources(transport *Transport, request *http.Request) (*quota.Request, error) {
+	return &quota.Request{Resources: quota.Resources{Volumes: 1}}, nil
+}
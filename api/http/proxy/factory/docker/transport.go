@@ -16,6 +16,7 @@ import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/proxy/factory/responseutils"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/policy"
 )
 
 var apiVersionRe = regexp.MustCompile(`(/v[0-9]\.[0-9]*)?`)
@@ -26,6 +27,10 @@ type (
 	Transport struct {
 		HTTPTransport          *http.Transport
 		endpoint               *portainer.Endpoint
+		containerPolicyService portainer.ContainerPolicyService
+		auditService           portainer.AuditService
+		endpointGroupService   portainer.EndpointGroupService
+		quotaChecker           *docker.QuotaChecker
 		resourceControlService portainer.ResourceControlService
 		userService            portainer.UserService
 		teamService            portainer.TeamService
@@ -43,6 +48,10 @@ type (
 	// TransportParameters is used to create a new Transport
 	TransportParameters struct {
 		Endpoint               *portainer.Endpoint
+		ContainerPolicyService portainer.ContainerPolicyService
+		AuditService           portainer.AuditService
+		EndpointGroupService   portainer.EndpointGroupService
+		QuotaChecker           *docker.QuotaChecker
 		ResourceControlService portainer.ResourceControlService
 		UserService            portainer.UserService
 		TeamService            portainer.TeamService
@@ -81,6 +90,10 @@ func NewTransport(parameters *TransportParameters, httpTransport *http.Transport
 
 	transport := &Transport{
 		endpoint:               parameters.Endpoint,
+		containerPolicyService: parameters.ContainerPolicyService,
+		auditService:           parameters.AuditService,
+		endpointGroupService:   parameters.EndpointGroupService,
+		quotaChecker:           parameters.QuotaChecker,
 		resourceControlService: parameters.ResourceControlService,
 		userService:            parameters.UserService,
 		teamService:            parameters.TeamService,
@@ -207,7 +220,19 @@ func (transport *Transport) proxyConfigRequest(request *http.Request) (*http.Res
 func (transport *Transport) proxyContainerRequest(request *http.Request) (*http.Response, error) {
 	switch requestPath := request.URL.Path; requestPath {
 	case "/containers/create":
-		return transport.decorateGenericResourceCreationOperation(request, containerObjectIdentifier, portainer.ContainerResourceControl)
+		response, err := transport.checkImageRestrictions(request, containerSpecImage(policy.ContainerSpecFromContainerCreate))
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		response, err = transport.checkContainerPolicies(request, policy.ContainerSpecFromContainerCreate)
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		return transport.checkQuotas(request, containerCreationResources, func() (*http.Response, error) {
+			return transport.decorateGenericResourceCreationOperation(request, containerObjectIdentifier, portainer.ContainerResourceControl)
+		})
 
 	case "/containers/prune":
 		return transport.administratorOperation(request)
@@ -224,6 +249,15 @@ func (transport *Transport) proxyContainerRequest(request *http.Request) (*http.
 
 			if action == "json" {
 				return transport.rewriteOperation(request, transport.containerInspectOperation)
+			} else if action == "update" {
+				response, err := transport.checkContainerPolicies(request, policy.ContainerSpecFromContainerUpdate)
+				if response != nil || err != nil {
+					return response, err
+				}
+
+				return transport.checkQuotas(request, containerUpdateResources, func() (*http.Response, error) {
+					return transport.restrictedResourceOperation(request, containerID, portainer.ContainerResourceControl, false)
+				})
 			}
 			return transport.restrictedResourceOperation(request, containerID, portainer.ContainerResourceControl, false)
 		} else if match, _ := path.Match("/containers/*", requestPath); match {
@@ -243,7 +277,19 @@ func (transport *Transport) proxyContainerRequest(request *http.Request) (*http.
 func (transport *Transport) proxyServiceRequest(request *http.Request) (*http.Response, error) {
 	switch requestPath := request.URL.Path; requestPath {
 	case "/services/create":
-		return transport.replaceRegistryAuthenticationHeader(request)
+		response, err := transport.checkImageRestrictions(request, containerSpecImage(policy.ContainerSpecFromServiceSpec))
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		response, err = transport.checkContainerPolicies(request, policy.ContainerSpecFromServiceSpec)
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		return transport.checkQuotas(request, serviceCreationResources, func() (*http.Response, error) {
+			return transport.replaceRegistryAuthenticationHeader(request)
+		})
 
 	case "/services":
 		return transport.rewriteOperation(request, transport.serviceListOperation)
@@ -253,6 +299,23 @@ func (transport *Transport) proxyServiceRequest(request *http.Request) (*http.Re
 		if match, _ := path.Match("/services/*/*", requestPath); match {
 			// Handle /services/{id}/{action} requests
 			serviceID := path.Base(path.Dir(requestPath))
+
+			if path.Base(requestPath) == "update" {
+				response, err := transport.checkImageRestrictions(request, containerSpecImage(policy.ContainerSpecFromServiceSpec))
+				if response != nil || err != nil {
+					return response, err
+				}
+
+				response, err = transport.checkContainerPolicies(request, policy.ContainerSpecFromServiceSpec)
+				if response != nil || err != nil {
+					return response, err
+				}
+
+				return transport.checkQuotas(request, serviceUpdateResources, func() (*http.Response, error) {
+					return transport.restrictedResourceOperation(request, serviceID, portainer.ServiceResourceControl, false)
+				})
+			}
+
 			return transport.restrictedResourceOperation(request, serviceID, portainer.ServiceResourceControl, false)
 		} else if match, _ := path.Match("/services/*", requestPath); match {
 			// Handle /services/{id} requests
@@ -273,7 +336,14 @@ func (transport *Transport) proxyServiceRequest(request *http.Request) (*http.Re
 func (transport *Transport) proxyVolumeRequest(request *http.Request) (*http.Response, error) {
 	switch requestPath := request.URL.Path; requestPath {
 	case "/volumes/create":
-		return transport.decorateGenericResourceCreationOperation(request, volumeObjectIdentifier, portainer.VolumeResourceControl)
+		response, err := transport.checkContainerPolicies(request, policy.ContainerSpecFromVolumeCreate)
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		return transport.checkQuotas(request, volumeCreationResources, func() (*http.Response, error) {
+			return transport.decorateGenericResourceCreationOperation(request, volumeObjectIdentifier, portainer.VolumeResourceControl)
+		})
 
 	case "/volumes/prune":
 		return transport.administratorOperation(request)
@@ -368,17 +438,41 @@ func (transport *Transport) proxyTaskRequest(request *http.Request) (*http.Respo
 }
 
 func (transport *Transport) proxyBuildRequest(request *http.Request) (*http.Response, error) {
+	response, err := transport.checkImageRestrictions(request, noImageReference)
+	if response != nil || err != nil {
+		return response, err
+	}
+
 	return transport.interceptAndRewriteRequest(request, buildOperation)
 }
 
 func (transport *Transport) proxyImageRequest(request *http.Request) (*http.Response, error) {
 	switch requestPath := request.URL.Path; requestPath {
 	case "/images/create":
+		response, err := transport.checkImageRestrictions(request, imagePullReference)
+		if response != nil || err != nil {
+			return response, err
+		}
+
 		return transport.replaceRegistryAuthenticationHeader(request)
+	case "/images/load":
+		response, err := transport.checkImageRestrictions(request, noImageReference)
+		if response != nil || err != nil {
+			return response, err
+		}
+
+		return transport.executeDockerRequest(request)
 	default:
 		if path.Base(requestPath) == "push" && request.Method == http.MethodPost {
 			return transport.replaceRegistryAuthenticationHeader(request)
 		}
+
+		if path.Base(requestPath) == "tag" && request.Method == http.MethodPost {
+			response, err := transport.checkImageRestrictions(request, imageTagReference)
+			if response != nil || err != nil {
+				return response, err
+			}
+		}
 		return transport.executeDockerRequest(request)
 	}
 }
@@ -1,3 +1,4 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
 // +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
 
 package factory
@@ -13,6 +14,10 @@ import (
 func (factory ProxyFactory) newOSBasedLocalProxy(path string, endpoint *portainer.Endpoint) (http.Handler, error) {
 	transportParameters := &docker.TransportParameters{
 		Endpoint:               endpoint,
+		ContainerPolicyService: factory.containerPolicyService,
+		AuditService:           factory.auditService,
+		EndpointGroupService:   factory.endpointGroupService,
+		QuotaChecker:           factory.quotaChecker,
 		ResourceControlService: factory.resourceControlService,
 		UserService:            factory.userService,
 		TeamService:            factory.teamService,
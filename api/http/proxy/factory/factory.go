@@ -21,6 +21,10 @@ var extensionPorts = map[portainer.ExtensionID]string{
 type (
 	// ProxyFactory is a factory to create reverse proxies to Docker endpoints and extensions
 	ProxyFactory struct {
+		containerPolicyService portainer.ContainerPolicyService
+		auditService           portainer.AuditService
+		endpointGroupService   portainer.EndpointGroupService
+		quotaChecker           *docker.QuotaChecker
 		resourceControlService portainer.ResourceControlService
 		userService            portainer.UserService
 		teamService            portainer.TeamService
@@ -36,6 +40,10 @@ type (
 
 	// ProxyFactoryParameters is used to create a new ProxyFactory
 	ProxyFactoryParameters struct {
+		ContainerPolicyService portainer.ContainerPolicyService
+		AuditService           portainer.AuditService
+		EndpointGroupService   portainer.EndpointGroupService
+		QuotaChecker           *docker.QuotaChecker
 		ResourceControlService portainer.ResourceControlService
 		UserService            portainer.UserService
 		TeamService            portainer.TeamService
@@ -53,6 +61,10 @@ type (
 // NewProxyFactory returns a pointer to a new instance of a ProxyFactory
 func NewProxyFactory(parameters *ProxyFactoryParameters) *ProxyFactory {
 	return &ProxyFactory{
+		containerPolicyService: parameters.ContainerPolicyService,
+		auditService:           parameters.AuditService,
+		endpointGroupService:   parameters.EndpointGroupService,
+		quotaChecker:           parameters.QuotaChecker,
 		resourceControlService: parameters.ResourceControlService,
 		userService:            parameters.UserService,
 		teamService:            parameters.TeamService,
@@ -23,6 +23,10 @@ type (
 
 	// ManagerParams represents the required parameters to create a new Manager instance.
 	ManagerParams struct {
+		ContainerPolicyService portainer.ContainerPolicyService
+		AuditService           portainer.AuditService
+		EndpointGroupService   portainer.EndpointGroupService
+		QuotaChecker           *docker.QuotaChecker
 		ResourceControlService portainer.ResourceControlService
 		UserService            portainer.UserService
 		TeamService            portainer.TeamService
@@ -40,6 +44,10 @@ type (
 // NewManager initializes a new proxy Service
 func NewManager(parameters *ManagerParams) *Manager {
 	proxyFactoryParameters := &factory.ProxyFactoryParameters{
+		ContainerPolicyService: parameters.ContainerPolicyService,
+		AuditService:           parameters.AuditService,
+		EndpointGroupService:   parameters.EndpointGroupService,
+		QuotaChecker:           parameters.QuotaChecker,
 		ResourceControlService: parameters.ResourceControlService,
 		UserService:            parameters.UserService,
 		TeamService:            parameters.TeamService,
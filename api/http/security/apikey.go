@@ -0,0 +1,122 @@
+package security
+
+import (
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// apiKeyPrefixLength is the number of characters of an API key stored in clear
+// to be able to identify the key without comparing every digest.
+const apiKeyPrefixLength = len(portainer.APIKeyPrefix) + 8
+
+const (
+	// apiKeyLastUsedUpdateInterval is the minimum number of seconds between two updates
+	// of the last used date of an API key.
+	apiKeyLastUsedUpdateInterval = 60
+	// apiKeyCacheSize is the maximum number of verified API keys kept in memory.
+	apiKeyCacheSize = 1000
+)
+
+// apiKeyCache keeps the digests of the API keys that were already verified, indexed by
+// the SHA-256 checksum of the raw key, to avoid a bcrypt comparison on every request.
+// A cached entry is only used while the stored digest of the key is unchanged, which means
+// that deleted or regenerated keys are never accepted from the cache.
+type apiKeyCache struct {
+	mu      sync.Mutex
+	digests map[[sha256.Size]byte]string
+}
+
+func newAPIKeyCache() *apiKeyCache {
+	return &apiKeyCache{digests: make(map[[sha256.Size]byte]string)}
+}
+
+func (cache *apiKeyCache) verified(checksum [sha256.Size]byte, digest string) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cachedDigest, ok := cache.digests[checksum]
+	return ok && cachedDigest == digest
+}
+
+func (cache *apiKeyCache) add(checksum [sha256.Size]byte, digest string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if len(cache.digests) >= apiKeyCacheSize {
+		cache.digests = make(map[[sha256.Size]byte]string)
+	}
+	cache.digests[checksum] = digest
+}
+
+// APIKeyPrefix returns the non-secret part of a raw API key that is stored alongside its digest.
+func APIKeyPrefix(rawAPIKey string) string {
+	if len(rawAPIKey) < apiKeyPrefixLength {
+		return rawAPIKey
+	}
+	return rawAPIKey[:apiKeyPrefixLength]
+}
+
+// authenticateAPIKey validates a raw API key and returns the token data associated to
+// the user owning the key. The last used date of the key is updated on success, at most
+// once every apiKeyLastUsedUpdateInterval seconds.
+func (bouncer *RequestBouncer) authenticateAPIKey(rawAPIKey string) (*portainer.TokenData, error) {
+	if !strings.HasPrefix(rawAPIKey, portainer.APIKeyPrefix) || len(rawAPIKey) <= apiKeyPrefixLength {
+		return nil, portainer.ErrInvalidAPIKey
+	}
+
+	apiKeys, err := bouncer.apiKeyService.APIKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := APIKeyPrefix(rawAPIKey)
+	checksum := sha256.Sum256([]byte(rawAPIKey))
+
+	for _, apiKey := range apiKeys {
+		if apiKey.Prefix != prefix {
+			continue
+		}
+
+		if !bouncer.apiKeyCache.verified(checksum, apiKey.Digest) {
+			err := bouncer.cryptoService.CompareHashAndData(apiKey.Digest, rawAPIKey)
+			if err != nil {
+				continue
+			}
+			bouncer.apiKeyCache.add(checksum, apiKey.Digest)
+		}
+
+		now := time.Now().Unix()
+		if apiKey.ExpiryDate != 0 && apiKey.ExpiryDate < now {
+			return nil, portainer.ErrAPIKeyExpired
+		}
+
+		user, err := bouncer.userService.User(apiKey.UserID)
+		if err == portainer.ErrObjectNotFound {
+			return nil, portainer.ErrInvalidAPIKey
+		} else if err != nil {
+			return nil, err
+		}
+
+		if user.Disabled {
+			return nil, portainer.ErrUserDisabled
+		}
+
+		if now-apiKey.LastUsedDate >= apiKeyLastUsedUpdateInterval {
+			apiKey.LastUsedDate = now
+			err = bouncer.apiKeyService.UpdateAPIKey(apiKey.ID, &apiKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &portainer.TokenData{
+			ID:       user.ID,
+			Username: user.Username,
+			Role:     user.Role,
+		}, nil
+	}
+
+	return nil, portainer.ErrInvalidAPIKey
+}
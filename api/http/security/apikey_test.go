@@ -0,0 +1,164 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// testAPIKeyService stores the API keys in memory.
+type testAPIKeyService struct {
+	portainer.APIKeyService
+	apiKeys []portainer.APIKey
+	updates int
+}
+
+func (service *testAPIKeyService) APIKeys() ([]portainer.APIKey, error) {
+	return append([]portainer.APIKey{}, service.apiKeys...), nil
+}
+
+func (service *testAPIKeyService) UpdateAPIKey(ID portainer.APIKeyID, apiKey *portainer.APIKey) error {
+	for idx := range service.apiKeys {
+		if service.apiKeys[idx].ID == ID {
+			service.apiKeys[idx] = *apiKey
+			service.updates++
+			return nil
+		}
+	}
+	return portainer.ErrObjectNotFound
+}
+
+type testUserService struct {
+	portainer.UserService
+	users []portainer.User
+}
+
+func (service *testUserService) User(ID portainer.UserID) (*portainer.User, error) {
+	for _, user := range service.users {
+		if user.ID == ID {
+			return &user, nil
+		}
+	}
+	return nil, portainer.ErrObjectNotFound
+}
+
+// testCryptoService uses the data as its own digest and counts the comparisons.
+type testCryptoService struct {
+	portainer.CryptoService
+	comparisons int
+}
+
+func (service *testCryptoService) CompareHashAndData(hash string, data string) error {
+	service.comparisons++
+	if hash != data {
+		return portainer.Error("digest mismatch")
+	}
+	return nil
+}
+
+func newTestAPIKey(ID portainer.APIKeyID, userID portainer.UserID, rawAPIKey string) portainer.APIKey {
+	return portainer.APIKey{
+		ID:     ID,
+		UserID: userID,
+		Prefix: APIKeyPrefix(rawAPIKey),
+		Digest: rawAPIKey,
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	validKey := portainer.APIKeyPrefix + "valid-api-key"
+	expiredKey := portainer.APIKeyPrefix + "expired-api-key"
+	disabledKey := portainer.APIKeyPrefix + "disabled-api-key"
+	orphanKey := portainer.APIKeyPrefix + "orphan-api-key"
+
+	expired := newTestAPIKey(2, 1, expiredKey)
+	expired.ExpiryDate = time.Now().Add(-time.Minute).Unix()
+
+	apiKeyService := &testAPIKeyService{
+		apiKeys: []portainer.APIKey{
+			newTestAPIKey(1, 1, validKey),
+			expired,
+			newTestAPIKey(3, 2, disabledKey),
+			newTestAPIKey(4, 3, orphanKey),
+		},
+	}
+
+	cryptoService := &testCryptoService{}
+	bouncer := &RequestBouncer{
+		apiKeyService: apiKeyService,
+		cryptoService: cryptoService,
+		userService: &testUserService{
+			users: []portainer.User{
+				{ID: 1, Username: "alice", Role: portainer.StandardUserRole},
+				{ID: 2, Username: "bob", Role: portainer.StandardUserRole, Disabled: true},
+			},
+		},
+		apiKeyCache: newAPIKeyCache(),
+	}
+
+	cases := []struct {
+		rawAPIKey string
+		err       error
+	}{
+		{rawAPIKey: validKey},
+		{rawAPIKey: validKey[:len(validKey)-1] + "x", err: portainer.ErrInvalidAPIKey},
+		{rawAPIKey: "valid-api-key", err: portainer.ErrInvalidAPIKey},
+		{rawAPIKey: portainer.APIKeyPrefix, err: portainer.ErrInvalidAPIKey},
+		{rawAPIKey: expiredKey, err: portainer.ErrAPIKeyExpired},
+		{rawAPIKey: disabledKey, err: portainer.ErrUserDisabled},
+		{rawAPIKey: orphanKey, err: portainer.ErrInvalidAPIKey},
+	}
+
+	for _, c := range cases {
+		tokenData, err := bouncer.authenticateAPIKey(c.rawAPIKey)
+		if err != c.err {
+			t.Errorf("expected error %v for key %s, got %v", c.err, c.rawAPIKey, err)
+			continue
+		}
+
+		if err == nil && (tokenData.ID != 1 || tokenData.Username != "alice") {
+			t.Errorf("unexpected token data for key %s: %+v", c.rawAPIKey, tokenData)
+		}
+	}
+
+	if apiKeyService.apiKeys[0].LastUsedDate == 0 {
+		t.Errorf("expected the last used date of the key to be updated")
+	}
+}
+
+func TestAuthenticateAPIKeyCache(t *testing.T) {
+	rawAPIKey := portainer.APIKeyPrefix + "valid-api-key"
+
+	apiKeyService := &testAPIKeyService{apiKeys: []portainer.APIKey{newTestAPIKey(1, 1, rawAPIKey)}}
+	cryptoService := &testCryptoService{}
+	bouncer := &RequestBouncer{
+		apiKeyService: apiKeyService,
+		cryptoService: cryptoService,
+		userService:   &testUserService{users: []portainer.User{{ID: 1, Username: "alice"}}},
+		apiKeyCache:   newAPIKeyCache(),
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := bouncer.authenticateAPIKey(rawAPIKey)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if cryptoService.comparisons != 1 {
+		t.Errorf("expected the key to be compared once, got %d comparisons", cryptoService.comparisons)
+	}
+
+	if apiKeyService.updates != 1 {
+		t.Errorf("expected the last used date to be updated once, got %d updates", apiKeyService.updates)
+	}
+
+	// a regenerated key must not be accepted from the cache
+	apiKeyService.apiKeys[0].Digest = portainer.APIKeyPrefix + "valid-api-key-regenerated"
+
+	_, err := bouncer.authenticateAPIKey(rawAPIKey)
+	if err != portainer.ErrInvalidAPIKey {
+		t.Errorf("expected the previous key to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package security
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+var dockerAPIVersionPattern = regexp.MustCompile(`^v\d+\.\d+$`)
+
+type auditContextKey int
+
+const contextAuditSubject auditContextKey = iota
+
+// auditSubject holds the username specified by an unauthenticated request, such as a login
+// attempt, so that it can be recorded inside the audit log.
+type auditSubject struct {
+	username string
+}
+
+// SetAuditUsername associates a username to an unauthenticated request. It is recorded inside
+// the audit log entry of the request when no user is authenticated.
+func SetAuditUsername(r *http.Request, username string) {
+	subject, ok := r.Context().Value(contextAuditSubject).(*auditSubject)
+	if ok {
+		subject.username = username
+	}
+}
+
+// auditResponseWriter wraps a http.ResponseWriter to keep track of the status code
+// returned to the client.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *auditResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Hijack is required by the Docker proxy to support attach and exec operations.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Flush is required by the Docker proxy to stream responses.
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// mwAuditLog records every mutating request (any method other than GET, HEAD and OPTIONS)
+// and every console session inside the audit log once it has been processed. The audit log
+// entry is timestamped with the start of the request.
+// When used on authenticated requests, it must be executed after mwCheckAuthentication as it
+// relies on the token data stored in the request context.
+func (bouncer *RequestBouncer) mwAuditLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if bouncer.auditService == nil || !auditedRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := &auditSubject{}
+		r = r.WithContext(context.WithValue(r.Context(), contextAuditSubject, subject))
+
+		start := time.Now()
+		writer := &auditResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r)
+
+		auditLog := newAuditLog(r, writer.status)
+		auditLog.Timestamp = start.Unix()
+		if auditLog.Username == "" {
+			auditLog.Username = subject.username
+		}
+		err := bouncer.auditService.CreateAuditLog(auditLog)
+		if err != nil {
+			log.Printf("[WARN] [http,security,audit] [message: unable to persist audit log] [err: %s]", err)
+		}
+	})
+}
+
+// auditedRequest returns true for the mutating requests and for the console sessions, which are
+// opened through GET requests on /api/websocket/exec and /api/websocket/attach.
+func auditedRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+		return true
+	}
+
+	path := requestPath(r)
+	return r.Method == http.MethodGet && (strings.HasPrefix(path, "/api/websocket/exec") || strings.HasPrefix(path, "/api/websocket/attach"))
+}
+
+// requestPath returns the path of the original request URI, as handlers are served behind http.StripPrefix.
+func requestPath(r *http.Request) string {
+	if requestURL, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		return requestURL.Path
+	}
+	return r.URL.Path
+}
+
+// newAuditLog builds an audit log entry from a request.
+func newAuditLog(r *http.Request, status int) *portainer.AuditLog {
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	path := requestPath(r)
+
+	auditLog := &portainer.AuditLog{
+		Method:    r.Method,
+		Path:      path,
+		Status:    status,
+		Timestamp: time.Now().Unix(),
+	}
+
+	tokenData, err := RetrieveTokenData(r)
+	if err == nil {
+		auditLog.UserID = tokenData.ID
+		auditLog.Username = tokenData.Username
+	}
+
+	endpointID, resourceType, resourceID, action := parseAuditPath(path)
+	auditLog.EndpointID = endpointID
+	auditLog.ResourceID = resourceID
+
+	// The webhook tokens are secrets, the executions of webhooks are recorded without them
+	if resourceType == "webhooks" && r.Method == http.MethodPost && resourceID != "" {
+		auditLog.Path = "/api/webhooks/{token}"
+		auditLog.ResourceID = ""
+	}
+
+	// The endpoint and the exec or container of a console session are specified as query parameters
+	if resourceType == "websocket" {
+		action = resourceID
+		auditLog.ResourceID = r.URL.Query().Get("id")
+		if id, err := strconv.Atoi(r.URL.Query().Get("endpointId")); err == nil {
+			auditLog.EndpointID = portainer.EndpointID(id)
+		}
+	}
+
+	auditLog.Operation = r.Method + " " + resourceType
+	if action != "" {
+		auditLog.Operation += "/" + action
+	}
+
+	return auditLog
+}
+
+// parseAuditPath extracts the endpoint identifier, resource type, resource identifier and
+// action from an API path such as /api/stacks/1/migrate or
+// /api/endpoints/1/docker/v1.40/containers/abc/start.
+func parseAuditPath(path string) (portainer.EndpointID, string, string, string) {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api"), "/"), "/")
+
+	var endpointID portainer.EndpointID
+	if len(segments) > 1 && segments[0] == "endpoints" {
+		id, err := strconv.Atoi(segments[1])
+		if err == nil {
+			endpointID = portainer.EndpointID(id)
+
+			if len(segments) > 2 && (segments[2] == "docker" || segments[2] == "storidge" || segments[2] == "azure") {
+				segments = segments[3:]
+				if len(segments) > 0 && dockerAPIVersionPattern.MatchString(segments[0]) {
+					segments = segments[1:]
+				}
+			}
+		}
+	}
+
+	var resourceType, resourceID, action string
+	if len(segments) > 0 {
+		resourceType = segments[0]
+	}
+	if len(segments) > 1 {
+		if segments[1] == "create" {
+			action = segments[1]
+		} else {
+			resourceID = segments[1]
+		}
+	}
+	if len(segments) > 2 {
+		action = strings.Join(segments[2:], "/")
+	}
+
+	return endpointID, resourceType, resourceID, action
+}
@@ -0,0 +1,30 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestNewAuditLogConsoleSession(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/websocket/exec?id=abc&endpointId=2&nodeName=&token=secret", nil)
+
+	if !auditedRequest(r) {
+		t.Fatalf("expected the console session to be audited")
+	}
+
+	auditLog := newAuditLog(r, http.StatusSwitchingProtocols)
+	if auditLog.Operation != "GET websocket/exec" || auditLog.EndpointID != portainer.EndpointID(2) || auditLog.ResourceID != "abc" {
+		t.Errorf("unexpected audit log: %+v", auditLog)
+	}
+
+	if auditLog.Path != "/api/websocket/exec" {
+		t.Errorf("expected the query parameters to be left out of the path, got %s", auditLog.Path)
+	}
+
+	if auditedRequest(httptest.NewRequest(http.MethodGet, "/api/endpoints/2/docker/containers/json", nil)) {
+		t.Errorf("expected a read request not to be audited")
+	}
+}
@@ -17,7 +17,10 @@ type (
 		endpointService       portainer.EndpointService
 		endpointGroupService  portainer.EndpointGroupService
 		extensionService      portainer.ExtensionService
-		rbacExtensionClient   *rbacExtensionClient
+		auditService          portainer.AuditService
+		apiKeyService         portainer.APIKeyService
+		cryptoService         portainer.CryptoService
+		apiKeyCache           *apiKeyCache
 		authDisabled          bool
 	}
 
@@ -29,7 +32,9 @@ type (
 		EndpointService       portainer.EndpointService
 		EndpointGroupService  portainer.EndpointGroupService
 		ExtensionService      portainer.ExtensionService
-		RBACExtensionURL      string
+		AuditService          portainer.AuditService
+		APIKeyService         portainer.APIKeyService
+		CryptoService         portainer.CryptoService
 		AuthDisabled          bool
 	}
 
@@ -52,21 +57,27 @@ func NewRequestBouncer(parameters *RequestBouncerParams) *RequestBouncer {
 		endpointService:       parameters.EndpointService,
 		endpointGroupService:  parameters.EndpointGroupService,
 		extensionService:      parameters.ExtensionService,
-		rbacExtensionClient:   newRBACExtensionClient(parameters.RBACExtensionURL),
+		auditService:          parameters.AuditService,
+		apiKeyService:         parameters.APIKeyService,
+		cryptoService:         parameters.CryptoService,
+		apiKeyCache:           newAPIKeyCache(),
 		authDisabled:          parameters.AuthDisabled,
 	}
 }
 
 // PublicAccess defines a security check for public API endpoints.
 // No authentication is required to access these endpoints.
+// Mutating requests are recorded inside the audit log.
 func (bouncer *RequestBouncer) PublicAccess(h http.Handler) http.Handler {
+	h = bouncer.mwAuditLog(h)
 	h = mwSecureHeaders(h)
 	return h
 }
 
 // AdminAccess defines a security check for API endpoints that require an authorization check.
 // Authentication is required to access these endpoints.
-// If the RBAC extension is enabled, authorizations are required to use these endpoints.
+// If the RBAC extension is enabled, the authorizations of the user are checked in-process against
+// the operation associated to the request.
 // If the RBAC extension is not enabled, the administrator role is required to use these endpoints.
 // The request context will be enhanced with a RestrictedRequestContext object
 // that might be used later to inside the API operation for extra authorization validation
@@ -80,7 +91,8 @@ func (bouncer *RequestBouncer) AdminAccess(h http.Handler) http.Handler {
 
 // RestrictedAccess defines a security check for restricted API endpoints.
 // Authentication is required to access these endpoints.
-// If the RBAC extension is enabled, authorizations are required to use these endpoints.
+// If the RBAC extension is enabled, the authorizations of the user are checked in-process against
+// the operation associated to the request.
 // If the RBAC extension is not enabled, access is granted to any authenticated user.
 // The request context will be enhanced with a RestrictedRequestContext object
 // that might be used later to inside the API operation for extra authorization validation
@@ -153,7 +165,7 @@ func (bouncer *RequestBouncer) checkEndpointOperationAuthorization(r *http.Reque
 		return nil
 	}
 
-	extension, err := bouncer.extensionService.Extension(portainer.RBACExtension)
+	_, err = bouncer.extensionService.Extension(portainer.RBACExtension)
 	if err == portainer.ErrObjectNotFound {
 		return nil
 	} else if err != nil {
@@ -165,14 +177,7 @@ func (bouncer *RequestBouncer) checkEndpointOperationAuthorization(r *http.Reque
 		return err
 	}
 
-	apiOperation := &portainer.APIOperationAuthorizationRequest{
-		Path:           r.URL.String(),
-		Method:         r.Method,
-		Authorizations: user.EndpointAuthorizations[endpoint.ID],
-	}
-
-	bouncer.rbacExtensionClient.setLicenseKey(extension.License.LicenseKey)
-	return bouncer.rbacExtensionClient.checkAuthorization(apiOperation)
+	return checkAuthorization(r, user.EndpointAuthorizations[endpoint.ID])
 }
 
 // RegistryAccess retrieves the JWT token from the request context and verifies
@@ -201,14 +206,15 @@ func (bouncer *RequestBouncer) RegistryAccess(r *http.Request, registry *portain
 }
 
 func (bouncer *RequestBouncer) mwAuthenticatedUser(h http.Handler) http.Handler {
+	h = bouncer.mwAuditLog(h)
 	h = bouncer.mwCheckAuthentication(h)
 	h = mwSecureHeaders(h)
 	return h
 }
 
 // mwCheckPortainerAuthorizations will verify that the user has the required authorization to access
-// a specific API endpoint. It will leverage the RBAC extension authorization validation if the extension
-// is enabled.
+// a specific API endpoint when the RBAC extension is enabled. The authorizations are checked in-process
+// against the authorizations computed for the user.
 // If the administratorOnly flag is specified and the RBAC extension is not enabled, this will prevent non-admin
 // users from accessing the endpoint.
 func (bouncer *RequestBouncer) mwCheckPortainerAuthorizations(next http.Handler, administratorOnly bool) http.Handler {
@@ -224,7 +230,7 @@ func (bouncer *RequestBouncer) mwCheckPortainerAuthorizations(next http.Handler,
 			return
 		}
 
-		extension, err := bouncer.extensionService.Extension(portainer.RBACExtension)
+		_, err = bouncer.extensionService.Extension(portainer.RBACExtension)
 		if err == portainer.ErrObjectNotFound {
 			if administratorOnly {
 				httperror.WriteError(w, http.StatusForbidden, "Access denied", portainer.ErrUnauthorized)
@@ -247,14 +253,7 @@ func (bouncer *RequestBouncer) mwCheckPortainerAuthorizations(next http.Handler,
 			return
 		}
 
-		apiOperation := &portainer.APIOperationAuthorizationRequest{
-			Path:           r.URL.String(),
-			Method:         r.Method,
-			Authorizations: user.PortainerAuthorizations,
-		}
-
-		bouncer.rbacExtensionClient.setLicenseKey(extension.License.LicenseKey)
-		err = bouncer.rbacExtensionClient.checkAuthorization(apiOperation)
+		err = checkAuthorization(r, user.PortainerAuthorizations)
 		if err != nil {
 			httperror.WriteError(w, http.StatusForbidden, "Access denied", portainer.ErrAuthorizationRequired)
 			return
@@ -285,24 +284,24 @@ func (bouncer *RequestBouncer) mwUpgradeToRestrictedRequest(next http.Handler) h
 	})
 }
 
-// mwCheckAuthentication provides Authentication middleware for handlers
+// mwCheckAuthentication provides Authentication middleware for handlers.
+// Requests can either be authenticated with a JWT token or with an API key
+// specified via the X-API-Key header.
 func (bouncer *RequestBouncer) mwCheckAuthentication(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var tokenData *portainer.TokenData
-		if !bouncer.authDisabled {
-			var token string
-
-			// Optionally, token might be set via the "token" query parameter.
-			// For example, in websocket requests
-			token = r.URL.Query().Get("token")
-
-			// Get token from the Authorization header
-			tokens, ok := r.Header["Authorization"]
-			if ok && len(tokens) >= 1 {
-				token = tokens[0]
-				token = strings.TrimPrefix(token, "Bearer ")
+		if !bouncer.authDisabled && r.Header.Get(portainer.APIKeyHeader) != "" {
+			var err error
+			tokenData, err = bouncer.authenticateAPIKey(r.Header.Get(portainer.APIKeyHeader))
+			if err == portainer.ErrInvalidAPIKey || err == portainer.ErrAPIKeyExpired {
+				httperror.WriteError(w, http.StatusUnauthorized, "Invalid API key", err)
+				return
+			} else if err != nil {
+				httperror.WriteError(w, http.StatusInternalServerError, "Unable to validate API key", err)
+				return
 			}
-
+		} else if !bouncer.authDisabled {
+			token := RetrieveRawToken(r)
 			if token == "" {
 				httperror.WriteError(w, http.StatusUnauthorized, "Unauthorized", portainer.ErrUnauthorized)
 				return
@@ -335,6 +334,23 @@ func (bouncer *RequestBouncer) mwCheckAuthentication(next http.Handler) http.Han
 	})
 }
 
+// RetrieveRawToken returns the JWT token associated to a request or an empty string if the
+// request does not contain any token.
+func RetrieveRawToken(r *http.Request) string {
+	// Optionally, token might be set via the "token" query parameter.
+	// For example, in websocket requests
+	token := r.URL.Query().Get("token")
+
+	// Get token from the Authorization header
+	tokens, ok := r.Header["Authorization"]
+	if ok && len(tokens) >= 1 {
+		token = tokens[0]
+		token = strings.TrimPrefix(token, "Bearer ")
+	}
+
+	return token
+}
+
 // mwSecureHeaders provides secure headers middleware for handlers.
 func mwSecureHeaders(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
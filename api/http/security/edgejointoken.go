@@ -0,0 +1,17 @@
+package security
+
+import (
+	"github.com/portainer/portainer/api"
+)
+
+// edgeJoinTokenPrefixLength is the number of characters of an Edge join token stored in clear
+// to be able to identify the token without comparing every digest.
+const edgeJoinTokenPrefixLength = len(portainer.EdgeJoinTokenPrefix) + 8
+
+// EdgeJoinTokenPrefix returns the non-secret part of a raw Edge join token that is stored alongside its digest.
+func EdgeJoinTokenPrefix(rawToken string) string {
+	if len(rawToken) < edgeJoinTokenPrefixLength {
+		return rawToken
+	}
+	return rawToken[:edgeJoinTokenPrefixLength]
+}
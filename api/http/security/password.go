@@ -0,0 +1,167 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	httperror "github.com/portainer/libhttp/error"
+	"github.com/portainer/portainer/api"
+)
+
+// PasswordPolicyError is the error returned when a password does not comply with the password policy.
+type PasswordPolicyError struct {
+	Violations []portainer.PasswordPolicyViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, ". ")
+}
+
+type passwordPolicyErrorResponse struct {
+	Message    string                              `json:"message"`
+	Details    string                              `json:"details"`
+	Violations []portainer.PasswordPolicyViolation `json:"violations"`
+}
+
+// CheckPasswordPolicy returns the requirements of the policy that are not satisfied by the password.
+func CheckPasswordPolicy(password string, policy *portainer.PasswordPolicy) []portainer.PasswordPolicyViolation {
+	violations := make([]portainer.PasswordPolicyViolation, 0)
+
+	if len([]rune(password)) < policy.MinLength {
+		violations = append(violations, portainer.PasswordPolicyViolation{
+			Code:    portainer.PasswordViolationMinLength,
+			Message: fmt.Sprintf("Password must contain at least %d characters", policy.MinLength),
+		})
+	}
+
+	var hasUppercase, hasLowercase, hasDigit, hasSpecialCharacter bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUppercase = true
+		case unicode.IsLower(char):
+			hasLowercase = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char) || unicode.IsSpace(char):
+			hasSpecialCharacter = true
+		}
+	}
+
+	if policy.RequireUppercase && !hasUppercase {
+		violations = append(violations, portainer.PasswordPolicyViolation{
+			Code:    portainer.PasswordViolationUppercase,
+			Message: "Password must contain at least one uppercase letter",
+		})
+	}
+
+	if policy.RequireLowercase && !hasLowercase {
+		violations = append(violations, portainer.PasswordPolicyViolation{
+			Code:    portainer.PasswordViolationLowercase,
+			Message: "Password must contain at least one lowercase letter",
+		})
+	}
+
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, portainer.PasswordPolicyViolation{
+			Code:    portainer.PasswordViolationDigit,
+			Message: "Password must contain at least one digit",
+		})
+	}
+
+	if policy.RequireSpecialCharacter && !hasSpecialCharacter {
+		violations = append(violations, portainer.PasswordPolicyViolation{
+			Code:    portainer.PasswordViolationSpecialCharacter,
+			Message: "Password must contain at least one special character",
+		})
+	}
+
+	return violations
+}
+
+// SetUserPassword validates the password against the policy and the most recent passwords of the
+// user before hashing it and storing it inside the user. It returns a *PasswordPolicyError when
+// the password does not comply with the policy. The user must be persisted by the caller.
+func SetUserPassword(user *portainer.User, password string, policy *portainer.PasswordPolicy, cryptoService portainer.CryptoService) error {
+	violations := CheckPasswordPolicy(password, policy)
+
+	recentPasswords := make([]string, 0)
+	if user.Password != "" {
+		recentPasswords = append(recentPasswords, user.Password)
+	}
+	recentPasswords = append(recentPasswords, user.PasswordHistory...)
+	if len(recentPasswords) > policy.HistorySize {
+		recentPasswords = recentPasswords[:policy.HistorySize]
+	}
+
+	for _, hash := range recentPasswords {
+		if cryptoService.CompareHashAndData(hash, password) == nil {
+			violations = append(violations, portainer.PasswordPolicyViolation{
+				Code:    portainer.PasswordViolationReused,
+				Message: fmt.Sprintf("Password must be different from the last %d passwords", policy.HistorySize),
+			})
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	hash, err := cryptoService.Hash(password)
+	if err != nil || hash == "" {
+		return portainer.ErrCryptoHashFailure
+	}
+
+	// The history only keeps the previous passwords, the current one is stored in user.Password
+	history := make([]string, 0)
+	if policy.HistorySize > 1 && len(recentPasswords) > 0 {
+		history = recentPasswords
+		if len(history) > policy.HistorySize-1 {
+			history = history[:policy.HistorySize-1]
+		}
+	}
+
+	user.Password = hash
+	user.PasswordHistory = history
+	user.PasswordChangedAt = time.Now().Unix()
+
+	return nil
+}
+
+// PasswordExpired returns true when the password of the user is older than the maximum age
+// defined in the policy.
+func PasswordExpired(user *portainer.User, policy *portainer.PasswordPolicy) bool {
+	if user.Password == "" || policy.MaxAge <= 0 {
+		return false
+	}
+
+	maxAge := time.Duration(policy.MaxAge) * 24 * time.Hour
+	return time.Since(time.Unix(user.PasswordChangedAt, 0)) > maxAge
+}
+
+// WritePasswordPolicyError writes an error response containing the requirements of the password
+// policy that are not satisfied so that they can be displayed to the user.
+func WritePasswordPolicyError(w http.ResponseWriter, policyError *PasswordPolicyError) *httperror.HandlerError {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	err := json.NewEncoder(w).Encode(&passwordPolicyErrorResponse{
+		Message:    portainer.ErrPasswordPolicyViolation.Error(),
+		Details:    policyError.Error(),
+		Violations: policyError.Violations,
+	})
+	if err != nil {
+		return &httperror.HandlerError{http.StatusInternalServerError, "Unable to write JSON response", err}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+type plainCryptoService struct{}
+
+func (plainCryptoService) Hash(data string) (string, error) {
+	return "hash:" + data, nil
+}
+
+func (plainCryptoService) CompareHashAndData(hash string, data string) error {
+	if hash != "hash:"+data {
+		return portainer.ErrUnauthorized
+	}
+	return nil
+}
+
+func violationCodes(violations []portainer.PasswordPolicyViolation) map[string]bool {
+	codes := make(map[string]bool)
+	for _, violation := range violations {
+		codes[violation.Code] = true
+	}
+	return codes
+}
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	policy := &portainer.PasswordPolicy{
+		MinLength:               8,
+		RequireUppercase:        true,
+		RequireLowercase:        true,
+		RequireDigit:            true,
+		RequireSpecialCharacter: true,
+	}
+
+	violations := CheckPasswordPolicy("Passw0rd!", policy)
+	if len(violations) != 0 {
+		t.Errorf("expected password to comply with the policy, got %v", violations)
+	}
+
+	codes := violationCodes(CheckPasswordPolicy("pass", policy))
+	for _, code := range []string{portainer.PasswordViolationMinLength, portainer.PasswordViolationUppercase, portainer.PasswordViolationDigit, portainer.PasswordViolationSpecialCharacter} {
+		if !codes[code] {
+			t.Errorf("expected violation %s", code)
+		}
+	}
+	if codes[portainer.PasswordViolationLowercase] {
+		t.Errorf("unexpected violation %s", portainer.PasswordViolationLowercase)
+	}
+
+	violations = CheckPasswordPolicy("a", &portainer.PasswordPolicy{})
+	if len(violations) != 0 {
+		t.Errorf("expected an empty policy to accept any password, got %v", violations)
+	}
+}
+
+func TestSetUserPasswordHistory(t *testing.T) {
+	policy := &portainer.PasswordPolicy{HistorySize: 2}
+	user := &portainer.User{}
+
+	for _, password := range []string{"first", "second", "third"} {
+		err := SetUserPassword(user, password, policy, plainCryptoService{})
+		if err != nil {
+			t.Fatalf("unexpected error when setting password %s: %s", password, err)
+		}
+	}
+
+	if user.Password != "hash:third" || len(user.PasswordHistory) != 1 || user.PasswordHistory[0] != "hash:second" {
+		t.Fatalf("unexpected password history: %s %v", user.Password, user.PasswordHistory)
+	}
+
+	err := SetUserPassword(user, "second", policy, plainCryptoService{})
+	policyError, ok := err.(*PasswordPolicyError)
+	if !ok || !violationCodes(policyError.Violations)[portainer.PasswordViolationReused] {
+		t.Errorf("expected a reused password violation, got %v", err)
+	}
+
+	err = SetUserPassword(user, "first", policy, plainCryptoService{})
+	if err != nil {
+		t.Errorf("expected a password older than the history to be accepted, got %s", err)
+	}
+}
@@ -1,59 +1,330 @@
 package security
 
 import (
-	"encoding/json"
 	"net/http"
-	"time"
+	"regexp"
+	"strings"
 
-	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api"
 )
 
-const (
-	defaultHTTPTimeout = 5
+// operationRule associates an API operation, identified by a HTTP method and a path pattern,
+// to the authorization required to execute it.
+type operationRule struct {
+	method        string
+	pattern       *regexp.Regexp
+	authorization portainer.Authorization
+}
+
+var (
+	dockerAPIVersionRe   = regexp.MustCompile(`^/v[0-9]+\.[0-9]+`)
+	endpointProxyRe      = regexp.MustCompile(`^/endpoints/[0-9]+/(docker|storidge|azure)(/.*)?$`)
+	routeVariableRe      = regexp.MustCompile(`{[a-zA-Z]+}`)
+	routeMultiVariableRe = regexp.MustCompile(`{[a-zA-Z]+\*}`)
 )
 
-type rbacExtensionClient struct {
-	httpClient   *http.Client
-	extensionURL string
-	licenseKey   string
-}
+// rule creates an operationRule. The path can contain route variables such as {id} matching a single
+// path segment and route variables such as {name*} matching one or multiple path segments.
+func rule(method, path string, authorization portainer.Authorization) operationRule {
+	pattern := routeMultiVariableRe.ReplaceAllString(path, `.+`)
+	pattern = routeVariableRe.ReplaceAllString(pattern, `[^/]+`)
 
-func newRBACExtensionClient(extensionURL string) *rbacExtensionClient {
-	return &rbacExtensionClient{
-		extensionURL: extensionURL,
-		httpClient: &http.Client{
-			Timeout: time.Second * time.Duration(defaultHTTPTimeout),
-		},
+	return operationRule{
+		method:        method,
+		pattern:       regexp.MustCompile("^" + pattern + "$"),
+		authorization: authorization,
 	}
 }
 
-func (client *rbacExtensionClient) setLicenseKey(licenseKey string) {
-	client.licenseKey = licenseKey
+// The rules are evaluated in order, the first matching rule defines the required authorization.
+var dockerOperationRules = []operationRule{
+	rule(http.MethodGet, "/containers/json", portainer.OperationDockerContainerList),
+	rule(http.MethodPost, "/containers/create", portainer.OperationDockerContainerCreate),
+	rule(http.MethodPost, "/containers/prune", portainer.OperationDockerContainerPrune),
+	rule(http.MethodGet, "/containers/{id}/json", portainer.OperationDockerContainerInspect),
+	rule(http.MethodGet, "/containers/{id}/top", portainer.OperationDockerContainerTop),
+	rule(http.MethodGet, "/containers/{id}/logs", portainer.OperationDockerContainerLogs),
+	rule(http.MethodGet, "/containers/{id}/changes", portainer.OperationDockerContainerChanges),
+	rule(http.MethodGet, "/containers/{id}/export", portainer.OperationDockerContainerExport),
+	rule(http.MethodGet, "/containers/{id}/stats", portainer.OperationDockerContainerStats),
+	rule(http.MethodPost, "/containers/{id}/resize", portainer.OperationDockerContainerResize),
+	rule(http.MethodPost, "/containers/{id}/start", portainer.OperationDockerContainerStart),
+	rule(http.MethodPost, "/containers/{id}/stop", portainer.OperationDockerContainerStop),
+	rule(http.MethodPost, "/containers/{id}/restart", portainer.OperationDockerContainerRestart),
+	rule(http.MethodPost, "/containers/{id}/kill", portainer.OperationDockerContainerKill),
+	rule(http.MethodPost, "/containers/{id}/update", portainer.OperationDockerContainerUpdate),
+	rule(http.MethodPost, "/containers/{id}/rename", portainer.OperationDockerContainerRename),
+	rule(http.MethodPost, "/containers/{id}/pause", portainer.OperationDockerContainerPause),
+	rule(http.MethodPost, "/containers/{id}/unpause", portainer.OperationDockerContainerUnpause),
+	rule(http.MethodPost, "/containers/{id}/attach", portainer.OperationDockerContainerAttach),
+	rule(http.MethodGet, "/containers/{id}/attach/ws", portainer.OperationDockerContainerAttachWebsocket),
+	rule(http.MethodPost, "/containers/{id}/wait", portainer.OperationDockerContainerWait),
+	rule(http.MethodHead, "/containers/{id}/archive", portainer.OperationDockerContainerArchiveInfo),
+	rule(http.MethodGet, "/containers/{id}/archive", portainer.OperationDockerContainerArchive),
+	rule(http.MethodPut, "/containers/{id}/archive", portainer.OperationDockerContainerPutContainerArchive),
+	rule(http.MethodPost, "/containers/{id}/exec", portainer.OperationDockerContainerExec),
+	rule(http.MethodDelete, "/containers/{id}", portainer.OperationDockerContainerDelete),
+
+	rule(http.MethodGet, "/images/json", portainer.OperationDockerImageList),
+	rule(http.MethodGet, "/images/search", portainer.OperationDockerImageSearch),
+	rule(http.MethodGet, "/images/get", portainer.OperationDockerImageGetAll),
+	rule(http.MethodPost, "/images/load", portainer.OperationDockerImageLoad),
+	rule(http.MethodPost, "/images/create", portainer.OperationDockerImageCreate),
+	rule(http.MethodPost, "/images/prune", portainer.OperationDockerImagePrune),
+	rule(http.MethodGet, "/images/{name*}/json", portainer.OperationDockerImageInspect),
+	rule(http.MethodGet, "/images/{name*}/history", portainer.OperationDockerImageHistory),
+	rule(http.MethodGet, "/images/{name*}/get", portainer.OperationDockerImageGet),
+	rule(http.MethodPost, "/images/{name*}/push", portainer.OperationDockerImagePush),
+	rule(http.MethodPost, "/images/{name*}/tag", portainer.OperationDockerImageTag),
+	rule(http.MethodDelete, "/images/{name*}", portainer.OperationDockerImageDelete),
+	rule(http.MethodPost, "/commit", portainer.OperationDockerImageCommit),
+	rule(http.MethodPost, "/build", portainer.OperationDockerImageBuild),
+	rule(http.MethodPost, "/build/prune", portainer.OperationDockerBuildPrune),
+	rule(http.MethodPost, "/build/cancel", portainer.OperationDockerBuildCancel),
+
+	rule(http.MethodGet, "/networks", portainer.OperationDockerNetworkList),
+	rule(http.MethodPost, "/networks/create", portainer.OperationDockerNetworkCreate),
+	rule(http.MethodPost, "/networks/prune", portainer.OperationDockerNetworkPrune),
+	rule(http.MethodGet, "/networks/{id}", portainer.OperationDockerNetworkInspect),
+	rule(http.MethodPost, "/networks/{id}/connect", portainer.OperationDockerNetworkConnect),
+	rule(http.MethodPost, "/networks/{id}/disconnect", portainer.OperationDockerNetworkDisconnect),
+	rule(http.MethodDelete, "/networks/{id}", portainer.OperationDockerNetworkDelete),
+
+	rule(http.MethodGet, "/volumes", portainer.OperationDockerVolumeList),
+	rule(http.MethodPost, "/volumes/create", portainer.OperationDockerVolumeCreate),
+	rule(http.MethodPost, "/volumes/prune", portainer.OperationDockerVolumePrune),
+	rule(http.MethodGet, "/volumes/{name}", portainer.OperationDockerVolumeInspect),
+	rule(http.MethodDelete, "/volumes/{name}", portainer.OperationDockerVolumeDelete),
+
+	rule(http.MethodGet, "/exec/{id}/json", portainer.OperationDockerExecInspect),
+	rule(http.MethodPost, "/exec/{id}/start", portainer.OperationDockerExecStart),
+	rule(http.MethodPost, "/exec/{id}/resize", portainer.OperationDockerExecResize),
+
+	rule(http.MethodGet, "/swarm", portainer.OperationDockerSwarmInspect),
+	rule(http.MethodGet, "/swarm/unlockkey", portainer.OperationDockerSwarmUnlockKey),
+	rule(http.MethodPost, "/swarm/init", portainer.OperationDockerSwarmInit),
+	rule(http.MethodPost, "/swarm/join", portainer.OperationDockerSwarmJoin),
+	rule(http.MethodPost, "/swarm/leave", portainer.OperationDockerSwarmLeave),
+	rule(http.MethodPost, "/swarm/update", portainer.OperationDockerSwarmUpdate),
+	rule(http.MethodPost, "/swarm/unlock", portainer.OperationDockerSwarmUnlock),
+
+	rule(http.MethodGet, "/nodes", portainer.OperationDockerNodeList),
+	rule(http.MethodGet, "/nodes/{id}", portainer.OperationDockerNodeInspect),
+	rule(http.MethodPost, "/nodes/{id}/update", portainer.OperationDockerNodeUpdate),
+	rule(http.MethodDelete, "/nodes/{id}", portainer.OperationDockerNodeDelete),
+
+	rule(http.MethodGet, "/services", portainer.OperationDockerServiceList),
+	rule(http.MethodPost, "/services/create", portainer.OperationDockerServiceCreate),
+	rule(http.MethodGet, "/services/{id}", portainer.OperationDockerServiceInspect),
+	rule(http.MethodGet, "/services/{id}/logs", portainer.OperationDockerServiceLogs),
+	rule(http.MethodPost, "/services/{id}/update", portainer.OperationDockerServiceUpdate),
+	rule(http.MethodDelete, "/services/{id}", portainer.OperationDockerServiceDelete),
+
+	rule(http.MethodGet, "/tasks", portainer.OperationDockerTaskList),
+	rule(http.MethodGet, "/tasks/{id}", portainer.OperationDockerTaskInspect),
+	rule(http.MethodGet, "/tasks/{id}/logs", portainer.OperationDockerTaskLogs),
+
+	rule(http.MethodGet, "/secrets", portainer.OperationDockerSecretList),
+	rule(http.MethodPost, "/secrets/create", portainer.OperationDockerSecretCreate),
+	rule(http.MethodGet, "/secrets/{id}", portainer.OperationDockerSecretInspect),
+	rule(http.MethodPost, "/secrets/{id}/update", portainer.OperationDockerSecretUpdate),
+	rule(http.MethodDelete, "/secrets/{id}", portainer.OperationDockerSecretDelete),
+
+	rule(http.MethodGet, "/configs", portainer.OperationDockerConfigList),
+	rule(http.MethodPost, "/configs/create", portainer.OperationDockerConfigCreate),
+	rule(http.MethodGet, "/configs/{id}", portainer.OperationDockerConfigInspect),
+	rule(http.MethodPost, "/configs/{id}/update", portainer.OperationDockerConfigUpdate),
+	rule(http.MethodDelete, "/configs/{id}", portainer.OperationDockerConfigDelete),
+
+	rule(http.MethodGet, "/plugins", portainer.OperationDockerPluginList),
+	rule(http.MethodGet, "/plugins/privileges", portainer.OperationDockerPluginPrivileges),
+	rule(http.MethodPost, "/plugins/pull", portainer.OperationDockerPluginPull),
+	rule(http.MethodPost, "/plugins/create", portainer.OperationDockerPluginCreate),
+	rule(http.MethodGet, "/plugins/{name*}/json", portainer.OperationDockerPluginInspect),
+	rule(http.MethodPost, "/plugins/{name*}/enable", portainer.OperationDockerPluginEnable),
+	rule(http.MethodPost, "/plugins/{name*}/disable", portainer.OperationDockerPluginDisable),
+	rule(http.MethodPost, "/plugins/{name*}/push", portainer.OperationDockerPluginPush),
+	rule(http.MethodPost, "/plugins/{name*}/upgrade", portainer.OperationDockerPluginUpgrade),
+	rule(http.MethodPost, "/plugins/{name*}/set", portainer.OperationDockerPluginSet),
+	rule(http.MethodDelete, "/plugins/{name*}", portainer.OperationDockerPluginDelete),
+
+	rule(http.MethodPost, "/session", portainer.OperationDockerSessionStart),
+	rule(http.MethodGet, "/distribution/{name*}/json", portainer.OperationDockerDistributionInspect),
+	rule(http.MethodGet, "/_ping", portainer.OperationDockerPing),
+	rule(http.MethodHead, "/_ping", portainer.OperationDockerPing),
+	rule(http.MethodGet, "/info", portainer.OperationDockerInfo),
+	rule(http.MethodGet, "/events", portainer.OperationDockerEvents),
+	rule(http.MethodGet, "/system/df", portainer.OperationDockerSystem),
+	rule(http.MethodGet, "/version", portainer.OperationDockerVersion),
+}
+
+var agentOperationRules = []operationRule{
+	rule(http.MethodGet, "/ping", portainer.OperationDockerAgentPing),
+	rule(http.MethodGet, "/agents", portainer.OperationDockerAgentList),
+	rule(http.MethodGet, "/host/info", portainer.OperationDockerAgentHostInfo),
+	rule(http.MethodGet, "/browse/ls", portainer.OperationDockerAgentBrowseList),
+	rule(http.MethodGet, "/browse/get", portainer.OperationDockerAgentBrowseGet),
+	rule(http.MethodDelete, "/browse/delete", portainer.OperationDockerAgentBrowseDelete),
+	rule(http.MethodPut, "/browse/rename", portainer.OperationDockerAgentBrowseRename),
+	rule(http.MethodPost, "/browse/put", portainer.OperationDockerAgentBrowsePut),
 }
 
-func (client *rbacExtensionClient) checkAuthorization(authRequest *portainer.APIOperationAuthorizationRequest) error {
-	encodedAuthRequest, err := json.Marshal(authRequest)
-	if err != nil {
-		return err
+var portainerOperationRules = []operationRule{
+	rule(http.MethodGet, "/dockerhub", portainer.OperationPortainerDockerHubInspect),
+	rule(http.MethodPut, "/dockerhub", portainer.OperationPortainerDockerHubUpdate),
+
+	rule(http.MethodGet, "/endpoint_groups", portainer.OperationPortainerEndpointGroupList),
+	rule(http.MethodPost, "/endpoint_groups", portainer.OperationPortainerEndpointGroupCreate),
+	rule(http.MethodGet, "/endpoint_groups/{id}", portainer.OperationPortainerEndpointGroupInspect),
+	rule(http.MethodPut, "/endpoint_groups/{id}", portainer.OperationPortainerEndpointGroupUpdate),
+	rule(http.MethodDelete, "/endpoint_groups/{id}", portainer.OperationPortainerEndpointGroupDelete),
+	rule(http.MethodPut, "/endpoint_groups/{id}/endpoints/{endpointId}", portainer.OperationPortainerEndpointGroupAccess),
+	rule(http.MethodDelete, "/endpoint_groups/{id}/endpoints/{endpointId}", portainer.OperationPortainerEndpointGroupAccess),
+
+	rule(http.MethodGet, "/endpoints", portainer.OperationPortainerEndpointList),
+	rule(http.MethodPost, "/endpoints", portainer.OperationPortainerEndpointCreate),
+	rule(http.MethodPost, "/endpoints/import", portainer.OperationPortainerEndpointCreate),
+	rule(http.MethodPost, "/endpoints/snapshot", portainer.OperationPortainerEndpointSnapshots),
+	rule(http.MethodGet, "/endpoints/{id}", portainer.OperationPortainerEndpointInspect),
+	rule(http.MethodPut, "/endpoints/{id}", portainer.OperationPortainerEndpointUpdate),
+	rule(http.MethodDelete, "/endpoints/{id}", portainer.OperationPortainerEndpointDelete),
+	rule(http.MethodPost, "/endpoints/{id}/approve", portainer.OperationPortainerEndpointUpdate),
+	rule(http.MethodGet, "/endpoints/{id}/events", portainer.OperationPortainerEndpointInspect),
+	rule(http.MethodPost, "/endpoints/{id}/extensions", portainer.OperationPortainerEndpointExtensionAdd),
+	rule(http.MethodDelete, "/endpoints/{id}/extensions/{extensionType}", portainer.OperationPortainerEndpointExtensionRemove),
+	rule(http.MethodPost, "/endpoints/{id}/job", portainer.OperationPortainerEndpointJob),
+	rule(http.MethodPost, "/endpoints/{id}/snapshot", portainer.OperationPortainerEndpointSnapshot),
+	rule(http.MethodGet, "/endpoints/{id}/snapshots", portainer.OperationPortainerEndpointInspect),
+
+	rule(http.MethodGet, "/extensions", portainer.OperationPortainerExtensionList),
+	rule(http.MethodPost, "/extensions", portainer.OperationPortainerExtensionCreate),
+	rule(http.MethodPost, "/extensions/upload", portainer.OperationPortainerExtensionCreate),
+	rule(http.MethodGet, "/extensions/{id}", portainer.OperationPortainerExtensionInspect),
+	rule(http.MethodDelete, "/extensions/{id}", portainer.OperationPortainerExtensionDelete),
+	rule(http.MethodPost, "/extensions/{id}/update", portainer.OperationPortainerExtensionUpdate),
+
+	rule(http.MethodGet, "/motd", portainer.OperationPortainerMOTD),
+
+	rule(http.MethodGet, "/registries", portainer.OperationPortainerRegistryList),
+	rule(http.MethodPost, "/registries", portainer.OperationPortainerRegistryCreate),
+	rule(http.MethodGet, "/registries/{id}", portainer.OperationPortainerRegistryInspect),
+	rule(http.MethodPut, "/registries/{id}", portainer.OperationPortainerRegistryUpdate),
+	rule(http.MethodDelete, "/registries/{id}", portainer.OperationPortainerRegistryDelete),
+	rule(http.MethodPost, "/registries/{id}/configure", portainer.OperationPortainerRegistryConfigure),
+	rule(http.MethodGet, "/registries/{id}/proxies/gitlab/{path*}", portainer.OperationPortainerRegistryInspect),
+
+	rule(http.MethodPost, "/resource_controls", portainer.OperationPortainerResourceControlCreate),
+	rule(http.MethodPut, "/resource_controls/{id}", portainer.OperationPortainerResourceControlUpdate),
+	rule(http.MethodDelete, "/resource_controls/{id}", portainer.OperationPortainerResourceControlDelete),
+
+	rule(http.MethodGet, "/roles", portainer.OperationPortainerRoleList),
+	rule(http.MethodPost, "/roles", portainer.OperationPortainerRoleCreate),
+	rule(http.MethodGet, "/roles/{id}", portainer.OperationPortainerRoleInspect),
+	rule(http.MethodPut, "/roles/{id}", portainer.OperationPortainerRoleUpdate),
+	rule(http.MethodDelete, "/roles/{id}", portainer.OperationPortainerRoleDelete),
+
+	rule(http.MethodGet, "/schedules", portainer.OperationPortainerScheduleList),
+	rule(http.MethodPost, "/schedules", portainer.OperationPortainerScheduleCreate),
+	rule(http.MethodGet, "/schedules/{id}", portainer.OperationPortainerScheduleInspect),
+	rule(http.MethodPut, "/schedules/{id}", portainer.OperationPortainerScheduleUpdate),
+	rule(http.MethodDelete, "/schedules/{id}", portainer.OperationPortainerScheduleDelete),
+	rule(http.MethodGet, "/schedules/{id}/file", portainer.OperationPortainerScheduleFile),
+	rule(http.MethodGet, "/schedules/{id}/tasks", portainer.OperationPortainerScheduleTasks),
+
+	rule(http.MethodGet, "/settings", portainer.OperationPortainerSettingsInspect),
+	rule(http.MethodPut, "/settings", portainer.OperationPortainerSettingsUpdate),
+	rule(http.MethodPut, "/settings/authentication/checkLDAP", portainer.OperationPortainerSettingsLDAPCheck),
+
+	rule(http.MethodGet, "/stacks", portainer.OperationPortainerStackList),
+	rule(http.MethodPost, "/stacks", portainer.OperationPortainerStackCreate),
+	rule(http.MethodGet, "/stacks/{id}", portainer.OperationPortainerStackInspect),
+	rule(http.MethodPut, "/stacks/{id}", portainer.OperationPortainerStackUpdate),
+	rule(http.MethodDelete, "/stacks/{id}", portainer.OperationPortainerStackDelete),
+	rule(http.MethodGet, "/stacks/{id}/file", portainer.OperationPortainerStackFile),
+	rule(http.MethodGet, "/stacks/{id}/versions", portainer.OperationPortainerStackInspect),
+	rule(http.MethodPost, "/stacks/{id}/migrate", portainer.OperationPortainerStackMigrate),
+	rule(http.MethodPost, "/stacks/{id}/rollback", portainer.OperationPortainerStackUpdate),
+
+	rule(http.MethodGet, "/tags", portainer.OperationPortainerTagList),
+	rule(http.MethodPost, "/tags", portainer.OperationPortainerTagCreate),
+	rule(http.MethodDelete, "/tags/{id}", portainer.OperationPortainerTagDelete),
+
+	rule(http.MethodGet, "/team_memberships", portainer.OperationPortainerTeamMembershipList),
+	rule(http.MethodPost, "/team_memberships", portainer.OperationPortainerTeamMembershipCreate),
+	rule(http.MethodPut, "/team_memberships/{id}", portainer.OperationPortainerTeamMembershipUpdate),
+	rule(http.MethodDelete, "/team_memberships/{id}", portainer.OperationPortainerTeamMembershipDelete),
+
+	rule(http.MethodGet, "/teams", portainer.OperationPortainerTeamList),
+	rule(http.MethodPost, "/teams", portainer.OperationPortainerTeamCreate),
+	rule(http.MethodGet, "/teams/{id}", portainer.OperationPortainerTeamInspect),
+	rule(http.MethodPut, "/teams/{id}", portainer.OperationPortainerTeamUpdate),
+	rule(http.MethodDelete, "/teams/{id}", portainer.OperationPortainerTeamDelete),
+	rule(http.MethodGet, "/teams/{id}/memberships", portainer.OperationPortainerTeamMemberships),
+
+	rule(http.MethodGet, "/templates", portainer.OperationPortainerTemplateList),
+	rule(http.MethodPost, "/templates", portainer.OperationPortainerTemplateCreate),
+	rule(http.MethodGet, "/templates/{id}", portainer.OperationPortainerTemplateInspect),
+	rule(http.MethodPut, "/templates/{id}", portainer.OperationPortainerTemplateUpdate),
+	rule(http.MethodDelete, "/templates/{id}", portainer.OperationPortainerTemplateDelete),
+
+	rule(http.MethodPost, "/upload/tls/{certificate}", portainer.OperationPortainerUploadTLS),
+
+	rule(http.MethodGet, "/users", portainer.OperationPortainerUserList),
+	rule(http.MethodPost, "/users", portainer.OperationPortainerUserCreate),
+	rule(http.MethodGet, "/users/{id}", portainer.OperationPortainerUserInspect),
+	rule(http.MethodPut, "/users/{id}", portainer.OperationPortainerUserUpdate),
+	rule(http.MethodDelete, "/users/{id}", portainer.OperationPortainerUserDelete),
+	rule(http.MethodGet, "/users/{id}/memberships", portainer.OperationPortainerUserMemberships),
+	rule(http.MethodPut, "/users/{id}/passwd", portainer.OperationPortainerUserUpdatePassword),
+
+	rule(http.MethodGet, "/websocket/exec", portainer.OperationPortainerWebsocketExec),
+	rule(http.MethodGet, "/websocket/attach", portainer.OperationDockerContainerAttachWebsocket),
+
+	rule(http.MethodGet, "/webhooks", portainer.OperationPortainerWebhookList),
+	rule(http.MethodPost, "/webhooks", portainer.OperationPortainerWebhookCreate),
+	rule(http.MethodDelete, "/webhooks/{id}", portainer.OperationPortainerWebhookDelete),
+}
+
+// operationAuthorization returns the authorization required to execute the API operation
+// associated to the request. An undefined operation authorization is returned when the
+// operation cannot be identified.
+func operationAuthorization(r *http.Request) portainer.Authorization {
+	requestPath := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+
+	match := endpointProxyRe.FindStringSubmatch(requestPath)
+	if match == nil {
+		return matchOperationRules(portainerOperationRules, r.Method, requestPath, portainer.OperationPortainerUndefined)
 	}
 
-	req, err := http.NewRequest("GET", client.extensionURL+"/authorized_operation", nil)
-	if err != nil {
-		return err
+	proxyPath := match[2]
+	switch match[1] {
+	case "storidge":
+		return portainer.OperationIntegrationStoridgeAdmin
+	case "docker":
+		if strings.HasPrefix(proxyPath, "/v2/") {
+			return matchOperationRules(agentOperationRules, r.Method, strings.TrimPrefix(proxyPath, "/v2"), portainer.OperationDockerAgentUndefined)
+		}
+		proxyPath = dockerAPIVersionRe.ReplaceAllString(proxyPath, "")
+		return matchOperationRules(dockerOperationRules, r.Method, proxyPath, portainer.OperationDockerUndefined)
 	}
 
-	req.Header.Set("X-RBAC-AuthorizationRequest", string(encodedAuthRequest))
-	req.Header.Set("X-PortainerExtension-License", client.licenseKey)
+	return portainer.OperationPortainerUndefined
+}
 
-	resp, err := client.httpClient.Do(req)
-	if err != nil {
-		return err
+func matchOperationRules(rules []operationRule, method, path string, undefined portainer.Authorization) portainer.Authorization {
+	for _, rule := range rules {
+		if rule.method == method && rule.pattern.MatchString(path) {
+			return rule.authorization
+		}
 	}
-	defer resp.Body.Close()
+	return undefined
+}
 
-	if resp.StatusCode != http.StatusNoContent {
+// checkAuthorization verifies that the authorizations allow the execution of the API operation
+// associated to the request.
+func checkAuthorization(r *http.Request, authorizations portainer.Authorizations) error {
+	if !authorizations[operationAuthorization(r)] {
 		return portainer.ErrAuthorizationRequired
 	}
-
 	return nil
 }
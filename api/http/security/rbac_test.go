@@ -0,0 +1,54 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestOperationAuthorization(t *testing.T) {
+	cases := []struct {
+		method        string
+		path          string
+		authorization portainer.Authorization
+	}{
+		{http.MethodGet, "/api/endpoints/1/docker/containers/json", portainer.OperationDockerContainerList},
+		{http.MethodGet, "/api/endpoints/1/docker/v1.40/containers/json", portainer.OperationDockerContainerList},
+		{http.MethodPost, "/api/endpoints/1/docker/containers/create", portainer.OperationDockerContainerCreate},
+		{http.MethodDelete, "/api/endpoints/1/docker/containers/abcdef", portainer.OperationDockerContainerDelete},
+		{http.MethodGet, "/api/endpoints/1/docker/images/portainer/portainer:latest/json", portainer.OperationDockerImageInspect},
+		{http.MethodPost, "/api/endpoints/1/docker/images/create", portainer.OperationDockerImageCreate},
+		{http.MethodGet, "/api/endpoints/1/docker/v2/browse/ls", portainer.OperationDockerAgentBrowseList},
+		{http.MethodGet, "/api/endpoints/1/docker/v2/unknown", portainer.OperationDockerAgentUndefined},
+		{http.MethodPost, "/api/endpoints/1/docker/unknown", portainer.OperationDockerUndefined},
+		{http.MethodGet, "/api/endpoints/1/storidge/profiles", portainer.OperationIntegrationStoridgeAdmin},
+		{http.MethodGet, "/api/endpoints/1", portainer.OperationPortainerEndpointInspect},
+		{http.MethodPut, "/api/stacks/3", portainer.OperationPortainerStackUpdate},
+		{http.MethodDelete, "/api/roles/5", portainer.OperationPortainerRoleDelete},
+		{http.MethodGet, "/api/alerts/rules", portainer.OperationPortainerUndefined},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, c.path, nil)
+		authorization := operationAuthorization(r)
+		if authorization != c.authorization {
+			t.Errorf("%s %s: expected %s, got %s", c.method, c.path, c.authorization, authorization)
+		}
+	}
+}
+
+func TestCheckAuthorization(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/endpoints/1/docker/containers/json", nil)
+
+	err := checkAuthorization(r, portainer.Authorizations{portainer.OperationDockerContainerList: true})
+	if err != nil {
+		t.Errorf("expected the operation to be authorized, got %s", err)
+	}
+
+	err = checkAuthorization(r, portainer.Authorizations{portainer.OperationDockerContainerInspect: true})
+	if err != portainer.ErrAuthorizationRequired {
+		t.Errorf("expected ErrAuthorizationRequired, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"time"
 
 	"github.com/portainer/portainer/api/http/handler/support"
@@ -10,14 +11,23 @@ import (
 	"github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/docker"
 	"github.com/portainer/portainer/api/http/handler"
+	"github.com/portainer/portainer/api/http/handler/alerts"
+	"github.com/portainer/portainer/api/http/handler/audit"
 	"github.com/portainer/portainer/api/http/handler/auth"
+	"github.com/portainer/portainer/api/http/handler/backup"
+	"github.com/portainer/portainer/api/http/handler/containerpolicies"
 	"github.com/portainer/portainer/api/http/handler/dockerhub"
+	"github.com/portainer/portainer/api/http/handler/edgejointokens"
+	"github.com/portainer/portainer/api/http/handler/edgestacks"
 	"github.com/portainer/portainer/api/http/handler/endpointgroups"
 	"github.com/portainer/portainer/api/http/handler/endpointproxy"
 	"github.com/portainer/portainer/api/http/handler/endpoints"
 	"github.com/portainer/portainer/api/http/handler/extensions"
 	"github.com/portainer/portainer/api/http/handler/file"
+	"github.com/portainer/portainer/api/http/handler/ldap"
+	"github.com/portainer/portainer/api/http/handler/metrics"
 	"github.com/portainer/portainer/api/http/handler/motd"
+	"github.com/portainer/portainer/api/http/handler/quotas"
 	"github.com/portainer/portainer/api/http/handler/registries"
 	"github.com/portainer/portainer/api/http/handler/resourcecontrols"
 	"github.com/portainer/portainer/api/http/handler/schedules"
@@ -34,6 +44,7 @@ import (
 	"github.com/portainer/portainer/api/http/handler/websocket"
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/security"
+	portainermetrics "github.com/portainer/portainer/api/metrics"
 
 	"net/http"
 	"path/filepath"
@@ -41,50 +52,74 @@ import (
 
 // Server implements the portainer.Server interface
 type Server struct {
-	BindAddress            string
-	AssetsPath             string
-	AuthDisabled           bool
-	EndpointManagement     bool
-	Status                 *portainer.Status
-	ReverseTunnelService   portainer.ReverseTunnelService
-	ExtensionManager       portainer.ExtensionManager
-	ComposeStackManager    portainer.ComposeStackManager
-	CryptoService          portainer.CryptoService
-	SignatureService       portainer.DigitalSignatureService
-	JobScheduler           portainer.JobScheduler
-	Snapshotter            portainer.Snapshotter
-	RoleService            portainer.RoleService
-	DockerHubService       portainer.DockerHubService
-	EndpointService        portainer.EndpointService
-	EndpointGroupService   portainer.EndpointGroupService
-	FileService            portainer.FileService
-	GitService             portainer.GitService
-	JWTService             portainer.JWTService
-	LDAPService            portainer.LDAPService
-	ExtensionService       portainer.ExtensionService
-	RegistryService        portainer.RegistryService
-	ResourceControlService portainer.ResourceControlService
-	ScheduleService        portainer.ScheduleService
-	SettingsService        portainer.SettingsService
-	StackService           portainer.StackService
-	SwarmStackManager      portainer.SwarmStackManager
-	TagService             portainer.TagService
-	TeamService            portainer.TeamService
-	TeamMembershipService  portainer.TeamMembershipService
-	TemplateService        portainer.TemplateService
-	UserService            portainer.UserService
-	WebhookService         portainer.WebhookService
-	Handler                *handler.Handler
-	SSL                    bool
-	SSLCert                string
-	SSLKey                 string
-	DockerClientFactory    *docker.ClientFactory
-	JobService             portainer.JobService
+	BindAddress             string
+	AssetsPath              string
+	AuthDisabled            bool
+	EndpointManagement      bool
+	Status                  *portainer.Status
+	ReverseTunnelService    portainer.ReverseTunnelService
+	ExtensionManager        portainer.ExtensionManager
+	ComposeStackManager     portainer.ComposeStackManager
+	CryptoService           portainer.CryptoService
+	SignatureService        portainer.DigitalSignatureService
+	JobScheduler            portainer.JobScheduler
+	AuditService            portainer.AuditService
+	BackupService           portainer.BackupService
+	SessionRecordingService portainer.SessionRecordingService
+	AlertRuleService        portainer.AlertRuleService
+	AlertNotifierService    portainer.AlertNotifierService
+	APIKeyService           portainer.APIKeyService
+	MetricsRegistry         *portainermetrics.Registry
+	MetricsToken            string
+	Snapshotter             portainer.Snapshotter
+	RoleService             portainer.RoleService
+	DockerHubService        portainer.DockerHubService
+	EndpointService         portainer.EndpointService
+	EdgeJoinTokenService    portainer.EdgeJoinTokenService
+	EdgeStackService        portainer.EdgeStackService
+	EndpointEventService    portainer.EndpointEventService
+	EndpointGroupService    portainer.EndpointGroupService
+	FileService             portainer.FileService
+	GitService              portainer.GitService
+	JWTService              portainer.JWTService
+	LDAPService             portainer.LDAPService
+	LDAPSyncReportService   portainer.LDAPSyncReportService
+	OIDCService             portainer.OIDCService
+	TOTPService             portainer.TOTPService
+	ExtensionService        portainer.ExtensionService
+	RegistryService         portainer.RegistryService
+	ContainerPolicyService  portainer.ContainerPolicyService
+	QuotaService            portainer.QuotaService
+	QuotaChecker            *docker.QuotaChecker
+	ResourceControlService  portainer.ResourceControlService
+	ScheduleService         portainer.ScheduleService
+	SettingsService         portainer.SettingsService
+	SnapshotHistoryService  portainer.SnapshotHistoryService
+	StackService            portainer.StackService
+	StackDeployer           portainer.StackDeployer
+	StackVersionManager     portainer.StackVersionManager
+	SwarmStackManager       portainer.SwarmStackManager
+	TagService              portainer.TagService
+	TeamService             portainer.TeamService
+	TeamMembershipService   portainer.TeamMembershipService
+	TemplateService         portainer.TemplateService
+	UserService             portainer.UserService
+	WebhookService          portainer.WebhookService
+	Handler                 *handler.Handler
+	SSL                     bool
+	SSLCert                 string
+	SSLKey                  string
+	DockerClientFactory     *docker.ClientFactory
+	JobService              portainer.JobService
 }
 
 // Start starts the HTTP server
 func (server *Server) Start() error {
 	proxyManagerParameters := &proxy.ManagerParams{
+		ContainerPolicyService: server.ContainerPolicyService,
+		AuditService:           server.AuditService,
+		EndpointGroupService:   server.EndpointGroupService,
+		QuotaChecker:           server.QuotaChecker,
 		ResourceControlService: server.ResourceControlService,
 		UserService:            server.UserService,
 		TeamService:            server.TeamService,
@@ -116,18 +151,51 @@ func (server *Server) Start() error {
 		EndpointService:       server.EndpointService,
 		EndpointGroupService:  server.EndpointGroupService,
 		ExtensionService:      server.ExtensionService,
-		RBACExtensionURL:      proxyManager.GetExtensionURL(portainer.RBACExtension),
+		AuditService:          server.AuditService,
+		APIKeyService:         server.APIKeyService,
+		CryptoService:         server.CryptoService,
 		AuthDisabled:          server.AuthDisabled,
 	}
 	requestBouncer := security.NewRequestBouncer(requestBouncerParameters)
 
 	rateLimiter := security.NewRateLimiter(10, 1*time.Second, 1*time.Hour)
 
+	var containerPolicyHandler = containerpolicies.NewHandler(requestBouncer)
+	containerPolicyHandler.ContainerPolicyService = server.ContainerPolicyService
+	containerPolicyHandler.EndpointGroupService = server.EndpointGroupService
+
+	var quotaHandler = quotas.NewHandler(requestBouncer)
+	quotaHandler.QuotaService = server.QuotaService
+	quotaHandler.UserService = server.UserService
+	quotaHandler.TeamService = server.TeamService
+	quotaHandler.EndpointService = server.EndpointService
+	quotaHandler.EndpointGroupService = server.EndpointGroupService
+
+	var alertHandler = alerts.NewHandler(requestBouncer)
+	alertHandler.AlertRuleService = server.AlertRuleService
+	alertHandler.AlertNotifierService = server.AlertNotifierService
+
+	var auditHandler = audit.NewHandler(requestBouncer)
+	auditHandler.AuditService = server.AuditService
+
+	var backupHandler = backup.NewHandler(requestBouncer)
+	backupHandler.BackupService = server.BackupService
+	shutdownComplete := make(chan struct{})
+	httpServer := &http.Server{Addr: server.BindAddress}
+	backupHandler.ShutdownTrigger = func() {
+		go func() {
+			httpServer.Shutdown(context.Background())
+			close(shutdownComplete)
+		}()
+	}
+
 	var authHandler = auth.NewHandler(requestBouncer, rateLimiter, server.AuthDisabled)
 	authHandler.UserService = server.UserService
 	authHandler.CryptoService = server.CryptoService
 	authHandler.JWTService = server.JWTService
 	authHandler.LDAPService = server.LDAPService
+	authHandler.OIDCService = server.OIDCService
+	authHandler.TOTPService = server.TOTPService
 	authHandler.SettingsService = server.SettingsService
 	authHandler.TeamService = server.TeamService
 	authHandler.TeamMembershipService = server.TeamMembershipService
@@ -140,19 +208,39 @@ func (server *Server) Start() error {
 
 	var roleHandler = roles.NewHandler(requestBouncer)
 	roleHandler.RoleService = server.RoleService
+	roleHandler.AuthorizationService = authorizationService
+	roleHandler.EndpointService = server.EndpointService
+	roleHandler.EndpointGroupService = server.EndpointGroupService
 
 	var dockerHubHandler = dockerhub.NewHandler(requestBouncer)
 	dockerHubHandler.DockerHubService = server.DockerHubService
 
-	var endpointHandler = endpoints.NewHandler(requestBouncer, server.EndpointManagement)
+	var edgeJoinTokenHandler = edgejointokens.NewHandler(requestBouncer)
+	edgeJoinTokenHandler.EdgeJoinTokenService = server.EdgeJoinTokenService
+	edgeJoinTokenHandler.EndpointService = server.EndpointService
+	edgeJoinTokenHandler.EndpointGroupService = server.EndpointGroupService
+	edgeJoinTokenHandler.CryptoService = server.CryptoService
+
+	var edgeStackHandler = edgestacks.NewHandler(requestBouncer)
+	edgeStackHandler.EdgeStackService = server.EdgeStackService
+	edgeStackHandler.EndpointService = server.EndpointService
+	edgeStackHandler.EndpointGroupService = server.EndpointGroupService
+	edgeStackHandler.FileService = server.FileService
+
+	var endpointHandler = endpoints.NewHandler(requestBouncer, rateLimiter, server.EndpointManagement)
 	endpointHandler.EndpointService = server.EndpointService
 	endpointHandler.EndpointGroupService = server.EndpointGroupService
+	endpointHandler.EndpointEventService = server.EndpointEventService
+	endpointHandler.EdgeJoinTokenService = server.EdgeJoinTokenService
+	endpointHandler.EdgeStackService = server.EdgeStackService
+	endpointHandler.CryptoService = server.CryptoService
 	endpointHandler.FileService = server.FileService
 	endpointHandler.ProxyManager = proxyManager
 	endpointHandler.Snapshotter = server.Snapshotter
 	endpointHandler.JobService = server.JobService
 	endpointHandler.ReverseTunnelService = server.ReverseTunnelService
 	endpointHandler.SettingsService = server.SettingsService
+	endpointHandler.SnapshotHistoryService = server.SnapshotHistoryService
 	endpointHandler.AuthorizationService = authorizationService
 
 	var endpointGroupHandler = endpointgroups.NewHandler(requestBouncer)
@@ -168,8 +256,16 @@ func (server *Server) Start() error {
 
 	var fileHandler = file.NewHandler(filepath.Join(server.AssetsPath, "public"))
 
+	var metricsHandler = metrics.NewHandler(requestBouncer, server.MetricsToken)
+	metricsHandler.EndpointService = server.EndpointService
+	metricsHandler.ReverseTunnelService = server.ReverseTunnelService
+	metricsHandler.MetricsRegistry = server.MetricsRegistry
+
 	var motdHandler = motd.NewHandler(requestBouncer)
 
+	var ldapHandler = ldap.NewHandler(requestBouncer)
+	ldapHandler.LDAPSyncReportService = server.LDAPSyncReportService
+
 	var extensionHandler = extensions.NewHandler(requestBouncer)
 	extensionHandler.ExtensionService = server.ExtensionService
 	extensionHandler.ExtensionManager = server.ExtensionManager
@@ -186,6 +282,7 @@ func (server *Server) Start() error {
 
 	var resourceControlHandler = resourcecontrols.NewHandler(requestBouncer)
 	resourceControlHandler.ResourceControlService = server.ResourceControlService
+	resourceControlHandler.QuotaChecker = server.QuotaChecker
 
 	var schedulesHandler = schedules.NewHandler(requestBouncer)
 	schedulesHandler.ScheduleService = server.ScheduleService
@@ -205,20 +302,27 @@ func (server *Server) Start() error {
 	settingsHandler.RoleService = server.RoleService
 	settingsHandler.ExtensionService = server.ExtensionService
 	settingsHandler.AuthorizationService = authorizationService
+	settingsHandler.JWTService = server.JWTService
 
 	var stackHandler = stacks.NewHandler(requestBouncer)
 	stackHandler.FileService = server.FileService
 	stackHandler.StackService = server.StackService
 	stackHandler.EndpointService = server.EndpointService
 	stackHandler.ResourceControlService = server.ResourceControlService
+	stackHandler.ContainerPolicyService = server.ContainerPolicyService
+	stackHandler.EndpointGroupService = server.EndpointGroupService
+	stackHandler.AuditService = server.AuditService
 	stackHandler.SwarmStackManager = server.SwarmStackManager
 	stackHandler.ComposeStackManager = server.ComposeStackManager
+	stackHandler.StackDeployer = server.StackDeployer
+	stackHandler.StackVersionManager = server.StackVersionManager
 	stackHandler.GitService = server.GitService
 	stackHandler.RegistryService = server.RegistryService
 	stackHandler.DockerHubService = server.DockerHubService
 	stackHandler.SettingsService = server.SettingsService
 	stackHandler.UserService = server.UserService
 	stackHandler.ExtensionService = server.ExtensionService
+	stackHandler.QuotaChecker = server.QuotaChecker
 
 	var tagHandler = tags.NewHandler(requestBouncer)
 	tagHandler.TagService = server.TagService
@@ -251,27 +355,56 @@ func (server *Server) Start() error {
 	userHandler.ResourceControlService = server.ResourceControlService
 	userHandler.SettingsService = server.SettingsService
 	userHandler.AuthorizationService = authorizationService
+	userHandler.APIKeyService = server.APIKeyService
+	userHandler.JWTService = server.JWTService
+	userHandler.TOTPService = server.TOTPService
 
 	var websocketHandler = websocket.NewHandler(requestBouncer)
 	websocketHandler.EndpointService = server.EndpointService
 	websocketHandler.SignatureService = server.SignatureService
 	websocketHandler.ReverseTunnelService = server.ReverseTunnelService
+	websocketHandler.SettingsService = server.SettingsService
+	websocketHandler.SessionRecordingService = server.SessionRecordingService
+	websocketHandler.FileService = server.FileService
+	websocketHandler.DockerClientFactory = server.DockerClientFactory
 
 	var webhookHandler = webhooks.NewHandler(requestBouncer)
 	webhookHandler.WebhookService = server.WebhookService
 	webhookHandler.EndpointService = server.EndpointService
+	webhookHandler.EndpointGroupService = server.EndpointGroupService
+	webhookHandler.AuditService = server.AuditService
+	webhookHandler.ResourceControlService = server.ResourceControlService
+	webhookHandler.UserService = server.UserService
+	webhookHandler.ReverseTunnelService = server.ReverseTunnelService
+	webhookHandler.SettingsService = server.SettingsService
+	webhookHandler.StackService = server.StackService
+	webhookHandler.RegistryService = server.RegistryService
+	webhookHandler.DockerHubService = server.DockerHubService
+	webhookHandler.GitService = server.GitService
+	webhookHandler.FileService = server.FileService
+	webhookHandler.StackDeployer = server.StackDeployer
+	webhookHandler.StackVersionManager = server.StackVersionManager
 	webhookHandler.DockerClientFactory = server.DockerClientFactory
 
 	server.Handler = &handler.Handler{
 		RoleHandler:            roleHandler,
+		AlertHandler:           alertHandler,
+		AuditHandler:           auditHandler,
 		AuthHandler:            authHandler,
+		BackupHandler:          backupHandler,
+		ContainerPolicyHandler: containerPolicyHandler,
 		DockerHubHandler:       dockerHubHandler,
+		EdgeJoinTokenHandler:   edgeJoinTokenHandler,
+		EdgeStackHandler:       edgeStackHandler,
 		EndpointGroupHandler:   endpointGroupHandler,
 		EndpointHandler:        endpointHandler,
 		EndpointProxyHandler:   endpointProxyHandler,
 		FileHandler:            fileHandler,
+		MetricsHandler:         metricsHandler,
 		MOTDHandler:            motdHandler,
 		ExtensionHandler:       extensionHandler,
+		LDAPHandler:            ldapHandler,
+		QuotaHandler:           quotaHandler,
 		RegistryHandler:        registryHandler,
 		ResourceControlHandler: resourceControlHandler,
 		SettingsHandler:        settingsHandler,
@@ -289,8 +422,18 @@ func (server *Server) Start() error {
 		SchedulesHanlder:       schedulesHandler,
 	}
 
+	httpServer.Handler = server.MetricsRegistry.InstrumentHandler(server.Handler)
+
+	var err error
 	if server.SSL {
-		return http.ListenAndServeTLS(server.BindAddress, server.SSLCert, server.SSLKey, server.Handler)
+		err = httpServer.ListenAndServeTLS(server.SSLCert, server.SSLKey)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+
+	if err == http.ErrServerClosed {
+		<-shutdownComplete
+		return portainer.ErrRestartRequired
 	}
-	return http.ListenAndServe(server.BindAddress, server.Handler)
+	return err
 }
@@ -3,7 +3,9 @@ package jwt
 import (
 	"github.com/portainer/portainer/api"
 
+	"encoding/base64"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -12,37 +14,85 @@ import (
 
 // Service represents a service for managing JWT tokens.
 type Service struct {
-	secret []byte
+	secret              []byte
+	sessionService      portainer.SessionService
+	userSessionDuration time.Duration
+	mu                  sync.RWMutex
 }
 
 type claims struct {
 	UserID   int    `json:"id"`
 	Username string `json:"username"`
 	Role     int    `json:"role"`
+	// IssuedAtNano is the issue date of the token in nanoseconds, used to compare it
+	// with the revocation date of the user sessions.
+	IssuedAtNano int64 `json:"iatn"`
 	jwt.StandardClaims
 }
 
-// NewService initializes a new service. It will generate a random key that will be used to sign JWT tokens.
-func NewService() (*Service, error) {
-	secret := securecookie.GenerateRandomKey(32)
+// NewService initializes a new service. The key used to sign JWT tokens is either the specified secret
+// or the key persisted via the session service. A random key is generated and persisted if none exists yet.
+func NewService(secret []byte, sessionService portainer.SessionService, userSessionDuration string) (*Service, error) {
+	duration, err := time.ParseDuration(userSessionDuration)
+	if err != nil {
+		return nil, err
+	}
+
 	if secret == nil {
-		return nil, portainer.ErrSecretGeneration
+		secret, err = sessionService.SigningKey()
+		if err == portainer.ErrObjectNotFound {
+			secret = securecookie.GenerateRandomKey(32)
+			if secret == nil {
+				return nil, portainer.ErrSecretGeneration
+			}
+
+			err = sessionService.UpdateSigningKey(secret)
+			if err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
 	}
+
 	service := &Service{
-		secret,
+		secret:              secret,
+		sessionService:      sessionService,
+		userSessionDuration: duration,
 	}
 	return service, nil
 }
 
+// SetUserSessionDuration updates the lifetime of the tokens generated by the service.
+func (service *Service) SetUserSessionDuration(duration time.Duration) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.userSessionDuration = duration
+}
+
+func (service *Service) sessionDuration() time.Duration {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+	return service.userSessionDuration
+}
+
 // GenerateToken generates a new JWT token.
 func (service *Service) GenerateToken(data *portainer.TokenData) (string, error) {
-	expireToken := time.Now().Add(time.Hour * 8).Unix()
+	tokenID := securecookie.GenerateRandomKey(16)
+	if tokenID == nil {
+		return "", portainer.ErrSecretGeneration
+	}
+
+	now := time.Now()
 	cl := claims{
-		UserID:   int(data.ID),
-		Username: data.Username,
-		Role:     int(data.Role),
+		UserID:       int(data.ID),
+		Username:     data.Username,
+		Role:         int(data.Role),
+		IssuedAtNano: now.UnixNano(),
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: expireToken,
+			Id:        base64.RawURLEncoding.EncodeToString(tokenID),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(service.sessionDuration()).Unix(),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, cl)
@@ -55,8 +105,64 @@ func (service *Service) GenerateToken(data *portainer.TokenData) (string, error)
 	return signedToken, nil
 }
 
-// ParseAndVerifyToken parses a JWT token and verify its validity. It returns an error if token is invalid.
+// ParseAndVerifyToken parses a JWT token and verify its validity. It returns an error if token is invalid
+// or if it has been revoked.
 func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData, error) {
+	cl, err := service.parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = service.sessionService.RevokedToken(cl.Id)
+	if err == nil {
+		return nil, portainer.ErrRevokedJWTToken
+	} else if err != portainer.ErrObjectNotFound {
+		return nil, err
+	}
+
+	revocationDate, err := service.sessionService.UserSessionsRevocationDate(portainer.UserID(cl.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if revocationDate != 0 && cl.IssuedAtNano < revocationDate {
+		return nil, portainer.ErrRevokedJWTToken
+	}
+
+	tokenData := &portainer.TokenData{
+		ID:       portainer.UserID(cl.UserID),
+		Username: cl.Username,
+		Role:     portainer.UserRole(cl.Role),
+	}
+	return tokenData, nil
+}
+
+// RevokeToken blacklists a token until its expiry.
+func (service *Service) RevokeToken(token string) error {
+	cl, err := service.parseToken(token)
+	if err != nil {
+		return err
+	}
+
+	err = service.sessionService.DeleteExpiredRevokedTokens(time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	revokedToken := &portainer.RevokedToken{
+		ID:        cl.Id,
+		ExpiresAt: cl.ExpiresAt,
+	}
+
+	return service.sessionService.CreateRevokedToken(revokedToken)
+}
+
+// RevokeUserSessions revokes all the tokens generated for a user so far.
+func (service *Service) RevokeUserSessions(userID portainer.UserID) error {
+	return service.sessionService.RevokeUserSessions(userID, time.Now().UnixNano())
+}
+
+func (service *Service) parseToken(token string) (*claims, error) {
 	parsedToken, err := jwt.ParseWithClaims(token, &claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			msg := fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
@@ -65,13 +171,8 @@ func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData,
 		return service.secret, nil
 	})
 	if err == nil && parsedToken != nil {
-		if cl, ok := parsedToken.Claims.(*claims); ok && parsedToken.Valid {
-			tokenData := &portainer.TokenData{
-				ID:       portainer.UserID(cl.UserID),
-				Username: cl.Username,
-				Role:     portainer.UserRole(cl.Role),
-			}
-			return tokenData, nil
+		if cl, ok := parsedToken.Claims.(*claims); ok && parsedToken.Valid && cl.Id != "" {
+			return cl, nil
 		}
 	}
 
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+// testSessionService stores the revoked tokens and the session revocation dates in memory.
+type testSessionService struct {
+	portainer.SessionService
+	revokedTokens   map[string]portainer.RevokedToken
+	revocationDates map[portainer.UserID]int64
+}
+
+func newTestSessionService() *testSessionService {
+	return &testSessionService{
+		revokedTokens:   make(map[string]portainer.RevokedToken),
+		revocationDates: make(map[portainer.UserID]int64),
+	}
+}
+
+func (service *testSessionService) RevokedToken(ID string) (*portainer.RevokedToken, error) {
+	token, ok := service.revokedTokens[ID]
+	if !ok {
+		return nil, portainer.ErrObjectNotFound
+	}
+	return &token, nil
+}
+
+func (service *testSessionService) CreateRevokedToken(token *portainer.RevokedToken) error {
+	service.revokedTokens[token.ID] = *token
+	return nil
+}
+
+func (service *testSessionService) DeleteExpiredRevokedTokens(timestamp int64) error {
+	for ID, token := range service.revokedTokens {
+		if token.ExpiresAt < timestamp {
+			delete(service.revokedTokens, ID)
+		}
+	}
+	return nil
+}
+
+func (service *testSessionService) UserSessionsRevocationDate(userID portainer.UserID) (int64, error) {
+	return service.revocationDates[userID], nil
+}
+
+func (service *testSessionService) RevokeUserSessions(userID portainer.UserID, timestamp int64) error {
+	service.revocationDates[userID] = timestamp
+	return nil
+}
+
+func newTestService(t *testing.T) *Service {
+	service, err := NewService([]byte("secret"), newTestSessionService(), "8h")
+	if err != nil {
+		t.Fatalf("unable to create the JWT service: %s", err)
+	}
+	return service
+}
+
+func generateTestToken(t *testing.T, service *Service, userID portainer.UserID) string {
+	token, err := service.GenerateToken(&portainer.TokenData{ID: userID, Username: "user", Role: portainer.StandardUserRole})
+	if err != nil {
+		t.Fatalf("unable to generate token: %s", err)
+	}
+	return token
+}
+
+func TestRevokeToken(t *testing.T) {
+	service := newTestService(t)
+
+	revoked := generateTestToken(t, service, 1)
+	other := generateTestToken(t, service, 1)
+
+	err := service.RevokeToken(revoked)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = service.ParseAndVerifyToken(revoked)
+	if err != portainer.ErrRevokedJWTToken {
+		t.Errorf("expected the revoked token to be rejected, got %v", err)
+	}
+
+	_, err = service.ParseAndVerifyToken(other)
+	if err != nil {
+		t.Errorf("expected the other session of the user to remain valid, got %v", err)
+	}
+
+	err = service.RevokeToken("invalid")
+	if err != portainer.ErrInvalidJWTToken {
+		t.Errorf("expected an invalid token not to be revoked, got %v", err)
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	service := newTestService(t)
+
+	first := generateTestToken(t, service, 1)
+	second := generateTestToken(t, service, 1)
+	otherUser := generateTestToken(t, service, 2)
+
+	err := service.RevokeUserSessions(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, token := range []string{first, second} {
+		_, err = service.ParseAndVerifyToken(token)
+		if err != portainer.ErrRevokedJWTToken {
+			t.Errorf("expected the sessions of the user to be revoked, got %v", err)
+		}
+	}
+
+	_, err = service.ParseAndVerifyToken(otherUser)
+	if err != nil {
+		t.Errorf("expected the sessions of another user to remain valid, got %v", err)
+	}
+
+	renewed := generateTestToken(t, service, 1)
+	_, err = service.ParseAndVerifyToken(renewed)
+	if err != nil {
+		t.Errorf("expected a session created after the revocation to be valid, got %v", err)
+	}
+}
@@ -11,9 +11,11 @@ import (
 )
 
 const (
-	// ErrUserNotFound defines an error raised when the user is not found via LDAP search
-	// or that too many entries (> 1) are returned.
-	ErrUserNotFound = portainer.Error("User not found or too many entries returned")
+	// ErrUserNotFound defines an error raised when the user is not found via LDAP search.
+	ErrUserNotFound = portainer.ErrLDAPUserNotFound
+	// ErrTooManyEntries defines an error raised when too many entries (> 1) are returned
+	// by the LDAP search of a user.
+	ErrTooManyEntries = portainer.ErrLDAPTooManyEntries
 )
 
 // Service represents a service used to authenticate users against a LDAP/AD.
@@ -22,6 +24,7 @@ type Service struct{}
 func searchUser(username string, conn *ldap.Conn, settings []portainer.LDAPSearchSettings) (string, error) {
 	var userDN string
 	found := false
+	tooManyEntries := false
 	usernameEscaped := ldap.EscapeFilter(username)
 
 	for _, searchSettings := range settings {
@@ -44,10 +47,14 @@ func searchUser(username string, conn *ldap.Conn, settings []portainer.LDAPSearc
 			found = true
 			userDN = sr.Entries[0].DN
 			break
+		} else if len(sr.Entries) > 1 {
+			tooManyEntries = true
 		}
 	}
 
-	if !found {
+	if !found && tooManyEntries {
+		return "", ErrTooManyEntries
+	} else if !found {
 		return "", ErrUserNotFound
 	}
 
@@ -156,7 +163,7 @@ func getGroups(userDN string, conn *ldap.Conn, settings []portainer.LDAPGroupSea
 
 		for _, entry := range sr.Entries {
 			for _, attr := range entry.Attributes {
-				groups = append(groups, attr.Values[0])
+				groups = append(groups, attr.Values...)
 			}
 		}
 	}
@@ -164,6 +171,49 @@ func getGroups(userDN string, conn *ldap.Conn, settings []portainer.LDAPGroupSea
 	return groups
 }
 
+// GetGroups is used to retrieve the names of all the groups matching the group search settings from LDAP/AD.
+func (*Service) GetGroups(settings *portainer.LDAPSettings) ([]string, error) {
+	connection, err := createConnection(settings)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	err = connection.Bind(settings.ReaderDN, settings.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0)
+	for _, searchSettings := range settings.GroupSearchSettings {
+		filter := searchSettings.GroupFilter
+		if filter == "" {
+			filter = "(objectClass=*)"
+		}
+
+		searchRequest := ldap.NewSearchRequest(
+			searchSettings.GroupBaseDN,
+			ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+			filter,
+			[]string{"cn"},
+			nil,
+		)
+
+		sr, err := connection.Search(searchRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range sr.Entries {
+			for _, attr := range entry.Attributes {
+				groups = append(groups, attr.Values...)
+			}
+		}
+	}
+
+	return groups, nil
+}
+
 // TestConnectivity is used to test a connection against the LDAP server using the credentials
 // specified in the LDAPSettings.
 func (*Service) TestConnectivity(settings *portainer.LDAPSettings) error {
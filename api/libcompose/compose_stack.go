@@ -60,41 +60,22 @@ func (manager *ComposeStackManager) createClient(endpoint *portainer.Endpoint) (
 
 // Up will deploy a compose stack (equivalent of docker-compose up)
 func (manager *ComposeStackManager) Up(stack *portainer.Stack, endpoint *portainer.Endpoint) error {
-
-	clientFactory, err := manager.createClient(endpoint)
+	proj, err := manager.createProject(stack, endpoint)
 	if err != nil {
 		return err
 	}
 
-	env := make(map[string]string)
-	for _, envvar := range stack.Env {
-		env[envvar.Name] = envvar.Value
-	}
+	return proj.Up(context.Background(), options.Up{})
+}
 
-	composeFilePath := path.Join(stack.ProjectPath, stack.EntryPoint)
-	proj, err := docker.NewProject(&ctx.Context{
-		ConfigDir: manager.dataPath,
-		Context: project.Context{
-			ComposeFiles: []string{composeFilePath},
-			EnvironmentLookup: &lookup.ComposableEnvLookup{
-				Lookups: []config.EnvironmentLookup{
-					&lookup.EnvfileLookup{
-						Path: filepath.Join(stack.ProjectPath, ".env"),
-					},
-					&lookup.MapLookup{
-						Vars: env,
-					},
-				},
-			},
-			ProjectName: stack.Name,
-		},
-		ClientFactory: clientFactory,
-	}, nil)
+// Pull will pull the latest version of the images used by a compose stack (equivalent of docker-compose pull)
+func (manager *ComposeStackManager) Pull(stack *portainer.Stack, endpoint *portainer.Endpoint) error {
+	proj, err := manager.createProject(stack, endpoint)
 	if err != nil {
 		return err
 	}
 
-	return proj.Up(context.Background(), options.Up{})
+	return proj.Pull(context.Background())
 }
 
 // Down will shutdown a compose stack (equivalent of docker-compose down)
@@ -118,3 +99,35 @@ func (manager *ComposeStackManager) Down(stack *portainer.Stack, endpoint *porta
 
 	return proj.Down(context.Background(), options.Down{RemoveVolume: false, RemoveOrphans: true})
 }
+
+func (manager *ComposeStackManager) createProject(stack *portainer.Stack, endpoint *portainer.Endpoint) (project.APIProject, error) {
+	clientFactory, err := manager.createClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	for _, envvar := range stack.Env {
+		env[envvar.Name] = envvar.Value
+	}
+
+	composeFilePath := path.Join(stack.ProjectPath, stack.EntryPoint)
+	return docker.NewProject(&ctx.Context{
+		ConfigDir: manager.dataPath,
+		Context: project.Context{
+			ComposeFiles: []string{composeFilePath},
+			EnvironmentLookup: &lookup.ComposableEnvLookup{
+				Lookups: []config.EnvironmentLookup{
+					&lookup.EnvfileLookup{
+						Path: filepath.Join(stack.ProjectPath, ".env"),
+					},
+					&lookup.MapLookup{
+						Vars: env,
+					},
+				},
+			},
+			ProjectName: stack.Name,
+		},
+		ClientFactory: clientFactory,
+	}, nil)
+}
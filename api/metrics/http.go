@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// apiHandlers contains the names of the API handlers used as the handler label of the
+// HTTP request metrics. Any other path is reported as otherHandler to keep the number
+// of series bounded.
+var apiHandlers = map[string]bool{
+	"alerts":             true,
+	"audit":              true,
+	"auth":               true,
+	"backup":             true,
+	"container_policies": true,
+	"dockerhub":          true,
+	"edge_join_tokens":   true,
+	"edge_stacks":        true,
+	"endpoint_groups":    true,
+	"endpoints":          true,
+	"extensions":         true,
+	"ldap":               true,
+	"metrics":            true,
+	"motd":               true,
+	"quotas":             true,
+	"registries":         true,
+	"resource_controls":  true,
+	"restore":            true,
+	"roles":              true,
+	"schedules":          true,
+	"settings":           true,
+	"stacks":             true,
+	"status":             true,
+	"support":            true,
+	"tags":               true,
+	"team_memberships":   true,
+	"teams":              true,
+	"templates":          true,
+	"upload":             true,
+	"users":              true,
+	"webhooks":           true,
+	"websocket":          true,
+}
+
+const otherHandler = "other"
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+func (w *statusResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// InstrumentHandler records the count and the duration of the requests served by a handler.
+// Requests are grouped by API handler, requests proxied to an endpoint are also counted per endpoint.
+func (registry *Registry) InstrumentHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		writer := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(writer, r)
+
+		handler, endpointID := handlerName(r.URL.Path)
+		if endpointID != 0 {
+			registry.IncProxyRequest(endpointID)
+		}
+
+		registry.ObserveHTTPRequest(handler, r.Method, writer.status, time.Since(start))
+	})
+}
+
+// handlerName returns the name of the API handler serving a path, e.g. stacks for /api/stacks/1.
+// Requests proxied to an endpoint are associated to the endpoint_proxy handler, the identifier
+// of the endpoint is returned as well in that case. Unknown API paths are associated to the other handler.
+func handlerName(path string) (string, portainer.EndpointID) {
+	if !strings.HasPrefix(path, "/api/") {
+		return "static", 0
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	if segments[0] == "endpoints" && len(segments) > 2 {
+		switch segments[2] {
+		case "docker", "storidge", "azure":
+			endpointID, err := strconv.Atoi(segments[1])
+			if err == nil {
+				return "endpoint_proxy", portainer.EndpointID(endpointID)
+			}
+		}
+	}
+
+	if !apiHandlers[segments[0]] {
+		return otherHandler, 0
+	}
+
+	return segments[0], 0
+}
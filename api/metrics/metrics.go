@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/portainer/portainer/api"
+)
+
+var tunnelStatuses = []string{portainer.EdgeAgentIdle, portainer.EdgeAgentManagementRequired, portainer.EdgeAgentActive}
+
+// Write writes all the metrics using the Prometheus text exposition format. The metrics collected
+// by the registry are completed with the state of each endpoint: the counters of their latest snapshot
+// and, for Edge endpoints, the status of their tunnel.
+func (registry *Registry) Write(w io.Writer, endpoints []portainer.Endpoint, reverseTunnelService portainer.ReverseTunnelService) error {
+	writer := newTextWriter(w)
+
+	registry.write(writer)
+	writeEndpointMetrics(writer, endpoints)
+	writeTunnelMetrics(writer, endpoints, reverseTunnelService)
+
+	return writer.flush()
+}
+
+func writeEndpointMetrics(w *textWriter, endpoints []portainer.Endpoint) {
+	gauges := []struct {
+		name  string
+		help  string
+		value func(snapshot *portainer.Snapshot) float64
+	}{
+		{"portainer_endpoint_running_containers", "Number of running containers on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.RunningContainerCount) }},
+		{"portainer_endpoint_stopped_containers", "Number of stopped containers on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.StoppedContainerCount) }},
+		{"portainer_endpoint_images", "Number of images on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.ImageCount) }},
+		{"portainer_endpoint_volumes", "Number of volumes on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.VolumeCount) }},
+		{"portainer_endpoint_services", "Number of Swarm services on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.ServiceCount) }},
+		{"portainer_endpoint_stacks", "Number of stacks on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.StackCount) }},
+		{"portainer_endpoint_cpus", "Number of CPUs available on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.TotalCPU) }},
+		{"portainer_endpoint_memory_bytes", "Memory available on the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.TotalMemory) }},
+		{"portainer_endpoint_snapshot_timestamp_seconds", "Time of the latest snapshot of the endpoint.", func(s *portainer.Snapshot) float64 { return float64(s.Time) }},
+	}
+
+	w.header("portainer_endpoint_up", "Whether the endpoint was reachable during its latest snapshot.", "gauge")
+	for _, endpoint := range endpoints {
+		up := 0.0
+		if endpoint.Status == portainer.EndpointStatusUp {
+			up = 1
+		}
+		w.sample("portainer_endpoint_up", endpointInfoLabels(&endpoint), up)
+	}
+
+	for _, gauge := range gauges {
+		w.header(gauge.name, gauge.help, "gauge")
+		for _, endpoint := range endpoints {
+			if len(endpoint.Snapshots) == 0 {
+				continue
+			}
+			snapshot := endpoint.Snapshots[len(endpoint.Snapshots)-1]
+			w.sample(gauge.name, endpointInfoLabels(&endpoint), gauge.value(&snapshot))
+		}
+	}
+}
+
+func writeTunnelMetrics(w *textWriter, endpoints []portainer.Endpoint, reverseTunnelService portainer.ReverseTunnelService) {
+	w.header("portainer_edge_tunnel_status", "Status of the tunnel associated to each Edge endpoint.", "gauge")
+	for _, endpoint := range endpoints {
+		if endpoint.Type != portainer.EdgeAgentEnvironment {
+			continue
+		}
+
+		tunnel := reverseTunnelService.GetTunnelDetails(endpoint.ID)
+		for _, status := range tunnelStatuses {
+			value := 0.0
+			if tunnel.Status == status {
+				value = 1
+			}
+			w.sample("portainer_edge_tunnel_status", append(endpointInfoLabels(&endpoint), "status", status), value)
+		}
+	}
+}
+
+func endpointInfoLabels(endpoint *portainer.Endpoint) labels {
+	return labels{"endpoint_id", strconv.Itoa(int(endpoint.ID)), "endpoint_name", endpoint.Name}
+}
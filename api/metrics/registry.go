@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// durationBuckets are the upper bounds (in seconds) of the buckets used for duration histograms.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type (
+	// Registry keeps track of the metrics collected by the Portainer instance during its lifetime.
+	Registry struct {
+		mu                sync.Mutex
+		httpRequests      map[httpRequestKey]uint64
+		httpDurations     map[string]*histogram
+		proxyRequests     map[portainer.EndpointID]uint64
+		snapshotDurations *histogram
+		snapshotFailures  map[portainer.EndpointID]uint64
+		lastSnapshots     map[portainer.EndpointID]float64
+	}
+
+	httpRequestKey struct {
+		handler string
+		method  string
+		status  int
+	}
+
+	histogram struct {
+		counts []uint64
+		count  uint64
+		sum    float64
+	}
+)
+
+// NewRegistry creates a new metrics registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		httpRequests:      make(map[httpRequestKey]uint64),
+		httpDurations:     make(map[string]*histogram),
+		proxyRequests:     make(map[portainer.EndpointID]uint64),
+		snapshotDurations: newHistogram(),
+		snapshotFailures:  make(map[portainer.EndpointID]uint64),
+		lastSnapshots:     make(map[portainer.EndpointID]float64),
+	}
+}
+
+func newHistogram() *histogram {
+	return &histogram{
+		counts: make([]uint64, len(durationBuckets)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	for idx, bound := range durationBuckets {
+		if value <= bound {
+			h.counts[idx]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// ObserveHTTPRequest records a request served by the API.
+func (registry *Registry) ObserveHTTPRequest(handler, method string, status int, duration time.Duration) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.httpRequests[httpRequestKey{handler: handler, method: method, status: status}]++
+
+	durations, ok := registry.httpDurations[handler]
+	if !ok {
+		durations = newHistogram()
+		registry.httpDurations[handler] = durations
+	}
+	durations.observe(duration.Seconds())
+}
+
+// IncProxyRequest records a request proxied to an endpoint.
+func (registry *Registry) IncProxyRequest(endpointID portainer.EndpointID) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.proxyRequests[endpointID]++
+}
+
+// ObserveSnapshot records the duration and the result of an endpoint snapshot.
+func (registry *Registry) ObserveSnapshot(endpointID portainer.EndpointID, duration time.Duration, err error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.snapshotDurations.observe(duration.Seconds())
+	registry.lastSnapshots[endpointID] = duration.Seconds()
+	if err != nil {
+		registry.snapshotFailures[endpointID]++
+	}
+}
+
+func (registry *Registry) write(w *textWriter) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	w.header("portainer_http_requests_total", "Total number of HTTP requests served by the API.", "counter")
+	requestKeys := make([]httpRequestKey, 0, len(registry.httpRequests))
+	for key := range registry.httpRequests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		if requestKeys[i].handler != requestKeys[j].handler {
+			return requestKeys[i].handler < requestKeys[j].handler
+		}
+		if requestKeys[i].method != requestKeys[j].method {
+			return requestKeys[i].method < requestKeys[j].method
+		}
+		return requestKeys[i].status < requestKeys[j].status
+	})
+	for _, key := range requestKeys {
+		w.sample("portainer_http_requests_total", labels{"handler", key.handler, "method", key.method, "code", strconv.Itoa(key.status)}, float64(registry.httpRequests[key]))
+	}
+
+	w.header("portainer_http_request_duration_seconds", "Duration of the HTTP requests served by the API.", "histogram")
+	handlers := make([]string, 0, len(registry.httpDurations))
+	for handler := range registry.httpDurations {
+		handlers = append(handlers, handler)
+	}
+	sort.Strings(handlers)
+	for _, handler := range handlers {
+		w.histogram("portainer_http_request_duration_seconds", labels{"handler", handler}, registry.httpDurations[handler])
+	}
+
+	w.header("portainer_proxy_requests_total", "Total number of requests proxied to each endpoint.", "counter")
+	for _, endpointID := range sortedEndpointIDs(registry.proxyRequests) {
+		w.sample("portainer_proxy_requests_total", endpointLabels(endpointID), float64(registry.proxyRequests[endpointID]))
+	}
+
+	w.header("portainer_snapshot_duration_seconds", "Duration of the endpoint snapshots.", "histogram")
+	w.histogram("portainer_snapshot_duration_seconds", nil, registry.snapshotDurations)
+
+	w.header("portainer_snapshot_last_duration_seconds", "Duration of the last snapshot of each endpoint.", "gauge")
+	lastSnapshotEndpoints := make([]portainer.EndpointID, 0, len(registry.lastSnapshots))
+	for endpointID := range registry.lastSnapshots {
+		lastSnapshotEndpoints = append(lastSnapshotEndpoints, endpointID)
+	}
+	sort.Slice(lastSnapshotEndpoints, func(i, j int) bool { return lastSnapshotEndpoints[i] < lastSnapshotEndpoints[j] })
+	for _, endpointID := range lastSnapshotEndpoints {
+		w.sample("portainer_snapshot_last_duration_seconds", endpointLabels(endpointID), registry.lastSnapshots[endpointID])
+	}
+
+	w.header("portainer_snapshot_failures_total", "Total number of failed snapshots for each endpoint.", "counter")
+	for _, endpointID := range sortedEndpointIDs(registry.snapshotFailures) {
+		w.sample("portainer_snapshot_failures_total", endpointLabels(endpointID), float64(registry.snapshotFailures[endpointID]))
+	}
+}
+
+func sortedEndpointIDs(counters map[portainer.EndpointID]uint64) []portainer.EndpointID {
+	endpointIDs := make([]portainer.EndpointID, 0, len(counters))
+	for endpointID := range counters {
+		endpointIDs = append(endpointIDs, endpointID)
+	}
+	sort.Slice(endpointIDs, func(i, j int) bool { return endpointIDs[i] < endpointIDs[j] })
+	return endpointIDs
+}
+
+func endpointLabels(endpointID portainer.EndpointID) labels {
+	return labels{"endpoint_id", strconv.Itoa(int(endpointID))}
+}
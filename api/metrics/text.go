@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// labels is a flat list of label name/value pairs.
+type labels []string
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// textWriter writes metrics using the Prometheus text exposition format.
+type textWriter struct {
+	writer *bufio.Writer
+}
+
+func newTextWriter(w io.Writer) *textWriter {
+	return &textWriter{
+		writer: bufio.NewWriter(w),
+	}
+}
+
+func (w *textWriter) header(name, help, metricType string) {
+	w.writer.WriteString("# HELP " + name + " " + help + "\n")
+	w.writer.WriteString("# TYPE " + name + " " + metricType + "\n")
+}
+
+func (w *textWriter) sample(name string, sampleLabels labels, value float64) {
+	w.writer.WriteString(name)
+
+	if len(sampleLabels) > 0 {
+		w.writer.WriteString("{")
+		for idx := 0; idx+1 < len(sampleLabels); idx += 2 {
+			if idx > 0 {
+				w.writer.WriteString(",")
+			}
+			w.writer.WriteString(sampleLabels[idx] + `="` + labelValueReplacer.Replace(sampleLabels[idx+1]) + `"`)
+		}
+		w.writer.WriteString("}")
+	}
+
+	w.writer.WriteString(" " + formatValue(value) + "\n")
+}
+
+func (w *textWriter) histogram(name string, sampleLabels labels, h *histogram) {
+	for idx, bound := range durationBuckets {
+		w.sample(name+"_bucket", append(append(labels{}, sampleLabels...), "le", formatValue(bound)), float64(h.counts[idx]))
+	}
+	w.sample(name+"_bucket", append(append(labels{}, sampleLabels...), "le", "+Inf"), float64(h.count))
+	w.sample(name+"_sum", sampleLabels, h.sum)
+	w.sample(name+"_count", sampleLabels, float64(h.count))
+}
+
+func (w *textWriter) flush() error {
+	return w.writer.Flush()
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
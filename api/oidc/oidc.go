@@ -0,0 +1,197 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	authorizationRequestTimeout = 10 * time.Minute
+	providerCacheDuration       = 1 * time.Hour
+	requestTimeout              = 10 * time.Second
+
+	// maxAuthorizationRequests bounds the number of pending authorization requests kept in memory,
+	// the oldest request is dropped when it is reached
+	maxAuthorizationRequests = 10000
+)
+
+// Service represents a service used to authenticate users through an OpenID Connect provider
+// using the authorization code flow with PKCE.
+type Service struct {
+	client         *http.Client
+	mutex          sync.Mutex
+	providers      map[string]*provider
+	authorizations map[string]*authorizationRequest
+}
+
+// authorizationRequest holds the values generated when redirecting a user to the provider
+// that are required to redeem the authorization code and validate the ID token.
+type authorizationRequest struct {
+	codeVerifier string
+	nonce        string
+	expiresAt    time.Time
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService() *Service {
+	return &Service{
+		client:         &http.Client{Timeout: requestTimeout},
+		providers:      make(map[string]*provider),
+		authorizations: make(map[string]*authorizationRequest),
+	}
+}
+
+// AuthorizationURL returns the URL of the authorization endpoint of the provider where users
+// must be redirected to authenticate, alongside the state of the authorization request. The state,
+// nonce and PKCE code verifier associated to the authorization request are kept until the
+// authorization code is redeemed.
+func (service *Service) AuthorizationURL(settings *portainer.OAuthSettings) (string, string, error) {
+	provider, err := service.provider(settings.OIDCIssuerURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	codeVerifier, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	authorizationURL, err := url.Parse(provider.configuration.AuthorizationEndpoint)
+	if err != nil {
+		return "", "", err
+	}
+
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", settings.ClientID)
+	query.Set("redirect_uri", settings.RedirectURI)
+	query.Set("scope", scopes(settings.Scopes))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	service.storeAuthorizationRequest(state, &authorizationRequest{
+		codeVerifier: codeVerifier,
+		nonce:        nonce,
+		expiresAt:    time.Now().Add(authorizationRequestTimeout),
+	})
+
+	return authorizationURL.String(), state, nil
+}
+
+// Authenticate redeems an authorization code against the token endpoint of the provider, validates
+// the ID token returned by the provider and returns the identity of the user.
+func (service *Service) Authenticate(code, state string, settings *portainer.OAuthSettings) (*portainer.OIDCIdentity, error) {
+	request := service.retrieveAuthorizationRequest(state)
+	if request == nil {
+		return nil, portainer.ErrOIDCInvalidState
+	}
+
+	provider, err := service.provider(settings.OIDCIssuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := service.exchangeCode(provider, code, request.codeVerifier, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := service.verifyIDToken(provider, rawIDToken, settings.ClientID, request.nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return identityFromClaims(claims, settings)
+}
+
+func (service *Service) storeAuthorizationRequest(state string, request *authorizationRequest) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	now := time.Now()
+	for key, pending := range service.authorizations {
+		if now.After(pending.expiresAt) {
+			delete(service.authorizations, key)
+		}
+	}
+
+	if len(service.authorizations) >= maxAuthorizationRequests {
+		oldestKey := ""
+		for key, pending := range service.authorizations {
+			if oldestKey == "" || pending.expiresAt.Before(service.authorizations[oldestKey].expiresAt) {
+				oldestKey = key
+			}
+		}
+		delete(service.authorizations, oldestKey)
+	}
+
+	service.authorizations[state] = request
+}
+
+// retrieveAuthorizationRequest returns the authorization request associated to a state.
+// A state can only be used once.
+func (service *Service) retrieveAuthorizationRequest(state string) *authorizationRequest {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	request, ok := service.authorizations[state]
+	if !ok {
+		return nil
+	}
+	delete(service.authorizations, state)
+
+	if time.Now().After(request.expiresAt) {
+		return nil
+	}
+
+	return request
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func codeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// scopes returns the space separated list of scopes requested to the provider,
+// the openid scope is always requested.
+func scopes(configuredScopes string) string {
+	scopes := strings.Fields(strings.Replace(configuredScopes, ",", " ", -1))
+	for _, scope := range scopes {
+		if scope == "openid" {
+			return strings.Join(scopes, " ")
+		}
+	}
+
+	return strings.Join(append([]string{"openid"}, scopes...), " ")
+}
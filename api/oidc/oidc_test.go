@@ -0,0 +1,202 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/portainer/portainer/api"
+)
+
+// mockProvider is a minimal OpenID Connect provider issuing ID tokens for a single authorization code.
+type mockProvider struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	audience      string
+	nonce         string
+	codeChallenge string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mockProvider{key: key, audience: "portainer"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&providerConfiguration{
+			Issuer:                provider.server.URL,
+			AuthorizationEndpoint: provider.server.URL + "/authorize",
+			TokenEndpoint:         provider.server.URL + "/token",
+			JWKSURI:               provider.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&jsonWebKeySet{Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Kid: "test",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "code" || codeChallenge(r.Form.Get("code_verifier")) != provider.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(&tokenResponse{Error: "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":    provider.server.URL,
+			"sub":    "1234",
+			"aud":    []string{provider.audience},
+			"exp":    time.Now().Add(time.Minute).Unix(),
+			"iat":    time.Now().Unix(),
+			"nonce":  provider.nonce,
+			"email":  "user@example.com",
+			"groups": []string{"developers", "operators"},
+		})
+		token.Header["kid"] = "test"
+
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(&tokenResponse{IDToken: idToken})
+	})
+
+	provider.server = httptest.NewServer(mux)
+	return provider
+}
+
+// authorize simulates the redirection of the user to the provider and returns the state
+// sent back to Portainer along with the authorization code.
+func (provider *mockProvider) authorize(t *testing.T, authorizationURL string) string {
+	parsedURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsedURL.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("scope") != "openid email" {
+		t.Fatalf("unexpected authorization request: %s", authorizationURL)
+	}
+
+	provider.nonce = query.Get("nonce")
+	provider.codeChallenge = query.Get("code_challenge")
+	return query.Get("state")
+}
+
+func TestAuthenticate(t *testing.T) {
+	provider := newMockProvider(t)
+	defer provider.server.Close()
+
+	settings := &portainer.OAuthSettings{
+		ClientID:       "portainer",
+		RedirectURI:    "http://localhost:9000",
+		Scopes:         "email",
+		UserIdentifier: "email",
+		GroupsClaim:    "groups",
+		OIDCIssuerURL:  provider.server.URL,
+	}
+
+	t.Run("Valid authorization code", func(t *testing.T) {
+		service := NewService()
+
+		authorizationURL, expectedState, err := service.AuthorizationURL(settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := provider.authorize(t, authorizationURL)
+		if state != expectedState {
+			t.Errorf("unexpected state: got %s want %s", state, expectedState)
+		}
+
+		identity, err := service.Authenticate("code", state, settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if identity.Username != "user@example.com" {
+			t.Errorf("unexpected username: got %s want %s", identity.Username, "user@example.com")
+		}
+		if len(identity.Groups) != 2 || identity.Groups[0] != "developers" || identity.Groups[1] != "operators" {
+			t.Errorf("unexpected groups: %v", identity.Groups)
+		}
+
+		_, err = service.Authenticate("code", state, settings)
+		if err != portainer.ErrOIDCInvalidState {
+			t.Errorf("state reused: got %v want %v", err, portainer.ErrOIDCInvalidState)
+		}
+	})
+
+	t.Run("Unknown state", func(t *testing.T) {
+		service := NewService()
+
+		_, err := service.Authenticate("code", "unknown", settings)
+		if err != portainer.ErrOIDCInvalidState {
+			t.Errorf("unexpected error: got %v want %v", err, portainer.ErrOIDCInvalidState)
+		}
+	})
+
+	t.Run("ID token issued for another client", func(t *testing.T) {
+		service := NewService()
+		provider.audience = "another-client"
+		defer func() { provider.audience = "portainer" }()
+
+		authorizationURL, _, err := service.AuthorizationURL(settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := provider.authorize(t, authorizationURL)
+
+		_, err = service.Authenticate("code", state, settings)
+		if err != portainer.ErrOIDCInvalidIDToken {
+			t.Errorf("unexpected error: got %v want %v", err, portainer.ErrOIDCInvalidIDToken)
+		}
+	})
+}
+
+func TestStoreAuthorizationRequestLimit(t *testing.T) {
+	service := NewService()
+
+	now := time.Now()
+	for i := 0; i < maxAuthorizationRequests; i++ {
+		service.storeAuthorizationRequest(strconv.Itoa(i), &authorizationRequest{expiresAt: now.Add(time.Hour + time.Duration(i)*time.Millisecond)})
+	}
+	service.storeAuthorizationRequest("expired", &authorizationRequest{expiresAt: now.Add(-time.Minute)})
+
+	if len(service.authorizations) != maxAuthorizationRequests {
+		t.Fatalf("expected at most %d pending requests, got %d", maxAuthorizationRequests, len(service.authorizations))
+	}
+
+	if _, ok := service.authorizations["0"]; ok {
+		t.Errorf("expected the oldest pending request to be dropped")
+	}
+
+	service.storeAuthorizationRequest("state", &authorizationRequest{expiresAt: now.Add(authorizationRequestTimeout)})
+
+	if len(service.authorizations) != maxAuthorizationRequests {
+		t.Errorf("expected the expired request to be removed, got %d pending requests", len(service.authorizations))
+	}
+
+	if service.retrieveAuthorizationRequest("state") == nil {
+		t.Errorf("expected the last request to be kept")
+	}
+}
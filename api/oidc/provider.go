@@ -0,0 +1,206 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// providerConfiguration represents the subset of the discovery document of a provider
+// used by the service.
+type providerConfiguration struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type provider struct {
+	configuration providerConfiguration
+	keys          map[string]interface{}
+	fetchedAt     time.Time
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// provider returns the configuration and the signing keys of the provider associated to an issuer.
+// They are retrieved from the discovery document of the provider and cached.
+func (service *Service) provider(issuerURL string) (*provider, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	cached, ok := service.providers[issuerURL]
+	if ok && time.Since(cached.fetchedAt) < providerCacheDuration {
+		return cached, nil
+	}
+
+	var configuration providerConfiguration
+	err := service.getJSON(strings.TrimSuffix(issuerURL, "/")+"/.well-known/openid-configuration", &configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(configuration.Issuer, "/") != strings.TrimSuffix(issuerURL, "/") {
+		return nil, fmt.Errorf("issuer mismatch in discovery document: expected %s, got %s", issuerURL, configuration.Issuer)
+	}
+
+	keys, err := service.fetchKeys(configuration.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &provider{
+		configuration: configuration,
+		keys:          keys,
+		fetchedAt:     time.Now(),
+	}
+	service.providers[issuerURL] = provider
+
+	return provider, nil
+}
+
+// publicKey returns the key identified by keyID used by the provider to sign ID tokens.
+// The keys are fetched again when the key cannot be found, to support key rotation.
+func (service *Service) publicKey(provider *provider, keyID string) (interface{}, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	key := lookupKey(provider.keys, keyID)
+	if key != nil {
+		return key, nil
+	}
+
+	keys, err := service.fetchKeys(provider.configuration.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	provider.keys = keys
+
+	key = lookupKey(provider.keys, keyID)
+	if key == nil {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	return key, nil
+}
+
+func lookupKey(keys map[string]interface{}, keyID string) interface{} {
+	if keyID == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+
+	return keys[keyID]
+}
+
+func (service *Service) fetchKeys(jwksURI string) (map[string]interface{}, error) {
+	var keySet jsonWebKeySet
+	err := service.getJSON(jwksURI, &keySet)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{})
+	for _, key := range keySet.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := key.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[key.Kid] = publicKey
+	}
+
+	return keys, nil
+}
+
+func (service *Service) getJSON(url string, target interface{}) error {
+	resp, err := service.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("unable to retrieve %s, received %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (key *jsonWebKey) publicKey() (interface{}, error) {
+	switch key.Kty {
+	case "RSA":
+		n, err := decodeBigInt(key.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(key.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch key.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", key.Crv)
+		}
+
+		x, err := decodeBigInt(key.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(key.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type: %s", key.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
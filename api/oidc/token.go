@@ -0,0 +1,152 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/portainer/portainer/api"
+)
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (service *Service) exchangeCode(provider *provider, code, codeVerifier string, settings *portainer.OAuthSettings) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", settings.RedirectURI)
+	form.Set("client_id", settings.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if settings.ClientSecret != "" {
+		form.Set("client_secret", settings.ClientSecret)
+	}
+
+	resp, err := service.client.PostForm(provider.configuration.TokenEndpoint, form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("unable to redeem authorization code, received %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return "", portainer.ErrOIDCInvalidIDToken
+	}
+
+	return token.IDToken, nil
+}
+
+// verifyIDToken validates the signature of the ID token against the keys of the provider
+// as well as its issuer, audience, expiry and nonce.
+func (service *Service) verifyIDToken(provider *provider, rawIDToken, clientID, nonce string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, portainer.ErrOIDCInvalidIDToken
+		}
+
+		keyID, _ := token.Header["kid"].(string)
+		return service.publicKey(provider, keyID)
+	})
+	if err != nil || !token.Valid {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	if claimString(claims["iss"]) != provider.configuration.Issuer {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	if !containsString(claimStrings(claims["aud"]), clientID) {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	if claimString(claims["nonce"]) != nonce {
+		return nil, portainer.ErrOIDCInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+// identityFromClaims maps the claims of an ID token to a user identity. The username is retrieved
+// from the claim defined in the UserIdentifier setting (sub by default) and the groups from the
+// claim defined in the GroupsClaim setting.
+func identityFromClaims(claims jwt.MapClaims, settings *portainer.OAuthSettings) (*portainer.OIDCIdentity, error) {
+	usernameClaim := settings.UserIdentifier
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+
+	username := claimString(claims[usernameClaim])
+	if username == "" {
+		return nil, portainer.ErrOIDCMissingClaim
+	}
+
+	identity := &portainer.OIDCIdentity{
+		Username: username,
+	}
+
+	if settings.GroupsClaim != "" {
+		identity.Groups = claimStrings(claims[settings.GroupsClaim])
+	}
+
+	return identity, nil
+}
+
+func claimString(claim interface{}) string {
+	switch value := claim.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if str := claimString(item); str != "" {
+				values = append(values, str)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+// ImageRestrictionViolationOperation is the operation recorded inside the audit log when an image is denied
+const ImageRestrictionViolationOperation = "image restriction violation"
+
+var imageIDPattern = regexp.MustCompile(`^(sha256:)?[a-f0-9]{12,64}$`)
+
+// imageReference represents the components of a Docker image reference
+type imageReference struct {
+	domain   string
+	path     string
+	digested bool
+}
+
+// parseImageReference returns the registry domain and repository path of an image reference.
+// Images without an explicit registry are hosted on the Docker Hub and official images are
+// located inside the library namespace.
+func parseImageReference(image string) imageReference {
+	reference := imageReference{}
+
+	name := image
+	if index := strings.Index(name, "@"); index != -1 {
+		reference.digested = true
+		name = name[:index]
+	}
+
+	if index := strings.LastIndex(name, ":"); index > strings.LastIndex(name, "/") {
+		name = name[:index]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 || (!strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost") {
+		reference.domain = defaultRegistry
+		reference.path = name
+	} else {
+		reference.domain = normalizeRegistryURL(parts[0])
+		reference.path = parts[1]
+	}
+
+	if reference.domain == defaultRegistry && !strings.Contains(reference.path, "/") {
+		reference.path = "library/" + reference.path
+	}
+
+	return reference
+}
+
+// ImageRestrictionViolation returns a description of the image restriction not respected by the
+// specified image or an empty string when the image can be used. An empty image denotes an image
+// that is built, loaded or imported instead of being pulled, which is not allowed.
+func ImageRestrictionViolation(restrictions *portainer.ImageRestrictions, registries []portainer.Registry, image string) string {
+	if !restrictions.Enabled {
+		return ""
+	}
+
+	if image == "" {
+		return "images can only be pulled from an allowed registry"
+	}
+
+	if imageIDPattern.MatchString(image) {
+		return fmt.Sprintf("image %s must be referenced by name", image)
+	}
+
+	reference := parseImageReference(image)
+
+	if restrictions.RequireDigest && !reference.digested {
+		return fmt.Sprintf("image %s must be pinned to a digest", image)
+	}
+
+	if !imageAllowed(restrictions, registries, reference) {
+		return fmt.Sprintf("image %s is not hosted on an allowed registry", image)
+	}
+
+	return ""
+}
+
+// RecordImageRestrictionViolation logs the denial of an image and records it inside the audit log.
+// The operation, the status and the timestamp of the audit log entry are set by this function.
+func RecordImageRestrictionViolation(auditService portainer.AuditService, auditLog *portainer.AuditLog, violation string) {
+	log.Printf("[WARN] [images] [message: image restriction violation] [endpoint: %d] [user: %s] [violation: %s]", auditLog.EndpointID, auditLog.Username, violation)
+
+	if auditService == nil {
+		return
+	}
+
+	auditLog.Operation = ImageRestrictionViolationOperation
+	auditLog.Status = http.StatusForbidden
+	auditLog.Timestamp = time.Now().Unix()
+
+	err := auditService.CreateAuditLog(auditLog)
+	if err != nil {
+		log.Printf("[WARN] [images] [message: unable to persist audit log] [err: %s]", err)
+	}
+}
+
+func imageAllowed(restrictions *portainer.ImageRestrictions, registries []portainer.Registry, reference imageReference) bool {
+	name := strings.ToLower(reference.domain + "/" + reference.path)
+
+	for _, registry := range registries {
+		registryURL := normalizeRegistryURL(registry.URL)
+		if registryURL != "" && strings.HasPrefix(name, registryURL+"/") {
+			return true
+		}
+	}
+
+	if reference.domain == defaultRegistry {
+		namespace := strings.SplitN(reference.path, "/", 2)[0]
+		return containsFold(restrictions.AllowedDockerHubNamespaces, namespace)
+	}
+
+	return false
+}
+
+func normalizeRegistryURL(registryURL string) string {
+	registryURL = strings.ToLower(registryURL)
+	registryURL = strings.TrimPrefix(registryURL, "https://")
+	registryURL = strings.TrimPrefix(registryURL, "http://")
+	registryURL = strings.TrimSuffix(registryURL, "/")
+
+	switch registryURL {
+	case "index.docker.io", "registry-1.docker.io":
+		return defaultRegistry
+	default:
+		return registryURL
+	}
+}
@@ -0,0 +1,406 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/portainer/portainer/api"
+)
+
+const defaultRegistry = "docker.io"
+
+type (
+	// ContainerSpec represents the settings of a container or of the tasks of a service
+	// that are evaluated against the container policies.
+	ContainerSpec struct {
+		Image        string
+		Privileged   bool
+		Capabilities []string
+		HostNetwork  bool
+		HostPID      bool
+		Labels       map[string]string
+		Memory       int64
+		BindMounts   []string
+		Devices      []string
+		// ResourcesOnly is set when the specification comes from a container update request,
+		// which only contains the resources of the container. Only the device rule is evaluated.
+		ResourcesOnly bool
+		// MountsOnly is set when the specification comes from a volume creation request,
+		// which can only define a bind mount. Only the bind mount rule is evaluated.
+		MountsOnly bool
+	}
+
+	// Violation represents a rule of a container policy that is not respected by a container specification.
+	Violation struct {
+		PolicyID   portainer.ContainerPolicyID   `json:"PolicyId"`
+		PolicyName string                        `json:"PolicyName"`
+		Mode       portainer.ContainerPolicyMode `json:"Mode"`
+		Message    string                        `json:"Message"`
+	}
+
+	containerCreatePayload struct {
+		Image      string
+		Labels     map[string]string
+		HostConfig containerHostConfig
+	}
+
+	containerHostConfig struct {
+		Privileged  bool
+		CapAdd      []string
+		NetworkMode string
+		PidMode     string
+		Memory      int64
+		Binds       []string
+		Mounts      []mountPayload
+		Devices     []struct {
+			PathOnHost string
+		}
+	}
+
+	mountPayload struct {
+		Type          string
+		Source        string
+		VolumeOptions *struct {
+			DriverConfig *struct {
+				Name    string
+				Options map[string]string
+			}
+		}
+	}
+
+	volumeCreatePayload struct {
+		Driver     string
+		DriverOpts map[string]string
+	}
+
+	serviceSpecPayload struct {
+		Labels       map[string]string
+		TaskTemplate struct {
+			ContainerSpec struct {
+				Image         string
+				Labels        map[string]string
+				CapabilityAdd []string
+				Mounts        []mountPayload
+			}
+			Resources struct {
+				Limits struct {
+					MemoryBytes int64
+				}
+			}
+			Networks []struct {
+				Target string
+			}
+		}
+		Networks []struct {
+			Target string
+		}
+	}
+)
+
+// ContainerSpecFromContainerCreate returns the specification of a container from the payload of a container creation request.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ContainerCreate
+func ContainerSpecFromContainerCreate(body []byte) (*ContainerSpec, error) {
+	var payload containerCreatePayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ContainerSpec{
+		Image:        payload.Image,
+		Privileged:   payload.HostConfig.Privileged,
+		Capabilities: payload.HostConfig.CapAdd,
+		HostNetwork:  payload.HostConfig.NetworkMode == "host",
+		HostPID:      payload.HostConfig.PidMode == "host",
+		Labels:       payload.Labels,
+		Memory:       payload.HostConfig.Memory,
+	}
+
+	for _, bind := range payload.HostConfig.Binds {
+		source := strings.Split(bind, ":")[0]
+		if strings.HasPrefix(source, "/") {
+			spec.BindMounts = append(spec.BindMounts, source)
+		}
+	}
+
+	spec.BindMounts = append(spec.BindMounts, mountBindSources(payload.HostConfig.Mounts)...)
+
+	for _, device := range payload.HostConfig.Devices {
+		spec.Devices = append(spec.Devices, device.PathOnHost)
+	}
+
+	return spec, nil
+}
+
+// ContainerSpecFromContainerUpdate returns the specification of a container from the payload of a container update request.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ContainerUpdate
+func ContainerSpecFromContainerUpdate(body []byte) (*ContainerSpec, error) {
+	var payload containerHostConfig
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ContainerSpec{
+		ResourcesOnly: true,
+	}
+
+	for _, device := range payload.Devices {
+		spec.Devices = append(spec.Devices, device.PathOnHost)
+	}
+
+	return spec, nil
+}
+
+// ContainerSpecFromServiceSpec returns the specification of the tasks of a service from the payload
+// of a service creation or update request. The labels of the service and of its containers are merged.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/ServiceCreate
+func ContainerSpecFromServiceSpec(body []byte) (*ContainerSpec, error) {
+	var payload serviceSpecPayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	containerSpec := payload.TaskTemplate.ContainerSpec
+	spec := &ContainerSpec{
+		Image:        containerSpec.Image,
+		Capabilities: containerSpec.CapabilityAdd,
+		Labels:       make(map[string]string),
+		Memory:       payload.TaskTemplate.Resources.Limits.MemoryBytes,
+	}
+
+	for key, value := range payload.Labels {
+		spec.Labels[key] = value
+	}
+
+	for key, value := range containerSpec.Labels {
+		spec.Labels[key] = value
+	}
+
+	for _, network := range append(payload.TaskTemplate.Networks, payload.Networks...) {
+		if network.Target == "host" {
+			spec.HostNetwork = true
+		}
+	}
+
+	spec.BindMounts = mountBindSources(containerSpec.Mounts)
+
+	return spec, nil
+}
+
+// ContainerSpecFromVolumeCreate returns the specification of the bind mount defined by the payload
+// of a volume creation request. A volume of the local driver can bind mount a path of the host.
+// API schema reference: https://docs.docker.com/engine/api/v1.40/#operation/VolumeCreate
+func ContainerSpecFromVolumeCreate(body []byte) (*ContainerSpec, error) {
+	var payload volumeCreatePayload
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ContainerSpec{
+		MountsOnly: true,
+	}
+
+	source := LocalVolumeBindSource(payload.Driver, payload.DriverOpts)
+	if source != "" {
+		spec.BindMounts = append(spec.BindMounts, source)
+	}
+
+	return spec, nil
+}
+
+// LocalVolumeBindSource returns the path of the host mounted by a volume of the local driver
+// created with the bind option, or an empty string when the volume does not bind mount a path.
+func LocalVolumeBindSource(driver string, options map[string]string) string {
+	if driver != "" && driver != "local" {
+		return ""
+	}
+
+	for _, option := range strings.Split(options["o"], ",") {
+		if strings.TrimSpace(option) == "bind" || strings.TrimSpace(option) == "rbind" {
+			return options["device"]
+		}
+	}
+
+	return ""
+}
+
+// mountBindSources returns the paths of the host mounted by bind mounts and by volumes
+// of the local driver created with the bind option.
+func mountBindSources(mounts []mountPayload) []string {
+	sources := make([]string, 0)
+
+	for _, mount := range mounts {
+		if mount.Type == "bind" {
+			sources = append(sources, mount.Source)
+			continue
+		}
+
+		if mount.Type == "volume" && mount.VolumeOptions != nil && mount.VolumeOptions.DriverConfig != nil {
+			source := LocalVolumeBindSource(mount.VolumeOptions.DriverConfig.Name, mount.VolumeOptions.DriverConfig.Options)
+			if source != "" {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	return sources
+}
+
+// Evaluate returns the violations of the specified policies by the container specification.
+func Evaluate(policies []portainer.ContainerPolicy, spec *ContainerSpec) []Violation {
+	violations := make([]Violation, 0)
+
+	for _, policy := range policies {
+		for _, message := range evaluatePolicy(&policy, spec) {
+			violations = append(violations, Violation{
+				PolicyID:   policy.ID,
+				PolicyName: policy.Name,
+				Mode:       policy.Mode,
+				Message:    message,
+			})
+		}
+	}
+
+	return violations
+}
+
+// EnforcedMessage returns a description of the violations associated to a policy in enforce mode,
+// or an empty string when no policy is enforced.
+func EnforcedMessage(violations []Violation) string {
+	messages := make([]string, 0)
+	for _, violation := range violations {
+		if violation.Mode == portainer.ContainerPolicyEnforce {
+			messages = append(messages, fmt.Sprintf("%s (%s)", violation.Message, violation.PolicyName))
+		}
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Enforced returns true when at least one of the violations is associated to a policy in enforce mode.
+func Enforced(violations []Violation) bool {
+	for _, violation := range violations {
+		if violation.Mode == portainer.ContainerPolicyEnforce {
+			return true
+		}
+	}
+	return false
+}
+
+func evaluatePolicy(policy *portainer.ContainerPolicy, spec *ContainerSpec) []string {
+	messages := make([]string, 0)
+
+	for _, device := range spec.Devices {
+		if matchPathPrefix(device, policy.DeniedDevices) {
+			messages = append(messages, fmt.Sprintf("device %s is not allowed", device))
+		}
+	}
+
+	if spec.ResourcesOnly {
+		return messages
+	}
+
+	if len(policy.AllowedBindMountPrefixes) > 0 {
+		for _, source := range spec.BindMounts {
+			if !matchPathPrefix(source, policy.AllowedBindMountPrefixes) {
+				messages = append(messages, fmt.Sprintf("bind mount of %s is not allowed", source))
+			}
+		}
+	}
+
+	if spec.MountsOnly {
+		return messages
+	}
+
+	if len(policy.AllowedRegistries) > 0 {
+		registry := imageRegistry(spec.Image)
+		if !containsFold(policy.AllowedRegistries, registry) {
+			messages = append(messages, fmt.Sprintf("images from registry %s are not allowed", registry))
+		}
+	}
+
+	// A privileged container is granted every capability and device of the host, which would bypass
+	// the capability, device and bind mount rules of the policy. Privileged mode is only denied by these
+	// rules so that the other policies do not override the AllowPrivilegedModeForRegularUsers setting.
+	if spec.Privileged && restrictsHostAccess(policy) {
+		messages = append(messages, "privileged mode is not allowed")
+	}
+
+	for _, capability := range policy.ForbiddenCapabilities {
+		if containsCapability(spec.Capabilities, capability) {
+			messages = append(messages, fmt.Sprintf("capability %s is not allowed", normalizeCapability(capability)))
+		}
+	}
+
+	if policy.DenyHostNetwork && spec.HostNetwork {
+		messages = append(messages, "host network is not allowed")
+	}
+
+	if policy.DenyHostPID && spec.HostPID {
+		messages = append(messages, "host PID namespace is not allowed")
+	}
+
+	for _, label := range policy.RequiredLabels {
+		if _, ok := spec.Labels[label]; !ok {
+			messages = append(messages, fmt.Sprintf("label %s is required", label))
+		}
+	}
+
+	if policy.RequireMemoryLimit && spec.Memory <= 0 {
+		messages = append(messages, "a memory limit is required")
+	}
+
+	return messages
+}
+
+// restrictsHostAccess returns true when the policy restricts the capabilities, the devices or
+// the bind mounts available to the containers.
+func restrictsHostAccess(policy *portainer.ContainerPolicy) bool {
+	return len(policy.ForbiddenCapabilities) > 0 || len(policy.DeniedDevices) > 0 || len(policy.AllowedBindMountPrefixes) > 0
+}
+
+// imageRegistry returns the registry hosting the specified image. Images without an explicit
+// registry are hosted on the Docker Hub.
+func imageRegistry(image string) string {
+	return parseImageReference(image).domain
+}
+
+// matchPathPrefix returns true when the path is one of the prefixes or is located under one of them.
+func matchPathPrefix(value string, prefixes []string) bool {
+	value = path.Clean(value)
+	for _, prefix := range prefixes {
+		prefix = path.Clean(prefix)
+		if value == prefix || prefix == "/" || strings.HasPrefix(value, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCapability(capabilities []string, capability string) bool {
+	capability = normalizeCapability(capability)
+	for _, value := range capabilities {
+		value = normalizeCapability(value)
+		if value == capability || value == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(capability), "CAP_")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                          "docker.io",
+		"portainer/portainer:latest":     "docker.io",
+		"index.docker.io/library/nginx":  "docker.io",
+		"localhost/app":                  "localhost",
+		"registry.example.com:5000/app":  "registry.example.com:5000",
+		"Quay.io/coreos/etcd@sha256:abc": "quay.io",
+	}
+
+	for image, expected := range tests {
+		if registry := imageRegistry(image); registry != expected {
+			t.Errorf("imageRegistry(%q) = %q, expected %q", image, registry, expected)
+		}
+	}
+}
+
+func TestEvaluateContainerCreate(t *testing.T) {
+	body := []byte(`{
+		"Image": "evil.example.com/miner",
+		"Labels": {"team": "ops"},
+		"HostConfig": {
+			"CapAdd": ["cap_sys_admin"],
+			"NetworkMode": "host",
+			"PidMode": "host",
+			"Binds": ["/etc:/host-etc:ro", "data:/data"],
+			"Devices": [{"PathOnHost": "/dev/kmsg"}]
+		}
+	}`)
+
+	spec, err := ContainerSpecFromContainerCreate(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	policies := []portainer.ContainerPolicy{
+		{
+			ID:                       1,
+			Name:                     "strict",
+			Mode:                     portainer.ContainerPolicyEnforce,
+			AllowedRegistries:        []string{"docker.io"},
+			ForbiddenCapabilities:    []string{"SYS_ADMIN"},
+			DenyHostNetwork:          true,
+			DenyHostPID:              true,
+			RequiredLabels:           []string{"team", "owner"},
+			RequireMemoryLimit:       true,
+			AllowedBindMountPrefixes: []string{"/srv"},
+			DeniedDevices:            []string{"/dev"},
+		},
+		{
+			ID:                       2,
+			Name:                     "lenient",
+			Mode:                     portainer.ContainerPolicyWarn,
+			AllowedBindMountPrefixes: []string{"/"},
+		},
+	}
+
+	violations := Evaluate(policies, spec)
+	if len(violations) != 8 {
+		t.Fatalf("expected 8 violations, got %d: %v", len(violations), violations)
+	}
+
+	for _, violation := range violations {
+		if violation.PolicyID != 1 {
+			t.Errorf("unexpected violation of policy %d: %s", violation.PolicyID, violation.Message)
+		}
+	}
+
+	if !Enforced(violations) {
+		t.Error("expected violations to be enforced")
+	}
+}
+
+func TestEvaluateServiceSpec(t *testing.T) {
+	body := []byte(`{
+		"Labels": {"team": "ops"},
+		"TaskTemplate": {
+			"ContainerSpec": {"Image": "nginx", "Labels": {"owner": "bob"}},
+			"Resources": {"Limits": {"MemoryBytes": 1048576}}
+		}
+	}`)
+
+	spec, err := ContainerSpecFromServiceSpec(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	policies := []portainer.ContainerPolicy{
+		{
+			ID:                 1,
+			Mode:               portainer.ContainerPolicyWarn,
+			AllowedRegistries:  []string{"docker.io"},
+			RequiredLabels:     []string{"team", "owner"},
+			RequireMemoryLimit: true,
+			DenyHostNetwork:    true,
+		},
+	}
+
+	violations := Evaluate(policies, spec)
+	if len(violations) != 0 {
+		t.Errorf("expected no violation, got %v", violations)
+	}
+}
+
+func TestEvaluateContainerUpdate(t *testing.T) {
+	spec, err := ContainerSpecFromContainerUpdate([]byte(`{"Devices": [{"PathOnHost": "/dev/sda"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	policies := []portainer.ContainerPolicy{
+		{
+			ID:                 1,
+			Mode:               portainer.ContainerPolicyWarn,
+			RequireMemoryLimit: true,
+			DeniedDevices:      []string{"/dev/sd"},
+		},
+	}
+
+	violations := Evaluate(policies, spec)
+	if len(violations) != 0 {
+		t.Errorf("expected no violation, got %v", violations)
+	}
+
+	policies[0].DeniedDevices = []string{"/dev"}
+	violations = Evaluate(policies, spec)
+	if len(violations) != 1 || Enforced(violations) {
+		t.Errorf("expected a single warning, got %v", violations)
+	}
+}
+
+func TestEvaluatePrivilegedAndVolumeBindMounts(t *testing.T) {
+	policies := []portainer.ContainerPolicy{
+		{
+			ID:                       1,
+			Mode:                     portainer.ContainerPolicyEnforce,
+			AllowedBindMountPrefixes: []string{"/srv"},
+		},
+	}
+
+	spec, err := ContainerSpecFromContainerCreate([]byte(`{"Image": "nginx", "HostConfig": {"Privileged": true}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	violations := Evaluate(policies, spec)
+	if len(violations) != 1 || violations[0].Message != "privileged mode is not allowed" {
+		t.Errorf("expected privileged mode to be denied, got %v", violations)
+	}
+
+	spec, err = ContainerSpecFromServiceSpec([]byte(`{
+		"TaskTemplate": {
+			"ContainerSpec": {
+				"Image": "nginx",
+				"Mounts": [{
+					"Type": "volume",
+					"Source": "data",
+					"VolumeOptions": {"DriverConfig": {"Name": "local", "Options": {"type": "none", "o": "bind", "device": "/etc"}}}
+				}]
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	violations = Evaluate(policies, spec)
+	if len(violations) != 1 || violations[0].Message != "bind mount of /etc is not allowed" {
+		t.Errorf("expected the volume bind mount to be denied, got %v", violations)
+	}
+
+	spec, err = ContainerSpecFromVolumeCreate([]byte(`{"Name": "data", "DriverOpts": {"type": "none", "o": "bind,ro", "device": "/etc"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	violations = Evaluate(policies, spec)
+	if len(violations) != 1 || violations[0].Message != "bind mount of /etc is not allowed" {
+		t.Errorf("expected the volume bind mount to be denied, got %v", violations)
+	}
+
+	spec, err = ContainerSpecFromVolumeCreate([]byte(`{"Name": "data", "Driver": "local"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	violations = Evaluate(policies, spec)
+	if len(violations) != 0 {
+		t.Errorf("expected no violation, got %v", violations)
+	}
+}
+
+func TestEvaluatePrivilegedWithoutHostAccessRules(t *testing.T) {
+	policies := []portainer.ContainerPolicy{
+		{
+			ID:             1,
+			Mode:           portainer.ContainerPolicyEnforce,
+			RequiredLabels: []string{"team"},
+		},
+	}
+
+	spec, err := ContainerSpecFromContainerCreate([]byte(`{"Image": "nginx", "Labels": {"team": "ops"}, "HostConfig": {"Privileged": true}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	violations := Evaluate(policies, spec)
+	if len(violations) != 0 {
+		t.Errorf("expected privileged mode to be allowed by a policy that only requires labels, got %v", violations)
+	}
+}
+
+func TestImageRestrictionViolation(t *testing.T) {
+	registries := []portainer.Registry{
+		{URL: "registry.example.com:5000"},
+		{URL: "https://registry.gitlab.com/acme/"},
+	}
+
+	restrictions := &portainer.ImageRestrictions{
+		Enabled:                    true,
+		AllowedDockerHubNamespaces: []string{"library", "portainer"},
+	}
+
+	tests := map[string]bool{
+		"nginx":                               true,
+		"nginx:1.17":                          true,
+		"portainer/agent":                     true,
+		"docker.io/bitnami/redis":             false,
+		"registry.example.com:5000/app:v1":    true,
+		"registry.example.com/app":            false,
+		"registry.gitlab.com/acme/service":    true,
+		"registry.gitlab.com/other/service":   false,
+		"3f57d9401f8d":                        false,
+		"sha256:3f57d9401f8d42f986df300f0c69": false,
+		"":                                    false,
+	}
+
+	for image, allowed := range tests {
+		violation := ImageRestrictionViolation(restrictions, registries, image)
+		if allowed && violation != "" {
+			t.Errorf("expected image %s to be allowed, got: %s", image, violation)
+		} else if !allowed && violation == "" {
+			t.Errorf("expected image %s to be denied", image)
+		}
+	}
+
+	restrictions.RequireDigest = true
+	if violation := ImageRestrictionViolation(restrictions, registries, "nginx:1.17"); violation == "" {
+		t.Error("expected a tagged image to be denied when a digest is required")
+	}
+
+	if violation := ImageRestrictionViolation(restrictions, registries, "nginx@sha256:0123456789abcdef"); violation != "" {
+		t.Errorf("expected a digest-pinned image to be allowed, got: %s", violation)
+	}
+
+	restrictions.Enabled = false
+	if violation := ImageRestrictionViolation(restrictions, registries, "evil.example.com/miner"); violation != "" {
+		t.Errorf("expected disabled restrictions to allow any image, got: %s", violation)
+	}
+}
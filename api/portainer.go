@@ -1,6 +1,9 @@
 package portainer
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 type (
 	// Pair defines a key/value string pair
@@ -11,32 +14,43 @@ type (
 
 	// CLIFlags represents the available flags on the CLI
 	CLIFlags struct {
-		Addr              *string
-		TunnelAddr        *string
-		TunnelPort        *string
-		AdminPassword     *string
-		AdminPasswordFile *string
-		Assets            *string
-		Data              *string
-		EndpointURL       *string
-		ExternalEndpoints *string
-		Labels            *[]Pair
-		Logo              *string
-		NoAuth            *bool
-		NoAnalytics       *bool
-		Templates         *string
-		TemplateFile      *string
-		TLS               *bool
-		TLSSkipVerify     *bool
-		TLSCacert         *string
-		TLSCert           *string
-		TLSKey            *string
-		SSL               *bool
-		SSLCert           *string
-		SSLKey            *string
-		SyncInterval      *string
-		Snapshot          *bool
-		SnapshotInterval  *string
+		Addr                *string
+		TunnelAddr          *string
+		TunnelPort          *string
+		AdminPassword       *string
+		AdminPasswordFile   *string
+		Assets              *string
+		Data                *string
+		EndpointURL         *string
+		ExternalEndpoints   *string
+		Labels              *[]Pair
+		Logo                *string
+		NoAuth              *bool
+		NoAnalytics         *bool
+		Templates           *string
+		TemplateFile        *string
+		TLS                 *bool
+		TLSSkipVerify       *bool
+		TLSCacert           *string
+		TLSCert             *string
+		TLSKey              *string
+		SSL                 *bool
+		SSLCert             *string
+		SSLKey              *string
+		SyncInterval        *string
+		Snapshot            *bool
+		SnapshotInterval    *string
+		StackUpdateInterval *string
+		LDAPSyncInterval    *string
+		JWTSecretFile       *string
+		EncryptionKey       *string
+		EncryptionKeyFile   *string
+		RotateEncryptionKey *string
+		MetricsToken        *string
+		BackupInterval      *string
+		BackupDirectory     *string
+		BackupRetention     *int
+		BackupPasswordFile  *string
 	}
 
 	// Status represents the application status
@@ -50,14 +64,16 @@ type (
 
 	// LDAPSettings represents the settings used to connect to a LDAP server
 	LDAPSettings struct {
-		ReaderDN            string                    `json:"ReaderDN"`
-		Password            string                    `json:"Password,omitempty"`
-		URL                 string                    `json:"URL"`
-		TLSConfig           TLSConfiguration          `json:"TLSConfig"`
-		StartTLS            bool                      `json:"StartTLS"`
-		SearchSettings      []LDAPSearchSettings      `json:"SearchSettings"`
-		GroupSearchSettings []LDAPGroupSearchSettings `json:"GroupSearchSettings"`
-		AutoCreateUsers     bool                      `json:"AutoCreateUsers"`
+		ReaderDN              string                    `json:"ReaderDN"`
+		Password              string                    `json:"Password,omitempty"`
+		URL                   string                    `json:"URL"`
+		TLSConfig             TLSConfiguration          `json:"TLSConfig"`
+		StartTLS              bool                      `json:"StartTLS"`
+		SearchSettings        []LDAPSearchSettings      `json:"SearchSettings"`
+		GroupSearchSettings   []LDAPGroupSearchSettings `json:"GroupSearchSettings"`
+		AutoCreateUsers       bool                      `json:"AutoCreateUsers"`
+		AutoCreateTeams       bool                      `json:"AutoCreateTeams"`
+		AutoCreateTeamsFilter string                    `json:"AutoCreateTeamsFilter"`
 	}
 
 	// OAuthSettings represents the settings used to authorize with an authorization server
@@ -72,6 +88,14 @@ type (
 		Scopes               string `json:"Scopes"`
 		OAuthAutoCreateUsers bool   `json:"OAuthAutoCreateUsers"`
 		DefaultTeamID        TeamID `json:"DefaultTeamID"`
+		OIDCIssuerURL        string `json:"OIDCIssuerURL"`
+		GroupsClaim          string `json:"GroupsClaim"`
+	}
+
+	// OIDCIdentity represents the identity of a user authenticated through an OpenID Connect provider
+	OIDCIdentity struct {
+		Username string
+		Groups   []string
 	}
 
 	// TLSConfiguration represents a TLS configuration
@@ -99,18 +123,27 @@ type (
 
 	// Settings represents the application settings
 	Settings struct {
-		LogoURL                            string               `json:"LogoURL"`
-		BlackListedLabels                  []Pair               `json:"BlackListedLabels"`
-		AuthenticationMethod               AuthenticationMethod `json:"AuthenticationMethod"`
-		LDAPSettings                       LDAPSettings         `json:"LDAPSettings"`
-		OAuthSettings                      OAuthSettings        `json:"OAuthSettings"`
-		AllowBindMountsForRegularUsers     bool                 `json:"AllowBindMountsForRegularUsers"`
-		AllowPrivilegedModeForRegularUsers bool                 `json:"AllowPrivilegedModeForRegularUsers"`
-		AllowVolumeBrowserForRegularUsers  bool                 `json:"AllowVolumeBrowserForRegularUsers"`
-		SnapshotInterval                   string               `json:"SnapshotInterval"`
-		TemplatesURL                       string               `json:"TemplatesURL"`
-		EnableHostManagementFeatures       bool                 `json:"EnableHostManagementFeatures"`
-		EdgeAgentCheckinInterval           int                  `json:"EdgeAgentCheckinInterval"`
+		LogoURL                            string                  `json:"LogoURL"`
+		BlackListedLabels                  []Pair                  `json:"BlackListedLabels"`
+		AuthenticationMethod               AuthenticationMethod    `json:"AuthenticationMethod"`
+		LDAPSettings                       LDAPSettings            `json:"LDAPSettings"`
+		OAuthSettings                      OAuthSettings           `json:"OAuthSettings"`
+		AllowBindMountsForRegularUsers     bool                    `json:"AllowBindMountsForRegularUsers"`
+		AllowPrivilegedModeForRegularUsers bool                    `json:"AllowPrivilegedModeForRegularUsers"`
+		AllowVolumeBrowserForRegularUsers  bool                    `json:"AllowVolumeBrowserForRegularUsers"`
+		SnapshotInterval                   string                  `json:"SnapshotInterval"`
+		TemplatesURL                       string                  `json:"TemplatesURL"`
+		EnableHostManagementFeatures       bool                    `json:"EnableHostManagementFeatures"`
+		EdgeAgentCheckinInterval           int                     `json:"EdgeAgentCheckinInterval"`
+		StackVersionRetention              int                     `json:"StackVersionRetention"`
+		UserSessionTimeout                 string                  `json:"UserSessionTimeout"`
+		EnableSessionRecording             bool                    `json:"EnableSessionRecording"`
+		SessionRecordingRetention          int                     `json:"SessionRecordingRetention"`
+		AuditLogRetention                  int                     `json:"AuditLogRetention"`
+		EnforceTwoFactorForAdministrators  bool                    `json:"EnforceTwoFactorForAdministrators"`
+		PasswordPolicy                     PasswordPolicy          `json:"PasswordPolicy"`
+		AccountLockout                     AccountLockoutSettings  `json:"AccountLockout"`
+		SnapshotHistory                    SnapshotHistorySettings `json:"SnapshotHistory"`
 
 		// Deprecated fields
 		DisplayDonationHeader       bool
@@ -125,11 +158,84 @@ type (
 		Role                    UserRole               `json:"Role"`
 		PortainerAuthorizations Authorizations         `json:"PortainerAuthorizations"`
 		EndpointAuthorizations  EndpointAuthorizations `json:"EndpointAuthorizations"`
+		Disabled                bool                   `json:"Disabled"`
+		TOTPEnabled             bool                   `json:"TOTPEnabled"`
+		TOTPSecret              string                 `json:"TOTPSecret,omitempty"`
+		TOTPRecoveryCodes       []string               `json:"TOTPRecoveryCodes,omitempty"`
+		TOTPLastCounter         int64                  `json:"TOTPLastCounter,omitempty"`
+		PasswordHistory         []string               `json:"PasswordHistory,omitempty"`
+		PasswordChangedAt       int64                  `json:"PasswordChangedAt"`
+		FailedLoginAttempts     int                    `json:"FailedLoginAttempts"`
+		LockedUntil             int64                  `json:"LockedUntil"`
+		// AuthenticationMethod is the source of the account: internal, LDAP or OAuth
+		AuthenticationMethod AuthenticationMethod `json:"AuthenticationMethod"`
+	}
+
+	// PasswordPolicy represents the requirements applied to the passwords of internal users.
+	// HistorySize is the number of most recent passwords, including the current one, that cannot
+	// be reused and MaxAge is the number of days after which a password must be changed.
+	// A zero value disables the associated requirement.
+	PasswordPolicy struct {
+		MinLength               int  `json:"MinLength"`
+		RequireUppercase        bool `json:"RequireUppercase"`
+		RequireLowercase        bool `json:"RequireLowercase"`
+		RequireDigit            bool `json:"RequireDigit"`
+		RequireSpecialCharacter bool `json:"RequireSpecialCharacter"`
+		HistorySize             int  `json:"HistorySize"`
+		MaxAge                  int  `json:"MaxAge"`
+	}
+
+	// PasswordPolicyViolation represents a requirement of the password policy that is not
+	// satisfied by a password
+	PasswordPolicyViolation struct {
+		Code    string `json:"Code"`
+		Message string `json:"Message"`
+	}
+
+	// AccountLockoutSettings represents the settings used to lock the account of an internal user
+	// after too many failed authentication attempts. The account is automatically unlocked
+	// once the lockout duration has elapsed. A MaxFailedAttempts of 0 disables the lockout.
+	AccountLockoutSettings struct {
+		MaxFailedAttempts int    `json:"MaxFailedAttempts"`
+		LockoutDuration   string `json:"LockoutDuration"`
+	}
+
+	// SnapshotHistorySettings represents the retention of the endpoint snapshot history.
+	// Records older than DownsamplingAge are merged into a single record per DownsamplingInterval
+	// and records older than Retention are removed. An empty value disables the associated behaviour.
+	SnapshotHistorySettings struct {
+		Retention            string `json:"Retention"`
+		DownsamplingAge      string `json:"DownsamplingAge"`
+		DownsamplingInterval string `json:"DownsamplingInterval"`
+	}
+
+	// TOTPEnrolment represents the information required by a user to configure an authenticator
+	// application. The recovery codes are only returned once, when the enrolment is created.
+	TOTPEnrolment struct {
+		Secret          string   `json:"Secret"`
+		ProvisioningURI string   `json:"ProvisioningURI"`
+		RecoveryCodes   []string `json:"RecoveryCodes"`
 	}
 
 	// UserID represents a user identifier
 	UserID int
 
+	// APIKeyID represents an API key identifier
+	APIKeyID int
+
+	// APIKey represents a personal access token that can be used by a user to
+	// authenticate against the API via the X-API-Key header
+	APIKey struct {
+		ID           APIKeyID `json:"Id"`
+		UserID       UserID   `json:"UserId"`
+		Name         string   `json:"Name"`
+		Prefix       string   `json:"Prefix"`
+		Digest       string   `json:"Digest,omitempty"`
+		CreationDate int64    `json:"CreationDate"`
+		ExpiryDate   int64    `json:"ExpiryDate"`
+		LastUsedDate int64    `json:"LastUsedDate"`
+	}
+
 	// UserRole represents the role of a user. It can be either an administrator
 	// or a regular user
 	UserRole int
@@ -167,6 +273,12 @@ type (
 		Role     UserRole
 	}
 
+	// RevokedToken represents a JWT token that has been revoked before its expiry
+	RevokedToken struct {
+		ID        string `json:"Id"`
+		ExpiresAt int64  `json:"ExpiresAt"`
+	}
+
 	// StackID represents a stack identifier (it must be composed of Name + "_" + SwarmID to create a unique identifier)
 	StackID int
 
@@ -184,6 +296,28 @@ type (
 		Env             []Pair           `json:"Env"`
 		ResourceControl *ResourceControl `json:"ResourceControl"`
 		ProjectPath     string
+		GitConfig       *StackGitConfig `json:"GitConfig"`
+	}
+
+	// StackVersion represents a revision of a stack file and of its environment variables
+	StackVersion struct {
+		Version          int    `json:"Version"`
+		StackFileContent string `json:"StackFileContent"`
+		Env              []Pair `json:"Env"`
+		CreatedBy        string `json:"CreatedBy"`
+		CreationDate     int64  `json:"CreationDate"`
+	}
+
+	// StackGitConfig represents the Git repository a stack was created from.
+	// CommitHash references the last commit that was deployed.
+	StackGitConfig struct {
+		URL            string `json:"URL"`
+		ReferenceName  string `json:"ReferenceName"`
+		Authentication bool   `json:"Authentication"`
+		Username       string `json:"Username"`
+		Password       string `json:"Password,omitempty"`
+		CommitHash     string `json:"CommitHash"`
+		AutoUpdate     bool   `json:"AutoUpdate"`
 	}
 
 	// RegistryID represents a registry identifier
@@ -241,6 +375,23 @@ type (
 	// EndpointID represents an endpoint identifier
 	EndpointID int
 
+	// EndpointEventID represents an endpoint event identifier
+	EndpointEventID int
+
+	// EndpointEventType represents the type of a change detected between two snapshots of an endpoint
+	EndpointEventType int
+
+	// EndpointEvent represents a change detected between two consecutive snapshots of an endpoint
+	EndpointEvent struct {
+		ID           EndpointEventID   `json:"Id"`
+		EndpointID   EndpointID        `json:"EndpointId"`
+		Type         EndpointEventType `json:"Type"`
+		ResourceID   string            `json:"ResourceId"`
+		ResourceName string            `json:"ResourceName"`
+		Message      string            `json:"Message"`
+		Time         int64             `json:"Time"`
+	}
+
 	// EndpointType represents the type of an endpoint
 	EndpointType int
 
@@ -250,22 +401,24 @@ type (
 	// Endpoint represents a Docker endpoint with all the info required
 	// to connect to it
 	Endpoint struct {
-		ID                 EndpointID          `json:"Id"`
-		Name               string              `json:"Name"`
-		Type               EndpointType        `json:"Type"`
-		URL                string              `json:"URL"`
-		GroupID            EndpointGroupID     `json:"GroupId"`
-		PublicURL          string              `json:"PublicURL"`
-		TLSConfig          TLSConfiguration    `json:"TLSConfig"`
-		Extensions         []EndpointExtension `json:"Extensions"`
-		AzureCredentials   AzureCredentials    `json:"AzureCredentials,omitempty"`
-		Tags               []string            `json:"Tags"`
-		Status             EndpointStatus      `json:"Status"`
-		Snapshots          []Snapshot          `json:"Snapshots"`
-		UserAccessPolicies UserAccessPolicies  `json:"UserAccessPolicies"`
-		TeamAccessPolicies TeamAccessPolicies  `json:"TeamAccessPolicies"`
-		EdgeID             string              `json:"EdgeID,omitempty"`
-		EdgeKey            string              `json:"EdgeKey"`
+		ID                  EndpointID          `json:"Id"`
+		Name                string              `json:"Name"`
+		Type                EndpointType        `json:"Type"`
+		URL                 string              `json:"URL"`
+		GroupID             EndpointGroupID     `json:"GroupId"`
+		PublicURL           string              `json:"PublicURL"`
+		TLSConfig           TLSConfiguration    `json:"TLSConfig"`
+		Extensions          []EndpointExtension `json:"Extensions"`
+		AzureCredentials    AzureCredentials    `json:"AzureCredentials,omitempty"`
+		Tags                []string            `json:"Tags"`
+		Status              EndpointStatus      `json:"Status"`
+		Snapshots           []Snapshot          `json:"Snapshots"`
+		UserAccessPolicies  UserAccessPolicies  `json:"UserAccessPolicies"`
+		TeamAccessPolicies  TeamAccessPolicies  `json:"TeamAccessPolicies"`
+		EdgeID              string              `json:"EdgeID,omitempty"`
+		EdgeKey             string              `json:"EdgeKey"`
+		EdgeJoinTokenID     EdgeJoinTokenID     `json:"EdgeJoinTokenId,omitempty"`
+		EdgePendingApproval bool                `json:"EdgePendingApproval"`
 		// Deprecated fields
 		// Deprecated in DBVersion == 4
 		TLS           bool   `json:"TLS,omitempty"`
@@ -287,13 +440,6 @@ type (
 	// EndpointAuthorizations represents the authorizations associated to a set of endpoints
 	EndpointAuthorizations map[EndpointID]Authorizations
 
-	// APIOperationAuthorizationRequest represent an request for the authorization to execute an API operation
-	APIOperationAuthorizationRequest struct {
-		Path           string
-		Method         string
-		Authorizations Authorizations
-	}
-
 	// RoleID represents a role identifier
 	RoleID int
 
@@ -305,6 +451,7 @@ type (
 		Description    string         `json:"Description"`
 		Authorizations Authorizations `json:"Authorizations"`
 		Priority       int            `json:"Priority"`
+		IsBuiltIn      bool           `json:"IsBuiltIn"`
 	}
 
 	// AccessPolicy represent a policy that can be associated to a user or team
@@ -338,21 +485,72 @@ type (
 	// EndpointSyncJob represents a scheduled job that synchronize endpoints based on an external file
 	EndpointSyncJob struct{}
 
+	// StackGitUpdateJob represents a scheduled job that redeploys Git based stacks
+	// when their repository reference is updated
+	StackGitUpdateJob struct{}
+
+	// BackupJob represents a scheduled job that creates backups of the Portainer data
+	// inside a local directory and only keeps the most recent ones
+	BackupJob struct {
+		Directory string
+		Retention int
+	}
+
+	// LDAPSyncJob represents a scheduled job that synchronizes the users and the team
+	// memberships with the LDAP server
+	LDAPSyncJob struct{}
+
+	// AuditLogPruneJob represents a scheduled job that removes the audit logs older than
+	// the audit log retention
+	AuditLogPruneJob struct{}
+
+	// SnapshotHistoryCompactJob represents a scheduled job that applies the retention and the
+	// downsampling of the snapshot history
+	SnapshotHistoryCompactJob struct{}
+
+	// LDAPSyncReportID represents a LDAP synchronization report identifier
+	LDAPSyncReportID int
+
+	// LDAPSyncReport represents the result of an execution of the LDAP synchronization job
+	LDAPSyncReport struct {
+		ID                 LDAPSyncReportID           `json:"Id"`
+		StartedAt          int64                      `json:"StartedAt"`
+		EndedAt            int64                      `json:"EndedAt"`
+		Error              string                     `json:"Error,omitempty"`
+		SynchronizedUsers  int                        `json:"SynchronizedUsers"`
+		CreatedTeams       []string                   `json:"CreatedTeams"`
+		AddedMemberships   []LDAPSyncMembershipChange `json:"AddedMemberships"`
+		RemovedMemberships []LDAPSyncMembershipChange `json:"RemovedMemberships"`
+		DisabledUsers      []string                   `json:"DisabledUsers"`
+		EnabledUsers       []string                   `json:"EnabledUsers"`
+	}
+
+	// LDAPSyncMembershipChange represents a team membership added or removed by the LDAP synchronization job
+	LDAPSyncMembershipChange struct {
+		Username string `json:"Username"`
+		Team     string `json:"Team"`
+	}
+
 	// Schedule represents a scheduled job.
 	// It only contains a pointer to one of the JobRunner implementations
 	// based on the JobType.
 	// NOTE: The Recurring option is only used by ScriptExecutionJob at the moment
 	Schedule struct {
-		ID                 ScheduleID `json:"Id"`
-		Name               string
-		CronExpression     string
-		Recurring          bool
-		Created            int64
-		JobType            JobType
-		EdgeSchedule       *EdgeSchedule
-		ScriptExecutionJob *ScriptExecutionJob
-		SnapshotJob        *SnapshotJob
-		EndpointSyncJob    *EndpointSyncJob
+		ID                        ScheduleID `json:"Id"`
+		Name                      string
+		CronExpression            string
+		Recurring                 bool
+		Created                   int64
+		JobType                   JobType
+		EdgeSchedule              *EdgeSchedule
+		ScriptExecutionJob        *ScriptExecutionJob
+		SnapshotJob               *SnapshotJob
+		EndpointSyncJob           *EndpointSyncJob
+		StackGitUpdateJob         *StackGitUpdateJob
+		BackupJob                 *BackupJob
+		LDAPSyncJob               *LDAPSyncJob
+		AuditLogPruneJob          *AuditLogPruneJob
+		SnapshotHistoryCompactJob *SnapshotHistoryCompactJob
 	}
 
 	// EdgeSchedule represents a scheduled job that can run on Edge environments.
@@ -364,6 +562,36 @@ type (
 		Endpoints      []EndpointID `json:"Endpoints"`
 	}
 
+	// EdgeStackID represents an Edge stack identifier
+	EdgeStackID int
+
+	// EdgeStackStatusType represents the deployment status of an Edge stack on an endpoint
+	EdgeStackStatusType int
+
+	// EdgeStackStatus represents the deployment status of an Edge stack reported by the agent of an endpoint
+	EdgeStackStatus struct {
+		EndpointID EndpointID          `json:"EndpointId"`
+		Type       EdgeStackStatusType `json:"Type"`
+		Error      string              `json:"Error"`
+		Version    int                 `json:"Version"`
+		UpdateDate int64               `json:"UpdateDate"`
+	}
+
+	// EdgeStack represents a Compose stack deployed by the agents of the Edge endpoints
+	// belonging to one of its endpoint groups or associated to one of its tags.
+	// The version is incremented each time the stack file is updated so that agents redeploy the stack.
+	EdgeStack struct {
+		ID             EdgeStackID                    `json:"Id"`
+		Name           string                         `json:"Name"`
+		EndpointGroups []EndpointGroupID              `json:"EndpointGroups"`
+		Tags           []string                       `json:"Tags"`
+		ProjectPath    string                         `json:"ProjectPath"`
+		EntryPoint     string                         `json:"EntryPoint"`
+		Version        int                            `json:"Version"`
+		Status         map[EndpointID]EdgeStackStatus `json:"Status"`
+		CreationDate   int64                          `json:"CreationDate"`
+	}
+
 	// WebhookID represents a webhook identifier.
 	WebhookID int
 
@@ -379,6 +607,99 @@ type (
 		WebhookType WebhookType `json:"Type"`
 	}
 
+	// QuotaID represents a quota identifier
+	QuotaID int
+
+	// Quota represents the limits applied to the resources owned by a user or a team on an endpoint.
+	// A quota is associated to either a user or a team and to either an endpoint or an endpoint group,
+	// in which case the limits apply to each endpoint of the group. A zero limit means unlimited.
+	Quota struct {
+		ID              QuotaID         `json:"Id"`
+		UserID          UserID          `json:"UserId"`
+		TeamID          TeamID          `json:"TeamId"`
+		EndpointID      EndpointID      `json:"EndpointId"`
+		EndpointGroupID EndpointGroupID `json:"EndpointGroupId"`
+		MaxContainers   int             `json:"MaxContainers"`
+		MaxMemory       int64           `json:"MaxMemory"`
+		MaxNanoCPUs     int64           `json:"MaxNanoCPUs"`
+		MaxVolumes      int             `json:"MaxVolumes"`
+	}
+
+	// ContainerPolicyID represents a container policy identifier
+	ContainerPolicyID int
+
+	// ContainerPolicyMode represents how the violations of a container policy are handled
+	ContainerPolicyMode int
+
+	// ContainerPolicy represents a set of rules evaluated when a container or a service is created
+	// or updated on an endpoint of the associated endpoint group. Empty rules are not evaluated.
+	ContainerPolicy struct {
+		ID                       ContainerPolicyID   `json:"Id"`
+		Name                     string              `json:"Name"`
+		EndpointGroupID          EndpointGroupID     `json:"EndpointGroupId"`
+		Mode                     ContainerPolicyMode `json:"Mode"`
+		AllowedRegistries        []string            `json:"AllowedRegistries"`
+		ForbiddenCapabilities    []string            `json:"ForbiddenCapabilities"`
+		DenyHostNetwork          bool                `json:"DenyHostNetwork"`
+		DenyHostPID              bool                `json:"DenyHostPID"`
+		RequiredLabels           []string            `json:"RequiredLabels"`
+		RequireMemoryLimit       bool                `json:"RequireMemoryLimit"`
+		AllowedBindMountPrefixes []string            `json:"AllowedBindMountPrefixes"`
+		DeniedDevices            []string            `json:"DeniedDevices"`
+	}
+
+	// AlertRuleID represents an alert rule identifier
+	AlertRuleID int
+
+	// AlertEventType represents the type of an event that can trigger an alert
+	AlertEventType int
+
+	// AlertRule represents a rule used to send a notification when an event occurs.
+	// A rule with an empty list of endpoints applies to all the endpoints.
+	AlertRule struct {
+		ID          AlertRuleID       `json:"Id"`
+		Name        string            `json:"Name"`
+		Enabled     bool              `json:"Enabled"`
+		EventTypes  []AlertEventType  `json:"EventTypes"`
+		EndpointIDs []EndpointID      `json:"EndpointIds"`
+		NotifierIDs []AlertNotifierID `json:"NotifierIds"`
+	}
+
+	// AlertNotifierID represents an alert notifier identifier
+	AlertNotifierID int
+
+	// AlertNotifierType represents the type of an alert notifier
+	AlertNotifierType int
+
+	// AlertNotifier represents the configuration used to deliver an alert.
+	// URL is used by the webhook and Slack notifiers, SMTPSettings by the email notifier.
+	AlertNotifier struct {
+		ID           AlertNotifierID   `json:"Id"`
+		Name         string            `json:"Name"`
+		Type         AlertNotifierType `json:"Type"`
+		URL          string            `json:"URL"`
+		SMTPSettings AlertSMTPSettings `json:"SMTPSettings"`
+	}
+
+	// AlertSMTPSettings represents the settings used by an email notifier
+	AlertSMTPSettings struct {
+		Host     string   `json:"Host"`
+		Port     int      `json:"Port"`
+		Username string   `json:"Username"`
+		Password string   `json:"Password,omitempty"`
+		From     string   `json:"From"`
+		To       []string `json:"To"`
+	}
+
+	// AlertEvent represents an event that can trigger an alert
+	AlertEvent struct {
+		Type         AlertEventType `json:"Type"`
+		EndpointID   EndpointID     `json:"EndpointId"`
+		EndpointName string         `json:"EndpointName"`
+		Message      string         `json:"Message"`
+		Timestamp    int64          `json:"Timestamp"`
+	}
+
 	// AzureCredentials represents the credentials used to connect to an Azure
 	// environment.
 	AzureCredentials struct {
@@ -403,16 +724,56 @@ type (
 		SnapshotRaw           SnapshotRaw `json:"SnapshotRaw"`
 	}
 
+	// SnapshotRecord represents the summary counters of an endpoint snapshot stored inside the
+	// snapshot history. Samples is the number of snapshots merged in the record by downsampling,
+	// the counters of a downsampled record are the average of the merged snapshots.
+	SnapshotRecord struct {
+		EndpointID            EndpointID `json:"EndpointId"`
+		Time                  int64      `json:"Time"`
+		TotalCPU              int        `json:"TotalCPU"`
+		TotalMemory           int64      `json:"TotalMemory"`
+		RunningContainerCount int        `json:"RunningContainerCount"`
+		StoppedContainerCount int        `json:"StoppedContainerCount"`
+		VolumeCount           int        `json:"VolumeCount"`
+		ImageCount            int        `json:"ImageCount"`
+		ServiceCount          int        `json:"ServiceCount"`
+		StackCount            int        `json:"StackCount"`
+		Samples               int        `json:"Samples"`
+	}
+
 	// SnapshotRaw represents all the information related to a snapshot as returned by the Docker API
 	SnapshotRaw struct {
 		Containers interface{} `json:"Containers"`
 		Volumes    interface{} `json:"Volumes"`
 		Networks   interface{} `json:"Networks"`
 		Images     interface{} `json:"Images"`
+		Services   interface{} `json:"Services"`
 		Info       interface{} `json:"Info"`
 		Version    interface{} `json:"Version"`
 	}
 
+	// EdgeJoinTokenID represents an Edge join token identifier
+	EdgeJoinTokenID int
+
+	// EdgeJoinToken represents a reusable token used by Edge agents to register themselves.
+	// The endpoints created with a token belong to its endpoint group and receive its tags.
+	// They are pending approval from an administrator unless the token is auto-approved.
+	EdgeJoinToken struct {
+		ID              EdgeJoinTokenID `json:"Id"`
+		Name            string          `json:"Name"`
+		Prefix          string          `json:"Prefix"`
+		Digest          string          `json:"Digest,omitempty"`
+		PortainerURL    string          `json:"PortainerURL"`
+		EndpointGroupID EndpointGroupID `json:"EndpointGroupId"`
+		Tags            []string        `json:"Tags"`
+		AutoApprove     bool            `json:"AutoApprove"`
+		CreationDate    int64           `json:"CreationDate"`
+		ExpiryDate      int64           `json:"ExpiryDate"`
+		Revoked         bool            `json:"Revoked"`
+		UsageCount      int             `json:"UsageCount"`
+		LastUsedDate    int64           `json:"LastUsedDate"`
+	}
+
 	// EndpointGroupID represents an endpoint group identifier
 	EndpointGroupID int
 
@@ -424,6 +785,7 @@ type (
 		UserAccessPolicies UserAccessPolicies `json:"UserAccessPolicies"`
 		TeamAccessPolicies TeamAccessPolicies `json:"TeamAccessPolicies"`
 		Tags               []string           `json:"Tags"`
+		ImageRestrictions  ImageRestrictions  `json:"ImageRestrictions"`
 
 		// Deprecated fields
 		Labels []Pair `json:"Labels"`
@@ -433,6 +795,16 @@ type (
 		AuthorizedTeams []TeamID `json:"AuthorizedTeams"`
 	}
 
+	// ImageRestrictions represents the restrictions applied to the images pulled or used to create
+	// containers and services on the endpoints of an endpoint group. When enabled, only images hosted
+	// on a registry defined in Portainer or inside one of the allowed Docker Hub namespaces can be used.
+	ImageRestrictions struct {
+		Enabled                    bool     `json:"Enabled"`
+		AllowedDockerHubNamespaces []string `json:"AllowedDockerHubNamespaces"`
+		RequireDigest              bool     `json:"RequireDigest"`
+		ApplyToAdministrators      bool     `json:"ApplyToAdministrators"`
+	}
+
 	// EndpointExtension represents a deprecated form of Portainer extension
 	// TODO: legacy extension management
 	EndpointExtension struct {
@@ -614,6 +986,46 @@ type (
 		PrivateKeySeed string `json:"PrivateKeySeed"`
 	}
 
+	// AuditLogID represents an audit log identifier
+	AuditLogID int
+
+	// AuditLog represents a mutating operation executed against the API or
+	// proxied to an endpoint, along with the user who executed it
+	AuditLog struct {
+		ID         AuditLogID `json:"Id"`
+		UserID     UserID     `json:"UserId"`
+		Username   string     `json:"Username"`
+		EndpointID EndpointID `json:"EndpointId"`
+		Method     string     `json:"Method"`
+		Path       string     `json:"Path"`
+		Operation  string     `json:"Operation"`
+		ResourceID string     `json:"ResourceId"`
+		Status     int        `json:"Status"`
+		Timestamp  int64      `json:"Timestamp"`
+	}
+
+	// SessionRecordingID represents a console session recording identifier
+	SessionRecordingID int
+
+	// SessionRecordingType represents the type of console session that was recorded
+	SessionRecordingType int
+
+	// SessionRecording represents the recording of a console session opened on a container
+	// through a websocket. The content of the session is stored in the file store
+	// using the asciicast v2 format.
+	SessionRecording struct {
+		ID          SessionRecordingID   `json:"Id"`
+		Type        SessionRecordingType `json:"Type"`
+		UserID      UserID               `json:"UserId"`
+		Username    string               `json:"Username"`
+		EndpointID  EndpointID           `json:"EndpointId"`
+		NodeName    string               `json:"NodeName"`
+		ContainerID string               `json:"ContainerId"`
+		ExecID      string               `json:"ExecId"`
+		StartedAt   int64                `json:"StartedAt"`
+		EndedAt     int64                `json:"EndedAt"`
+	}
+
 	// CLIService represents a service for managing CLI
 	CLIService interface {
 		ParseFlags(version string) (*CLIFlags, error)
@@ -626,6 +1038,14 @@ type (
 		Init() error
 		Close() error
 		MigrateData() error
+		Backup(databasePath string) error
+		Restore(databasePath string) error
+	}
+
+	// BackupService represents a service used to create and restore archives of the Portainer data
+	BackupService interface {
+		CreateBackup(w io.Writer, password string) error
+		RestoreBackup(r io.Reader, password string) error
 	}
 
 	// Server defines the interface to serve the API
@@ -633,6 +1053,22 @@ type (
 		Start() error
 	}
 
+	// AuditService represents a service for managing audit log data
+	AuditService interface {
+		AuditLogs(afterID AuditLogID, limit int, match func(auditLog *AuditLog) bool) ([]AuditLog, error)
+		CreateAuditLog(auditLog *AuditLog) error
+		PruneAuditLogs(before int64) error
+	}
+
+	// SessionRecordingService represents a service for managing console session recording data
+	SessionRecordingService interface {
+		SessionRecording(ID SessionRecordingID) (*SessionRecording, error)
+		SessionRecordings() ([]SessionRecording, error)
+		CreateSessionRecording(recording *SessionRecording) error
+		UpdateSessionRecording(ID SessionRecordingID, recording *SessionRecording) error
+		DeleteSessionRecording(ID SessionRecordingID) error
+	}
+
 	// UserService represents a service for managing user data
 	UserService interface {
 		User(ID UserID) (*User, error)
@@ -641,14 +1077,26 @@ type (
 		UsersByRole(role UserRole) ([]User, error)
 		CreateUser(user *User) error
 		UpdateUser(ID UserID, user *User) error
+		UpdateUserFunc(ID UserID, updateFunc func(user *User)) error
 		DeleteUser(ID UserID) error
 	}
 
+	// APIKeyService represents a service for managing API key data
+	APIKeyService interface {
+		APIKey(ID APIKeyID) (*APIKey, error)
+		APIKeys() ([]APIKey, error)
+		APIKeysByUserID(userID UserID) ([]APIKey, error)
+		CreateAPIKey(apiKey *APIKey) error
+		UpdateAPIKey(ID APIKeyID, apiKey *APIKey) error
+		DeleteAPIKey(ID APIKeyID) error
+	}
+
 	RoleService interface {
 		Role(ID RoleID) (*Role, error)
 		Roles() ([]Role, error)
 		CreateRole(role *Role) error
 		UpdateRole(ID RoleID, role *Role) error
+		DeleteRole(ID RoleID) error
 	}
 
 	// TeamService represents a service for managing user data
@@ -674,6 +1122,34 @@ type (
 		DeleteTeamMembershipByTeamID(teamID TeamID) error
 	}
 
+	// EdgeStackService represents a service for managing Edge stack data
+	EdgeStackService interface {
+		EdgeStack(ID EdgeStackID) (*EdgeStack, error)
+		EdgeStacks() ([]EdgeStack, error)
+		CreateEdgeStack(edgeStack *EdgeStack) error
+		UpdateEdgeStack(ID EdgeStackID, edgeStack *EdgeStack) error
+		UpdateEdgeStackFunc(ID EdgeStackID, updateFunc func(edgeStack *EdgeStack)) error
+		DeleteEdgeStack(ID EdgeStackID) error
+		GetNextIdentifier() int
+	}
+
+	// EdgeJoinTokenService represents a service for managing Edge join token data
+	EdgeJoinTokenService interface {
+		EdgeJoinToken(ID EdgeJoinTokenID) (*EdgeJoinToken, error)
+		EdgeJoinTokens() ([]EdgeJoinToken, error)
+		CreateEdgeJoinToken(token *EdgeJoinToken) error
+		UpdateEdgeJoinToken(ID EdgeJoinTokenID, token *EdgeJoinToken) error
+		DeleteEdgeJoinToken(ID EdgeJoinTokenID) error
+	}
+
+	// EndpointEventService represents a service for managing the events detected between endpoint snapshots
+	EndpointEventService interface {
+		EndpointEvents(endpointID EndpointID, from, to int64) ([]EndpointEvent, error)
+		CreateEndpointEvents(events []EndpointEvent) error
+		DeleteEndpointEvents(endpointID EndpointID) error
+		PruneEndpointEvents(before int64) error
+	}
+
 	// EndpointService represents a service for managing endpoint data
 	EndpointService interface {
 		Endpoint(ID EndpointID) (*Endpoint, error)
@@ -710,6 +1186,7 @@ type (
 		Stacks() ([]Stack, error)
 		CreateStack(stack *Stack) error
 		UpdateStack(ID StackID, stack *Stack) error
+		UpdateStackFunc(ID StackID, updateFunc func(stack *Stack)) error
 		DeleteStack(ID StackID) error
 		GetNextIdentifier() int
 	}
@@ -743,11 +1220,53 @@ type (
 		Webhooks() ([]Webhook, error)
 		Webhook(ID WebhookID) (*Webhook, error)
 		CreateWebhook(portainer *Webhook) error
+		UpdateWebhook(ID WebhookID, webhook *Webhook) error
 		WebhookByResourceID(resourceID string) (*Webhook, error)
 		WebhookByToken(token string) (*Webhook, error)
 		DeleteWebhook(serviceID WebhookID) error
 	}
 
+	// QuotaService represents a service for managing quota data
+	QuotaService interface {
+		Quota(ID QuotaID) (*Quota, error)
+		Quotas() ([]Quota, error)
+		CreateQuota(quota *Quota) error
+		UpdateQuota(ID QuotaID, quota *Quota) error
+		DeleteQuota(ID QuotaID) error
+	}
+
+	// ContainerPolicyService represents a service for managing container policy data
+	ContainerPolicyService interface {
+		ContainerPolicy(ID ContainerPolicyID) (*ContainerPolicy, error)
+		ContainerPolicies() ([]ContainerPolicy, error)
+		CreateContainerPolicy(policy *ContainerPolicy) error
+		UpdateContainerPolicy(ID ContainerPolicyID, policy *ContainerPolicy) error
+		DeleteContainerPolicy(ID ContainerPolicyID) error
+	}
+
+	// AlertRuleService represents a service for managing alert rule data
+	AlertRuleService interface {
+		AlertRule(ID AlertRuleID) (*AlertRule, error)
+		AlertRules() ([]AlertRule, error)
+		CreateAlertRule(rule *AlertRule) error
+		UpdateAlertRule(ID AlertRuleID, rule *AlertRule) error
+		DeleteAlertRule(ID AlertRuleID) error
+	}
+
+	// AlertNotifierService represents a service for managing alert notifier data
+	AlertNotifierService interface {
+		AlertNotifier(ID AlertNotifierID) (*AlertNotifier, error)
+		AlertNotifiers() ([]AlertNotifier, error)
+		CreateAlertNotifier(notifier *AlertNotifier) error
+		UpdateAlertNotifier(ID AlertNotifierID, notifier *AlertNotifier) error
+		DeleteAlertNotifier(ID AlertNotifierID) error
+	}
+
+	// Alerter represents a service used to deliver alerts when an event occurs
+	Alerter interface {
+		Notify(event *AlertEvent)
+	}
+
 	// ResourceControlService represents a service for managing resource control data
 	ResourceControlService interface {
 		ResourceControl(ID ResourceControlID) (*ResourceControl, error)
@@ -799,6 +1318,19 @@ type (
 		CompareHashAndData(hash string, data string) error
 	}
 
+	// EncryptionService represents a service used to encrypt and decrypt data stored by Portainer
+	EncryptionService interface {
+		Encrypt(data string) (string, error)
+		Decrypt(data string) (string, error)
+	}
+
+	// TOTPService represents a service used to manage the time-based one-time password
+	// second authentication factor of users
+	TOTPService interface {
+		Enrol(user *User) (*TOTPEnrolment, error)
+		Verify(user *User, code string) error
+	}
+
 	// DigitalSignatureService represents a service to manage digital signatures
 	DigitalSignatureService interface {
 		ParseKeyPair(private, public []byte) error
@@ -812,6 +1344,20 @@ type (
 	JWTService interface {
 		GenerateToken(data *TokenData) (string, error)
 		ParseAndVerifyToken(token string) (*TokenData, error)
+		RevokeToken(token string) error
+		RevokeUserSessions(userID UserID) error
+		SetUserSessionDuration(duration time.Duration)
+	}
+
+	// SessionService represents a service for managing the JWT signing key and the revoked sessions
+	SessionService interface {
+		SigningKey() ([]byte, error)
+		UpdateSigningKey(key []byte) error
+		RevokedToken(ID string) (*RevokedToken, error)
+		CreateRevokedToken(token *RevokedToken) error
+		DeleteExpiredRevokedTokens(timestamp int64) error
+		UserSessionsRevocationDate(userID UserID) (int64, error)
+		RevokeUserSessions(userID UserID, timestamp int64) error
 	}
 
 	// FileService represents a service for managing files
@@ -825,22 +1371,35 @@ type (
 		DeleteTLSFiles(folder string) error
 		GetStackProjectPath(stackIdentifier string) string
 		StoreStackFileFromBytes(stackIdentifier, fileName string, data []byte) (string, error)
+		GetEdgeStackProjectPath(edgeStackIdentifier string) string
+		StoreEdgeStackFileFromBytes(edgeStackIdentifier, fileName string, data []byte) (string, error)
 		StoreRegistryManagementFileFromBytes(folder, fileName string, data []byte) (string, error)
 		KeyPairFilesExist() (bool, error)
 		StoreKeyPair(private, public []byte, privatePEMHeader, publicPEMHeader string) error
 		LoadKeyPair() ([]byte, []byte, error)
+		EncryptionKeyFileExists() (bool, error)
+		StoreEncryptionKey(key []byte) error
+		LoadEncryptionKey() ([]byte, error)
 		WriteJSONToFile(path string, content interface{}) error
 		FileExists(path string) (bool, error)
 		StoreScheduledJobFileFromBytes(identifier string, data []byte) (string, error)
 		GetScheduleFolder(identifier string) string
 		ExtractExtensionArchive(data []byte) error
 		GetBinaryFolder() string
+		StoreStackVersion(stackIdentifier string, version *StackVersion) error
+		GetStackVersions(stackIdentifier string) ([]StackVersion, error)
+		DeleteStackVersion(stackIdentifier string, version int) error
+		GetSessionRecordingPath(identifier string) string
+		CreateSessionRecordingFile(identifier string) (io.WriteCloser, error)
+		DeleteSessionRecordingFile(identifier string) error
 	}
 
 	// GitService represents a service for managing Git
 	GitService interface {
 		ClonePublicRepository(repositoryURL, referenceName string, destination string) error
 		ClonePrivateRepositoryWithBasicAuth(repositoryURL, referenceName string, destination, username, password string) error
+		LatestCommitID(repositoryURL, referenceName, username, password string) (string, error)
+		HeadCommitID(repositoryPath string) (string, error)
 	}
 
 	// JobScheduler represents a service to run jobs on a periodic basis
@@ -863,11 +1422,33 @@ type (
 		CreateSnapshot(endpoint *Endpoint) (*Snapshot, error)
 	}
 
+	// SnapshotHistoryService represents a service for managing the snapshot history of endpoints
+	SnapshotHistoryService interface {
+		SnapshotRecords(endpointID EndpointID, from, to int64) ([]SnapshotRecord, error)
+		CreateSnapshotRecord(record *SnapshotRecord) error
+		DeleteSnapshotRecords(endpointID EndpointID) error
+		CompactSnapshotRecords(downsamplingBefore, downsamplingInterval, retentionBefore int64) error
+	}
+
 	// LDAPService represents a service used to authenticate users against a LDAP/AD
 	LDAPService interface {
 		AuthenticateUser(username, password string, settings *LDAPSettings) error
 		TestConnectivity(settings *LDAPSettings) error
 		GetUserGroups(username string, settings *LDAPSettings) ([]string, error)
+		GetGroups(settings *LDAPSettings) ([]string, error)
+	}
+
+	// LDAPSyncReportService represents a service for managing LDAP synchronization report data
+	LDAPSyncReportService interface {
+		LDAPSyncReports() ([]LDAPSyncReport, error)
+		CreateLDAPSyncReport(report *LDAPSyncReport) error
+		DeleteLDAPSyncReport(ID LDAPSyncReportID) error
+	}
+
+	// OIDCService represents a service used to authenticate users through an OpenID Connect provider
+	OIDCService interface {
+		AuthorizationURL(settings *OAuthSettings) (string, string, error)
+		Authenticate(code, state string, settings *OAuthSettings) (*OIDCIdentity, error)
 	}
 
 	// SwarmStackManager represents a service to manage Swarm stacks
@@ -881,9 +1462,23 @@ type (
 	// ComposeStackManager represents a service to manage Compose stacks
 	ComposeStackManager interface {
 		Up(stack *Stack, endpoint *Endpoint) error
+		Pull(stack *Stack, endpoint *Endpoint) error
 		Down(stack *Stack, endpoint *Endpoint) error
 	}
 
+	// StackDeployer represents a service used to deploy stacks through the stack managers
+	StackDeployer interface {
+		DeploySwarmStack(stack *Stack, endpoint *Endpoint, registries []Registry, dockerhub *DockerHub, prune bool) error
+		DeployComposeStack(stack *Stack, endpoint *Endpoint, registries []Registry, dockerhub *DockerHub, pullImages bool) error
+		LockStack(stackID StackID) func()
+	}
+
+	// StackVersionManager represents a service used to keep the versions of the stacks
+	StackVersionManager interface {
+		CreateStackVersion(stack *Stack, createdBy string) error
+		StackVersions(stack *Stack) ([]StackVersion, error)
+	}
+
 	// JobService represents a service to manage job execution on hosts
 	JobService interface {
 		ExecuteScript(endpoint *Endpoint, nodeName, image string, script []byte, schedule *Schedule) error
@@ -907,6 +1502,7 @@ type (
 		SetTunnelStatusToRequired(endpointID EndpointID) error
 		SetTunnelStatusToIdle(endpointID EndpointID)
 		GetTunnelDetails(endpointID EndpointID) *TunnelDetails
+		SetLastCheckin(endpointID EndpointID, checkinInterval int)
 		AddSchedule(endpointID EndpointID, schedule *EdgeSchedule)
 		RemoveSchedule(scheduleID ScheduleID)
 	}
@@ -916,7 +1512,7 @@ const (
 	// APIVersion is the version number of the Portainer API
 	APIVersion = "1.24.0-dev"
 	// DBVersion is the version number of the Portainer database
-	DBVersion = 22
+	DBVersion = 23
 	// AssetsServerURL represents the URL of the Portainer asset server
 	AssetsServerURL = "https://portainer-io-assets.sfo2.digitaloceanspaces.com"
 	// MessageOfTheDayURL represents the URL where Portainer MOTD message can be retrieved
@@ -944,6 +1540,28 @@ const (
 	ExtensionServer = "localhost"
 	// DefaultEdgeAgentCheckinIntervalInSeconds represents the default interval (in seconds) used by Edge agents to checkin with the Portainer instance
 	DefaultEdgeAgentCheckinIntervalInSeconds = 5
+	// DefaultStackVersionRetention represents the default number of versions kept for each stack
+	DefaultStackVersionRetention = 10
+	// DefaultUserSessionTimeout represents the default lifetime of the JWT tokens delivered to users
+	DefaultUserSessionTimeout = "8h"
+	// DefaultSessionRecordingRetention represents the default number of days a console session recording is kept
+	DefaultSessionRecordingRetention = 30
+	// DefaultAuditLogRetention represents the default number of days an audit log is kept
+	DefaultAuditLogRetention = 90
+	// DefaultAccountLockoutDuration represents the default duration of the lockout of a user account
+	DefaultAccountLockoutDuration = "15m"
+	// DefaultSnapshotHistoryRetention represents the default duration the snapshot history of an endpoint is kept
+	DefaultSnapshotHistoryRetention = "2160h"
+	// DefaultSnapshotHistoryDownsamplingAge represents the default age after which snapshot records are downsampled
+	DefaultSnapshotHistoryDownsamplingAge = "24h"
+	// DefaultSnapshotHistoryDownsamplingInterval represents the default interval of downsampled snapshot records
+	DefaultSnapshotHistoryDownsamplingInterval = "1h"
+	// APIKeyHeader represents the name of the header used to authenticate a request with an API key
+	APIKeyHeader = "X-API-Key"
+	// APIKeyPrefix represents the prefix prepended to every generated API key
+	APIKeyPrefix = "ptr_"
+	// EdgeJoinTokenPrefix represents the prefix prepended to every generated Edge join token
+	EdgeJoinTokenPrefix = "ptj_"
 	// LocalExtensionManifestFile represents the name of the local manifest file for extensions
 	LocalExtensionManifestFile = "/extensions.json"
 )
@@ -989,6 +1607,14 @@ const (
 	ReadWriteAccessLevel
 )
 
+const (
+	_ ContainerPolicyMode = iota
+	// ContainerPolicyWarn represents a policy whose violations are logged without blocking the operation
+	ContainerPolicyWarn
+	// ContainerPolicyEnforce represents a policy whose violations block the operation
+	ContainerPolicyEnforce
+)
+
 const (
 	_ ResourceControlType = iota
 	// ContainerResourceControl represents a resource control associated to a Docker container
@@ -1055,6 +1681,68 @@ const (
 	_ WebhookType = iota
 	// ServiceWebhook is a webhook for restarting a docker service
 	ServiceWebhook
+	// StackWebhook is a webhook for redeploying a stack
+	StackWebhook
+	// ContainerWebhook is a webhook for recreating a docker container
+	ContainerWebhook
+)
+
+const (
+	_ AlertEventType = iota
+	// EndpointDownAlertEvent is triggered when an endpoint becomes unavailable
+	EndpointDownAlertEvent
+	// EndpointUpAlertEvent is triggered when an endpoint becomes available again
+	EndpointUpAlertEvent
+	// EdgeMissedCheckinAlertEvent is triggered when an Edge agent stops checking in with the Portainer instance
+	EdgeMissedCheckinAlertEvent
+	// StoppedContainersIncreaseAlertEvent is triggered when a snapshot detects more stopped containers than the previous one
+	StoppedContainersIncreaseAlertEvent
+)
+
+const (
+	_ EdgeStackStatusType = iota
+	// EdgeStackStatusPending represents an Edge stack that is not yet deployed with its latest version on an endpoint
+	EdgeStackStatusPending
+	// EdgeStackStatusOk represents an Edge stack successfully deployed on an endpoint
+	EdgeStackStatusOk
+	// EdgeStackStatusError represents an Edge stack that could not be deployed on an endpoint
+	EdgeStackStatusError
+)
+
+const (
+	_ EndpointEventType = iota
+	// ContainerCreatedEndpointEvent is recorded when a container appears on an endpoint
+	ContainerCreatedEndpointEvent
+	// ContainerRemovedEndpointEvent is recorded when a container disappears from an endpoint
+	ContainerRemovedEndpointEvent
+	// ContainerRestartedEndpointEvent is recorded when a container was started again since the previous snapshot
+	ContainerRestartedEndpointEvent
+	// ImageAddedEndpointEvent is recorded when an image appears on an endpoint
+	ImageAddedEndpointEvent
+	// ImageRemovedEndpointEvent is recorded when an image disappears from an endpoint
+	ImageRemovedEndpointEvent
+	// VolumeOrphanedEndpointEvent is recorded when a volume is no longer used by any container
+	VolumeOrphanedEndpointEvent
+	// ServiceScaledEndpointEvent is recorded when the number of replicas of a service changes
+	ServiceScaledEndpointEvent
+)
+
+const (
+	_ AlertNotifierType = iota
+	// WebhookAlertNotifier delivers alerts as JSON payloads to a generic webhook
+	WebhookAlertNotifier
+	// SlackAlertNotifier delivers alerts to a Slack compatible incoming webhook
+	SlackAlertNotifier
+	// EmailAlertNotifier delivers alerts by email via a SMTP server
+	EmailAlertNotifier
+)
+
+const (
+	_ SessionRecordingType = iota
+	// ExecSessionRecording represents the recording of an exec console session
+	ExecSessionRecording
+	// AttachSessionRecording represents the recording of an attach console session
+	AttachSessionRecording
 )
 
 const (
@@ -1077,6 +1765,17 @@ const (
 	// EndpointSyncJobType is a system job used to synchronize endpoints from
 	// an external definition store
 	EndpointSyncJobType
+	// StackGitUpdateJobType is a system job used to redeploy stacks created from
+	// a Git repository when the associated reference is updated
+	StackGitUpdateJobType
+	// BackupJobType is a system job used to create backups of the Portainer data
+	BackupJobType
+	// LDAPSyncJobType is a system job used to synchronize the users and the team memberships with the LDAP server
+	LDAPSyncJobType
+	// AuditLogPruneJobType is a system job used to remove the audit logs older than the audit log retention
+	AuditLogPruneJobType
+	// SnapshotHistoryCompactJobType is a system job used to apply the retention and the downsampling of the snapshot history
+	SnapshotHistoryCompactJobType
 )
 
 const (
@@ -1100,6 +1799,21 @@ const (
 	EdgeAgentActive string = "ACTIVE"
 )
 
+const (
+	// PasswordViolationMinLength represents a password shorter than the minimum length of the policy
+	PasswordViolationMinLength = "MinLength"
+	// PasswordViolationUppercase represents a password without any uppercase letter
+	PasswordViolationUppercase = "Uppercase"
+	// PasswordViolationLowercase represents a password without any lowercase letter
+	PasswordViolationLowercase = "Lowercase"
+	// PasswordViolationDigit represents a password without any digit
+	PasswordViolationDigit = "Digit"
+	// PasswordViolationSpecialCharacter represents a password without any special character
+	PasswordViolationSpecialCharacter = "SpecialCharacter"
+	// PasswordViolationReused represents a password matching one of the most recent passwords of the user
+	PasswordViolationReused = "Reused"
+)
+
 const (
 	OperationDockerContainerArchiveInfo         Authorization = "DockerContainerArchiveInfo"
 	OperationDockerContainerList                Authorization = "DockerContainerList"
@@ -0,0 +1,158 @@
+package quota
+
+import (
+	"fmt"
+
+	"github.com/portainer/portainer/api"
+)
+
+type (
+	// Resources represents an amount of resources counted against a quota.
+	// When containers are requested, a zero memory or CPU amount means that the containers are not limited.
+	Resources struct {
+		Containers int
+		Memory     int64
+		NanoCPUs   int64
+		Volumes    int
+	}
+
+	// Request represents the resources requested by an operation on an endpoint
+	Request struct {
+		Resources Resources
+		// PerNodeResources are the resources requested on each node of the cluster, such as the tasks of a global service
+		PerNodeResources Resources
+		// ReplacedResourceID is the identifier of the container or service replaced by an update operation.
+		// The resources it currently consumes are not counted.
+		ReplacedResourceID string
+		// ReplacedStack is the name of the stack replaced by a stack deployment.
+		// The resources currently consumed by the stack are not counted.
+		ReplacedStack string
+	}
+)
+
+// Add adds the specified resources to the resources.
+func (resources *Resources) Add(other *Resources) {
+	resources.Containers += other.Containers
+	resources.Memory += other.Memory
+	resources.NanoCPUs += other.NanoCPUs
+	resources.Volumes += other.Volumes
+}
+
+// Multiply returns the resources multiplied by the specified factor.
+func (resources Resources) Multiply(factor int) Resources {
+	return Resources{
+		Containers: resources.Containers * factor,
+		Memory:     resources.Memory * int64(factor),
+		NanoCPUs:   resources.NanoCPUs * int64(factor),
+		Volumes:    resources.Volumes * factor,
+	}
+}
+
+// ContainerNanoCPUs returns the CPU limit of a container, defined either with NanoCPUs or
+// with a CPU quota and period.
+func ContainerNanoCPUs(nanoCPUs, cpuQuota, cpuPeriod int64) int64 {
+	if nanoCPUs == 0 && cpuQuota > 0 && cpuPeriod > 0 {
+		return cpuQuota * 1e9 / cpuPeriod
+	}
+	return nanoCPUs
+}
+
+// ApplicableQuotas returns the quotas associated to the user and to the teams of the user that apply
+// to the endpoint. A quota defined on the endpoint takes precedence over a quota defined on the
+// endpoint group for the same user or team.
+func ApplicableQuotas(quotas []portainer.Quota, endpoint *portainer.Endpoint, userID portainer.UserID, teamMemberships []portainer.TeamMembership) []portainer.Quota {
+	ownerQuotas := make(map[string]portainer.Quota)
+	for _, quota := range UserQuotas(quotas, userID, teamMemberships) {
+		endpointQuota := quota.EndpointID == endpoint.ID
+		groupQuota := quota.EndpointID == 0 && quota.EndpointGroupID == endpoint.GroupID
+		if !endpointQuota && !groupQuota {
+			continue
+		}
+
+		owner := fmt.Sprintf("user:%d", quota.UserID)
+		if quota.TeamID != 0 {
+			owner = fmt.Sprintf("team:%d", quota.TeamID)
+		}
+
+		if _, exists := ownerQuotas[owner]; exists && groupQuota {
+			continue
+		}
+		ownerQuotas[owner] = quota
+	}
+
+	applicableQuotas := make([]portainer.Quota, 0, len(ownerQuotas))
+	for _, quota := range ownerQuotas {
+		applicableQuotas = append(applicableQuotas, quota)
+	}
+
+	return applicableQuotas
+}
+
+// UserQuotas returns the quotas associated to the user and to the teams of the user, on any endpoint.
+func UserQuotas(quotas []portainer.Quota, userID portainer.UserID, teamMemberships []portainer.TeamMembership) []portainer.Quota {
+	userTeams := make(map[portainer.TeamID]bool)
+	for _, membership := range teamMemberships {
+		userTeams[membership.TeamID] = true
+	}
+
+	userQuotas := make([]portainer.Quota, 0)
+	for _, quota := range quotas {
+		if (quota.TeamID == 0 && quota.UserID == userID) || (quota.TeamID != 0 && userTeams[quota.TeamID]) {
+			userQuotas = append(userQuotas, quota)
+		}
+	}
+
+	return userQuotas
+}
+
+// OwnsResource returns true when the resource control associates the resource to the owner of the quota.
+// The resources of a team are the resources shared with the team and the resources owned by its members.
+func OwnsResource(quota *portainer.Quota, resourceControl *portainer.ResourceControl, teamMembers map[portainer.UserID]bool) bool {
+	for _, access := range resourceControl.UserAccesses {
+		if (quota.TeamID == 0 && access.UserID == quota.UserID) || teamMembers[access.UserID] {
+			return true
+		}
+	}
+
+	if quota.TeamID != 0 {
+		for _, access := range resourceControl.TeamAccesses {
+			if access.TeamID == quota.TeamID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Violation returns a description of the limit of the quota exceeded by the requested resources
+// or an empty string when the quota is respected.
+func Violation(quota *portainer.Quota, usage, requested *Resources) string {
+	if requested.Containers > 0 {
+		if quota.MaxContainers > 0 && usage.Containers+requested.Containers > quota.MaxContainers {
+			return fmt.Sprintf("maximum number of containers (%d) reached", quota.MaxContainers)
+		}
+
+		if quota.MaxMemory > 0 && requested.Memory == 0 {
+			return "a memory limit is required"
+		}
+
+		if quota.MaxMemory > 0 && usage.Memory+requested.Memory > quota.MaxMemory {
+			return fmt.Sprintf("maximum total memory (%d bytes) exceeded", quota.MaxMemory)
+		}
+
+		if quota.MaxNanoCPUs > 0 && requested.NanoCPUs == 0 {
+			return "a CPU limit is required"
+		}
+
+		if quota.MaxNanoCPUs > 0 && usage.NanoCPUs+requested.NanoCPUs > quota.MaxNanoCPUs {
+			return fmt.Sprintf("maximum total CPU (%d NanoCPUs) exceeded", quota.MaxNanoCPUs)
+		}
+	}
+
+	if requested.Volumes > 0 && quota.MaxVolumes > 0 && usage.Volumes+requested.Volumes > quota.MaxVolumes {
+		return fmt.Sprintf("maximum number of volumes (%d) reached", quota.MaxVolumes)
+	}
+
+	return ""
+}
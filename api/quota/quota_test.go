@@ -0,0 +1,95 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api"
+)
+
+func TestViolation(t *testing.T) {
+	quota := &portainer.Quota{
+		MaxContainers: 2,
+		MaxMemory:     1024,
+		MaxNanoCPUs:   2e9,
+		MaxVolumes:    1,
+	}
+
+	tests := []struct {
+		name      string
+		usage     Resources
+		requested Resources
+		violation string
+	}{
+		{"within limits", Resources{Containers: 1, Memory: 512, NanoCPUs: 1e9}, Resources{Containers: 1, Memory: 512, NanoCPUs: 1e9}, ""},
+		{"too many containers", Resources{Containers: 2, Memory: 512, NanoCPUs: 1e9}, Resources{Containers: 1, Memory: 1, NanoCPUs: 1}, "maximum number of containers (2) reached"},
+		{"missing memory limit", Resources{}, Resources{Containers: 1, NanoCPUs: 1e9}, "a memory limit is required"},
+		{"memory exceeded", Resources{Containers: 1, Memory: 768, NanoCPUs: 1e9}, Resources{Containers: 1, Memory: 512, NanoCPUs: 1e9}, "maximum total memory (1024 bytes) exceeded"},
+		{"missing CPU limit", Resources{}, Resources{Containers: 1, Memory: 512}, "a CPU limit is required"},
+		{"CPU exceeded", Resources{Containers: 1, Memory: 512, NanoCPUs: 15e8}, Resources{Containers: 1, Memory: 512, NanoCPUs: 1e9}, "maximum total CPU (2000000000 NanoCPUs) exceeded"},
+		{"too many volumes", Resources{Volumes: 1}, Resources{Volumes: 1}, "maximum number of volumes (1) reached"},
+		{"volume without containers", Resources{Containers: 5}, Resources{Volumes: 1}, ""},
+	}
+
+	for _, test := range tests {
+		violation := Violation(quota, &test.usage, &test.requested)
+		if violation != test.violation {
+			t.Errorf("%s: expected %q, got %q", test.name, test.violation, violation)
+		}
+	}
+
+	unlimited := &portainer.Quota{}
+	if violation := Violation(unlimited, &Resources{Containers: 100}, &Resources{Containers: 1, Volumes: 1}); violation != "" {
+		t.Errorf("expected a quota without limits to be respected, got %q", violation)
+	}
+}
+
+func TestApplicableQuotas(t *testing.T) {
+	endpoint := &portainer.Endpoint{ID: 1, GroupID: 2}
+	memberships := []portainer.TeamMembership{{UserID: 1, TeamID: 10}}
+
+	quotas := []portainer.Quota{
+		{ID: 1, UserID: 1, EndpointGroupID: 2},
+		{ID: 2, UserID: 1, EndpointID: 1},
+		{ID: 3, TeamID: 10, EndpointGroupID: 2},
+		{ID: 4, TeamID: 11, EndpointID: 1},
+		{ID: 5, UserID: 2, EndpointID: 1},
+		{ID: 6, UserID: 1, EndpointID: 3},
+		{ID: 7, TeamID: 10, EndpointGroupID: 5},
+	}
+
+	applicable := ApplicableQuotas(quotas, endpoint, 1, memberships)
+
+	identifiers := make(map[portainer.QuotaID]bool)
+	for _, quota := range applicable {
+		identifiers[quota.ID] = true
+	}
+
+	if len(applicable) != 2 || !identifiers[2] || !identifiers[3] {
+		t.Errorf("expected the endpoint quota of the user and the group quota of the team, got %v", applicable)
+	}
+
+	if len(ApplicableQuotas(quotas, endpoint, 3, nil)) != 0 {
+		t.Errorf("expected no quota to apply to a user without quotas")
+	}
+}
+
+func TestOwnsResource(t *testing.T) {
+	userQuota := &portainer.Quota{UserID: 1}
+	teamQuota := &portainer.Quota{TeamID: 10}
+	teamMembers := map[portainer.UserID]bool{1: true, 2: true}
+
+	owned := &portainer.ResourceControl{UserAccesses: []portainer.UserResourceAccess{{UserID: 1}}}
+	if !OwnsResource(userQuota, owned, nil) || !OwnsResource(teamQuota, owned, teamMembers) {
+		t.Errorf("expected a resource of the user to be counted by the user and team quotas")
+	}
+
+	shared := &portainer.ResourceControl{TeamAccesses: []portainer.TeamResourceAccess{{TeamID: 10}}}
+	if OwnsResource(userQuota, shared, nil) || !OwnsResource(teamQuota, shared, teamMembers) {
+		t.Errorf("expected a resource shared with the team to be counted by the team quota only")
+	}
+
+	public := &portainer.ResourceControl{Public: true}
+	if OwnsResource(userQuota, public, nil) || OwnsResource(teamQuota, public, teamMembers) {
+		t.Errorf("expected a public resource without accesses not to be counted")
+	}
+}
@@ -0,0 +1,84 @@
+package stacks
+
+import (
+	"sync"
+
+	"github.com/portainer/portainer/api"
+)
+
+// StackDeployer is used to deploy Swarm and Compose stacks.
+// Both stack managers rely on the embedded Docker binary to login/logout against
+// registries, which stores credentials inside a shared config.json file. Deployments
+// are serialized to prevent concurrent deployments from using each other's credentials.
+// The operations replacing the project folder of a stack are serialized per stack through LockStack.
+type StackDeployer struct {
+	lock                *sync.Mutex
+	stackLocksMutex     *sync.Mutex
+	stackLocks          map[portainer.StackID]*sync.Mutex
+	swarmStackManager   portainer.SwarmStackManager
+	composeStackManager portainer.ComposeStackManager
+}
+
+// NewStackDeployer initializes a new StackDeployer
+func NewStackDeployer(swarmStackManager portainer.SwarmStackManager, composeStackManager portainer.ComposeStackManager) *StackDeployer {
+	return &StackDeployer{
+		lock:                &sync.Mutex{},
+		stackLocksMutex:     &sync.Mutex{},
+		stackLocks:          make(map[portainer.StackID]*sync.Mutex),
+		swarmStackManager:   swarmStackManager,
+		composeStackManager: composeStackManager,
+	}
+}
+
+// DeploySwarmStack deploys a Swarm stack using the specified registries credentials.
+// The image digests are always resolved against the registries, services are updated
+// when a newer version of their image is available.
+func (deployer *StackDeployer) DeploySwarmStack(stack *portainer.Stack, endpoint *portainer.Endpoint, registries []portainer.Registry, dockerhub *portainer.DockerHub, prune bool) error {
+	deployer.lock.Lock()
+	defer deployer.lock.Unlock()
+
+	deployer.swarmStackManager.Login(dockerhub, registries, endpoint)
+	defer deployer.swarmStackManager.Logout(endpoint)
+
+	return deployer.swarmStackManager.Deploy(stack, prune, endpoint)
+}
+
+// DeployComposeStack deploys a Compose stack using the specified registries credentials.
+// When pullImages is true, the latest version of each image is pulled before the deployment
+// so that containers are recreated when their image was updated.
+// TODO: libcompose uses credentials store into a config.json file to pull images from
+// private registries. Right now the only solution is to re-use the embedded Docker binary
+// to login/logout, which will generate the required data in the config.json file and then
+// clean it.
+// We should contribute to libcompose to support authentication without using the config.json file.
+func (deployer *StackDeployer) DeployComposeStack(stack *portainer.Stack, endpoint *portainer.Endpoint, registries []portainer.Registry, dockerhub *portainer.DockerHub, pullImages bool) error {
+	deployer.lock.Lock()
+	defer deployer.lock.Unlock()
+
+	deployer.swarmStackManager.Login(dockerhub, registries, endpoint)
+	defer deployer.swarmStackManager.Logout(endpoint)
+
+	if pullImages {
+		err := deployer.composeStackManager.Pull(stack, endpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return deployer.composeStackManager.Up(stack, endpoint)
+}
+
+// LockStack acquires the lock of a stack, which must be held while the project folder of the stack
+// is refreshed from its Git repository and redeployed. It returns the function releasing the lock.
+func (deployer *StackDeployer) LockStack(stackID portainer.StackID) func() {
+	deployer.stackLocksMutex.Lock()
+	stackLock, ok := deployer.stackLocks[stackID]
+	if !ok {
+		stackLock = &sync.Mutex{}
+		deployer.stackLocks[stackID] = stackLock
+	}
+	deployer.stackLocksMutex.Unlock()
+
+	stackLock.Lock()
+	return stackLock.Unlock
+}
@@ -0,0 +1,68 @@
+package stacks
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/policy"
+)
+
+// LoadStackFile parses the file of a stack and substitutes the environment variables of the stack.
+// It returns the raw content of the file, which contains the Compose file format version 2
+// settings that are not part of the parsed configuration, and the parsed configuration.
+func LoadStackFile(stack *portainer.Stack, stackFileContent []byte) (map[string]interface{}, *types.Config, error) {
+	composeConfigYAML, err := loader.ParseYAML(stackFileContent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	environment := make(map[string]string)
+	for _, variable := range stack.Env {
+		environment[variable.Name] = variable.Value
+	}
+
+	composeConfigDetails := types.ConfigDetails{
+		WorkingDir:  stack.ProjectPath,
+		ConfigFiles: []types.ConfigFile{{Config: composeConfigYAML}},
+		Environment: environment,
+	}
+
+	composeConfig, err := loader.Load(composeConfigDetails, func(options *loader.Options) {
+		options.SkipValidation = true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return composeConfigYAML, composeConfig, nil
+}
+
+// ImageRestrictionViolation returns the image of the first service of a stack that does not respect
+// the image restrictions and a description of the violation, or empty strings when every image can be used.
+// The images of the services built from a Dockerfile are not allowed.
+func ImageRestrictionViolation(restrictions *portainer.ImageRestrictions, registries []portainer.Registry, stack *portainer.Stack, stackFileContent []byte) (string, string, error) {
+	if !restrictions.Enabled {
+		return "", "", nil
+	}
+
+	_, composeConfig, err := LoadStackFile(stack, stackFileContent)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, service := range composeConfig.Services {
+		image := service.Image
+		if service.Build.Context != "" {
+			image = ""
+		}
+
+		violation := policy.ImageRestrictionViolation(restrictions, registries, image)
+		if violation != "" {
+			return image, fmt.Sprintf("service %s: %s", service.Name, violation), nil
+		}
+	}
+
+	return "", "", nil
+}
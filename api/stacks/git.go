@@ -0,0 +1,109 @@
+package stacks
+
+import (
+	"path"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+// RefreshGitRepository retrieves the latest commit of the reference tracked by a stack
+// created from a Git repository. When it differs from the last deployed commit, the stack
+// project folder is replaced with a fresh clone of the repository and the commit hash
+// of the stack is updated to the commit checked out in the clone. It returns true when
+// the project folder was updated.
+// The caller must hold the lock of the stack (see StackDeployer.LockStack), as the project
+// folder is replaced.
+func RefreshGitRepository(stack *portainer.Stack, gitService portainer.GitService, fileService portainer.FileService) (bool, error) {
+	config := stack.GitConfig
+
+	username, password := "", ""
+	if config.Authentication {
+		username, password = config.Username, config.Password
+	}
+
+	commitID, err := gitService.LatestCommitID(config.URL, config.ReferenceName, username, password)
+	if err != nil {
+		return false, err
+	}
+
+	if commitID == config.CommitHash {
+		return false, nil
+	}
+
+	clonePath := stack.ProjectPath + "-" + commitID
+	err = cloneGitRepository(gitService, config, clonePath)
+	if err != nil {
+		fileService.RemoveDirectory(clonePath)
+		return false, err
+	}
+
+	// the reference may have moved since its latest commit was retrieved
+	commitID, err = gitService.HeadCommitID(clonePath)
+	if err != nil || commitID == config.CommitHash {
+		fileService.RemoveDirectory(clonePath)
+		return false, err
+	}
+
+	err = moveStackVersions(fileService, stack.ProjectPath, clonePath)
+	if err != nil {
+		fileService.RemoveDirectory(clonePath)
+		return false, err
+	}
+
+	err = swapProjectFolder(fileService, stack.ProjectPath, clonePath)
+	if err != nil {
+		moveStackVersions(fileService, clonePath, stack.ProjectPath)
+		fileService.RemoveDirectory(clonePath)
+		return false, err
+	}
+
+	config.CommitHash = commitID
+	return true, nil
+}
+
+// swapProjectFolder replaces the project folder of a stack with another folder using renames only,
+// so that the project folder is never missing. The previous project folder is restored when the
+// new folder cannot be moved into place.
+func swapProjectFolder(fileService portainer.FileService, projectPath, replacementPath string) error {
+	previousPath := projectPath + "-previous"
+
+	err := fileService.RemoveDirectory(previousPath)
+	if err != nil {
+		return err
+	}
+
+	err = fileService.Rename(projectPath, previousPath)
+	if err != nil {
+		return err
+	}
+
+	err = fileService.Rename(replacementPath, projectPath)
+	if err != nil {
+		fileService.Rename(previousPath, projectPath)
+		return err
+	}
+
+	return fileService.RemoveDirectory(previousPath)
+}
+
+// cloneGitRepository clones the repository described by config in the destination folder.
+func cloneGitRepository(gitService portainer.GitService, config *portainer.StackGitConfig, destination string) error {
+	if config.Authentication {
+		return gitService.ClonePrivateRepositoryWithBasicAuth(config.URL, config.ReferenceName, destination, config.Username, config.Password)
+	}
+	return gitService.ClonePublicRepository(config.URL, config.ReferenceName, destination)
+}
+
+// moveStackVersions moves the versions of a stack stored inside its project folder to another folder
+// so that they are not lost when the project folder is replaced.
+func moveStackVersions(fileService portainer.FileService, projectPath, destination string) error {
+	versionsPath := path.Join(projectPath, filesystem.StackVersionsFolder)
+
+	exists, err := fileService.FileExists(versionsPath)
+	if err != nil || !exists {
+		return err
+	}
+
+	return fileService.Rename(versionsPath, path.Join(destination, filesystem.StackVersionsFolder))
+}
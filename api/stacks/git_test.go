@@ -0,0 +1,197 @@
+package stacks
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+type testGitService struct {
+	commitID string
+	// clonedCommitID is the commit checked out by a clone when the reference moved after commitID was retrieved
+	clonedCommitID string
+	cloneErr       error
+}
+
+func (service *testGitService) ClonePublicRepository(repositoryURL, referenceName string, destination string) error {
+	if service.cloneErr != nil {
+		return service.cloneErr
+	}
+
+	err := os.MkdirAll(destination, 0755)
+	if err != nil {
+		return err
+	}
+
+	commitID := service.commitID
+	if service.clonedCommitID != "" {
+		commitID = service.clonedCommitID
+	}
+
+	return ioutil.WriteFile(path.Join(destination, filesystem.ComposeFileDefaultName), []byte(commitID), 0644)
+}
+
+func (service *testGitService) ClonePrivateRepositoryWithBasicAuth(repositoryURL, referenceName string, destination, username, password string) error {
+	return service.ClonePublicRepository(repositoryURL, referenceName, destination)
+}
+
+func (service *testGitService) LatestCommitID(repositoryURL, referenceName, username, password string) (string, error) {
+	return service.commitID, nil
+}
+
+func (service *testGitService) HeadCommitID(repositoryPath string) (string, error) {
+	content, err := ioutil.ReadFile(path.Join(repositoryPath, filesystem.ComposeFileDefaultName))
+	return string(content), err
+}
+
+// testFileService only implements the file operations used to refresh a Git repository.
+type testFileService struct {
+	portainer.FileService
+}
+
+func (service *testFileService) RemoveDirectory(directoryPath string) error {
+	return os.RemoveAll(directoryPath)
+}
+
+func (service *testFileService) Rename(oldPath, newPath string) error {
+	return os.Rename(oldPath, newPath)
+}
+
+func (service *testFileService) FileExists(filePath string) (bool, error) {
+	_, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func setupGitStack(t *testing.T) (*portainer.Stack, func()) {
+	root, err := ioutil.TempDir("", "portainer-stack")
+	if err != nil {
+		t.Fatalf("unable to create temporary folder: %s", err)
+	}
+
+	projectPath := path.Join(root, "1")
+	err = os.MkdirAll(path.Join(projectPath, filesystem.StackVersionsFolder), 0755)
+	if err != nil {
+		t.Fatalf("unable to create project folder: %s", err)
+	}
+
+	err = ioutil.WriteFile(path.Join(projectPath, filesystem.ComposeFileDefaultName), []byte("old"), 0644)
+	if err != nil {
+		t.Fatalf("unable to create stack file: %s", err)
+	}
+
+	stack := &portainer.Stack{
+		ID:          1,
+		ProjectPath: projectPath,
+		EntryPoint:  filesystem.ComposeFileDefaultName,
+		GitConfig: &portainer.StackGitConfig{
+			URL:           "https://github.com/portainer/example",
+			ReferenceName: "refs/heads/master",
+			CommitHash:    "old",
+		},
+	}
+
+	return stack, func() { os.RemoveAll(root) }
+}
+
+func readStackFile(t *testing.T, stack *portainer.Stack) string {
+	content, err := ioutil.ReadFile(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		t.Fatalf("unable to read stack file: %s", err)
+	}
+	return string(content)
+}
+
+func TestRefreshGitRepositoryUpToDate(t *testing.T) {
+	stack, teardown := setupGitStack(t)
+	defer teardown()
+
+	updated, err := RefreshGitRepository(stack, &testGitService{commitID: "old"}, &testFileService{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if updated {
+		t.Errorf("expected the stack not to be updated")
+	}
+
+	if content := readStackFile(t, stack); content != "old" {
+		t.Errorf("expected the project folder to be untouched, got %q", content)
+	}
+}
+
+func TestRefreshGitRepositoryNewCommit(t *testing.T) {
+	stack, teardown := setupGitStack(t)
+	defer teardown()
+
+	updated, err := RefreshGitRepository(stack, &testGitService{commitID: "new"}, &testFileService{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !updated || stack.GitConfig.CommitHash != "new" {
+		t.Errorf("expected the stack to be updated to the new commit, got %t (%s)", updated, stack.GitConfig.CommitHash)
+	}
+
+	if content := readStackFile(t, stack); content != "new" {
+		t.Errorf("expected the project folder to contain the new clone, got %q", content)
+	}
+
+	if _, err := os.Stat(path.Join(stack.ProjectPath, filesystem.StackVersionsFolder)); err != nil {
+		t.Errorf("expected the stack versions to be kept: %s", err)
+	}
+
+	entries, err := ioutil.ReadDir(path.Dir(stack.ProjectPath))
+	if err != nil {
+		t.Fatalf("unable to list stack folders: %s", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected only the project folder to remain, got %d entries", len(entries))
+	}
+}
+
+func TestRefreshGitRepositoryReferenceMoved(t *testing.T) {
+	stack, teardown := setupGitStack(t)
+	defer teardown()
+
+	updated, err := RefreshGitRepository(stack, &testGitService{commitID: "new", clonedCommitID: "newer"}, &testFileService{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !updated || stack.GitConfig.CommitHash != "newer" {
+		t.Errorf("expected the commit hash to be the commit of the clone, got %t (%s)", updated, stack.GitConfig.CommitHash)
+	}
+
+	if content := readStackFile(t, stack); content != "newer" {
+		t.Errorf("expected the project folder to contain the new clone, got %q", content)
+	}
+}
+
+func TestRefreshGitRepositoryCloneFailure(t *testing.T) {
+	stack, teardown := setupGitStack(t)
+	defer teardown()
+
+	gitService := &testGitService{commitID: "new", cloneErr: errors.New("clone failed")}
+
+	updated, err := RefreshGitRepository(stack, gitService, &testFileService{})
+	if err == nil || updated {
+		t.Fatalf("expected the refresh to fail")
+	}
+
+	if stack.GitConfig.CommitHash != "old" {
+		t.Errorf("expected the commit hash to be unchanged, got %s", stack.GitConfig.CommitHash)
+	}
+
+	if content := readStackFile(t, stack); content != "old" {
+		t.Errorf("expected the project folder to be untouched, got %q", content)
+	}
+}
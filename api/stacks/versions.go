@@ -0,0 +1,119 @@
+package stacks
+
+import (
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/crypto"
+)
+
+// VersionManager is used to keep the versions of the stacks on the filesystem.
+// The values of the environment variables of each version are encrypted the same way
+// as the environment variables of the stacks stored inside the database.
+type VersionManager struct {
+	fileService       portainer.FileService
+	settingsService   portainer.SettingsService
+	encryptionService portainer.EncryptionService
+}
+
+// NewVersionManager initializes a new VersionManager
+func NewVersionManager(fileService portainer.FileService, settingsService portainer.SettingsService, encryptionService portainer.EncryptionService) *VersionManager {
+	return &VersionManager{
+		fileService:       fileService,
+		settingsService:   settingsService,
+		encryptionService: encryptionService,
+	}
+}
+
+// CreateStackVersion stores the current stack file and environment variables of a stack
+// as a new version. The oldest versions are removed based on the version retention setting.
+func (manager *VersionManager) CreateStackVersion(stack *portainer.Stack, createdBy string) error {
+	settings, err := manager.settingsService.Settings()
+	if err != nil {
+		return err
+	}
+
+	stackFileContent, err := manager.fileService.GetFileContent(path.Join(stack.ProjectPath, stack.EntryPoint))
+	if err != nil {
+		return err
+	}
+
+	env, err := manager.encryptEnv(stack.Env)
+	if err != nil {
+		return err
+	}
+
+	stackFolder := strconv.Itoa(int(stack.ID))
+	versions, err := manager.fileService.GetStackVersions(stackFolder)
+	if err != nil {
+		return err
+	}
+
+	version := &portainer.StackVersion{
+		Version:          1,
+		StackFileContent: string(stackFileContent),
+		Env:              env,
+		CreatedBy:        createdBy,
+		CreationDate:     time.Now().Unix(),
+	}
+
+	if len(versions) > 0 {
+		version.Version = versions[len(versions)-1].Version + 1
+	}
+
+	err = manager.fileService.StoreStackVersion(stackFolder, version)
+	if err != nil {
+		return err
+	}
+
+	versions = append(versions, *version)
+	for len(versions) > settings.StackVersionRetention {
+		err = manager.fileService.DeleteStackVersion(stackFolder, versions[0].Version)
+		if err != nil {
+			return err
+		}
+		versions = versions[1:]
+	}
+
+	return nil
+}
+
+// StackVersions returns the versions of a stack ordered by version number, with the values
+// of their environment variables decrypted.
+func (manager *VersionManager) StackVersions(stack *portainer.Stack) ([]portainer.StackVersion, error) {
+	versions, err := manager.fileService.GetStackVersions(strconv.Itoa(int(stack.ID)))
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range versions {
+		for envIdx := range versions[idx].Env {
+			value, err := crypto.DecryptSecret(manager.encryptionService, versions[idx].Env[envIdx].Value)
+			if err != nil {
+				return nil, err
+			}
+			versions[idx].Env[envIdx].Value = value
+		}
+	}
+
+	return versions, nil
+}
+
+func (manager *VersionManager) encryptEnv(env []portainer.Pair) ([]portainer.Pair, error) {
+	if env == nil {
+		return nil, nil
+	}
+
+	encryptedEnv := make([]portainer.Pair, len(env))
+	for idx, pair := range env {
+		value, err := crypto.EncryptSecret(manager.encryptionService, pair.Value)
+		if err != nil {
+			return nil, err
+		}
+		encryptedEnv[idx] = portainer.Pair{Name: pair.Name, Value: value}
+	}
+
+	return encryptedEnv, nil
+}
@@ -0,0 +1,208 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portainer/portainer/api"
+)
+
+const (
+	issuer            = "Portainer"
+	secretSize        = 20
+	period            = 30
+	digits            = 6
+	allowedSkew       = 1
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 5
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Service represents a service used to manage the time-based one-time password (RFC 6238)
+// second authentication factor of users.
+// The secrets are stored encrypted and the recovery codes are stored hashed.
+type Service struct {
+	encryptionService portainer.EncryptionService
+	cryptoService     portainer.CryptoService
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(encryptionService portainer.EncryptionService, cryptoService portainer.CryptoService) *Service {
+	return &Service{
+		encryptionService: encryptionService,
+		cryptoService:     cryptoService,
+	}
+}
+
+// Enrol generates a new secret and a new set of recovery codes for the user.
+// The enrolment is pending until the user is updated with TOTPEnabled set to true, which
+// should only be done once a code generated with the new secret has been verified.
+func (service *Service) Enrol(user *portainer.User) (*portainer.TOTPEnrolment, error) {
+	key := make([]byte, secretSize)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+	secret := secretEncoding.EncodeToString(key)
+
+	encryptedSecret, err := service.encryptionService.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	recoveryCodeHashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := service.cryptoService.Hash(normalizeRecoveryCode(code))
+		if err != nil || hash == "" {
+			return nil, portainer.ErrCryptoHashFailure
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+		recoveryCodeHashes = append(recoveryCodeHashes, hash)
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = encryptedSecret
+	user.TOTPRecoveryCodes = recoveryCodeHashes
+	user.TOTPLastCounter = 0
+
+	return &portainer.TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: provisioningURI(user.Username, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
+
+// Verify validates the code against the secret of the user. When two-factor authentication
+// is enabled for the user, the code can also be one of the recovery codes.
+// A code can only be used once: the last accepted time step is recorded and a matching
+// recovery code is removed from the user. The user must be persisted by the caller.
+func (service *Service) Verify(user *portainer.User, code string) error {
+	if user.TOTPSecret == "" {
+		return portainer.ErrTwoFactorNotEnrolled
+	}
+
+	code = strings.Replace(strings.TrimSpace(code), " ", "", -1)
+
+	secret, err := service.encryptionService.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return err
+	}
+
+	key, err := secretEncoding.DecodeString(secret)
+	if err != nil {
+		return err
+	}
+
+	counter, valid := validateCode(key, code, time.Now().Unix(), user.TOTPLastCounter)
+	if valid {
+		user.TOTPLastCounter = counter
+		return nil
+	}
+
+	if !user.TOTPEnabled || len(code) == digits {
+		return portainer.ErrTwoFactorInvalidCode
+	}
+
+	recoveryCode := normalizeRecoveryCode(code)
+	for idx, hash := range user.TOTPRecoveryCodes {
+		if service.cryptoService.CompareHashAndData(hash, recoveryCode) == nil {
+			user.TOTPRecoveryCodes = append(user.TOTPRecoveryCodes[:idx], user.TOTPRecoveryCodes[idx+1:]...)
+			return nil
+		}
+	}
+
+	return portainer.ErrTwoFactorInvalidCode
+}
+
+// validateCode checks the code against the time steps around the timestamp and returns
+// the matching time step. Time steps lower or equal to lastCounter are rejected to prevent
+// the reuse of a code.
+func validateCode(key []byte, code string, timestamp, lastCounter int64) (int64, bool) {
+	if len(code) != digits {
+		return 0, false
+	}
+
+	current := timestamp / period
+	for offset := int64(-allowedSkew); offset <= allowedSkew; offset++ {
+		counter := current + offset
+		if counter <= lastCounter {
+			continue
+		}
+
+		if hmac.Equal([]byte(generateCode(key, counter)), []byte(code)) {
+			return counter, true
+		}
+	}
+
+	return 0, false
+}
+
+// generateCode computes the HOTP value (RFC 4226) of the key for the specified counter.
+func generateCode(key []byte, counter int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
+
+func provisioningURI(username, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", strconv.Itoa(digits))
+	values.Set("period", strconv.Itoa(period))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + username,
+		RawQuery: values.Encode(),
+	}
+
+	return uri.String()
+}
+
+func generateRecoveryCode() (string, error) {
+	data := make([]byte, recoveryCodeSize*2)
+	_, err := io.ReadFull(rand.Reader, data)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data[:recoveryCodeSize]) + "-" + hex.EncodeToString(data[recoveryCodeSize:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.Replace(code, "-", "", -1))
+}
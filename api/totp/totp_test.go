@@ -0,0 +1,57 @@
+package totp
+
+import "testing"
+
+// Test vectors from RFC 6238 (SHA1), truncated to 6 digits
+func TestGenerateCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	tests := []struct {
+		timestamp int64
+		code      string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		code := generateCode(key, test.timestamp/period)
+		if code != test.code {
+			t.Errorf("unexpected code for timestamp %d: got %s, want %s", test.timestamp, code, test.code)
+		}
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	key := []byte("12345678901234567890")
+	timestamp := int64(1234567890)
+
+	counter, valid := validateCode(key, "005924", timestamp, 0)
+	if !valid || counter != timestamp/period {
+		t.Fatalf("expected code to be valid for the current time step")
+	}
+
+	_, valid = validateCode(key, "005924", timestamp+period, 0)
+	if !valid {
+		t.Errorf("expected code of the previous time step to be accepted")
+	}
+
+	_, valid = validateCode(key, "005924", timestamp+2*period, 0)
+	if valid {
+		t.Errorf("expected code outside of the allowed skew to be rejected")
+	}
+
+	_, valid = validateCode(key, "005924", timestamp, counter)
+	if valid {
+		t.Errorf("expected code to be rejected once used")
+	}
+
+	_, valid = validateCode(key, "00592", timestamp, 0)
+	if valid {
+		t.Errorf("expected code with an invalid length to be rejected")
+	}
+}